@@ -0,0 +1,18 @@
+package main
+
+import (
+	"flag"
+	"university-db-admin/internal/app"
+)
+
+func main() {
+	steps := flag.Int("steps", 1, "number of migrations to revert with down")
+	flag.Parse()
+
+	command := flag.Arg(0)
+	if command == "" {
+		command = "up"
+	}
+
+	app.Migrate(command, *steps)
+}
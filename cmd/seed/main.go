@@ -0,0 +1,16 @@
+package main
+
+import (
+	"flag"
+	"strings"
+	"university-db-admin/internal/app"
+	"university-db-admin/internal/seeder"
+)
+
+func main() {
+	preset := flag.String("preset", "small", "size of the generated data: "+strings.Join(seeder.PresetNames(), ", "))
+	seed := flag.Uint64("seed", 1, "seed of the random generator, the same seed gives the same data")
+	flag.Parse()
+
+	app.Seed(*preset, *seed)
+}
@@ -0,0 +1,10 @@
+package main
+
+import (
+	"os"
+	"university-db-admin/internal/app"
+)
+
+func main() {
+	os.Exit(app.RunCLI(os.Args[1:]))
+}
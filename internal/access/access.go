@@ -0,0 +1,111 @@
+// Package access restricts a repository.Repository to what the role of a
+// user allows. Reads pass through unchanged except for the audit log, the
+// console and the accounts; changes the user may not make fail with
+// ErrForbidden before reaching the database.
+package access
+
+import (
+	"context"
+	"errors"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+)
+
+var ErrForbidden = errors.New("недостаточно прав для выполнения операции")
+
+// tables each role may change, admins may change everything
+var writable = map[domain.Role][]string{
+	domain.RoleDean: {
+		"groups", "students", "subjects", "lesson_types",
+		"lessons", "marks", "employees_subjects",
+	},
+	domain.RoleTeacher: {"marks"}, // only their own, see marks
+}
+
+// May tells whether the role may change the table
+func May(role domain.Role, table string) bool {
+	if role == domain.RoleAdmin {
+		return true
+	}
+	for _, t := range writable[role] {
+		if t == table {
+			return true
+		}
+	}
+	return false
+}
+
+// Wrap returns r as seen by u, transactions started on the result
+// hand out repositories restricted the same way
+func Wrap(r *repository.Repository, u domain.User) *repository.Repository {
+	return wrap(r, u, false)
+}
+
+// Bootstrap returns r as seen by whoever sets up a database without
+// accounts: the data may be read like with the read only role and the
+// accounts may be managed, so the first administrator can be created
+func Bootstrap(r *repository.Repository) *repository.Repository {
+	return wrap(r, domain.User{Role: domain.RoleReadOnly}, true)
+}
+
+// accounts lifts the restriction on the accounts for Bootstrap
+func wrap(r *repository.Repository, u domain.User, accounts bool) *repository.Repository {
+	// nil when u may change the table
+	deny := func(table string) error {
+		if May(u.Role, table) {
+			return nil
+		}
+		return ErrForbidden
+	}
+	// nil when u may use a feature reserved to some roles
+	only := func(roles ...domain.Role) error {
+		for _, role := range roles {
+			if u.Role == role {
+				return nil
+			}
+		}
+		return ErrForbidden
+	}
+
+	m := marks{Marks: r.Marks, err: deny("marks"), subjects: r.EmployeesSubjects}
+	if u.Role == domain.RoleTeacher {
+		m.teacher = u.EmployeeID
+		if m.teacher == nil { // prevented by the users_teacher_check constraint
+			m.err = ErrForbidden
+		}
+	}
+
+	usersErr := only(domain.RoleAdmin)
+	if accounts {
+		usersErr = nil
+	}
+
+	return &repository.Repository{
+		Transactor:        transactor{Transactor: r.Transactor, user: u, accounts: accounts},
+		Employees:         employees{Employees: r.Employees, err: deny("employees")},
+		Groups:            groups{Groups: r.Groups, err: deny("groups")},
+		LessonTypes:       lessonTypes{LessonTypes: r.LessonTypes, err: deny("lesson_types")},
+		Lessons:           lessons{Lessons: r.Lessons, err: deny("lessons")},
+		Marks:             m,
+		Positions:         positions{Positions: r.Positions, err: deny("positions")},
+		Students:          students{Students: r.Students, err: deny("students")},
+		Subjects:          subjects{Subjects: r.Subjects, err: deny("subjects")},
+		EmployeesSubjects: employeesSubjects{EmployeesSubjects: r.EmployeesSubjects, err: deny("employees_subjects")},
+		Audit:             audit{Audit: r.Audit, err: only(domain.RoleAdmin, domain.RoleDean)},
+		Console:           console{Console: r.Console, err: only(domain.RoleAdmin)},
+		Users:             users{Users: r.Users, err: usersErr},
+		Purger:            purger{Purger: r.Purger, err: only(domain.RoleAdmin)},
+	}
+}
+
+type transactor struct {
+	repository.Transactor
+	user     domain.User
+	accounts bool
+}
+
+func (t transactor) WithTx(ctx context.Context, fn func(ctx context.Context, r *repository.Repository) error) error {
+	return t.Transactor.WithTx(ctx, func(ctx context.Context, r *repository.Repository) error {
+		return fn(ctx, wrap(r, t.user, t.accounts))
+	})
+}
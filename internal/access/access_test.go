@@ -0,0 +1,205 @@
+package access_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"university-db-admin/internal/access"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+	"university-db-admin/internal/repository/memory"
+)
+
+// ids of the rows created by fixture
+type fixture struct {
+	r                *repository.Repository
+	ivanov, petrova  uint64 // teachers
+	math, physics    uint64 // math is taught by ivanov, physics by petrova
+	student          uint64
+	ivanovs, petrovs uint64 // marks given by ivanov in math and petrova in physics
+}
+
+func newFixture(t *testing.T) fixture {
+	t.Helper()
+	ctx := context.Background()
+	f := fixture{r: memory.NewRepository()}
+
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("creating fixture: %v", err)
+		}
+	}
+	pos, err := f.r.Positions.Create(ctx, domain.Position{Name: "Преподаватель"})
+	must(err)
+	f.ivanov, err = f.r.Employees.Create(ctx, domain.Employee{Name: "Ivanov Ivan", Passport: "MP0000001", PositionID: pos})
+	must(err)
+	f.petrova, err = f.r.Employees.Create(ctx, domain.Employee{Name: "Petrova Olga", Passport: "MP0000002", PositionID: pos})
+	must(err)
+	grp, err := f.r.Groups.Create(ctx, domain.Group{Number: 101})
+	must(err)
+	f.student, err = f.r.Students.Create(ctx, domain.Student{Name: "Anna Smirnova", Passport: "MP1000001", EmployeeID: f.ivanov, GroupID: grp})
+	must(err)
+	f.math, err = f.r.Subjects.Create(ctx, domain.Subject{Name: "Mathematics", Description: "math"})
+	must(err)
+	f.physics, err = f.r.Subjects.Create(ctx, domain.Subject{Name: "Physics", Description: "physics"})
+	must(err)
+	must(f.r.EmployeesSubjects.Create(ctx, domain.EmployeeSubject{EmployeeID: f.ivanov, SubjectID: f.math}))
+	must(f.r.EmployeesSubjects.Create(ctx, domain.EmployeeSubject{EmployeeID: f.petrova, SubjectID: f.physics}))
+	lecture, err := f.r.LessonTypes.Create(ctx, domain.LessonType{Name: "LK"})
+	must(err)
+	for i, sbj := range []uint64{f.math, f.physics} {
+		_, err = f.r.Lessons.Create(ctx, domain.Lesson{GroupID: grp, SubjectID: sbj, LessonTypeID: lecture, Week: 1, Weekday: uint16(1 + i), Pair: 1, Room: 101})
+		must(err)
+	}
+	f.ivanovs, err = f.r.Marks.Create(ctx, f.mark(f.ivanov, f.math))
+	must(err)
+	f.petrovs, err = f.r.Marks.Create(ctx, f.mark(f.petrova, f.physics))
+	must(err)
+	return f
+}
+
+func (f fixture) mark(employeeID, subjectID uint64) domain.Mark {
+	date := time.Date(2024, time.September, 2, 0, 0, 0, 0, time.UTC)
+	return domain.Mark{EmployeeID: employeeID, StudentID: f.student, SubjectID: subjectID, Mark: 8, Date: date}
+}
+
+func wantError(t *testing.T, err, target error) {
+	t.Helper()
+	if !errors.Is(err, target) {
+		t.Fatalf("got error %v, want %v", err, target)
+	}
+}
+
+func must(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	r := access.Wrap(f.r, domain.User{Login: "guest", Role: domain.RoleReadOnly})
+
+	students, err := r.Students.FindAll(ctx)
+	must(t, err)
+	if len(students) != 1 {
+		t.Fatalf("got %d students, want 1", len(students))
+	}
+
+	_, err = r.Students.Create(ctx, domain.Student{Name: "Boris Volkov", Passport: "MP1000002", EmployeeID: f.ivanov, GroupID: 1})
+	wantError(t, err, access.ErrForbidden)
+	wantError(t, r.Marks.Delete(ctx, f.ivanovs), access.ErrForbidden)
+	_, err = r.Audit.List(ctx, repository.ListOptions{})
+	wantError(t, err, access.ErrForbidden)
+	_, err = r.Users.FindAll(ctx)
+	wantError(t, err, access.ErrForbidden)
+}
+
+func TestDean(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	r := access.Wrap(f.r, domain.User{Login: "dean", Role: domain.RoleDean})
+
+	_, err := r.Marks.Create(ctx, f.mark(f.petrova, f.physics))
+	must(t, err)
+	_, err = r.Audit.List(ctx, repository.ListOptions{})
+	must(t, err)
+
+	_, err = r.Positions.Create(ctx, domain.Position{Name: "Dean"})
+	wantError(t, err, access.ErrForbidden)
+	wantError(t, r.Employees.Delete(ctx, f.ivanov), access.ErrForbidden)
+	_, err = r.Console.Exec(ctx, "SELECT 1", false, 0)
+	wantError(t, err, access.ErrForbidden)
+}
+
+func TestTeacher(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	r := access.Wrap(f.r, domain.User{Login: "ivanov", Role: domain.RoleTeacher, EmployeeID: &f.ivanov})
+
+	_, err := r.Marks.Create(ctx, f.mark(f.ivanov, f.math))
+	must(t, err)
+	must(t, r.Marks.Update(ctx, f.ivanovs, domain.Mark{EmployeeID: f.ivanov, StudentID: f.student, SubjectID: f.math, Mark: 9, Date: time.Now()}))
+
+	// a subject they don't teach, a mark of another teacher and a mark given in their name
+	_, err = r.Marks.Create(ctx, f.mark(f.ivanov, f.physics))
+	wantError(t, err, access.ErrForeignMark)
+	wantError(t, r.Marks.Delete(ctx, f.petrovs), access.ErrForeignMark)
+	wantError(t, r.Marks.Update(ctx, f.ivanovs, f.mark(f.petrova, f.physics)), access.ErrForeignMark)
+	_, err = r.Marks.CopyFrom(ctx, []domain.Mark{f.mark(f.ivanov, f.math), f.mark(f.petrova, f.physics)})
+	wantError(t, err, access.ErrForbidden)
+
+	_, err = r.Groups.Create(ctx, domain.Group{Number: 102})
+	wantError(t, err, access.ErrForbidden)
+}
+
+func TestTransactionsAreRestricted(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	r := access.Wrap(f.r, domain.User{Login: "ivanov", Role: domain.RoleTeacher, EmployeeID: &f.ivanov})
+
+	err := r.WithTx(ctx, func(ctx context.Context, tx *repository.Repository) error {
+		_, err := tx.Subjects.Create(ctx, domain.Subject{Name: "Chemistry", Description: "chemistry"})
+		return err
+	})
+	wantError(t, err, access.ErrForbidden)
+
+	subjects, err := f.r.Subjects.FindAll(ctx)
+	must(t, err)
+	if len(subjects) != 2 {
+		t.Fatalf("got %d subjects, want 2", len(subjects))
+	}
+}
+
+func TestBootstrap(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	r := access.Bootstrap(f.r)
+
+	err := r.WithTx(ctx, func(ctx context.Context, tx *repository.Repository) error {
+		_, err := tx.Users.Create(ctx, domain.User{Login: "admin", PasswordHash: "hash", Role: domain.RoleAdmin})
+		return err
+	})
+	must(t, err)
+
+	_, err = r.Groups.Create(ctx, domain.Group{Number: 102})
+	wantError(t, err, access.ErrForbidden)
+	_, err = r.Console.Exec(ctx, "SELECT 1", false, 0)
+	wantError(t, err, access.ErrForbidden)
+}
+
+// answers every statement with a single row, the memory backend has no SQL engine
+type stubConsole struct{}
+
+func (stubConsole) Exec(ctx context.Context, statement string, write bool, limit int, args ...any) (repository.ConsoleResult, error) {
+	return repository.ConsoleResult{Columns: []string{"n"}, Rows: [][]any{{1}}}, nil
+}
+
+func (c stubConsole) Report(ctx context.Context, statement string, args ...any) (repository.ConsoleResult, error) {
+	return c.Exec(ctx, statement, false, 0, args...)
+}
+
+func TestReportsAreOpen(t *testing.T) {
+	f := newFixture(t)
+	ctx := context.Background()
+	f.r.Console = stubConsole{}
+
+	for _, role := range domain.Roles {
+		u := domain.User{Login: string(role), Role: role}
+		if role == domain.RoleTeacher {
+			u.EmployeeID = &f.ivanov
+		}
+		r := access.Wrap(f.r, u)
+
+		if _, err := r.Console.Report(ctx, "SELECT 1"); err != nil {
+			t.Fatalf("report as %s: %v", role, err)
+		}
+		if _, err := r.Console.Exec(ctx, "SELECT 1", false, 0); role != domain.RoleAdmin {
+			wantError(t, err, access.ErrForbidden)
+		}
+	}
+}
@@ -0,0 +1,91 @@
+package access
+
+import (
+	"context"
+	"fmt"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+)
+
+// ErrForeignMark is returned when a teacher changes a mark given by someone
+// else or by a subject they don't teach
+var ErrForeignMark = fmt.Errorf("%w: преподаватель может изменять только свои оценки по предметам, которые он ведёт", ErrForbidden)
+
+type marks struct {
+	repository.Marks
+	err error
+
+	// employee record of a teacher, nil for other roles,
+	// whose marks are checked one by one
+	teacher  *uint64
+	subjects repository.EmployeesSubjects
+}
+
+// checks that the user may give or take away the mark
+func (r marks) check(ctx context.Context, mark domain.Mark) error {
+	if r.err != nil {
+		return r.err
+	}
+	if r.teacher == nil {
+		return nil
+	}
+	if mark.EmployeeID != *r.teacher {
+		return ErrForeignMark
+	}
+
+	taught, err := r.subjects.FindByEmployeeID(ctx, *r.teacher)
+	if err != nil {
+		return err
+	}
+	for _, es := range taught {
+		if es.SubjectID == mark.SubjectID {
+			return nil
+		}
+	}
+	return ErrForeignMark
+}
+
+// checks the stored mark a change is made to
+func (r marks) checkStored(ctx context.Context, id uint64) error {
+	if r.err != nil || r.teacher == nil {
+		return r.err
+	}
+	old, err := r.Marks.FindOne(ctx, id)
+	if err != nil {
+		return err
+	}
+	return r.check(ctx, old)
+}
+
+func (r marks) Create(ctx context.Context, mark domain.Mark) (uint64, error) {
+	if err := r.check(ctx, mark); err != nil {
+		return 0, err
+	}
+	return r.Marks.Create(ctx, mark)
+}
+
+func (r marks) CopyFrom(ctx context.Context, marks []domain.Mark) (int64, error) {
+	for _, mark := range marks {
+		if err := r.check(ctx, mark); err != nil {
+			return 0, err
+		}
+	}
+	return r.Marks.CopyFrom(ctx, marks)
+}
+
+func (r marks) Update(ctx context.Context, id uint64, mark domain.Mark) error {
+	if err := r.checkStored(ctx, id); err != nil {
+		return err
+	}
+	if err := r.check(ctx, mark); err != nil {
+		return err
+	}
+	return r.Marks.Update(ctx, id, mark)
+}
+
+func (r marks) Delete(ctx context.Context, id uint64) error {
+	if err := r.checkStored(ctx, id); err != nil {
+		return err
+	}
+	return r.Marks.Delete(ctx, id)
+}
@@ -0,0 +1,342 @@
+package access
+
+import (
+	"context"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+)
+
+// every repository below embeds the one it guards, so methods that are
+// not overridden pass through. err is nil when the user may change the table
+
+type employees struct {
+	repository.Employees
+	err error
+}
+
+func (r employees) Create(ctx context.Context, emp domain.Employee) (uint64, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return r.Employees.Create(ctx, emp)
+}
+
+func (r employees) CopyFrom(ctx context.Context, emps []domain.Employee) (int64, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return r.Employees.CopyFrom(ctx, emps)
+}
+
+func (r employees) Update(ctx context.Context, id uint64, emp domain.Employee) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.Employees.Update(ctx, id, emp)
+}
+
+func (r employees) Delete(ctx context.Context, id uint64) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.Employees.Delete(ctx, id)
+}
+
+func (r employees) Restore(ctx context.Context, id uint64) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.Employees.Restore(ctx, id)
+}
+
+type groups struct {
+	repository.Groups
+	err error
+}
+
+func (r groups) Create(ctx context.Context, grp domain.Group) (uint64, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return r.Groups.Create(ctx, grp)
+}
+
+func (r groups) Update(ctx context.Context, id uint64, grp domain.Group) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.Groups.Update(ctx, id, grp)
+}
+
+func (r groups) Delete(ctx context.Context, id uint64) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.Groups.Delete(ctx, id)
+}
+
+func (r groups) Restore(ctx context.Context, id uint64) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.Groups.Restore(ctx, id)
+}
+
+type lessonTypes struct {
+	repository.LessonTypes
+	err error
+}
+
+func (r lessonTypes) Create(ctx context.Context, lsn domain.LessonType) (uint64, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return r.LessonTypes.Create(ctx, lsn)
+}
+
+func (r lessonTypes) Update(ctx context.Context, id uint64, lsn domain.LessonType) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.LessonTypes.Update(ctx, id, lsn)
+}
+
+func (r lessonTypes) Delete(ctx context.Context, id uint64) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.LessonTypes.Delete(ctx, id)
+}
+
+type lessons struct {
+	repository.Lessons
+	err error
+}
+
+func (r lessons) Create(ctx context.Context, lsn domain.Lesson) (uint64, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return r.Lessons.Create(ctx, lsn)
+}
+
+func (r lessons) Update(ctx context.Context, id uint64, lsn domain.Lesson) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.Lessons.Update(ctx, id, lsn)
+}
+
+func (r lessons) Delete(ctx context.Context, id uint64) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.Lessons.Delete(ctx, id)
+}
+
+type positions struct {
+	repository.Positions
+	err error
+}
+
+func (r positions) Create(ctx context.Context, pos domain.Position) (uint64, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return r.Positions.Create(ctx, pos)
+}
+
+func (r positions) Update(ctx context.Context, id uint64, pos domain.Position) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.Positions.Update(ctx, id, pos)
+}
+
+func (r positions) Delete(ctx context.Context, id uint64) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.Positions.Delete(ctx, id)
+}
+
+type students struct {
+	repository.Students
+	err error
+}
+
+func (r students) Create(ctx context.Context, stud domain.Student) (uint64, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return r.Students.Create(ctx, stud)
+}
+
+func (r students) CopyFrom(ctx context.Context, studs []domain.Student) (int64, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return r.Students.CopyFrom(ctx, studs)
+}
+
+func (r students) Update(ctx context.Context, id uint64, stud domain.Student) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.Students.Update(ctx, id, stud)
+}
+
+func (r students) Delete(ctx context.Context, id uint64) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.Students.Delete(ctx, id)
+}
+
+func (r students) Restore(ctx context.Context, id uint64) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.Students.Restore(ctx, id)
+}
+
+type subjects struct {
+	repository.Subjects
+	err error
+}
+
+func (r subjects) Create(ctx context.Context, sbj domain.Subject) (uint64, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return r.Subjects.Create(ctx, sbj)
+}
+
+func (r subjects) Update(ctx context.Context, id uint64, sbj domain.Subject) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.Subjects.Update(ctx, id, sbj)
+}
+
+func (r subjects) Delete(ctx context.Context, id uint64) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.Subjects.Delete(ctx, id)
+}
+
+type employeesSubjects struct {
+	repository.EmployeesSubjects
+	err error
+}
+
+func (r employeesSubjects) Create(ctx context.Context, es domain.EmployeeSubject) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.EmployeesSubjects.Create(ctx, es)
+}
+
+func (r employeesSubjects) Update(ctx context.Context, eid uint64, sid uint64, es domain.EmployeeSubject) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.EmployeesSubjects.Update(ctx, eid, sid, es)
+}
+
+func (r employeesSubjects) Delete(ctx context.Context, eid uint64, sid uint64) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.EmployeesSubjects.Delete(ctx, eid, sid)
+}
+
+// the audit log, the console and the accounts are closed to
+// some roles entirely, reads included
+
+type audit struct {
+	repository.Audit
+	err error
+}
+
+func (r audit) List(ctx context.Context, opts repository.ListOptions) (repository.Page[domain.AuditEntry], error) {
+	if r.err != nil {
+		return repository.Page[domain.AuditEntry]{}, r.err
+	}
+	return r.Audit.List(ctx, opts)
+}
+
+// statements of the console could read the password hashes
+// or change any table, so it's kept to admins. Reports run the
+// statements of the special queries file and are left open
+type console struct {
+	repository.Console
+	err error
+}
+
+func (r console) Exec(ctx context.Context, statement string, write bool, limit int, args ...any) (repository.ConsoleResult, error) {
+	if r.err != nil {
+		return repository.ConsoleResult{}, r.err
+	}
+	return r.Console.Exec(ctx, statement, write, limit, args...)
+}
+
+type users struct {
+	repository.Users
+	err error
+}
+
+func (r users) Create(ctx context.Context, u domain.User) (uint64, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return r.Users.Create(ctx, u)
+}
+
+func (r users) FindAll(ctx context.Context) ([]domain.User, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.Users.FindAll(ctx)
+}
+
+func (r users) FindByLogin(ctx context.Context, login string) (domain.User, error) {
+	if r.err != nil {
+		return domain.User{}, r.err
+	}
+	return r.Users.FindByLogin(ctx, login)
+}
+
+func (r users) Count(ctx context.Context) (uint64, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	return r.Users.Count(ctx)
+}
+
+func (r users) Update(ctx context.Context, id uint64, u domain.User) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.Users.Update(ctx, id, u)
+}
+
+func (r users) Delete(ctx context.Context, id uint64) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.Users.Delete(ctx, id)
+}
+
+type purger struct {
+	repository.Purger
+	err error
+}
+
+func (r purger) Purge(ctx context.Context, tables ...string) error {
+	if r.err != nil {
+		return r.err
+	}
+	return r.Purger.Purge(ctx, tables...)
+}
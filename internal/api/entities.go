@@ -0,0 +1,417 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+	"university-db-admin/internal/schedule"
+	"university-db-admin/internal/service"
+)
+
+// the service of an entity identified by a single id
+type store[T any] interface {
+	List(ctx context.Context, opts repository.ListOptions) (repository.Page[T], error)
+	FindOne(ctx context.Context, id uint64) (T, error)
+	Create(ctx context.Context, item T) (uint64, error)
+	Update(ctx context.Context, id uint64, item T) error
+	Delete(ctx context.Context, id uint64) error
+}
+
+// the service of an entity with soft delete
+type restorer interface {
+	Restore(ctx context.Context, id uint64) error
+}
+
+// crud describes the endpoints of an entity identified by a single id,
+// entities with soft delete also set restore. Both pick the service
+// out of the ones of the user sending the request
+type crud[T any] struct {
+	tag     string
+	path    string
+	store   func(s *service.Service) store[T]
+	restore func(s *service.Service) restorer
+}
+
+func (c crud[T]) routes() []route {
+	var zero T
+	item := c.path + "/{id}"
+
+	query := listParams
+	if c.restore != nil {
+		query = append(query[:len(query):len(query)], deletedParam)
+	}
+
+	routes := []route{
+		{
+			method:  http.MethodGet,
+			path:    c.path,
+			summary: "list " + c.tag,
+			tag:     c.tag,
+			query:   query,
+			resp:    repository.Page[T]{},
+			status:  http.StatusOK,
+			handle: func(r *http.Request, s *service.Service) (any, error) {
+				opts, err := listOptions(r)
+				if err != nil {
+					return nil, err
+				}
+				page, err := c.store(s).List(r.Context(), opts)
+				if page.Items == nil {
+					page.Items = []T{}
+				}
+				return page, err
+			},
+		},
+		{
+			method:  http.MethodGet,
+			path:    item,
+			summary: "get one of " + c.tag,
+			tag:     c.tag,
+			resp:    zero,
+			status:  http.StatusOK,
+			handle: func(r *http.Request, s *service.Service) (any, error) {
+				id, err := pathID(r, "id")
+				if err != nil {
+					return nil, err
+				}
+				return c.store(s).FindOne(r.Context(), id)
+			},
+		},
+		{
+			method:  http.MethodPost,
+			path:    c.path,
+			summary: "create one of " + c.tag,
+			tag:     c.tag,
+			body:    zero,
+			resp:    zero,
+			status:  http.StatusCreated,
+			handle: func(r *http.Request, s *service.Service) (any, error) {
+				var body T
+				if err := decodeBody(r, &body); err != nil {
+					return nil, err
+				}
+				id, err := c.store(s).Create(r.Context(), body)
+				if err != nil {
+					return nil, err
+				}
+				return c.store(s).FindOne(r.Context(), id)
+			},
+		},
+		{
+			method:  http.MethodPut,
+			path:    item,
+			summary: "update one of " + c.tag,
+			tag:     c.tag,
+			body:    zero,
+			resp:    zero,
+			status:  http.StatusOK,
+			handle: func(r *http.Request, s *service.Service) (any, error) {
+				id, err := pathID(r, "id")
+				if err != nil {
+					return nil, err
+				}
+				var body T
+				if err = decodeBody(r, &body); err != nil {
+					return nil, err
+				}
+				if err = c.store(s).Update(r.Context(), id, body); err != nil {
+					return nil, err
+				}
+				return c.store(s).FindOne(r.Context(), id)
+			},
+		},
+		{
+			method:  http.MethodDelete,
+			path:    item,
+			summary: "delete one of " + c.tag,
+			tag:     c.tag,
+			status:  http.StatusNoContent,
+			handle: func(r *http.Request, s *service.Service) (any, error) {
+				id, err := pathID(r, "id")
+				if err != nil {
+					return nil, err
+				}
+				return nil, c.store(s).Delete(r.Context(), id)
+			},
+		},
+	}
+	if c.restore == nil {
+		return routes
+	}
+
+	return append(routes, route{
+		method:  http.MethodPost,
+		path:    item + "/restore",
+		summary: "restore a deleted one of " + c.tag,
+		tag:     c.tag,
+		resp:    zero,
+		status:  http.StatusOK,
+		handle: func(r *http.Request, s *service.Service) (any, error) {
+			id, err := pathID(r, "id")
+			if err != nil {
+				return nil, err
+			}
+			if err = c.restore(s).Restore(r.Context(), id); err != nil {
+				return nil, err
+			}
+			return c.store(s).FindOne(r.Context(), id)
+		},
+	})
+}
+
+func (srv *Server) entityRoutes() []route {
+	var routes []route
+	routes = append(routes, crud[domain.Employee]{
+		tag: "employees", path: "/api/employees",
+		store:   func(s *service.Service) store[domain.Employee] { return s.Employees },
+		restore: func(s *service.Service) restorer { return s.Employees },
+	}.routes()...)
+	routes = append(routes, crud[domain.Group]{
+		tag: "groups", path: "/api/groups",
+		store:   func(s *service.Service) store[domain.Group] { return s.Groups },
+		restore: func(s *service.Service) restorer { return s.Groups },
+	}.routes()...)
+	routes = append(routes, crud[domain.LessonType]{
+		tag: "lesson-types", path: "/api/lesson-types",
+		store: func(s *service.Service) store[domain.LessonType] { return s.LessonTypes },
+	}.routes()...)
+	routes = append(routes, crud[domain.Lesson]{
+		tag: "lessons", path: "/api/lessons",
+		store: func(s *service.Service) store[domain.Lesson] { return s.Schedule },
+	}.routes()...)
+	routes = append(routes, crud[domain.Mark]{
+		tag: "marks", path: "/api/marks",
+		store: func(s *service.Service) store[domain.Mark] { return s.Marks },
+	}.routes()...)
+	routes = append(routes, crud[domain.Position]{
+		tag: "positions", path: "/api/positions",
+		store: func(s *service.Service) store[domain.Position] { return s.Positions },
+	}.routes()...)
+	routes = append(routes, crud[domain.Student]{
+		tag: "students", path: "/api/students",
+		store:   func(s *service.Service) store[domain.Student] { return s.Students },
+		restore: func(s *service.Service) restorer { return s.Students },
+	}.routes()...)
+	routes = append(routes, crud[domain.Subject]{
+		tag: "subjects", path: "/api/subjects",
+		store: func(s *service.Service) store[domain.Subject] { return s.Subjects },
+	}.routes()...)
+	routes = append(routes, srv.employeesSubjectsRoutes()...)
+	routes = append(routes, srv.auditRoute())
+	routes = append(routes, srv.conflictsRoute())
+
+	return routes
+}
+
+// the schedule report, lessons are checked on create and update as well
+func (srv *Server) conflictsRoute() route {
+	return route{
+		method:  http.MethodGet,
+		path:    "/api/lessons/conflicts",
+		summary: "list lessons sharing a room, a group or a teacher at the same time",
+		tag:     "lessons",
+		resp:    []schedule.Conflict{},
+		status:  http.StatusOK,
+		handle: func(r *http.Request, s *service.Service) (any, error) {
+			conflicts, err := s.Schedule.Conflicts(r.Context())
+			if conflicts == nil {
+				conflicts = []schedule.Conflict{}
+			}
+			return conflicts, err
+		},
+	}
+}
+
+// the change log is written by the database, so it is only listed
+func (srv *Server) auditRoute() route {
+	return route{
+		method:  http.MethodGet,
+		path:    "/api/audit",
+		summary: "list recorded changes, newest first",
+		tag:     "audit",
+		query:   listParams,
+		resp:    repository.Page[domain.AuditEntry]{},
+		status:  http.StatusOK,
+		handle: func(r *http.Request, s *service.Service) (any, error) {
+			opts, err := listOptions(r)
+			if err != nil {
+				return nil, err
+			}
+			page, err := s.Audit.List(r.Context(), opts)
+			if page.Items == nil {
+				page.Items = []domain.AuditEntry{}
+			}
+			return page, err
+		},
+	}
+}
+
+// employees_subjects has a composite key, so it doesn't fit crud
+func (srv *Server) employeesSubjectsRoutes() []route {
+	const (
+		tag  = "employees-subjects"
+		path = "/api/employees-subjects"
+		item = path + "/{employee_id}/{subject_id}"
+	)
+	return []route{
+		{
+			method:  http.MethodGet,
+			path:    path,
+			summary: "list subjects known by teachers",
+			tag:     tag,
+			query:   listParams,
+			resp:    repository.Page[domain.EmployeeSubject]{},
+			status:  http.StatusOK,
+			handle: func(r *http.Request, s *service.Service) (any, error) {
+				opts, err := listOptions(r)
+				if err != nil {
+					return nil, err
+				}
+				page, err := s.EmployeesSubjects.List(r.Context(), opts)
+				if page.Items == nil {
+					page.Items = []domain.EmployeeSubject{}
+				}
+				return page, err
+			},
+		},
+		{
+			method:  http.MethodPost,
+			path:    path,
+			summary: "add a subject known by a teacher",
+			tag:     tag,
+			body:    domain.EmployeeSubject{},
+			resp:    domain.EmployeeSubject{},
+			status:  http.StatusCreated,
+			handle: func(r *http.Request, s *service.Service) (any, error) {
+				var body domain.EmployeeSubject
+				if err := decodeBody(r, &body); err != nil {
+					return nil, err
+				}
+				return body, s.EmployeesSubjects.Create(r.Context(), body)
+			},
+		},
+		{
+			method:  http.MethodPut,
+			path:    item,
+			summary: "replace a subject known by a teacher",
+			tag:     tag,
+			body:    domain.EmployeeSubject{},
+			resp:    domain.EmployeeSubject{},
+			status:  http.StatusOK,
+			handle: func(r *http.Request, s *service.Service) (any, error) {
+				eid, sid, err := employeeSubjectKey(r)
+				if err != nil {
+					return nil, err
+				}
+				var body domain.EmployeeSubject
+				if err = decodeBody(r, &body); err != nil {
+					return nil, err
+				}
+				return body, s.EmployeesSubjects.Update(r.Context(), eid, sid, body)
+			},
+		},
+		{
+			method:  http.MethodDelete,
+			path:    item,
+			summary: "remove a subject known by a teacher",
+			tag:     tag,
+			status:  http.StatusNoContent,
+			handle: func(r *http.Request, s *service.Service) (any, error) {
+				eid, sid, err := employeeSubjectKey(r)
+				if err != nil {
+					return nil, err
+				}
+				return nil, s.EmployeesSubjects.Delete(r.Context(), eid, sid)
+			},
+		},
+	}
+}
+
+func employeeSubjectKey(r *http.Request) (uint64, uint64, error) {
+	eid, err := pathID(r, "employee_id")
+	if err != nil {
+		return 0, 0, err
+	}
+	sid, err := pathID(r, "subject_id")
+	if err != nil {
+		return 0, 0, err
+	}
+	return eid, sid, nil
+}
+
+// query parameters accepted by every list endpoint
+var listParams = []queryParam{
+	{name: "limit", description: "maximum number of items, 0 for all", kind: "integer"},
+	{name: "offset", description: "number of items to skip", kind: "integer"},
+	{name: "after", description: "keyset cursor, the next_cursor of the previous page", kind: "integer"},
+	{name: "sort", description: "comma separated fields, a leading - sorts in descending order", kind: "string"},
+	{name: "filter", description: "field:op:value where op is eq, ne, lt, lte, gt, gte or contains", kind: "string", repeated: true},
+}
+
+// accepted by lists of entities with soft delete
+var deletedParam = queryParam{name: "deleted", description: "include to list deleted items too, only to list just them", kind: "string"}
+
+func listOptions(r *http.Request) (repository.ListOptions, error) {
+	q := r.URL.Query()
+
+	var (
+		opts repository.ListOptions
+		err  error
+	)
+	for name, dst := range map[string]*uint64{"limit": &opts.Limit, "offset": &opts.Offset, "after": &opts.AfterID} {
+		if v := q.Get(name); v != "" {
+			if *dst, err = strconv.ParseUint(v, 10, 64); err != nil {
+				return opts, badRequest("%s must be a non-negative integer", name)
+			}
+		}
+	}
+
+	if v := q.Get("sort"); v != "" {
+		for _, field := range strings.Split(v, ",") {
+			if field == "" {
+				continue
+			}
+			opts.Sort = append(opts.Sort, repository.Sort{
+				Field: strings.TrimPrefix(field, "-"),
+				Desc:  strings.HasPrefix(field, "-"),
+			})
+		}
+	}
+
+	opts.Deleted = repository.Deleted(q.Get("deleted"))
+
+	for _, f := range q["filter"] {
+		parts := strings.SplitN(f, ":", 3)
+		if len(parts) != 3 {
+			return opts, badRequest("filter %q must look like field:op:value", f)
+		}
+		opts.Filters = append(opts.Filters, repository.Filter{
+			Field: parts[0],
+			Op:    repository.FilterOp(parts[1]),
+			Value: parts[2],
+		})
+	}
+
+	return opts, nil
+}
+
+func pathID(r *http.Request, name string) (uint64, error) {
+	id, err := strconv.ParseUint(r.PathValue(name), 10, 64)
+	if err != nil {
+		return 0, badRequest("%s must be a positive integer", name)
+	}
+	return id, nil
+}
+
+func decodeBody(r *http.Request, v any) error {
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return badRequest("invalid request body: %v", err)
+	}
+	return nil
+}
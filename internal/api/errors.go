@@ -0,0 +1,84 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"university-db-admin/internal/access"
+	"university-db-admin/internal/repository"
+	"university-db-admin/internal/schedule"
+	"university-db-admin/internal/service"
+	"university-db-admin/internal/special"
+	"university-db-admin/pkg/validation"
+)
+
+// body of every error response
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// malformed request that never reached the service layer
+type requestError struct {
+	msg string
+}
+
+func (e *requestError) Error() string {
+	return e.msg
+}
+
+func badRequest(format string, args ...any) error {
+	return &requestError{msg: fmt.Sprintf(format, args...)}
+}
+
+// returned to requests without the Authorization header
+var errNoCredentials = errors.New("authentication required, log in with Basic auth")
+
+// maps service and repository errors to status codes
+func statusOf(err error) int {
+	var (
+		reqErr *requestError
+		valErr *validation.Error
+	)
+
+	switch {
+	case errors.As(err, &reqErr):
+		return http.StatusBadRequest
+	case errors.Is(err, errNoCredentials),
+		errors.Is(err, service.ErrInvalidCredentials),
+		errors.Is(err, service.ErrNoUsers):
+		return http.StatusUnauthorized
+	case errors.Is(err, access.ErrForbidden),
+		errors.Is(err, access.ErrForeignMark):
+		return http.StatusForbidden
+	case errors.Is(err, repository.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrDuplicate),
+		errors.Is(err, repository.ErrReferenced),
+		errors.Is(err, schedule.ErrConflict):
+		return http.StatusConflict
+	case errors.Is(err, repository.ErrInvalidReference),
+		errors.Is(err, repository.ErrCheckViolation),
+		errors.Is(err, repository.ErrInvalidOption),
+		errors.Is(err, service.ErrNotTeacher),
+		errors.Is(err, service.ErrNotCurator),
+		errors.Is(err, service.ErrNotAdministrator),
+		errors.Is(err, service.ErrUnknownPair),
+		errors.Is(err, special.ErrInvalidArgument),
+		errors.As(err, &valErr):
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusInternalServerError
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := statusOf(err)
+
+	msg := err.Error()
+	if status == http.StatusInternalServerError {
+		log.Println("api error:", err)
+		msg = http.StatusText(status)
+	}
+
+	writeJSON(w, status, errorResponse{Error: msg})
+}
@@ -0,0 +1,181 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var pathParamPattern = regexp.MustCompile(`\{(\w+)\}`)
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	rawType  = reflect.TypeOf(json.RawMessage{})
+)
+
+// builds the openapi 3 document from the route table, request and
+// response schemas are derived from the go types by reflection
+func (srv *Server) openAPI() map[string]any {
+	schemas := map[string]any{
+		"Error": schemaOf(reflect.TypeOf(errorResponse{}), nil),
+	}
+	paths := map[string]map[string]any{}
+
+	for _, rt := range srv.routes {
+		op := map[string]any{
+			"summary":     rt.summary,
+			"tags":        []string{rt.tag},
+			"operationId": operationID(rt),
+		}
+
+		var params []map[string]any
+		for _, match := range pathParamPattern.FindAllStringSubmatch(rt.path, -1) {
+			params = append(params, map[string]any{
+				"name":     match[1],
+				"in":       "path",
+				"required": true,
+				"schema":   map[string]any{"type": "integer", "minimum": 1},
+			})
+		}
+		for _, q := range rt.query {
+			schema := map[string]any{"type": q.kind}
+			if q.repeated {
+				schema = map[string]any{"type": "array", "items": schema}
+			}
+			params = append(params, map[string]any{
+				"name":        q.name,
+				"in":          "query",
+				"description": q.description,
+				"schema":      schema,
+			})
+		}
+		if len(params) > 0 {
+			op["parameters"] = params
+		}
+
+		if rt.body != nil {
+			op["requestBody"] = map[string]any{
+				"required": true,
+				"content":  jsonContent(schemaOf(reflect.TypeOf(rt.body), schemas)),
+			}
+		}
+
+		success := map[string]any{"description": http.StatusText(rt.status)}
+		if rt.resp != nil {
+			success["content"] = jsonContent(schemaOf(reflect.TypeOf(rt.resp), schemas))
+		}
+		op["responses"] = map[string]any{
+			strconv.Itoa(rt.status): success,
+			"default": map[string]any{
+				"description": "error",
+				"content":     jsonContent(map[string]any{"$ref": "#/components/schemas/Error"}),
+			},
+		}
+
+		if paths[rt.path] == nil {
+			paths[rt.path] = map[string]any{}
+		}
+		paths[rt.path][strings.ToLower(rt.method)] = op
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   "University database API",
+			"version": "1.0.0",
+		},
+		"paths":      paths,
+		"components": map[string]any{"schemas": schemas},
+	}
+}
+
+func jsonContent(schema any) map[string]any {
+	return map[string]any{
+		"application/json": map[string]any{"schema": schema},
+	}
+}
+
+// e.g. "get_api_students_id"
+func operationID(rt route) string {
+	path := strings.NewReplacer("/", "_", "{", "", "}", "", "-", "_", ".", "_").Replace(rt.path)
+	return strings.ToLower(rt.method) + path
+}
+
+// returns the json schema of t, named structs are stored in
+// schemas and referenced; generic types are inlined
+func schemaOf(t reflect.Type, schemas map[string]any) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return map[string]any{"type": "string", "format": "date-time"}
+	}
+	if t == rawType {
+		return map[string]any{"nullable": true} // any json value
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return map[string]any{"type": "integer"}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer", "minimum": 0}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": schemaOf(t.Elem(), schemas)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": true}
+	case reflect.Struct:
+		name := t.Name()
+		if schemas != nil && name != "" && !strings.Contains(name, "[") {
+			if _, ok := schemas[name]; !ok {
+				schemas[name] = map[string]any{} // breaks cycles
+				schemas[name] = structSchema(t, schemas)
+			}
+			return map[string]any{"$ref": "#/components/schemas/" + name}
+		}
+		return structSchema(t, schemas)
+	}
+
+	return map[string]any{}
+}
+
+func structSchema(t reflect.Type, schemas map[string]any) map[string]any {
+	properties := map[string]any{}
+	var required []string
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name := field.Name
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			name = strings.Split(tag, ",")[0]
+		}
+		if name == "-" {
+			continue
+		}
+
+		properties[name] = schemaOf(field.Type, schemas)
+		if strings.Contains(field.Tag.Get("validate"), "required") {
+			required = append(required, name)
+		}
+	}
+
+	schema := map[string]any{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
@@ -0,0 +1,95 @@
+package api
+
+import (
+	"net/http"
+	"university-db-admin/internal/service"
+	"university-db-admin/internal/special"
+)
+
+// description of a special query returned by the index endpoint
+type queryInfo struct {
+	Name    string       `json:"name"`
+	Title   string       `json:"title"`
+	Help    string       `json:"help"`
+	Path    string       `json:"path"`
+	Params  []paramInfo  `json:"params"`
+	Columns []columnInfo `json:"columns"`
+}
+
+type columnInfo struct {
+	Name   string `json:"name"`
+	Header string `json:"header"`
+}
+
+type paramInfo struct {
+	Name string `json:"name"`
+	Help string `json:"help"`
+	Kind string `json:"kind"`
+}
+
+// every special query gets its own path so the openapi
+// document can describe its parameters
+func (srv *Server) queryRoutes() []route {
+	index := make([]queryInfo, len(special.Queries))
+	routes := []route{{
+		method:  http.MethodGet,
+		path:    "/api/queries",
+		summary: "list special queries",
+		tag:     "queries",
+		resp:    index,
+		status:  http.StatusOK,
+		handle: func(r *http.Request, s *service.Service) (any, error) {
+			return index, nil
+		},
+	}}
+
+	for i, q := range special.Queries {
+		info := queryInfo{Name: q.Name, Title: q.Title, Help: q.Help, Path: "/api/queries/" + q.Name, Params: []paramInfo{}}
+		for _, c := range q.Columns {
+			info.Columns = append(info.Columns, columnInfo(c))
+		}
+
+		params := make([]queryParam, len(q.Params))
+		for j, p := range q.Params {
+			kind := "string"
+			if p.Kind == special.Uint {
+				kind = "integer"
+			}
+			params[j] = queryParam{name: p.Name, description: p.Help, kind: kind}
+			info.Params = append(info.Params, paramInfo{Name: p.Name, Help: p.Help, Kind: string(p.Kind)})
+		}
+		index[i] = info
+
+		routes = append(routes, route{
+			method:  http.MethodGet,
+			path:    info.Path,
+			summary: q.Help,
+			tag:     "queries",
+			query:   params,
+			resp:    []map[string]any{},
+			status:  http.StatusOK,
+			handle: func(r *http.Request, s *service.Service) (any, error) {
+				args := special.Args{}
+				for _, p := range q.Params {
+					args[p.Name] = r.URL.Query().Get(p.Name)
+				}
+
+				res, err := q.Run(r.Context(), s, args)
+				if err != nil {
+					return nil, err
+				}
+
+				rows := make([]map[string]any, len(res.Rows))
+				for i, row := range res.Rows {
+					rows[i] = make(map[string]any, len(res.Columns))
+					for j, column := range res.Columns {
+						rows[i][column] = row[j]
+					}
+				}
+				return rows, nil
+			},
+		})
+	}
+
+	return routes
+}
@@ -0,0 +1,171 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/service"
+	"university-db-admin/pkg/dbclient"
+)
+
+// route is both a registered handler and its entry in the openapi document
+type route struct {
+	method  string
+	path    string // net/http pattern path, path parameters are written as {name}
+	summary string
+	tag     string
+	query   []queryParam
+	body    any // zero value of the request body type, nil if there is none
+	resp    any // zero value of the response type, nil if there is none
+	status  int // status of a successful response
+	// s are the services of the user sending r
+	handle func(r *http.Request, s *service.Service) (any, error)
+}
+
+type queryParam struct {
+	name        string
+	description string
+	kind        string // openapi type of the parameter
+	repeated    bool
+}
+
+// Login checks the credentials of a client and returns the services
+// restricted to the role of the user
+type Login func(ctx context.Context, login, password string) (*service.Service, domain.User, error)
+
+type Server struct {
+	login  Login
+	mux    *http.ServeMux
+	routes []route
+}
+
+// NewServer serves the API to clients logging in with Basic auth
+func NewServer(login Login) *Server {
+	srv := &Server{
+		login: login,
+		mux:   http.NewServeMux(),
+	}
+
+	srv.routes = append(srv.routes, srv.entityRoutes()...)
+	srv.routes = append(srv.routes, srv.queryRoutes()...)
+	srv.routes = append(srv.routes, route{
+		method:  http.MethodGet,
+		path:    "/api/openapi.json",
+		summary: "OpenAPI document of this API",
+		tag:     "meta",
+		resp:    map[string]any{},
+		status:  http.StatusOK,
+		handle: func(r *http.Request, s *service.Service) (any, error) {
+			return srv.openAPI(), nil
+		},
+	})
+
+	for _, rt := range srv.routes {
+		srv.mux.HandleFunc(rt.method+" "+rt.path, handler(rt))
+	}
+
+	return srv
+}
+
+// key of the services of the user sending a request in its context
+type serviceKey struct{}
+
+// every request logs in, its changes are recorded in the
+// audit log under the login of the user
+func (srv *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	start := time.Now()
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+	s, u, err := srv.authenticate(r)
+	if err != nil {
+		if statusOf(err) == http.StatusUnauthorized {
+			rec.Header().Set("WWW-Authenticate", `Basic realm="university-db-admin", charset="UTF-8"`)
+		}
+		writeError(rec, err)
+	} else {
+		ctx := dbclient.WithOperator(r.Context(), u.Login)
+		r = r.WithContext(context.WithValue(ctx, serviceKey{}, s))
+		srv.mux.ServeHTTP(rec, r)
+	}
+	log.Printf("%s %s %d %s\n", r.Method, r.URL.Path, rec.status, time.Since(start))
+}
+
+// logs in the user sending r
+func (srv *Server) authenticate(r *http.Request) (*service.Service, domain.User, error) {
+	login, password, ok := r.BasicAuth()
+	if !ok {
+		return nil, domain.User{}, errNoCredentials
+	}
+	return srv.login(r.Context(), login, password)
+}
+
+// serves the API on addr until ctx is canceled
+func ListenAndServe(ctx context.Context, addr string, h http.Handler) error {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           h,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		log.Println("api listening on", addr)
+		errCh <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		log.Println("shutting down api server")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			return err
+		}
+		if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	}
+}
+
+func handler(rt route) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		res, err := rt.handle(r, r.Context().Value(serviceKey{}).(*service.Service))
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		if res == nil {
+			w.WriteHeader(rt.status)
+			return
+		}
+		writeJSON(w, rt.status, res)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		log.Println("cant write response:", err)
+	}
+}
+
+// remembers the status code for request logging
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
@@ -1,17 +1,29 @@
 package app
 
 import (
+	"context"
+	"errors"
 	"log"
+	"university-db-admin/internal/access"
 	"university-db-admin/internal/config"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/migrations"
 	"university-db-admin/internal/repository"
 	"university-db-admin/internal/repository/postgres"
+	"university-db-admin/internal/schedule"
+	"university-db-admin/internal/service"
 	"university-db-admin/internal/ui"
 	"university-db-admin/pkg/dbclient"
+
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type App struct {
 	cfg        *config.Config
+	db         *pgxpool.Pool
 	repository *repository.Repository
+	service    *service.Service
+	bells      schedule.BellSchedule
 }
 
 func NewApp() App {
@@ -19,45 +31,88 @@ func NewApp() App {
 
 	log.Println("initializing config")
 	cfg := config.LoadConfig()
+	loadSpecialQueries(cfg.SpecialQueriesFile)
+	bells := loadBells(cfg.BellSchedule)
+
+	if cfg.Backend == config.BackendDemo {
+		return newDemoApp(cfg, bells)
+	}
+	if cfg.Backend != config.BackendPostgres {
+		log.Fatalf("unknown backend %q, expected %s or %s", cfg.Backend, config.BackendPostgres, config.BackendDemo)
+	}
 
 	log.Println("initializing database")
 	pg := dbclient.NewClientPG(cfg.DB)
 
+	if cfg.DB.AutoMigrate {
+		log.Println("applying migrations")
+		migrator, err := migrations.NewMigrator(pg)
+		if err != nil {
+			log.Fatal("cant initialize migrations: ", err)
+		}
+		if err = migrator.Up(context.Background()); err != nil {
+			log.Fatal("cant apply migrations: ", err)
+		}
+	}
+
 	log.Println("initializing repositories")
-	employees := postgres.NewEmployeesRepository(pg)
-	groups := postgres.NewGroupsRepository(pg)
-	lessons := postgres.NewLessonsRepository(pg)
-	positions := postgres.NewPositionsRepository(pg)
-	subjects := postgres.NewSubjectsRepository(pg)
-	lessonTypes := postgres.NewLessonTypesRepository(pg)
-	marks := postgres.NewMarksRepository(pg)
-	students := postgres.NewStudentsRepository(pg)
-	employeesSubjects := postgres.NewEmployeesSubjectsRepository(pg)
+	repo := postgres.NewRepository(pg)
+
+	log.Println("initializing services")
+	svc := service.NewService(repo, bells)
 
 	log.Println("application initialized")
 
 	return App{
-		cfg: cfg,
-		repository: &repository.Repository{
-			Employees:         employees,
-			Groups:            groups,
-			Lessons:           lessons,
-			Positions:         positions,
-			Subjects:          subjects,
-			LessonTypes:       lessonTypes,
-			Marks:             marks,
-			Students:          students,
-			EmployeesSubjects: employeesSubjects,
-		},
+		cfg:        cfg,
+		db:         pg,
+		repository: repo,
+		service:    svc,
+		bells:      bells,
+	}
+}
+
+// closes the database connection, the demo backend has none
+func (a *App) Close() {
+	if a.db != nil {
+		a.db.Close()
 	}
 }
 
 func (a *App) startUI() {
-	ui.Run(a.repository)
+	ui.Run(a.login, a.cfg.UI)
+}
+
+// checks the credentials of a user of the UI, the services returned
+// to them are restricted to what their role allows
+func (a *App) login(ctx context.Context, login, password string) (*service.Service, domain.User, error) {
+	u, err := a.service.Users.Authenticate(ctx, login, password)
+	if err != nil {
+		return nil, domain.User{}, err
+	}
+
+	log.Printf("user %s logged in as %s", u.Login, u.Role)
+	return service.NewService(access.Wrap(a.repository, u), a.bells), u, nil
+}
+
+// the services of the command line, restricted to the account given by
+// APP_LOGIN and APP_PASSWORD. While the database has no accounts the
+// first one can be created without them
+func (a *App) cliService(ctx context.Context, login, password string) (*service.Service, error) {
+	s, _, err := a.login(ctx, login, password)
+	switch {
+	case errors.Is(err, service.ErrNoUsers):
+		log.Println("no accounts yet, only accounts can be created")
+		return service.NewService(access.Bootstrap(a.repository), a.bells), nil
+	case errors.Is(err, service.ErrInvalidCredentials) && login == "":
+		return nil, errors.New("set APP_LOGIN and APP_PASSWORD to the account the commands run as")
+	}
+	return s, err
 }
 
 func Run() {
 	app := NewApp()
+	defer app.Close()
 
 	log.Println("application started")
 	app.startUI()
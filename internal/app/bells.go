@@ -0,0 +1,18 @@
+package app
+
+import (
+	"log"
+	"university-db-admin/internal/schedule"
+)
+
+// parses the configured bell schedule, the default one when none is set
+func loadBells(s string) schedule.BellSchedule {
+	if s == "" {
+		s = schedule.DefaultBells
+	}
+	bells, err := schedule.ParseBells(s)
+	if err != nil {
+		log.Fatal("cant parse BELL_SCHEDULE: ", err)
+	}
+	return bells
+}
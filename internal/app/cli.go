@@ -0,0 +1,65 @@
+package app
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"university-db-admin/internal/cli"
+	"university-db-admin/internal/config"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/service"
+	"university-db-admin/pkg/dbclient"
+)
+
+// runs a command line invocation and returns its exit code,
+// the database is connected only when a command needs it
+func RunCLI(args []string) int {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// query list shows the queries of the file without a database connection
+	loadSpecialQueries(config.SpecialQueriesFile())
+
+	// changes are attributed to the account the commands run as
+	login, password := config.Credentials()
+	if login != "" {
+		ctx = dbclient.WithOperator(ctx, login)
+	}
+
+	var app *App
+	defer func() {
+		if app != nil {
+			app.Close()
+		}
+	}()
+	connect := func() *App {
+		if app == nil {
+			a := NewApp()
+			app = &a
+		}
+		return app
+	}
+
+	var svc *service.Service
+	env := cli.Env{
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+		Service: func() *service.Service {
+			if svc == nil {
+				s, err := connect().cliService(ctx, login, password)
+				if err != nil {
+					log.Fatal("cant log in: ", err)
+				}
+				svc = s
+			}
+			return svc
+		},
+		Login: func(ctx context.Context, login, password string) (*service.Service, domain.User, error) {
+			return connect().login(ctx, login, password)
+		},
+	}
+
+	return cli.Run(ctx, env, args)
+}
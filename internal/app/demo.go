@@ -0,0 +1,155 @@
+package app
+
+import (
+	"context"
+	"log"
+	"time"
+	"university-db-admin/internal/config"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+	"university-db-admin/internal/repository/memory"
+	"university-db-admin/internal/schedule"
+	"university-db-admin/internal/service"
+	"university-db-admin/pkg/dbclient"
+)
+
+// builds the application on an in-memory database with sample data
+func newDemoApp(cfg *config.Config, bells schedule.BellSchedule) App {
+	log.Println("initializing in-memory repositories")
+	repo := memory.NewRepository()
+
+	ctx := dbclient.WithOperator(context.Background(), "demo")
+	if err := repo.WithTx(ctx, loadDemoData); err != nil {
+		log.Fatal("cant load demo data: ", err)
+	}
+
+	log.Println("initializing services")
+	svc := service.NewService(repo, bells)
+
+	if err := createDemoUsers(ctx, svc); err != nil {
+		log.Fatal("cant create demo users: ", err)
+	}
+	log.Printf("demo users admin, dean, teacher and guest have the password %s", demoPassword)
+
+	log.Println("application initialized in demo mode")
+
+	return App{
+		cfg:        cfg,
+		repository: repo,
+		service:    svc,
+		bells:      bells,
+	}
+}
+
+// fills an empty database with a small faculty, ids follow the insertion order
+func loadDemoData(ctx context.Context, r *repository.Repository) error {
+	positions := []domain.Position{
+		{Name: "Преподаватель", CanTeach: true, CanCurate: true},
+		{Name: "Ассистент", CanCurate: true},
+		{Name: "Заведующий кафедрой", CanCurate: true, CanAdminister: true},
+	}
+	for _, pos := range positions {
+		if _, err := r.Positions.Create(ctx, pos); err != nil {
+			return err
+		}
+	}
+
+	employees := []domain.Employee{
+		{Name: "Иванов Пётр Сергеевич", Passport: "MP1000001", PositionID: 1},
+		{Name: "Смирнова Анна Викторовна", Passport: "MP1000002", PositionID: 1},
+		{Name: "Кузнецов Олег Игоревич", Passport: "MP1000003", PositionID: 2},
+		{Name: "Попова Елена Андреевна", Passport: "MP1000004", PositionID: 3},
+	}
+	if _, err := r.Employees.CopyFrom(ctx, employees); err != nil {
+		return err
+	}
+
+	for _, number := range []uint64{153501, 153502, 153503} {
+		if _, err := r.Groups.Create(ctx, domain.Group{Number: number}); err != nil {
+			return err
+		}
+	}
+
+	students := []domain.Student{
+		{Name: "Соколов Артём Дмитриевич", Passport: "MP2000001", EmployeeID: 1, GroupID: 1},
+		{Name: "Морозова Дарья Павловна", Passport: "MP2000002", EmployeeID: 1, GroupID: 1},
+		{Name: "Волков Никита Алексеевич", Passport: "MP2000003", EmployeeID: 2, GroupID: 2},
+		{Name: "Лебедева Мария Олеговна", Passport: "MP2000004", EmployeeID: 2, GroupID: 2},
+		{Name: "Новиков Илья Романович", Passport: "MP2000005", EmployeeID: 3, GroupID: 3},
+	}
+	if _, err := r.Students.CopyFrom(ctx, students); err != nil {
+		return err
+	}
+
+	subjects := []domain.Subject{
+		{Name: "Математический анализ", Description: "Пределы, производные и интегралы"},
+		{Name: "Базы данных", Description: "Реляционная модель и язык SQL"},
+		{Name: "Программирование", Description: "Основы алгоритмизации"},
+	}
+	for _, sbj := range subjects {
+		if _, err := r.Subjects.Create(ctx, sbj); err != nil {
+			return err
+		}
+	}
+
+	for _, name := range []string{"ЛК", "ПЗ", "ЛР"} {
+		if _, err := r.LessonTypes.Create(ctx, domain.LessonType{Name: name}); err != nil {
+			return err
+		}
+	}
+
+	for _, es := range []domain.EmployeeSubject{{EmployeeID: 1, SubjectID: 1}, {EmployeeID: 2, SubjectID: 2}, {EmployeeID: 2, SubjectID: 3}} {
+		if err := r.EmployeesSubjects.Create(ctx, es); err != nil {
+			return err
+		}
+	}
+
+	ivanov, smirnova := uint64(1), uint64(2)
+	lessons := []domain.Lesson{
+		{GroupID: 1, SubjectID: 1, LessonTypeID: 1, Week: 1, Weekday: 1, Pair: 1, Room: 101, EmployeeID: &ivanov},
+		{GroupID: 1, SubjectID: 2, LessonTypeID: 3, Week: 1, Weekday: 3, Pair: 2, Room: 214, EmployeeID: &smirnova},
+		{GroupID: 2, SubjectID: 2, LessonTypeID: 1, Week: 2, Weekday: 2, Pair: 1, Room: 101, EmployeeID: &smirnova},
+		{GroupID: 3, SubjectID: 3, LessonTypeID: 2, Week: 2, Weekday: 5, Pair: 3, Room: 305, EmployeeID: &smirnova},
+	}
+	for _, lsn := range lessons {
+		if _, err := r.Lessons.Create(ctx, lsn); err != nil {
+			return err
+		}
+	}
+
+	day := func(d int) time.Time { return time.Date(2024, time.September, d, 0, 0, 0, 0, time.UTC) }
+	marks := []domain.Mark{
+		{EmployeeID: 1, StudentID: 1, SubjectID: 1, Mark: 8, Date: day(2)},
+		{EmployeeID: 1, StudentID: 2, SubjectID: 1, Mark: 6, Date: day(2)},
+		{EmployeeID: 2, StudentID: 3, SubjectID: 2, Mark: 9, Date: day(4)},
+		{EmployeeID: 2, StudentID: 4, SubjectID: 2, Mark: 7, Date: day(4)},
+		{EmployeeID: 2, StudentID: 5, SubjectID: 3, Mark: 10, Date: day(6)},
+	}
+	_, err := r.Marks.CopyFrom(ctx, marks)
+	return err
+}
+
+// password of every demo account
+const demoPassword = "demo1234"
+
+// creates an account for every role, the teacher is the first employee and
+// the dean's office the head of the department
+func createDemoUsers(ctx context.Context, svc *service.Service) error {
+	teacher, head := uint64(1), uint64(4)
+	users := []struct {
+		login      string
+		role       domain.Role
+		employeeID *uint64
+	}{
+		{"admin", domain.RoleAdmin, nil},
+		{"dean", domain.RoleDean, &head},
+		{"teacher", domain.RoleTeacher, &teacher},
+		{"guest", domain.RoleReadOnly, nil},
+	}
+	for _, u := range users {
+		if _, err := svc.Users.Create(ctx, u.login, demoPassword, u.role, u.employeeID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"university-db-admin/internal/config"
+	"university-db-admin/internal/migrations"
+	"university-db-admin/pkg/dbclient"
+)
+
+// runs a migration command ("up", "down" or "status") against the configured database
+func Migrate(command string, steps int) {
+	cfg := config.LoadConfig()
+	pg := dbclient.NewClientPG(cfg.DB)
+	defer pg.Close()
+
+	migrator, err := migrations.NewMigrator(pg)
+	if err != nil {
+		log.Fatal("cant initialize migrations: ", err)
+	}
+
+	ctx := context.Background()
+	switch command {
+	case "up":
+		err = migrator.Up(ctx)
+	case "down":
+		err = migrator.Down(ctx, steps)
+	case "status":
+		var statuses []migrations.Status
+		statuses, err = migrator.Status(ctx)
+		applied := 0
+		for _, s := range statuses {
+			state := "pending"
+			if s.Applied {
+				applied++
+				state = "applied at " + s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%s\t%s\n", s.Version, s.Name, state)
+		}
+		if err == nil && applied == 0 {
+			fmt.Println("no migrations applied")
+		}
+	default:
+		log.Fatalf("unknown migration command: %s\n", command)
+	}
+
+	if err != nil {
+		log.Fatal("migration failed: ", err)
+	}
+}
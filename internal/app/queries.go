@@ -0,0 +1,22 @@
+package app
+
+import (
+	"log"
+	"sync"
+	"university-db-admin/internal/special"
+)
+
+var loadQueriesOnce sync.Once
+
+// registers the special queries of the file, if one is configured
+func loadSpecialQueries(path string) {
+	loadQueriesOnce.Do(func() {
+		if path == "" {
+			return
+		}
+		log.Println("loading special queries from", path)
+		if err := special.LoadFile(path); err != nil {
+			log.Fatal("cant load special queries: ", err)
+		}
+	})
+}
@@ -0,0 +1,32 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"university-db-admin/internal/config"
+	"university-db-admin/internal/repository/postgres"
+	"university-db-admin/internal/seeder"
+	"university-db-admin/pkg/dbclient"
+)
+
+// fills the configured database with generated data of the given size,
+// records left from an earlier run with the same preset and seed are kept
+func Seed(preset string, seed uint64) {
+	p, ok := seeder.Presets[preset]
+	if !ok {
+		log.Fatalf("unknown preset %q, expected one of %s\n", preset, strings.Join(seeder.PresetNames(), ", "))
+	}
+
+	cfg := config.LoadConfig()
+	pg := dbclient.NewClientPG(cfg.DB)
+	defer pg.Close()
+
+	ctx := dbclient.WithOperator(context.Background(), "seeder")
+	stats, err := seeder.Seed(ctx, postgres.NewRepository(pg), p, seed)
+	if err != nil {
+		log.Fatal("seeding failed: ", err)
+	}
+	fmt.Print(stats)
+}
@@ -0,0 +1,360 @@
+// Package backup dumps every record reachable through repository.Repository
+// to a single archive and restores it.
+//
+// An archive is a zip file with one JSON array per table and a manifest
+// listing the tables with their row counts and SHA-256 checksums. Records
+// keep the ids they had when dumped, Restore maps them to the ids given by
+// the target database. Records in the trash are dumped too and go back to
+// the trash on restore. The change log is not part of a backup, neither
+// are the accounts, so password hashes never leave the database: accounts
+// of a new database are created again with university_db_cli user add.
+// A restore replacing the records of a database keeps its accounts.
+package backup
+
+import (
+	"archive/zip"
+	"bytes"
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"time"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+)
+
+// Format identifies backup archives, Version changes whenever
+// the layout of the tables in an archive does
+const (
+	Format  = "university-db-backup"
+	Version = 3 // positions carry capabilities since version 2, lessons a pair and teacher since 3
+)
+
+const manifestName = "manifest.json"
+
+// tables of an archive in the order they are restored in,
+// every table only references the ones before it
+var Tables = []string{
+	"positions",
+	"employees",
+	"groups",
+	"students",
+	"subjects",
+	"lesson_types",
+	"employees_subjects",
+	"lessons",
+	"marks",
+}
+
+type Manifest struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Tables    []Entry   `json:"tables"`
+}
+
+// Entry describes the file holding the rows of one table
+type Entry struct {
+	Table  string `json:"table"`
+	File   string `json:"file"`
+	Rows   int    `json:"rows"`
+	SHA256 string `json:"sha256"`
+}
+
+// Data is the content of a backup
+type Data struct {
+	Positions         []domain.Position
+	Employees         []domain.Employee
+	Groups            []domain.Group
+	Students          []domain.Student
+	Subjects          []domain.Subject
+	LessonTypes       []domain.LessonType
+	EmployeesSubjects []domain.EmployeeSubject
+	Lessons           []domain.Lesson
+	Marks             []domain.Mark
+}
+
+// pointers to the table slices of d by table name
+func (d *Data) tables() map[string]any {
+	return map[string]any{
+		"positions":          &d.Positions,
+		"employees":          &d.Employees,
+		"groups":             &d.Groups,
+		"students":           &d.Students,
+		"subjects":           &d.Subjects,
+		"lesson_types":       &d.LessonTypes,
+		"employees_subjects": &d.EmployeesSubjects,
+		"lessons":            &d.Lessons,
+		"marks":              &d.Marks,
+	}
+}
+
+// ErrInvalidArchive is returned for files that aren't backups
+// or whose content doesn't match the manifest
+var ErrInvalidArchive = errors.New("invalid backup archive")
+
+// Dump reads every table of r from one snapshot, so the backup is
+// consistent while others keep changing the database
+func Dump(ctx context.Context, r *repository.Repository) (*Data, error) {
+	d := &Data{}
+	all := repository.ListOptions{Deleted: repository.IncludeDeleted}
+
+	err := r.WithTx(repository.WithSnapshot(ctx), func(ctx context.Context, tx *repository.Repository) error {
+		var err error
+		if d.Positions, err = tx.Positions.FindAll(ctx); err != nil {
+			return err
+		}
+		employees, err := tx.Employees.List(ctx, all)
+		if err != nil {
+			return err
+		}
+		d.Employees = employees.Items
+		groups, err := tx.Groups.List(ctx, all)
+		if err != nil {
+			return err
+		}
+		d.Groups = groups.Items
+		students, err := tx.Students.List(ctx, all)
+		if err != nil {
+			return err
+		}
+		d.Students = students.Items
+		if d.Subjects, err = tx.Subjects.FindAll(ctx); err != nil {
+			return err
+		}
+		if d.LessonTypes, err = tx.LessonTypes.FindAll(ctx); err != nil {
+			return err
+		}
+		if d.EmployeesSubjects, err = tx.EmployeesSubjects.FindAll(ctx); err != nil {
+			return err
+		}
+		if d.Lessons, err = tx.Lessons.FindAll(ctx); err != nil {
+			return err
+		}
+		d.Marks, err = tx.Marks.FindAll(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Write stores d as an archive and returns its manifest
+func Write(w io.Writer, d *Data) (Manifest, error) {
+	m := Manifest{Format: Format, Version: Version, CreatedAt: time.Now().UTC()}
+	zw := zip.NewWriter(w)
+
+	tables := d.tables()
+	for _, table := range Tables {
+		content, err := json.MarshalIndent(tables[table], "", "  ")
+		if err != nil {
+			return Manifest{}, err
+		}
+
+		entry := Entry{Table: table, File: table + ".json", Rows: rows(tables[table]), SHA256: checksum(content)}
+		if err = writeFile(zw, entry.File, content, m.CreatedAt); err != nil {
+			return Manifest{}, err
+		}
+		m.Tables = append(m.Tables, entry)
+	}
+
+	content, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return Manifest{}, err
+	}
+	if err = writeFile(zw, manifestName, content, m.CreatedAt); err != nil {
+		return Manifest{}, err
+	}
+	return m, zw.Close()
+}
+
+// Read loads an archive checking it against its manifest
+func Read(r io.ReaderAt, size int64) (*Data, Manifest, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, Manifest{}, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+
+	content, err := readFile(zr, manifestName)
+	if err != nil {
+		return nil, Manifest{}, err
+	}
+	var m Manifest
+	if err = json.Unmarshal(content, &m); err != nil {
+		return nil, Manifest{}, fmt.Errorf("%w: manifest: %v", ErrInvalidArchive, err)
+	}
+	if m.Format != Format {
+		return nil, Manifest{}, fmt.Errorf("%w: unknown format %q", ErrInvalidArchive, m.Format)
+	}
+	if m.Version < 1 || m.Version > Version {
+		return nil, Manifest{}, fmt.Errorf("%w: version %d is not supported, expected %d", ErrInvalidArchive, m.Version, Version)
+	}
+
+	entries := map[string]Entry{}
+	for _, entry := range m.Tables {
+		entries[entry.Table] = entry
+	}
+
+	d := &Data{}
+	tables := d.tables()
+	for _, table := range Tables {
+		entry, ok := entries[table]
+		if !ok {
+			return nil, Manifest{}, fmt.Errorf("%w: table %s is missing", ErrInvalidArchive, table)
+		}
+
+		content, err := readFile(zr, entry.File)
+		if err != nil {
+			return nil, Manifest{}, err
+		}
+		if checksum(content) != entry.SHA256 {
+			return nil, Manifest{}, fmt.Errorf("%w: checksum of %s doesn't match", ErrInvalidArchive, entry.File)
+		}
+		if err = json.Unmarshal(content, tables[table]); err != nil {
+			return nil, Manifest{}, fmt.Errorf("%w: %s: %v", ErrInvalidArchive, entry.File, err)
+		}
+		if n := rows(tables[table]); n != entry.Rows {
+			return nil, Manifest{}, fmt.Errorf("%w: %s has %d rows, the manifest lists %d", ErrInvalidArchive, entry.File, n, entry.Rows)
+		}
+	}
+	if m.Version < 2 {
+		upgradeV1(d)
+	}
+	if m.Version < 3 {
+		upgradeV2(d)
+	}
+
+	return d, m, nil
+}
+
+// gives the positions of a version 1 archive the capabilities the
+// 0006_position_capabilities migration gives to existing positions
+func upgradeV1(d *Data) {
+	curators := map[uint64]bool{}
+	for _, stud := range d.Students {
+		curators[stud.EmployeeID] = true
+	}
+	curating := map[uint64]bool{}
+	for _, emp := range d.Employees {
+		if curators[emp.ID] {
+			curating[emp.PositionID] = true
+		}
+	}
+
+	for i, pos := range d.Positions {
+		if pos.Name == "Преподаватель" {
+			d.Positions[i].CanTeach = true
+			d.Positions[i].CanCurate = true
+		}
+		if curating[pos.ID] {
+			d.Positions[i].CanCurate = true
+		}
+	}
+}
+
+// puts the lessons of an archive older than version 3 into consecutive
+// pairs of their group's day in the order of their ids, like the
+// 0007_lesson_slots migration does; they have no teacher
+func upgradeV2(d *Data) {
+	type day struct {
+		group         uint64
+		week, weekday uint16
+	}
+
+	order := make([]int, len(d.Lessons))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortFunc(order, func(a, b int) int { return cmp.Compare(d.Lessons[a].ID, d.Lessons[b].ID) })
+
+	pairs := map[day]uint16{}
+	for _, i := range order {
+		lsn := &d.Lessons[i]
+		key := day{lsn.GroupID, lsn.Week, lsn.Weekday}
+		pairs[key]++
+		lsn.Pair = pairs[key]
+	}
+}
+
+// WriteFile dumps r to a new archive at path
+func WriteFile(ctx context.Context, r *repository.Repository, path string) (Manifest, error) {
+	d, err := Dump(ctx, r)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	// the archive is written next to its destination and moved
+	// in place once complete, so a failed backup leaves no file behind
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".backup-*")
+	if err != nil {
+		return Manifest{}, err
+	}
+	defer os.Remove(tmp.Name())
+
+	m, err := Write(tmp, d)
+	if err != nil {
+		tmp.Close()
+		return Manifest{}, err
+	}
+	if err = tmp.Close(); err != nil {
+		return Manifest{}, err
+	}
+	return m, os.Rename(tmp.Name(), path)
+}
+
+// ReadFile loads the archive at path
+func ReadFile(path string) (*Data, Manifest, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, Manifest{}, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, Manifest{}, err
+	}
+	return Read(f, info.Size())
+}
+
+func writeFile(zw *zip.Writer, name string, content []byte, modified time.Time) error {
+	w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: modified})
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(content)
+	return err
+}
+
+func readFile(zr *zip.Reader, name string) ([]byte, error) {
+	f, err := zr.Open(name)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s is missing", ErrInvalidArchive, name)
+	}
+	defer f.Close()
+
+	var b bytes.Buffer
+	if _, err = b.ReadFrom(f); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidArchive, name, err)
+	}
+	return b.Bytes(), nil
+}
+
+// length of the table slice p points to
+func rows(p any) int {
+	return reflect.ValueOf(p).Elem().Len()
+}
+
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
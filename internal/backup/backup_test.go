@@ -0,0 +1,408 @@
+package backup_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+	"university-db-admin/internal/backup"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+	"university-db-admin/internal/repository/memory"
+)
+
+func must(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// writes d to an archive and reads it back
+func roundTrip(t *testing.T, d *backup.Data) *backup.Data {
+	t.Helper()
+	var buf bytes.Buffer
+	_, err := backup.Write(&buf, d)
+	must(t, err)
+	read, _, err := backup.Read(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	must(t, err)
+	return read
+}
+
+// a mark whose grader no longer teaches the subject was given before the
+// grading rule, restoring it must not run the rule again
+func TestRestoreLegacyMark(t *testing.T) {
+	ctx := context.Background()
+	r := memory.NewRepository()
+
+	pos, err := r.Positions.Create(ctx, domain.Position{Name: "Преподаватель", CanTeach: true, CanCurate: true})
+	must(t, err)
+	teacher, err := r.Employees.Create(ctx, domain.Employee{Name: "Ivanov Ivan", Passport: "MP0000001", PositionID: pos})
+	must(t, err)
+	grp, err := r.Groups.Create(ctx, domain.Group{Number: 101})
+	must(t, err)
+	stud, err := r.Students.Create(ctx, domain.Student{Name: "Anna Smirnova", Passport: "MP1000001", EmployeeID: teacher, GroupID: grp})
+	must(t, err)
+	sbj, err := r.Subjects.Create(ctx, domain.Subject{Name: "Mathematics", Description: "math"})
+	must(t, err)
+	lt, err := r.LessonTypes.Create(ctx, domain.LessonType{Name: "LK"})
+	must(t, err)
+	_, err = r.Lessons.Create(ctx, domain.Lesson{GroupID: grp, SubjectID: sbj, LessonTypeID: lt, Week: 1, Weekday: 1, Pair: 1, Room: 101})
+	must(t, err)
+	must(t, r.EmployeesSubjects.Create(ctx, domain.EmployeeSubject{EmployeeID: teacher, SubjectID: sbj}))
+	date := time.Date(2024, time.September, 2, 0, 0, 0, 0, time.UTC)
+	_, err = r.Marks.Create(ctx, domain.Mark{EmployeeID: teacher, StudentID: stud, SubjectID: sbj, Mark: 8, Date: date})
+	must(t, err)
+	must(t, r.EmployeesSubjects.Delete(ctx, teacher, sbj))
+
+	d, err := backup.Dump(ctx, r)
+	must(t, err)
+	restored := memory.NewRepository()
+	must(t, backup.Restore(ctx, restored, roundTrip(t, d), backup.RestoreOptions{}))
+
+	marks, err := restored.Marks.FindAll(ctx)
+	must(t, err)
+	if len(marks) != 1 || marks[0].Mark != 8 {
+		t.Fatalf("got marks %+v, want the legacy mark", marks)
+	}
+
+	// new marks are still checked
+	_, err = restored.Marks.Create(ctx, domain.Mark{EmployeeID: marks[0].EmployeeID, StudentID: marks[0].StudentID, SubjectID: marks[0].SubjectID, Mark: 9, Date: date})
+	var checkErr *repository.CheckViolationError
+	if !errors.As(err, &checkErr) || checkErr.Constraint != "marks_grader_subject_check" {
+		t.Fatalf("got error %v, want marks_grader_subject_check", err)
+	}
+}
+
+// fills r with a record of every table, one employee, group and student
+// of them in the trash. Trashed records share their keys with active ones,
+// created before and after them
+func seed(t *testing.T, r *repository.Repository) {
+	t.Helper()
+	ctx := context.Background()
+
+	teaching, err := r.Positions.Create(ctx, domain.Position{Name: "Преподаватель", CanTeach: true, CanCurate: true})
+	must(t, err)
+	heading, err := r.Positions.Create(ctx, domain.Position{Name: "Заведующий кафедрой", CanCurate: true, CanAdminister: true})
+	must(t, err)
+	teacher, err := r.Employees.Create(ctx, domain.Employee{Name: "Ivanov Ivan", Passport: "MP0000001", PositionID: teaching})
+	must(t, err)
+	head, err := r.Employees.Create(ctx, domain.Employee{Name: "Petrova Olga", Passport: "MP0000002", PositionID: heading})
+	must(t, err)
+	retired, err := r.Employees.Create(ctx, domain.Employee{Name: "Sidorov Petr", Passport: "MP0000003", PositionID: teaching})
+	must(t, err)
+	grp, err := r.Groups.Create(ctx, domain.Group{Number: 101})
+	must(t, err)
+	graduated, err := r.Groups.Create(ctx, domain.Group{Number: 102})
+	must(t, err)
+	stud, err := r.Students.Create(ctx, domain.Student{Name: "Anna Smirnova", Passport: "MP1000001", EmployeeID: teacher, GroupID: grp})
+	must(t, err)
+	expelled, err := r.Students.Create(ctx, domain.Student{Name: "Boris Orlov", Passport: "MP1000002", EmployeeID: head, GroupID: graduated})
+	must(t, err)
+	sbj, err := r.Subjects.Create(ctx, domain.Subject{Name: "Mathematics", Description: "math"})
+	must(t, err)
+	lt, err := r.LessonTypes.Create(ctx, domain.LessonType{Name: "LK"})
+	must(t, err)
+	must(t, r.EmployeesSubjects.Create(ctx, domain.EmployeeSubject{EmployeeID: teacher, SubjectID: sbj}))
+	_, err = r.Lessons.Create(ctx, domain.Lesson{GroupID: grp, SubjectID: sbj, LessonTypeID: lt, Week: 1, Weekday: 1, Pair: 2, Room: 101, EmployeeID: &teacher})
+	must(t, err)
+	date := time.Date(2024, time.September, 2, 0, 0, 0, 0, time.UTC)
+	_, err = r.Marks.Create(ctx, domain.Mark{EmployeeID: teacher, StudentID: stud, SubjectID: sbj, Mark: 8, Date: date})
+	must(t, err)
+
+	must(t, r.Students.Delete(ctx, expelled))
+	must(t, r.Groups.Delete(ctx, graduated))
+	must(t, r.Employees.Delete(ctx, retired))
+
+	// a student expelled and enrolled again, a new group numbered like a
+	// graduated one, and an employee whose duplicate record was trashed
+	_, err = r.Students.Create(ctx, domain.Student{Name: "Boris Orlov", Passport: "MP1000002", EmployeeID: head, GroupID: grp})
+	must(t, err)
+	_, err = r.Groups.Create(ctx, domain.Group{Number: 102})
+	must(t, err)
+	kept, err := r.Employees.Create(ctx, domain.Employee{Name: "Kozlov Ilya", Passport: "MP0000004", PositionID: heading})
+	must(t, err)
+	must(t, r.Employees.Delete(ctx, kept))
+	duplicate, err := r.Employees.Create(ctx, domain.Employee{Name: "Kozlov Ilya", Passport: "MP0000004", PositionID: heading})
+	must(t, err)
+	must(t, r.Employees.Delete(ctx, duplicate))
+	must(t, r.Employees.Restore(ctx, kept))
+}
+
+// the time records went to the trash at is not kept by a restore
+func withoutTrashTimes(d *backup.Data) *backup.Data {
+	trashed := time.Time{}
+	for i := range d.Employees {
+		if d.Employees[i].DeletedAt != nil {
+			d.Employees[i].DeletedAt = &trashed
+		}
+	}
+	for i := range d.Groups {
+		if d.Groups[i].DeletedAt != nil {
+			d.Groups[i].DeletedAt = &trashed
+		}
+	}
+	for i := range d.Students {
+		if d.Students[i].DeletedAt != nil {
+			d.Students[i].DeletedAt = &trashed
+		}
+	}
+	return d
+}
+
+func TestRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	r := memory.NewRepository()
+	seed(t, r)
+
+	want, err := backup.Dump(ctx, r)
+	must(t, err)
+	restored := memory.NewRepository()
+	must(t, backup.Restore(ctx, restored, roundTrip(t, want), backup.RestoreOptions{}))
+
+	got, err := backup.Dump(ctx, restored)
+	must(t, err)
+	if !reflect.DeepEqual(withoutTrashTimes(got), withoutTrashTimes(want)) {
+		t.Fatalf("restored\n%+v\nwant\n%+v", got, want)
+	}
+
+	// restoring needs an empty database
+	var notEmpty *backup.NotEmptyError
+	if err := backup.Restore(ctx, restored, want, backup.RestoreOptions{}); !errors.As(err, &notEmpty) {
+		t.Fatalf("got error %v, want a not empty error", err)
+	}
+}
+
+// trashed records come back in the trash, out of the lists of active
+// records, next to the active records sharing their keys
+func TestRestoreTrash(t *testing.T) {
+	ctx := context.Background()
+	r := memory.NewRepository()
+	seed(t, r)
+
+	d, err := backup.Dump(ctx, r)
+	must(t, err)
+	restored := memory.NewRepository()
+	must(t, backup.Restore(ctx, restored, roundTrip(t, d), backup.RestoreOptions{}))
+
+	employees, err := restored.Employees.List(ctx, repository.ListOptions{})
+	must(t, err)
+	if len(employees.Items) != 3 {
+		t.Fatalf("got %d active employees, want 3", len(employees.Items))
+	}
+	groups, err := restored.Groups.List(ctx, repository.ListOptions{Deleted: repository.OnlyDeleted})
+	must(t, err)
+	if len(groups.Items) != 1 || groups.Items[0].Number != 102 {
+		t.Fatalf("got trashed groups %+v, want group 102", groups.Items)
+	}
+	students, err := restored.Students.List(ctx, repository.ListOptions{Deleted: repository.OnlyDeleted})
+	must(t, err)
+	if len(students.Items) != 1 || students.Items[0].Passport != "MP1000002" {
+		t.Fatalf("got trashed students %+v, want Boris Orlov", students.Items)
+	}
+
+	// the active records kept their keys, the trashed ones can't take them back
+	if err := restored.Groups.Restore(ctx, groups.Items[0].ID); !errors.Is(err, repository.ErrDuplicate) {
+		t.Fatalf("got error %v restoring group 102, want a duplicate", err)
+	}
+	if err := restored.Students.Restore(ctx, students.Items[0].ID); !errors.Is(err, repository.ErrDuplicate) {
+		t.Fatalf("got error %v restoring Boris Orlov, want a duplicate", err)
+	}
+}
+
+// a replace throws away every record, trash included, keeps the accounts
+// and links them to the restored employees by passport
+func TestRestoreReplace(t *testing.T) {
+	ctx := context.Background()
+	r := memory.NewRepository()
+	seed(t, r)
+	want, err := backup.Dump(ctx, r)
+	must(t, err)
+
+	current := memory.NewRepository()
+	pos, err := current.Positions.Create(ctx, domain.Position{Name: "Ассистент", CanTeach: true})
+	must(t, err)
+	_, err = current.Employees.Create(ctx, domain.Employee{Name: "Orlova Vera", Passport: "MP0000009", PositionID: pos})
+	must(t, err)
+	teacher, err := current.Employees.Create(ctx, domain.Employee{Name: "Ivanov Ivan", Passport: "MP0000001", PositionID: pos})
+	must(t, err)
+	grp, err := current.Groups.Create(ctx, domain.Group{Number: 101})
+	must(t, err)
+	must(t, current.Groups.Delete(ctx, grp))
+	_, err = current.Users.Create(ctx, domain.User{Login: "admin", PasswordHash: "hash", Role: domain.RoleAdmin})
+	must(t, err)
+	_, err = current.Users.Create(ctx, domain.User{Login: "ivanov", PasswordHash: "hash", Role: domain.RoleTeacher, EmployeeID: &teacher})
+	must(t, err)
+
+	var notEmpty *backup.NotEmptyError
+	if err := backup.Restore(ctx, current, want, backup.RestoreOptions{}); !errors.As(err, &notEmpty) {
+		t.Fatalf("got error %v, want a not empty error", err)
+	}
+	must(t, backup.Restore(ctx, current, roundTrip(t, want), backup.RestoreOptions{Replace: true}))
+
+	// the ids go on from the deleted records, both dumps restore alike
+	// into empty databases
+	got, err := backup.Dump(ctx, current)
+	must(t, err)
+	gotAgain, wantAgain := memory.NewRepository(), memory.NewRepository()
+	must(t, backup.Restore(ctx, gotAgain, got, backup.RestoreOptions{}))
+	must(t, backup.Restore(ctx, wantAgain, want, backup.RestoreOptions{}))
+	got, err = backup.Dump(ctx, gotAgain)
+	must(t, err)
+	want, err = backup.Dump(ctx, wantAgain)
+	must(t, err)
+	if !reflect.DeepEqual(withoutTrashTimes(got), withoutTrashTimes(want)) {
+		t.Fatalf("restored\n%+v\nwant\n%+v", got, want)
+	}
+
+	accounts, err := current.Users.FindAll(ctx)
+	must(t, err)
+	if len(accounts) != 2 {
+		t.Fatalf("got accounts %+v, want admin and ivanov", accounts)
+	}
+	ivanov, err := current.Users.FindByLogin(ctx, "ivanov")
+	must(t, err)
+	emp, err := current.Employees.FindOne(ctx, *ivanov.EmployeeID)
+	must(t, err)
+	if emp.Passport != "MP0000001" {
+		t.Fatalf("ivanov is linked to %+v, want the employee with passport MP0000001", emp)
+	}
+
+	// a teacher account whose employee is not in the archive stops the replace
+	_, err = current.Users.Create(ctx, domain.User{Login: "kozlov", PasswordHash: "hash", Role: domain.RoleTeacher, EmployeeID: &emp.ID})
+	must(t, err)
+	without := roundTrip(t, want)
+	without.Marks = nil
+	without.Lessons = nil
+	without.EmployeesSubjects = nil
+	without.Employees = slices.DeleteFunc(without.Employees, func(e domain.Employee) bool { return e.Passport == emp.Passport })
+	without.Students = slices.DeleteFunc(without.Students, func(s domain.Student) bool {
+		return !slices.ContainsFunc(without.Employees, func(e domain.Employee) bool { return e.ID == s.EmployeeID })
+	})
+	var restErr *backup.RestoreError
+	err = backup.Restore(ctx, current, without, backup.RestoreOptions{Replace: true})
+	if !errors.As(err, &restErr) || restErr.Table != "users" || !errors.Is(err, backup.ErrMissingEmployee) {
+		t.Fatalf("got error %v, want a missing employee of an account", err)
+	}
+}
+
+// rewrites the file name of an archive with edit, keeping the others as they are
+func editArchive(t *testing.T, archive []byte, name string, edit func(content []byte) []byte) []byte {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	must(t, err)
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		must(t, err)
+		content, err := io.ReadAll(rc)
+		must(t, err)
+		must(t, rc.Close())
+		if f.Name == name {
+			content = edit(content)
+		}
+		w, err := zw.Create(f.Name)
+		must(t, err)
+		_, err = w.Write(content)
+		must(t, err)
+	}
+	must(t, zw.Close())
+	return buf.Bytes()
+}
+
+// writes d as an archive of an earlier version
+func writeVersion(t *testing.T, d *backup.Data, version int) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	_, err := backup.Write(&buf, d)
+	must(t, err)
+	return editArchive(t, buf.Bytes(), "manifest.json", func(content []byte) []byte {
+		var m backup.Manifest
+		must(t, json.Unmarshal(content, &m))
+		m.Version = version
+		content, err := json.Marshal(m)
+		must(t, err)
+		return content
+	})
+}
+
+// archives of earlier versions read like the migrations left the database
+func TestReadUpgrades(t *testing.T) {
+	// positions had no capabilities before version 2, lessons no pair before 3
+	old := &backup.Data{
+		Positions: []domain.Position{{ID: 1, Name: "Преподаватель"}, {ID: 2, Name: "Лаборант"}, {ID: 3, Name: "Методист"}},
+		Employees: []domain.Employee{{ID: 1, Name: "Ivanov Ivan", Passport: "MP0000001", PositionID: 2}},
+		Students:  []domain.Student{{ID: 1, Name: "Anna Smirnova", Passport: "MP1000001", EmployeeID: 1, GroupID: 1}},
+		Lessons: []domain.Lesson{
+			{ID: 7, GroupID: 1, SubjectID: 1, LessonTypeID: 1, Week: 1, Weekday: 1, Room: 101},
+			{ID: 3, GroupID: 1, SubjectID: 1, LessonTypeID: 1, Week: 1, Weekday: 1, Room: 102},
+			{ID: 5, GroupID: 2, SubjectID: 1, LessonTypeID: 1, Week: 1, Weekday: 1, Room: 103},
+		},
+	}
+
+	archive := writeVersion(t, old, 1)
+	d, m, err := backup.Read(bytes.NewReader(archive), int64(len(archive)))
+	must(t, err)
+	if m.Version != 1 {
+		t.Fatalf("got version %d, want 1", m.Version)
+	}
+	wantPositions := []domain.Position{
+		{ID: 1, Name: "Преподаватель", CanTeach: true, CanCurate: true},
+		{ID: 2, Name: "Лаборант", CanCurate: true}, // curates a student
+		{ID: 3, Name: "Методист"},
+	}
+	if !reflect.DeepEqual(d.Positions, wantPositions) {
+		t.Fatalf("got positions %+v, want %+v", d.Positions, wantPositions)
+	}
+	// pairs follow the ids within the day of a group
+	for _, lsn := range d.Lessons {
+		want := map[uint64]uint16{3: 1, 5: 1, 7: 2}[lsn.ID]
+		if lsn.Pair != want {
+			t.Fatalf("got pair %d for lesson %d, want %d", lsn.Pair, lsn.ID, want)
+		}
+	}
+
+	archive = writeVersion(t, old, 2)
+	d, _, err = backup.Read(bytes.NewReader(archive), int64(len(archive)))
+	must(t, err)
+	if !reflect.DeepEqual(d.Positions, old.Positions) {
+		t.Fatalf("got positions %+v, want them unchanged", d.Positions)
+	}
+	if d.Lessons[0].Pair != 2 {
+		t.Fatalf("got pair %d for lesson 7, want 2", d.Lessons[0].Pair)
+	}
+}
+
+func TestReadRejectsTamperedArchive(t *testing.T) {
+	ctx := context.Background()
+	r := memory.NewRepository()
+	seed(t, r)
+	d, err := backup.Dump(ctx, r)
+	must(t, err)
+	var buf bytes.Buffer
+	_, err = backup.Write(&buf, d)
+	must(t, err)
+
+	tampered := editArchive(t, buf.Bytes(), "marks.json", func(content []byte) []byte {
+		if !bytes.Contains(content, []byte(`"mark": 8`)) {
+			t.Fatalf("the mark to tamper with is not in %s", content)
+		}
+		return bytes.Replace(content, []byte(`"mark": 8`), []byte(`"mark": 10`), 1)
+	})
+	if _, _, err := backup.Read(bytes.NewReader(tampered), int64(len(tampered))); !errors.Is(err, backup.ErrInvalidArchive) {
+		t.Fatalf("got error %v, want ErrInvalidArchive", err)
+	}
+
+	future := writeVersion(t, d, backup.Version+1)
+	if _, _, err := backup.Read(bytes.NewReader(future), int64(len(future))); !errors.Is(err, backup.ErrInvalidArchive) {
+		t.Fatalf("got error %v for a newer version, want ErrInvalidArchive", err)
+	}
+}
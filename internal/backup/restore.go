@@ -0,0 +1,340 @@
+package backup
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+)
+
+// NotEmptyError is returned by Restore when the target database has records
+// and they aren't replaced, restoring only adds rows so it would mix two datasets
+type NotEmptyError struct {
+	Table string
+}
+
+func (e *NotEmptyError) Error() string {
+	return fmt.Sprintf("database is not empty: table %s has records", e.Table)
+}
+
+// RestoreError tells which record of the backup was rejected
+type RestoreError struct {
+	Table string
+	ID    string // id of the record in the backup, the login of an account
+	Err   error
+}
+
+func (e *RestoreError) Error() string {
+	return fmt.Sprintf("%s %s: %v", e.Table, e.ID, e.Err)
+}
+
+func (e *RestoreError) Unwrap() error {
+	return e.Err
+}
+
+// ErrMissingEmployee is returned when the records are replaced and the
+// employee of a teacher account isn't in the backup
+var ErrMissingEmployee = errors.New("the employee of the account is not in the backup")
+
+// RestoreOptions choose what Restore does with the records of the database
+type RestoreOptions struct {
+	// Replace deletes every record of the tables of a backup, trash
+	// included, before restoring it. The accounts stay, they are linked
+	// again to the restored employees that have the passports of theirs
+	Replace bool
+}
+
+// Restore writes d to the database in one transaction, tables are filled
+// in the order of Tables and nothing is written if a record is rejected.
+// Unless the records are replaced the database has to be empty
+func Restore(ctx context.Context, r *repository.Repository, d *Data, opts RestoreOptions) error {
+	return r.WithTx(ctx, func(ctx context.Context, tx *repository.Repository) error {
+		if opts.Replace {
+			return replace(ctx, tx, d)
+		}
+		if err := checkEmpty(ctx, tx); err != nil {
+			return err
+		}
+		return restore(ctx, tx, d)
+	})
+}
+
+// purges the tables of a backup and restores d in their place. The
+// accounts are purged as well, while they reference the old employees,
+// and created again afterwards
+func replace(ctx context.Context, tx *repository.Repository, d *Data) error {
+	accounts, err := tx.Users.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+	old, err := tx.Employees.List(ctx, repository.ListOptions{Deleted: repository.IncludeDeleted})
+	if err != nil {
+		return err
+	}
+	passports := map[uint64]string{}
+	for _, emp := range old.Items {
+		passports[emp.ID] = emp.Passport
+	}
+
+	// tables go in the reverse order, each one is referenced by the later ones
+	tables := []string{"users"}
+	for _, table := range slices.Backward(Tables) {
+		tables = append(tables, table)
+	}
+	if err = tx.Purger.Purge(ctx, tables...); err != nil {
+		return err
+	}
+	if err = restore(ctx, tx, d); err != nil {
+		return err
+	}
+
+	restored, err := tx.Employees.List(ctx, repository.ListOptions{Deleted: repository.IncludeDeleted})
+	if err != nil {
+		return err
+	}
+	// an active employee wins over trashed ones with the same passport
+	employees := map[string]uint64{}
+	for _, emp := range restored.Items {
+		if _, ok := employees[emp.Passport]; !ok || emp.DeletedAt == nil {
+			employees[emp.Passport] = emp.ID
+		}
+	}
+
+	for _, u := range accounts {
+		if u.EmployeeID != nil {
+			id, ok := employees[passports[*u.EmployeeID]]
+			switch {
+			case ok:
+				u.EmployeeID = &id
+			case u.Role == domain.RoleTeacher:
+				return &RestoreError{Table: "users", ID: u.Login, Err: ErrMissingEmployee}
+			default:
+				u.EmployeeID = nil
+			}
+		}
+		if _, err = tx.Users.Create(ctx, u); err != nil {
+			return &RestoreError{Table: "users", ID: u.Login, Err: err}
+		}
+	}
+	return nil
+}
+
+// ids given by the target database to the records of the backup
+type idMap map[uint64]uint64
+
+// Trashed records go to the trash as soon as they are created. Their
+// passports and group numbers may be reused by active records, which are
+// only unique among each other; an active record sharing its key with a
+// trashed one is parked in the trash until the whole table is restored,
+// so the records keep the order of their ids without clashing
+func restore(ctx context.Context, tx *repository.Repository, d *Data) error {
+	positions := idMap{}
+	for _, pos := range d.Positions {
+		id, err := tx.Positions.Create(ctx, domain.Position{
+			Name:          pos.Name,
+			CanTeach:      pos.CanTeach,
+			CanCurate:     pos.CanCurate,
+			CanAdminister: pos.CanAdminister,
+		})
+		if err != nil {
+			return restoreError("positions", pos.ID, err)
+		}
+		positions[pos.ID] = id
+	}
+
+	employees := idMap{}
+	var parked []uint64
+	shared := trashedKeys(d.Employees, func(emp domain.Employee) (string, bool) { return emp.Passport, emp.DeletedAt != nil })
+	for _, emp := range d.Employees {
+		id, err := tx.Employees.Create(ctx, domain.Employee{
+			Name:       emp.Name,
+			Passport:   emp.Passport,
+			PositionID: positions[emp.PositionID],
+		})
+		if err == nil && (emp.DeletedAt != nil || shared[emp.Passport]) {
+			err = tx.Employees.Delete(ctx, id)
+		}
+		if err != nil {
+			return restoreError("employees", emp.ID, err)
+		}
+		employees[emp.ID] = id
+		if emp.DeletedAt == nil && shared[emp.Passport] {
+			parked = append(parked, emp.ID)
+		}
+	}
+	for _, id := range parked {
+		if err := tx.Employees.Restore(ctx, employees[id]); err != nil {
+			return restoreError("employees", id, err)
+		}
+	}
+
+	groups := idMap{}
+	parked = nil
+	sharedNumbers := trashedKeys(d.Groups, func(grp domain.Group) (uint64, bool) { return grp.Number, grp.DeletedAt != nil })
+	for _, grp := range d.Groups {
+		id, err := tx.Groups.Create(ctx, domain.Group{Number: grp.Number})
+		if err == nil && (grp.DeletedAt != nil || sharedNumbers[grp.Number]) {
+			err = tx.Groups.Delete(ctx, id)
+		}
+		if err != nil {
+			return restoreError("groups", grp.ID, err)
+		}
+		groups[grp.ID] = id
+		if grp.DeletedAt == nil && sharedNumbers[grp.Number] {
+			parked = append(parked, grp.ID)
+		}
+	}
+	for _, id := range parked {
+		if err := tx.Groups.Restore(ctx, groups[id]); err != nil {
+			return restoreError("groups", id, err)
+		}
+	}
+
+	students := idMap{}
+	parked = nil
+	shared = trashedKeys(d.Students, func(stud domain.Student) (string, bool) { return stud.Passport, stud.DeletedAt != nil })
+	for _, stud := range d.Students {
+		id, err := tx.Students.Create(ctx, domain.Student{
+			Name:       stud.Name,
+			Passport:   stud.Passport,
+			EmployeeID: employees[stud.EmployeeID],
+			GroupID:    groups[stud.GroupID],
+		})
+		if err == nil && (stud.DeletedAt != nil || shared[stud.Passport]) {
+			err = tx.Students.Delete(ctx, id)
+		}
+		if err != nil {
+			return restoreError("students", stud.ID, err)
+		}
+		students[stud.ID] = id
+		if stud.DeletedAt == nil && shared[stud.Passport] {
+			parked = append(parked, stud.ID)
+		}
+	}
+	for _, id := range parked {
+		if err := tx.Students.Restore(ctx, students[id]); err != nil {
+			return restoreError("students", id, err)
+		}
+	}
+
+	subjects := idMap{}
+	for _, sbj := range d.Subjects {
+		id, err := tx.Subjects.Create(ctx, domain.Subject{Name: sbj.Name, Description: sbj.Description})
+		if err != nil {
+			return restoreError("subjects", sbj.ID, err)
+		}
+		subjects[sbj.ID] = id
+	}
+
+	lessonTypes := idMap{}
+	for _, lt := range d.LessonTypes {
+		id, err := tx.LessonTypes.Create(ctx, domain.LessonType{Name: lt.Name})
+		if err != nil {
+			return restoreError("lesson_types", lt.ID, err)
+		}
+		lessonTypes[lt.ID] = id
+	}
+
+	for _, es := range d.EmployeesSubjects {
+		err := tx.EmployeesSubjects.Create(ctx, domain.EmployeeSubject{
+			EmployeeID: employees[es.EmployeeID],
+			SubjectID:  subjects[es.SubjectID],
+		})
+		if err != nil {
+			return &RestoreError{Table: "employees_subjects", ID: fmt.Sprintf("%d/%d", es.EmployeeID, es.SubjectID), Err: err}
+		}
+	}
+
+	for _, lsn := range d.Lessons {
+		var teacher *uint64
+		if lsn.EmployeeID != nil {
+			id := employees[*lsn.EmployeeID]
+			teacher = &id
+		}
+		_, err := tx.Lessons.Create(ctx, domain.Lesson{
+			GroupID:      groups[lsn.GroupID],
+			SubjectID:    subjects[lsn.SubjectID],
+			LessonTypeID: lessonTypes[lsn.LessonTypeID],
+			Week:         lsn.Week,
+			Weekday:      lsn.Weekday,
+			Pair:         lsn.Pair,
+			Room:         lsn.Room,
+			EmployeeID:   teacher,
+		})
+		if err != nil {
+			return restoreError("lessons", lsn.ID, err)
+		}
+	}
+
+	marks := make([]domain.Mark, 0, len(d.Marks))
+	for _, mark := range d.Marks {
+		marks = append(marks, domain.Mark{
+			EmployeeID: employees[mark.EmployeeID],
+			StudentID:  students[mark.StudentID],
+			SubjectID:  subjects[mark.SubjectID],
+			Mark:       mark.Mark,
+			Date:       mark.Date,
+		})
+	}
+	// marks given before the grading rule existed are kept as they were
+	if len(marks) > 0 {
+		if _, err := tx.Marks.CopyFrom(repository.WithoutGradingCheck(ctx), marks); err != nil {
+			return &RestoreError{Table: "marks", Err: err}
+		}
+	}
+
+	return nil
+}
+
+// keys of the trashed items, key returns the key of an item and whether it's in the trash
+func trashedKeys[T any, K comparable](items []T, key func(T) (K, bool)) map[K]bool {
+	keys := map[K]bool{}
+	for _, item := range items {
+		if k, trashed := key(item); trashed {
+			keys[k] = true
+		}
+	}
+	return keys
+}
+
+// fails with NotEmptyError naming the first table with records, trash included
+func checkEmpty(ctx context.Context, tx *repository.Repository) error {
+	plain := repository.ListOptions{Limit: 1}
+	withTrash := repository.ListOptions{Limit: 1, Deleted: repository.IncludeDeleted}
+	totals := map[string]func() (uint64, error){
+		"positions":          total(ctx, tx.Positions.List, plain),
+		"employees":          total(ctx, tx.Employees.List, withTrash),
+		"groups":             total(ctx, tx.Groups.List, withTrash),
+		"students":           total(ctx, tx.Students.List, withTrash),
+		"subjects":           total(ctx, tx.Subjects.List, plain),
+		"lesson_types":       total(ctx, tx.LessonTypes.List, plain),
+		"employees_subjects": total(ctx, tx.EmployeesSubjects.List, plain),
+		"lessons":            total(ctx, tx.Lessons.List, plain),
+		"marks":              total(ctx, tx.Marks.List, plain),
+	}
+
+	for _, table := range Tables {
+		n, err := totals[table]()
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			return &NotEmptyError{Table: table}
+		}
+	}
+	return nil
+}
+
+// number of rows list finds with opts
+func total[T any](ctx context.Context, list func(context.Context, repository.ListOptions) (repository.Page[T], error), opts repository.ListOptions) func() (uint64, error) {
+	return func() (uint64, error) {
+		page, err := list(ctx, opts)
+		return page.Total, err
+	}
+}
+
+func restoreError(table string, id uint64, err error) error {
+	return &RestoreError{Table: table, ID: fmt.Sprint(id), Err: err}
+}
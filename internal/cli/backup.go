@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"university-db-admin/internal/backup"
+)
+
+func backupGroup() group {
+	return group{
+		name: "backup",
+		help: "saves the whole database to an archive or restores it",
+		commands: []command{
+			{
+				name:  "create",
+				usage: "--file path",
+				help:  "writes every record to a .zip archive",
+				run:   runBackupCreate,
+			},
+			{
+				name:  "restore",
+				usage: "--file path [--replace]",
+				help:  "loads an archive into an empty database or in place of its records, accounts aren't part of it",
+				run:   runBackupRestore,
+			},
+		},
+	}
+}
+
+func runBackupCreate(ctx context.Context, env Env, args []string) error {
+	fs, out := newFlagSet(env, "backup create")
+	file := fs.String("file", "", "path of the archive to write")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "file"); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+
+	m, err := env.Service().Backup.Create(ctx, *file)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(env.Stderr, "backup written to %s\n", *file)
+	return out.write(env, manifestTable(m))
+}
+
+func runBackupRestore(ctx context.Context, env Env, args []string) error {
+	fs, out := newFlagSet(env, "backup restore")
+	file := fs.String("file", "", "path of the archive to restore")
+	replace := fs.Bool("replace", false, "delete every record, trash included, and restore the archive in their place; accounts are kept")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "file"); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+
+	m, err := env.Service().Backup.Restore(ctx, *file, backup.RestoreOptions{Replace: *replace})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(env.Stderr, "restored backup of %s\n", m.CreatedAt.Local().Format("2006-01-02 15:04:05"))
+	return out.write(env, manifestTable(m))
+}
+
+func manifestTable(m backup.Manifest) table {
+	t := table{title: "backup", headers: []string{"table", "rows", "sha256"}}
+	for _, e := range m.Tables {
+		t.rows = append(t.rows, []any{e.Table, e.Rows, e.SHA256})
+	}
+	return t
+}
@@ -0,0 +1,210 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"university-db-admin/internal/api"
+	"university-db-admin/internal/service"
+)
+
+// exit codes returned by Run
+const (
+	ExitOK    = 0
+	ExitError = 1
+	ExitUsage = 2
+)
+
+// Env holds everything a command needs from the outside world
+type Env struct {
+	Stdin  io.Reader // read by commands taking a password
+	Stdout io.Writer
+	Stderr io.Writer
+
+	// connects to the database on first use, so usage errors
+	// and help don't require a running server. The services are
+	// restricted to the account the commands run as
+	Service func() *service.Service
+
+	// checks the credentials of API clients, connecting like Service
+	Login api.Login
+}
+
+type command struct {
+	name  string
+	usage string // arguments shown after the command name
+	help  string
+	run   func(ctx context.Context, env Env, args []string) error
+}
+
+type group struct {
+	name     string
+	help     string
+	commands []command
+}
+
+// returned by commands when arguments are missing or malformed
+type usageError struct {
+	msg string
+}
+
+func (e usageError) Error() string {
+	return e.msg
+}
+
+func usagef(format string, args ...any) error {
+	return usageError{msg: fmt.Sprintf(format, args...)}
+}
+
+// runs the command described by args, e.g. ["students", "list", "--group", "3"],
+// and returns the process exit code
+func Run(ctx context.Context, env Env, args []string) int {
+	groups := commandGroups()
+
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		printUsage(env.Stdout, groups)
+		if len(args) == 0 {
+			return ExitUsage
+		}
+		return ExitOK
+	}
+
+	g, ok := groups[args[0]]
+	if !ok {
+		fmt.Fprintf(env.Stderr, "unknown command %q\n\n", args[0])
+		printUsage(env.Stderr, groups)
+		return ExitUsage
+	}
+
+	// groups with a single unnamed command take no subcommand, e.g. "serve"
+	sub, rest := "", args[1:]
+	if len(g.commands) != 1 || g.commands[0].name != "" {
+		if len(args) < 2 {
+			printGroupUsage(env.Stderr, g)
+			return ExitUsage
+		}
+		sub, rest = args[1], args[2:]
+	}
+
+	for _, c := range g.commands {
+		if c.name != sub {
+			continue
+		}
+
+		err := c.run(ctx, env, rest)
+		switch {
+		case err == nil:
+			return ExitOK
+		case errors.Is(err, flag.ErrHelp):
+			return ExitOK
+		case errors.As(err, &usageError{}):
+			name := strings.TrimSpace(g.name + " " + c.name)
+			fmt.Fprintf(env.Stderr, "%s: %v\n", name, err)
+			fmt.Fprintf(env.Stderr, "usage: %s %s\n", name, c.usage)
+			return ExitUsage
+		default:
+			fmt.Fprintln(env.Stderr, "error:", err)
+			return ExitError
+		}
+	}
+
+	fmt.Fprintf(env.Stderr, "unknown %s command %q\n\n", g.name, args[1])
+	printGroupUsage(env.Stderr, g)
+	return ExitUsage
+}
+
+func commandGroups() map[string]group {
+	groups := map[string]group{
+		"schedule": scheduleGroup(),
+		"query":    queryGroup(),
+		"serve":    serveGroup(),
+		"import":   importGroup(),
+		"backup":   backupGroup(),
+		"user":     userGroup(),
+	}
+	for _, e := range entities {
+		groups[e.name] = entityGroup(e)
+	}
+	return groups
+}
+
+func printUsage(w io.Writer, groups map[string]group) {
+	fmt.Fprintln(w, "usage: university_db_cli <command> [subcommand] [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		g := groups[name]
+		subcommands := make([]string, 0, len(g.commands))
+		for _, c := range g.commands {
+			if c.name != "" {
+				subcommands = append(subcommands, c.name)
+			}
+		}
+		if len(subcommands) == 0 {
+			fmt.Fprintf(w, "  %-20s %s\n", name, g.help)
+			continue
+		}
+		fmt.Fprintf(w, "  %-20s %s (%s)\n", name, g.help, strings.Join(subcommands, ", "))
+	}
+
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "every subcommand accepts --format table|json|csv|xlsx|pdf, --output file and -h for its flags")
+}
+
+func printGroupUsage(w io.Writer, g group) {
+	fmt.Fprintf(w, "usage: university_db_cli %s <subcommand> [flags]\n\n", g.name)
+	for _, c := range g.commands {
+		fmt.Fprintf(w, "  %-8s %s\n", c.name, c.help)
+	}
+}
+
+// creates a flag set with the flags shared by all subcommands
+func newFlagSet(env Env, name string) (*flag.FlagSet, *output) {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(env.Stderr)
+
+	out := &output{}
+	fs.StringVar(&out.format, "format", "", "output format: table, json, csv, xlsx or pdf (default taken from --output, else table)")
+	fs.StringVar(&out.file, "output", "", "write the result to this file instead of stdout")
+	return fs, out
+}
+
+// parses flags and rejects positional leftovers
+func parseFlags(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return usageError{msg: err.Error()}
+	}
+	if fs.NArg() > 0 {
+		return usagef("unexpected argument %q", fs.Arg(0))
+	}
+	return nil
+}
+
+// fails with a usage error naming the first required flag left unset
+func requireFlags(fs *flag.FlagSet, names ...string) error {
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+	})
+
+	for _, name := range names {
+		if !set[name] {
+			return usagef("flag --%s is required", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,592 @@
+package cli
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"time"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+	"university-db-admin/internal/service"
+)
+
+// entity describes the list, add and delete commands of one table,
+// read-only tables leave add and delete empty; tables with soft delete
+// also have a restore command and list deleted records on request
+type entity struct {
+	name    string
+	help    string
+	columns []string // output columns, also the fields accepted by --filter and --sort
+
+	// list flags filtering by equality on a column, e.g. --group for group_id
+	shortcuts map[string]string
+
+	list    func(ctx context.Context, s *service.Service, opts repository.ListOptions) ([][]any, uint64, error)
+	add     mutation
+	delete  mutation
+	restore mutation
+}
+
+// mutation registers the flags of a modifying command and returns the
+// action that uses their parsed values, the action reports the id of a
+// created record or 0
+type mutation struct {
+	required []string
+	bind     func(fs *flag.FlagSet) func(ctx context.Context, s *service.Service) (uint64, error)
+}
+
+var entities = []entity{
+	{
+		name:    "employees",
+		help:    "university staff",
+		columns: []string{"id", "name", "passport", "position_id", "deleted_at"},
+		shortcuts: map[string]string{
+			"position": "position_id",
+		},
+		list: func(ctx context.Context, s *service.Service, opts repository.ListOptions) ([][]any, uint64, error) {
+			page, err := s.Employees.List(ctx, opts)
+			rows := make([][]any, len(page.Items))
+			for i, e := range page.Items {
+				rows[i] = []any{e.ID, e.Name, e.Passport, e.PositionID, deletedAt(e.DeletedAt)}
+			}
+			return rows, page.Total, err
+		},
+		add: mutation{
+			required: []string{"name", "passport", "position"},
+			bind: func(fs *flag.FlagSet) func(ctx context.Context, s *service.Service) (uint64, error) {
+				name := fs.String("name", "", "full name")
+				passport := fs.String("passport", "", "passport number")
+				position := fs.Uint64("position", 0, "position id")
+				return func(ctx context.Context, s *service.Service) (uint64, error) {
+					return s.Employees.Create(ctx, domain.Employee{
+						Name:       *name,
+						Passport:   *passport,
+						PositionID: *position,
+					})
+				}
+			},
+		},
+		delete: byID(func(s *service.Service) func(context.Context, uint64) error {
+			return s.Employees.Delete
+		}),
+		restore: byID(func(s *service.Service) func(context.Context, uint64) error {
+			return s.Employees.Restore
+		}),
+	},
+	{
+		name:    "groups",
+		help:    "student groups",
+		columns: []string{"id", "number", "deleted_at"},
+		list: func(ctx context.Context, s *service.Service, opts repository.ListOptions) ([][]any, uint64, error) {
+			page, err := s.Groups.List(ctx, opts)
+			rows := make([][]any, len(page.Items))
+			for i, g := range page.Items {
+				rows[i] = []any{g.ID, g.Number, deletedAt(g.DeletedAt)}
+			}
+			return rows, page.Total, err
+		},
+		add: mutation{
+			required: []string{"number"},
+			bind: func(fs *flag.FlagSet) func(ctx context.Context, s *service.Service) (uint64, error) {
+				number := fs.Uint64("number", 0, "group number")
+				return func(ctx context.Context, s *service.Service) (uint64, error) {
+					return s.Groups.Create(ctx, domain.Group{Number: *number})
+				}
+			},
+		},
+		delete: byID(func(s *service.Service) func(context.Context, uint64) error {
+			return s.Groups.Delete
+		}),
+		restore: byID(func(s *service.Service) func(context.Context, uint64) error {
+			return s.Groups.Restore
+		}),
+	},
+	{
+		name:    "lesson-types",
+		help:    "kinds of lessons",
+		columns: []string{"id", "name"},
+		list: func(ctx context.Context, s *service.Service, opts repository.ListOptions) ([][]any, uint64, error) {
+			page, err := s.LessonTypes.List(ctx, opts)
+			rows := make([][]any, len(page.Items))
+			for i, l := range page.Items {
+				rows[i] = []any{l.ID, l.Name}
+			}
+			return rows, page.Total, err
+		},
+		add: mutation{
+			required: []string{"name"},
+			bind: func(fs *flag.FlagSet) func(ctx context.Context, s *service.Service) (uint64, error) {
+				name := fs.String("name", "", "two letter abbreviation")
+				return func(ctx context.Context, s *service.Service) (uint64, error) {
+					return s.LessonTypes.Create(ctx, domain.LessonType{Name: *name})
+				}
+			},
+		},
+		delete: byID(func(s *service.Service) func(context.Context, uint64) error {
+			return s.LessonTypes.Delete
+		}),
+	},
+	{
+		name:    "lessons",
+		help:    "scheduled lessons",
+		columns: []string{"id", "group_id", "subject_id", "lesson_type_id", "week", "weekday", "pair", "room", "employee_id"},
+		shortcuts: map[string]string{
+			"group":   "group_id",
+			"subject": "subject_id",
+			"week":    "week",
+			"teacher": "employee_id",
+		},
+		list: func(ctx context.Context, s *service.Service, opts repository.ListOptions) ([][]any, uint64, error) {
+			page, err := s.Schedule.List(ctx, opts)
+			rows := make([][]any, len(page.Items))
+			for i, l := range page.Items {
+				var teacher uint64 // 0 for lessons without a teacher
+				if l.EmployeeID != nil {
+					teacher = *l.EmployeeID
+				}
+				rows[i] = []any{l.ID, l.GroupID, l.SubjectID, l.LessonTypeID, l.Week, l.Weekday, l.Pair, l.Room, teacher}
+			}
+			return rows, page.Total, err
+		},
+		add: mutation{
+			required: []string{"group", "subject", "type", "week", "weekday", "pair", "room"},
+			bind: func(fs *flag.FlagSet) func(ctx context.Context, s *service.Service) (uint64, error) {
+				group := fs.Uint64("group", 0, "group id")
+				subject := fs.Uint64("subject", 0, "subject id")
+				lessonType := fs.Uint64("type", 0, "lesson type id")
+				week := fs.Uint("week", 0, "week number")
+				weekday := fs.Uint("weekday", 0, "day of the week, 1 is monday")
+				pair := fs.Uint("pair", 0, "pair number in the bell schedule")
+				room := fs.Uint64("room", 0, "room number")
+				teacher := fs.Uint64("teacher", 0, "id of an employee teaching the subject")
+				return func(ctx context.Context, s *service.Service) (uint64, error) {
+					return s.Schedule.Create(ctx, domain.Lesson{
+						GroupID:      *group,
+						SubjectID:    *subject,
+						LessonTypeID: *lessonType,
+						Week:         uint16(*week),
+						Weekday:      uint16(*weekday),
+						Pair:         uint16(*pair),
+						Room:         *room,
+						EmployeeID:   employeeID(*teacher),
+					})
+				}
+			},
+		},
+		delete: byID(func(s *service.Service) func(context.Context, uint64) error {
+			return s.Schedule.Delete
+		}),
+	},
+	{
+		name:    "marks",
+		help:    "student marks",
+		columns: []string{"id", "employee_id", "student_id", "subject_id", "mark", "date"},
+		shortcuts: map[string]string{
+			"employee": "employee_id",
+			"student":  "student_id",
+			"subject":  "subject_id",
+		},
+		list: func(ctx context.Context, s *service.Service, opts repository.ListOptions) ([][]any, uint64, error) {
+			page, err := s.Marks.List(ctx, opts)
+			rows := make([][]any, len(page.Items))
+			for i, m := range page.Items {
+				rows[i] = []any{m.ID, m.EmployeeID, m.StudentID, m.SubjectID, m.Mark, m.Date.Format(dateLayout)}
+			}
+			return rows, page.Total, err
+		},
+		add: mutation{
+			required: []string{"employee", "student", "subject", "mark"},
+			bind: func(fs *flag.FlagSet) func(ctx context.Context, s *service.Service) (uint64, error) {
+				employee := fs.Uint64("employee", 0, "id of the grading teacher")
+				student := fs.Uint64("student", 0, "student id")
+				subject := fs.Uint64("subject", 0, "subject id")
+				mark := fs.Uint("mark", 0, "mark from 1 to 10")
+				date := fs.String("date", time.Now().Format(dateLayout), "date in YYYY-MM-DD format")
+				return func(ctx context.Context, s *service.Service) (uint64, error) {
+					parsed, err := time.Parse(dateLayout, *date)
+					if err != nil {
+						return 0, usagef("invalid date %q, expected YYYY-MM-DD", *date)
+					}
+					return s.Marks.Create(ctx, domain.Mark{
+						EmployeeID: *employee,
+						StudentID:  *student,
+						SubjectID:  *subject,
+						Mark:       uint16(*mark),
+						Date:       parsed,
+					})
+				}
+			},
+		},
+		delete: byID(func(s *service.Service) func(context.Context, uint64) error {
+			return s.Marks.Delete
+		}),
+	},
+	{
+		name:    "positions",
+		help:    "staff positions",
+		columns: []string{"id", "name", "can_teach", "can_curate", "can_administer"},
+		list: func(ctx context.Context, s *service.Service, opts repository.ListOptions) ([][]any, uint64, error) {
+			page, err := s.Positions.List(ctx, opts)
+			rows := make([][]any, len(page.Items))
+			for i, p := range page.Items {
+				rows[i] = []any{p.ID, p.Name, p.CanTeach, p.CanCurate, p.CanAdminister}
+			}
+			return rows, page.Total, err
+		},
+		add: mutation{
+			required: []string{"name"},
+			bind: func(fs *flag.FlagSet) func(ctx context.Context, s *service.Service) (uint64, error) {
+				name := fs.String("name", "", "position name")
+				teach := fs.Bool("teach", false, "holders may give marks and know subjects")
+				curate := fs.Bool("curate", false, "holders may curate students")
+				administer := fs.Bool("administer", false, "holders work in the dean's office")
+				return func(ctx context.Context, s *service.Service) (uint64, error) {
+					return s.Positions.Create(ctx, domain.Position{Name: *name, CanTeach: *teach, CanCurate: *curate, CanAdminister: *administer})
+				}
+			},
+		},
+		delete: byID(func(s *service.Service) func(context.Context, uint64) error {
+			return s.Positions.Delete
+		}),
+	},
+	{
+		name:    "students",
+		help:    "students",
+		columns: []string{"id", "name", "passport", "employee_id", "group_id", "deleted_at"},
+		shortcuts: map[string]string{
+			"group":   "group_id",
+			"curator": "employee_id",
+		},
+		list: func(ctx context.Context, s *service.Service, opts repository.ListOptions) ([][]any, uint64, error) {
+			page, err := s.Students.List(ctx, opts)
+			rows := make([][]any, len(page.Items))
+			for i, st := range page.Items {
+				rows[i] = []any{st.ID, st.Name, st.Passport, st.EmployeeID, st.GroupID, deletedAt(st.DeletedAt)}
+			}
+			return rows, page.Total, err
+		},
+		add: mutation{
+			required: []string{"name", "passport", "curator", "group"},
+			bind: func(fs *flag.FlagSet) func(ctx context.Context, s *service.Service) (uint64, error) {
+				name := fs.String("name", "", "full name")
+				passport := fs.String("passport", "", "passport number")
+				curator := fs.Uint64("curator", 0, "curator employee id")
+				group := fs.Uint64("group", 0, "group id")
+				return func(ctx context.Context, s *service.Service) (uint64, error) {
+					return s.Students.Create(ctx, domain.Student{
+						Name:       *name,
+						Passport:   *passport,
+						EmployeeID: *curator,
+						GroupID:    *group,
+					})
+				}
+			},
+		},
+		delete: byID(func(s *service.Service) func(context.Context, uint64) error {
+			return s.Students.Delete
+		}),
+		restore: byID(func(s *service.Service) func(context.Context, uint64) error {
+			return s.Students.Restore
+		}),
+	},
+	{
+		name:    "subjects",
+		help:    "taught subjects",
+		columns: []string{"id", "name", "description"},
+		list: func(ctx context.Context, s *service.Service, opts repository.ListOptions) ([][]any, uint64, error) {
+			page, err := s.Subjects.List(ctx, opts)
+			rows := make([][]any, len(page.Items))
+			for i, sb := range page.Items {
+				rows[i] = []any{sb.ID, sb.Name, sb.Description}
+			}
+			return rows, page.Total, err
+		},
+		add: mutation{
+			required: []string{"name", "description"},
+			bind: func(fs *flag.FlagSet) func(ctx context.Context, s *service.Service) (uint64, error) {
+				name := fs.String("name", "", "subject name")
+				description := fs.String("description", "", "subject description")
+				return func(ctx context.Context, s *service.Service) (uint64, error) {
+					return s.Subjects.Create(ctx, domain.Subject{Name: *name, Description: *description})
+				}
+			},
+		},
+		delete: byID(func(s *service.Service) func(context.Context, uint64) error {
+			return s.Subjects.Delete
+		}),
+	},
+	{
+		name:    "employees-subjects",
+		help:    "subjects known by teachers",
+		columns: []string{"employee_id", "subject_id"},
+		shortcuts: map[string]string{
+			"employee": "employee_id",
+			"subject":  "subject_id",
+		},
+		list: func(ctx context.Context, s *service.Service, opts repository.ListOptions) ([][]any, uint64, error) {
+			page, err := s.EmployeesSubjects.List(ctx, opts)
+			rows := make([][]any, len(page.Items))
+			for i, es := range page.Items {
+				rows[i] = []any{es.EmployeeID, es.SubjectID}
+			}
+			return rows, page.Total, err
+		},
+		add: mutation{
+			required: []string{"employee", "subject"},
+			bind: func(fs *flag.FlagSet) func(ctx context.Context, s *service.Service) (uint64, error) {
+				employee := fs.Uint64("employee", 0, "teacher id")
+				subject := fs.Uint64("subject", 0, "subject id")
+				return func(ctx context.Context, s *service.Service) (uint64, error) {
+					return 0, s.EmployeesSubjects.Create(ctx, domain.EmployeeSubject{
+						EmployeeID: *employee,
+						SubjectID:  *subject,
+					})
+				}
+			},
+		},
+		delete: mutation{
+			required: []string{"employee", "subject"},
+			bind: func(fs *flag.FlagSet) func(ctx context.Context, s *service.Service) (uint64, error) {
+				employee := fs.Uint64("employee", 0, "teacher id")
+				subject := fs.Uint64("subject", 0, "subject id")
+				return func(ctx context.Context, s *service.Service) (uint64, error) {
+					return 0, s.EmployeesSubjects.Delete(ctx, *employee, *subject)
+				}
+			},
+		},
+	},
+	{
+		name:    "audit",
+		help:    "change log, read-only",
+		columns: []string{"id", "entity", "record_id", "operation", "operator", "changed_at", "old_values", "new_values"},
+		shortcuts: map[string]string{
+			"entity":   "entity",
+			"record":   "record_id",
+			"operator": "operator",
+		},
+		list: func(ctx context.Context, s *service.Service, opts repository.ListOptions) ([][]any, uint64, error) {
+			page, err := s.Audit.List(ctx, opts)
+			rows := make([][]any, len(page.Items))
+			for i, e := range page.Items {
+				rows[i] = []any{e.ID, e.Entity, e.RecordID, e.Operation, e.Operator, e.ChangedAt.Format(time.RFC3339), string(e.OldValues), string(e.NewValues)}
+			}
+			return rows, page.Total, err
+		},
+	},
+}
+
+// empty for records that aren't deleted
+func deletedAt(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// mutation taking only --id, used by delete and restore commands
+func byID(method func(s *service.Service) func(context.Context, uint64) error) mutation {
+	return mutation{
+		required: []string{"id"},
+		bind: func(fs *flag.FlagSet) func(ctx context.Context, s *service.Service) (uint64, error) {
+			id := fs.Uint64("id", 0, "id of the record")
+			return func(ctx context.Context, s *service.Service) (uint64, error) {
+				return 0, method(s)(ctx, *id)
+			}
+		},
+	}
+}
+
+func entityGroup(e entity) group {
+	g := group{
+		name: e.name,
+		help: e.help,
+		commands: []command{
+			{
+				name:  "list",
+				usage: "[--filter field<op>value]... [--sort [-]field,...] [--limit n] [--offset n]",
+				help:  "prints records matching the filters",
+				run: func(ctx context.Context, env Env, args []string) error {
+					return runList(ctx, env, e, args)
+				},
+			},
+		},
+	}
+	if e.add.bind == nil {
+		return g
+	}
+
+	g.commands = append(g.commands,
+		command{
+			name:  "add",
+			usage: "--" + strings.Join(e.add.required, " ... --") + " ...",
+			help:  "creates a record",
+			run: func(ctx context.Context, env Env, args []string) error {
+				return runMutation(ctx, env, e.name+" add", e.add, args, "created")
+			},
+		},
+		command{
+			name:  "delete",
+			usage: "--" + strings.Join(e.delete.required, " ... --") + " ...",
+			help:  "deletes a record",
+			run: func(ctx context.Context, env Env, args []string) error {
+				return runMutation(ctx, env, e.name+" delete", e.delete, args, "deleted")
+			},
+		},
+	)
+	if e.restore.bind == nil {
+		return g
+	}
+
+	g.commands = append(g.commands, command{
+		name:  "restore",
+		usage: "--id id",
+		help:  "brings a deleted record back",
+		run: func(ctx context.Context, env Env, args []string) error {
+			return runMutation(ctx, env, e.name+" restore", e.restore, args, "restored")
+		},
+	})
+	return g
+}
+
+func runList(ctx context.Context, env Env, e entity, args []string) error {
+	fs, out := newFlagSet(env, e.name+" list")
+
+	var filters, sorts multiFlag
+	fs.Var(&filters, "filter", "filter as field<op>value, op is one of = != < <= > >= ~ (contains); repeatable")
+	fs.Var(&sorts, "sort", "comma separated fields, prefix a field with - for descending order")
+	limit := fs.Uint64("limit", 0, "maximum number of rows, 0 for all")
+	offset := fs.Uint64("offset", 0, "number of rows to skip")
+
+	var deleted *string
+	if e.restore.bind != nil {
+		deleted = fs.String("deleted", "", "include to list deleted records too, only to list just them")
+	}
+
+	shortcuts := make(map[string]*string, len(e.shortcuts))
+	for name, field := range e.shortcuts {
+		shortcuts[name] = fs.String(name, "", "only rows with this "+field)
+	}
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+
+	opts := repository.ListOptions{Limit: *limit, Offset: *offset}
+	if deleted != nil {
+		opts.Deleted = repository.Deleted(*deleted)
+	}
+	for name, value := range shortcuts {
+		if *value != "" {
+			opts.Filters = append(opts.Filters, repository.Filter{
+				Field: e.shortcuts[name],
+				Op:    repository.OpEq,
+				Value: *value,
+			})
+		}
+	}
+	for _, f := range filters {
+		filter, err := parseFilter(f)
+		if err != nil {
+			return err
+		}
+		opts.Filters = append(opts.Filters, filter)
+	}
+	for _, s := range sorts {
+		for _, field := range strings.Split(s, ",") {
+			if field == "" {
+				continue
+			}
+			desc := strings.HasPrefix(field, "-")
+			opts.Sort = append(opts.Sort, repository.Sort{Field: strings.TrimPrefix(field, "-"), Desc: desc})
+		}
+	}
+
+	rows, total, err := e.list(ctx, env.Service(), opts)
+	if err != nil {
+		return err
+	}
+
+	if err = out.write(env, table{title: e.name, headers: e.columns, rows: rows}); err != nil {
+		return err
+	}
+	if out.format == formatTable && out.file == "" {
+		fmt.Fprintf(env.Stderr, "%d of %d rows\n", len(rows), total)
+	}
+	return nil
+}
+
+func runMutation(ctx context.Context, env Env, name string, m mutation, args []string, done string) error {
+	fs, _ := newFlagSet(env, name)
+	action := m.bind(fs)
+
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, m.required...); err != nil {
+		return err
+	}
+
+	id, err := action(ctx, env.Service())
+	if err != nil {
+		return err
+	}
+
+	if id > 0 {
+		fmt.Fprintln(env.Stdout, done, id)
+	} else {
+		fmt.Fprintln(env.Stdout, done)
+	}
+	return nil
+}
+
+// operators accepted by --filter, two character ones first
+var filterOps = []struct {
+	token string
+	op    repository.FilterOp
+}{
+	{"!=", repository.OpNe},
+	{"<=", repository.OpLte},
+	{">=", repository.OpGte},
+	{"=", repository.OpEq},
+	{"<", repository.OpLt},
+	{">", repository.OpGt},
+	{"~", repository.OpContains},
+}
+
+// parses "field<op>value", field names only contain letters, digits and underscores
+func parseFilter(s string) (repository.Filter, error) {
+	i := strings.IndexFunc(s, func(r rune) bool {
+		return !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_')
+	})
+	if i <= 0 {
+		return repository.Filter{}, usagef("invalid filter %q, expected field<op>value", s)
+	}
+
+	for _, o := range filterOps {
+		if strings.HasPrefix(s[i:], o.token) {
+			return repository.Filter{
+				Field: s[:i],
+				Op:    o.op,
+				Value: s[i+len(o.token):],
+			}, nil
+		}
+	}
+
+	return repository.Filter{}, usagef("invalid filter %q, unknown operator", s)
+}
+
+// flag value collecting every occurrence of a repeated flag
+type multiFlag []string
+
+func (m *multiFlag) String() string {
+	return strings.Join(*m, " ")
+}
+
+func (m *multiFlag) Set(value string) error {
+	*m = append(*m, value)
+	return nil
+}
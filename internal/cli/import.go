@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"university-db-admin/internal/importer"
+)
+
+func importGroup() group {
+	g := group{
+		name: "import",
+		help: "loads records from a .csv or .xlsx file",
+	}
+
+	for _, kind := range importer.Kinds {
+		g.commands = append(g.commands, command{
+			name:  string(kind),
+			usage: "--file path [--dry-run] [--skip-invalid]",
+			help:  "imports " + string(kind),
+			run: func(ctx context.Context, env Env, args []string) error {
+				return runImport(ctx, env, kind, args)
+			},
+		})
+	}
+	return g
+}
+
+func runImport(ctx context.Context, env Env, kind importer.Kind, args []string) error {
+	fs, out := newFlagSet(env, "import "+string(kind))
+	file := fs.String("file", "", "path to a .csv or .xlsx file")
+	dryRun := fs.Bool("dry-run", false, "only check the rows")
+	skipInvalid := fs.Bool("skip-invalid", false, "import valid rows even if some rows are invalid")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "file"); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+
+	sheet, err := importer.ReadFile(*file)
+	if err != nil {
+		return err
+	}
+
+	preview, err := importer.New(env.Service()).Preview(ctx, kind, sheet)
+	if err != nil {
+		return err
+	}
+
+	t := table{title: "invalid " + string(kind), headers: []string{"line", "error"}}
+	for _, row := range preview.Rows {
+		if row.Err != nil {
+			t.rows = append(t.rows, []any{row.Line, row.Err.Error()})
+		}
+	}
+	if len(t.rows) > 0 {
+		if err = out.write(env, t); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(env.Stderr, "%d valid and %d invalid rows\n", preview.Valid(), preview.Invalid())
+
+	if *dryRun {
+		if preview.Invalid() > 0 {
+			return fmt.Errorf("file has invalid rows")
+		}
+		return nil
+	}
+	if preview.Invalid() > 0 && !*skipInvalid {
+		return fmt.Errorf("nothing imported, fix the invalid rows or pass --skip-invalid")
+	}
+
+	n, err := preview.Commit(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(env.Stderr, "imported %d rows\n", n)
+	return nil
+}
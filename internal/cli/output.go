@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"text/tabwriter"
+	"university-db-admin/internal/export"
+)
+
+// supported output formats
+const (
+	formatTable = "table"
+	formatJSON  = "json"
+	formatCSV   = "csv"
+	formatXLSX  = "xlsx"
+	formatPDF   = "pdf"
+)
+
+const dateLayout = "2006-01-02"
+
+// result of a command, cells keep their go types so json output
+// can tell numbers from strings
+type table struct {
+	title   string // used by xlsx and pdf output
+	headers []string
+	rows    [][]any
+}
+
+func writeTable(w io.Writer, format string, t table) error {
+	switch format {
+	case formatTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		writeTabRow(tw, stringCells(t.headers))
+		for _, row := range t.rows {
+			writeTabRow(tw, row)
+		}
+		return tw.Flush()
+	case formatJSON:
+		objects := make([]map[string]any, len(t.rows))
+		for i, row := range t.rows {
+			objects[i] = make(map[string]any, len(t.headers))
+			for j, h := range t.headers {
+				objects[i][h] = row[j]
+			}
+		}
+
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(objects)
+	case formatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(t.headers); err != nil {
+			return err
+		}
+		for _, row := range t.rows {
+			record := make([]string, len(row))
+			for i, cell := range row {
+				record[i] = fmt.Sprint(cell)
+			}
+			if err := cw.Write(record); err != nil {
+				return err
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	case formatXLSX, formatPDF:
+		rows := make([][]string, len(t.rows))
+		for i, row := range t.rows {
+			rows[i] = make([]string, len(row))
+			for j, cell := range row {
+				rows[i][j] = fmt.Sprint(cell)
+			}
+		}
+		return export.Write(w, export.Format(format), export.Table{Title: t.title, Headers: t.headers, Rows: rows})
+	}
+
+	return usagef("unknown output format %q", format)
+}
+
+func writeTabRow(w io.Writer, row []any) {
+	for i, cell := range row {
+		if i > 0 {
+			fmt.Fprint(w, "\t")
+		}
+		fmt.Fprint(w, cell)
+	}
+	fmt.Fprintln(w)
+}
+
+func stringCells(values []string) []any {
+	cells := make([]any, len(values))
+	for i, v := range values {
+		cells[i] = v
+	}
+	return cells
+}
+
+// output flags shared by all subcommands
+type output struct {
+	format string
+	file   string
+}
+
+// checks the output flags before any work is done, the format
+// defaults to the extension of the output file or to table
+func (o *output) validate() error {
+	if o.format == "" {
+		o.format = formatTable
+		if o.file != "" {
+			format, err := export.FormatOf(o.file)
+			if err != nil {
+				return usageError{msg: err.Error()}
+			}
+			o.format = string(format)
+		}
+	}
+
+	switch o.format {
+	case formatTable, formatJSON, formatCSV:
+		return nil
+	case formatXLSX, formatPDF:
+		if o.file == "" {
+			return usagef("format %s is binary and needs --output", o.format)
+		}
+		return nil
+	}
+	return usagef("unknown output format %q", o.format)
+}
+
+// writes t to the output file, or to stdout when none is given
+func (o *output) write(env Env, t table) error {
+	if o.file == "" {
+		return writeTable(env.Stdout, o.format, t)
+	}
+
+	f, err := os.Create(o.file)
+	if err != nil {
+		return err
+	}
+	if err = writeTable(f, o.format, t); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(env.Stderr, "written %d rows to %s\n", len(t.rows), o.file)
+	return nil
+}
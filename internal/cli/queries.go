@@ -0,0 +1,196 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"university-db-admin/internal/schedule"
+	"university-db-admin/internal/special"
+)
+
+func queryGroup() group {
+	return group{
+		name: "query",
+		help: "predefined reports",
+		commands: []command{
+			{
+				name: "list",
+				help: "prints the available queries",
+				run:  runQueryList,
+			},
+			{
+				name:  "run",
+				usage: "<query> [flags]",
+				help:  "runs a query",
+				run:   runQuery,
+			},
+		},
+	}
+}
+
+func runQueryList(ctx context.Context, env Env, args []string) error {
+	fs, out := newFlagSet(env, "query list")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+
+	t := table{title: "queries", headers: []string{"name", "flags", "description"}}
+	for _, q := range special.Queries {
+		flags := make([]string, len(q.Params))
+		for i, p := range q.Params {
+			flags[i] = "--" + p.Name
+		}
+		t.rows = append(t.rows, []any{q.Name, strings.Join(flags, " "), q.Help})
+	}
+
+	return out.write(env, t)
+}
+
+func runQuery(ctx context.Context, env Env, args []string) error {
+	if len(args) == 0 {
+		return usagef("query name is required, see \"query list\"")
+	}
+
+	q, ok := special.Find(args[0])
+	if !ok {
+		return usagef("unknown query %q, see \"query list\"", args[0])
+	}
+
+	fs, out := newFlagSet(env, "query run "+q.Name)
+	values := make(map[string]*string, len(q.Params))
+	for _, p := range q.Params {
+		values[p.Name] = fs.String(p.Name, "", p.Help)
+	}
+	if err := parseFlags(fs, args[1:]); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+
+	queryArgs := make(special.Args, len(values))
+	for name, value := range values {
+		queryArgs[name] = *value
+	}
+	if err := q.Validate(queryArgs); err != nil {
+		return usageError{msg: err.Error()}
+	}
+
+	res, err := q.Run(ctx, env.Service(), queryArgs)
+	if err != nil {
+		return err
+	}
+	return out.write(env, table{title: q.Name, headers: res.Columns, rows: res.Rows})
+}
+
+func scheduleGroup() group {
+	return group{
+		name: "schedule",
+		help: "lesson schedule",
+		commands: []command{
+			{
+				name:  "show",
+				usage: "[--week n] [--group number]",
+				help:  "prints the schedule with group numbers, subjects, teachers and pair times",
+				run:   runScheduleShow,
+			},
+			{
+				name:  "conflicts",
+				usage: "[--fail]",
+				help:  "prints lessons sharing a room, a group or a teacher at the same time",
+				run:   runScheduleConflicts,
+			},
+			{
+				name: "bells",
+				help: "prints the pairs of the bell schedule set by BELL_SCHEDULE",
+				run:  runScheduleBells,
+			},
+		},
+	}
+}
+
+func runScheduleShow(ctx context.Context, env Env, args []string) error {
+	fs, out := newFlagSet(env, "schedule show")
+	week := fs.Uint("week", 0, "only lessons of this week")
+	group := fs.Uint64("group", 0, "only lessons of the group with this number")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+
+	s := env.Service()
+	lessons, err := s.Schedule.FindSchedule(ctx)
+	if err != nil {
+		return err
+	}
+	bells := s.Schedule.Bells()
+
+	t := table{title: "schedule", headers: []string{"group_number", "subject", "lesson_type", "teacher", "room", "week", "weekday", "pair", "time"}}
+	for _, l := range lessons {
+		if *week != 0 && uint(l.Week) != *week {
+			continue
+		}
+		if *group != 0 && l.GroupNumber != *group {
+			continue
+		}
+		t.rows = append(t.rows, []any{l.GroupNumber, l.Subject, l.LessonType, l.Teacher, l.Room, l.Week, l.Weekday, l.Pair, bells.Time(l.Pair)})
+	}
+
+	if len(t.rows) == 0 && out.format == formatTable && out.file == "" {
+		fmt.Fprintln(env.Stderr, "no lessons found")
+	}
+	return out.write(env, t)
+}
+
+func runScheduleBells(ctx context.Context, env Env, args []string) error {
+	fs, out := newFlagSet(env, "schedule bells")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+
+	t := table{title: "bells", headers: []string{"pair", "start", "end"}}
+	for i, b := range env.Service().Schedule.Bells() {
+		t.rows = append(t.rows, []any{i + 1, schedule.Clock(b.Start), schedule.Clock(b.End)})
+	}
+	return out.write(env, t)
+}
+
+func runScheduleConflicts(ctx context.Context, env Env, args []string) error {
+	fs, out := newFlagSet(env, "schedule conflicts")
+	fail := fs.Bool("fail", false, "exit with an error when there are conflicts")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+
+	conflicts, err := env.Service().Schedule.Conflicts(ctx)
+	if err != nil {
+		return err
+	}
+
+	t := table{title: "conflicts", headers: []string{"kind", "lesson_id", "other_id", "week", "weekday", "pair", "room", "group_id", "employee_id", "explanation"}}
+	for _, c := range conflicts {
+		t.rows = append(t.rows, []any{string(c.Kind), c.LessonID, c.OtherID, c.Week, c.Weekday, c.Pair, c.Room, c.GroupID, c.EmployeeID, c.String()})
+	}
+
+	if len(t.rows) == 0 && out.format == formatTable && out.file == "" {
+		fmt.Fprintln(env.Stderr, "no conflicts found")
+	}
+	if err := out.write(env, t); err != nil {
+		return err
+	}
+	if *fail && len(conflicts) > 0 {
+		return fmt.Errorf("%d schedule conflicts found", len(conflicts))
+	}
+	return nil
+}
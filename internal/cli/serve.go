@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"context"
+	"university-db-admin/internal/api"
+)
+
+func serveGroup() group {
+	return group{
+		name: "serve",
+		help: "runs the REST API server",
+		commands: []command{
+			{
+				usage: "[--addr host:port]",
+				help:  "serves the REST API until interrupted, clients log in with Basic auth",
+				run:   runServe,
+			},
+		},
+	}
+}
+
+func runServe(ctx context.Context, env Env, args []string) error {
+	fs, _ := newFlagSet(env, "serve")
+	addr := fs.String("addr", "127.0.0.1:8080", "address to listen on")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+
+	return api.ListenAndServe(ctx, *addr, api.NewServer(env.Login))
+}
@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"strings"
+	"university-db-admin/internal/domain"
+)
+
+func userGroup() group {
+	roles := make([]string, len(domain.Roles))
+	for i, r := range domain.Roles {
+		roles[i] = string(r)
+	}
+
+	return group{
+		name: "user",
+		help: "accounts of the desktop application",
+		commands: []command{
+			{
+				name: "list",
+				help: "prints the accounts",
+				run:  runUserList,
+			},
+			{
+				name:  "add",
+				usage: "--login name --role " + strings.Join(roles, "|") + " [--employee id] [--password text]",
+				help:  "creates an account, the password is read from stdin unless given",
+				run:   runUserAdd,
+			},
+			{
+				name:  "passwd",
+				usage: "--login name [--password text]",
+				help:  "changes the password of an account",
+				run:   runUserPasswd,
+			},
+			{
+				name:  "role",
+				usage: "--login name --role " + strings.Join(roles, "|") + " [--employee id]",
+				help:  "changes the role of an account",
+				run:   runUserRole,
+			},
+			{
+				name:  "delete",
+				usage: "--login name",
+				help:  "deletes an account",
+				run:   runUserDelete,
+			},
+		},
+	}
+}
+
+func runUserList(ctx context.Context, env Env, args []string) error {
+	fs, out := newFlagSet(env, "user list")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := out.validate(); err != nil {
+		return err
+	}
+
+	users, err := env.Service().Users.FindAll(ctx)
+	if err != nil {
+		return err
+	}
+
+	t := table{title: "users", headers: []string{"id", "login", "role", "employee_id"}}
+	for _, u := range users {
+		var employee uint64 // 0 like the curator of a student without one
+		if u.EmployeeID != nil {
+			employee = *u.EmployeeID
+		}
+		t.rows = append(t.rows, []any{u.ID, u.Login, string(u.Role), employee})
+	}
+	return out.write(env, t)
+}
+
+func runUserAdd(ctx context.Context, env Env, args []string) error {
+	fs, _ := newFlagSet(env, "user add")
+	login := fs.String("login", "", "login of the account")
+	role := fs.String("role", "", "role of the account")
+	employee := fs.Uint64("employee", 0, "employee record of a teacher or of the dean's office")
+	password := fs.String("password", "", "password, read from stdin when omitted")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "login", "role"); err != nil {
+		return err
+	}
+	if err := readPassword(env, fs, password); err != nil {
+		return err
+	}
+
+	id, err := env.Service().Users.Create(ctx, *login, *password, domain.Role(*role), employeeID(*employee))
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(env.Stdout, "created", id)
+	return nil
+}
+
+func runUserPasswd(ctx context.Context, env Env, args []string) error {
+	fs, _ := newFlagSet(env, "user passwd")
+	login := fs.String("login", "", "login of the account")
+	password := fs.String("password", "", "new password, read from stdin when omitted")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "login"); err != nil {
+		return err
+	}
+	if err := readPassword(env, fs, password); err != nil {
+		return err
+	}
+
+	if err := env.Service().Users.SetPassword(ctx, *login, *password); err != nil {
+		return err
+	}
+	fmt.Fprintln(env.Stdout, "updated")
+	return nil
+}
+
+func runUserRole(ctx context.Context, env Env, args []string) error {
+	fs, _ := newFlagSet(env, "user role")
+	login := fs.String("login", "", "login of the account")
+	role := fs.String("role", "", "new role of the account")
+	employee := fs.Uint64("employee", 0, "employee record of a teacher or of the dean's office")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "login", "role"); err != nil {
+		return err
+	}
+
+	if err := env.Service().Users.SetRole(ctx, *login, domain.Role(*role), employeeID(*employee)); err != nil {
+		return err
+	}
+	fmt.Fprintln(env.Stdout, "updated")
+	return nil
+}
+
+func runUserDelete(ctx context.Context, env Env, args []string) error {
+	fs, _ := newFlagSet(env, "user delete")
+	login := fs.String("login", "", "login of the account")
+	if err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := requireFlags(fs, "login"); err != nil {
+		return err
+	}
+
+	if err := env.Service().Users.DeleteByLogin(ctx, *login); err != nil {
+		return err
+	}
+	fmt.Fprintln(env.Stdout, "deleted")
+	return nil
+}
+
+// reads the password from the first line of stdin unless --password is set,
+// so it doesn't have to appear in the shell history
+func readPassword(env Env, fs *flag.FlagSet, password *string) error {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		set = set || f.Name == "password"
+	})
+	if set {
+		return nil
+	}
+
+	line, err := bufio.NewReader(env.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return usagef("password is required, pass --password or write it to stdin")
+	}
+	*password = strings.TrimRight(line, "\r\n")
+	return nil
+}
+
+// 0 stands for no employee record
+func employeeID(id uint64) *uint64 {
+	if id == 0 {
+		return nil
+	}
+	return &id
+}
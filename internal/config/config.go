@@ -2,28 +2,87 @@ package config
 
 import (
 	"log"
+	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
 )
 
 type DatabaseConfig struct {
-	Host     string `env:"DB_HOST"`
-	Port     string `env:"DB_PORT"`
-	Name     string `env:"DB_NAME"`
-	User     string `env:"DB_USER"`
-	Password string `env:"DB_PASS"`
+	Host        string `env:"DB_HOST"`
+	Port        string `env:"DB_PORT"`
+	Name        string `env:"DB_NAME"`
+	User        string `env:"DB_USER"`
+	Password    string `env:"DB_PASS"`
+	AutoMigrate bool   `env:"DB_AUTO_MIGRATE" env-default:"false"`
+
+	// connection pool settings
+	MinConns          int32         `env:"DB_MIN_CONNS" env-default:"1"`
+	MaxConns          int32         `env:"DB_MAX_CONNS" env-default:"10"`
+	MaxConnLifetime   time.Duration `env:"DB_MAX_CONN_LIFETIME" env-default:"1h"`
+	MaxConnIdleTime   time.Duration `env:"DB_MAX_CONN_IDLE_TIME" env-default:"30m"`
+	HealthCheckPeriod time.Duration `env:"DB_HEALTH_CHECK_PERIOD" env-default:"30s"`
+	ConnectTimeout    time.Duration `env:"DB_CONNECT_TIMEOUT" env-default:"5s"`
+}
+
+// storage backends selectable with APP_BACKEND
+const (
+	BackendPostgres = "postgres"
+	BackendDemo     = "demo" // in-memory database filled with sample data, changes are lost on exit
+)
+
+// timeouts of the queries run from the UI, long queries are imports,
+// exports and backups going through whole tables. The queries of single
+// forms can be given their own, like marks:1m,export:30m
+type UIConfig struct {
+	QueryTimeout     time.Duration            `env:"UI_QUERY_TIMEOUT" env-default:"30s"`
+	LongQueryTimeout time.Duration            `env:"UI_LONG_QUERY_TIMEOUT" env-default:"10m"`
+	FormTimeouts     map[string]time.Duration `env:"UI_FORM_TIMEOUTS"`
 }
 
 type Config struct {
-	DB DatabaseConfig
+	Backend string `env:"APP_BACKEND" env-default:"postgres"`
+	DB      DatabaseConfig
+	UI      UIConfig
+
+	// JSON file with special queries added to the built-in ones
+	SpecialQueriesFile string `env:"SPECIAL_QUERIES_FILE"`
+
+	// pairs of the day like 08:00-09:35,09:45-11:20, the default
+	// schedule is used when empty
+	BellSchedule string `env:"BELL_SCHEDULE"`
 }
 
 var cfg *Config = &Config{}
 
 func LoadConfig() *Config {
-	log.Println("reading database config")
-	if err := cleanenv.ReadConfig(".env", &cfg.DB); err != nil {
-		log.Fatal("cant get database config: ", err)
+	log.Println("reading config")
+	if err := cleanenv.ReadConfig(".env", cfg); err != nil {
+		log.Fatal("cant get config: ", err)
 	}
 	return cfg
 }
+
+// SpecialQueriesFile reads SPECIAL_QUERIES_FILE without the rest of the config,
+// commands listing the special queries don't need the .env file
+func SpecialQueriesFile() string {
+	var c struct {
+		File string `env:"SPECIAL_QUERIES_FILE"`
+	}
+	if err := cleanenv.ReadConfig(".env", &c); err != nil {
+		cleanenv.ReadEnv(&c)
+	}
+	return c.File
+}
+
+// Credentials reads the account the command line tool acts as from
+// APP_LOGIN and APP_PASSWORD, taken from .env like the rest of the config
+func Credentials() (login, password string) {
+	var c struct {
+		Login    string `env:"APP_LOGIN"`
+		Password string `env:"APP_PASSWORD"`
+	}
+	if err := cleanenv.ReadConfig(".env", &c); err != nil {
+		cleanenv.ReadEnv(&c)
+	}
+	return c.Login, c.Password
+}
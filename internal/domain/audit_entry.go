@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// AuditEntry is one row change recorded by the database, OldValues is
+// null for created records and NewValues is null for deleted ones
+type AuditEntry struct {
+	ID        uint64          `json:"id"`
+	Entity    string          `json:"entity"`
+	RecordID  string          `json:"record_id"`
+	Operation string          `json:"operation"`
+	OldValues json.RawMessage `json:"old_values"`
+	NewValues json.RawMessage `json:"new_values"`
+	Operator  string          `json:"operator"`
+	ChangedAt time.Time       `json:"changed_at"`
+}
@@ -1,6 +1,6 @@
 package domain
 
 type EmployeeSubject struct {
-	EmployeeID uint64 `validate:"required,gt=0"`
-	SubjectID  uint64 `validate:"required,gt=0"`
+	EmployeeID uint64 `json:"employee_id" validate:"required,gt=0"`
+	SubjectID  uint64 `json:"subject_id" validate:"required,gt=0"`
 }
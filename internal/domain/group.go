@@ -1,6 +1,9 @@
 package domain
 
+import "time"
+
 type Group struct {
-	ID     uint64 `validate:"gte=0"`
-	Number uint64 `validate:"required,gt=0"`
+	ID        uint64     `json:"id" validate:"gte=0"`
+	Number    uint64     `json:"number" validate:"required,gt=0"`
+	DeletedAt *time.Time `json:"deleted_at,omitempty"` // set while the record is in the trash
 }
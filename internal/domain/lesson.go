@@ -1,11 +1,15 @@
 package domain
 
 type Lesson struct {
-	ID           uint64 `validate:"gte=0"`
-	GroupID      uint64 `validate:"required,gt=0"`
-	SubjectID    uint64 `validate:"required,gt=0"`
-	LessonTypeID uint64 `validate:"required,gt=0"`
-	Week         uint16 `validate:"required,gt=0"`
-	Weekday      uint16 `validate:"required,gt=0"`
-	Room         uint64 `validate:"required,gt=0"`
+	ID           uint64 `json:"id" validate:"gte=0"`
+	GroupID      uint64 `json:"group_id" validate:"required,gt=0"`
+	SubjectID    uint64 `json:"subject_id" validate:"required,gt=0"`
+	LessonTypeID uint64 `json:"lesson_type_id" validate:"required,gt=0"`
+	Week         uint16 `json:"week" validate:"required,gt=0"`
+	Weekday      uint16 `json:"weekday" validate:"required,gt=0"`
+	Pair         uint16 `json:"pair" validate:"required,gt=0"`
+	Room         uint64 `json:"room" validate:"required,gt=0"`
+	// teacher of the lesson, one linked to its subject, lessons
+	// scheduled before teachers were assigned have none
+	EmployeeID *uint64 `json:"employee_id,omitempty" validate:"omitempty,gt=0"`
 }
@@ -1,6 +1,6 @@
 package domain
 
 type LessonType struct {
-	ID   uint64 `validate:"gte=0"`
-	Name string `validate:"required,len=2"`
+	ID   uint64 `json:"id" validate:"gte=0"`
+	Name string `json:"name" validate:"required,len=2"`
 }
@@ -3,10 +3,10 @@ package domain
 import "time"
 
 type Mark struct {
-	ID         uint64    `validate:"gte=0"`
-	EmployeeID uint64    `validate:"required,gt=0"`
-	StudentID  uint64    `validate:"required,gt=0"`
-	SubjectID  uint64    `validate:"required,gt=0"`
-	Mark       uint16    `validate:"required,gt=0"`
-	Date       time.Time `validate:"required"`
+	ID         uint64    `json:"id" validate:"gte=0"`
+	EmployeeID uint64    `json:"employee_id" validate:"required,gt=0"`
+	StudentID  uint64    `json:"student_id" validate:"required,gt=0"`
+	SubjectID  uint64    `json:"subject_id" validate:"required,gt=0"`
+	Mark       uint16    `json:"mark" validate:"required,gt=0"`
+	Date       time.Time `json:"date" validate:"required"`
 }
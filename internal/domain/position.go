@@ -1,6 +1,36 @@
 package domain
 
+// Position is a staff position, what its holders may do is decided
+// by the capabilities rather than by the name
 type Position struct {
-	ID   uint64 `validate:"gte=0"`
-	Name string `validate:"required,min=1"`
+	ID            uint64 `json:"id" validate:"gte=0"`
+	Name          string `json:"name" validate:"required,min=1"`
+	CanTeach      bool   `json:"can_teach"`      // give marks and know subjects
+	CanCurate     bool   `json:"can_curate"`     // curate students
+	CanAdminister bool   `json:"can_administer"` // work in the dean's office
+}
+
+// Capability names a capability flag of a position after its column
+type Capability string
+
+const (
+	CapabilityTeach      Capability = "can_teach"
+	CapabilityCurate     Capability = "can_curate"
+	CapabilityAdminister Capability = "can_administer"
+)
+
+// Capabilities lists every capability in the order of the columns
+var Capabilities = []Capability{CapabilityTeach, CapabilityCurate, CapabilityAdminister}
+
+// Can tells whether holders of the position have the capability
+func (p Position) Can(c Capability) bool {
+	switch c {
+	case CapabilityTeach:
+		return p.CanTeach
+	case CapabilityCurate:
+		return p.CanCurate
+	case CapabilityAdminister:
+		return p.CanAdminister
+	}
+	return false
 }
@@ -1,9 +1,12 @@
 package domain
 
+import "time"
+
 type Student struct {
-	ID         uint64 `validate:"gte=0"`
-	Name       string `validate:"required,min=5"`
-	Passport   string `validate:"required,len=9"`
-	EmployeeID uint64 `validate:"required,gt=0"`
-	GroupID    uint64 `validate:"required,gt=0"`
+	ID         uint64     `json:"id" validate:"gte=0"`
+	Name       string     `json:"name" validate:"required,min=5"`
+	Passport   string     `json:"passport" validate:"required,len=9"`
+	EmployeeID uint64     `json:"employee_id" validate:"required,gt=0"`
+	GroupID    uint64     `json:"group_id" validate:"required,gt=0"`
+	DeletedAt  *time.Time `json:"deleted_at,omitempty"` // set while the record is in the trash
 }
@@ -1,7 +1,7 @@
 package domain
 
 type Subject struct {
-	ID          uint64 `validate:"gte=0"`
-	Name        string `validate:"required,min=1"`
-	Description string `validate:"required,min=1"`
+	ID          uint64 `json:"id" validate:"gte=0"`
+	Name        string `json:"name" validate:"required,min=1"`
+	Description string `json:"description" validate:"required,min=1"`
 }
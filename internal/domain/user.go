@@ -0,0 +1,23 @@
+package domain
+
+// Role decides which changes a user of the application may make
+type Role string
+
+const (
+	RoleAdmin    Role = "admin"    // everything, including the console and restores
+	RoleDean     Role = "dean"     // the dean's office: students, groups, schedule and marks
+	RoleTeacher  Role = "teacher"  // marks of the subjects the linked employee teaches
+	RoleReadOnly Role = "readonly" // reading only
+)
+
+// Roles lists every role from the most to the least privileged
+var Roles = []Role{RoleAdmin, RoleDean, RoleTeacher, RoleReadOnly}
+
+// User is an account of the application, teachers are linked to their employee record
+type User struct {
+	ID           uint64  `json:"id" validate:"gte=0"`
+	Login        string  `json:"login" validate:"required,min=3,max=64"`
+	PasswordHash string  `json:"-" validate:"required"`
+	Role         Role    `json:"role" validate:"required,oneof=admin dean teacher readonly"`
+	EmployeeID   *uint64 `json:"employee_id,omitempty" validate:"omitempty,gt=0"`
+}
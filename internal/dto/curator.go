@@ -1,8 +1,8 @@
 package dto
 
 type StudentCuratorDTO struct {
-	StudentName     string
-	StudentPassport string
-	CuratorName     string
-	CuratorPassport string
+	StudentName     string `json:"student_name"`
+	StudentPassport string `json:"student_passport"`
+	CuratorName     string `json:"curator_name"`
+	CuratorPassport string `json:"curator_passport"`
 }
@@ -1,14 +1,15 @@
 package dto
 
 type EmployeeDTO struct {
-	Name     string
-	Passport string
+	Name     string `json:"name"`
+	Passport string `json:"passport"`
 }
 
 type EmployeeRoleDTO struct {
-	IsTeacher bool
+	IsTeacher bool `json:"is_teacher"`
+	IsCurator bool `json:"is_curator"`
 }
 
 type EmployeePositionDTO struct {
-	Name string
+	Name string `json:"name"`
 }
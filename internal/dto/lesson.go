@@ -1,10 +1,12 @@
 package dto
 
 type LessonScheduleDTO struct {
-	GroupNumber uint64
-	Subject     string
-	LessonType  string
-	Room        uint64
-	Week        uint16
-	Weekday     uint16
+	GroupNumber uint64 `json:"group_number"`
+	Subject     string `json:"subject"`
+	LessonType  string `json:"lesson_type"`
+	Teacher     string `json:"teacher"` // empty for lessons without a teacher
+	Room        uint64 `json:"room"`
+	Week        uint16 `json:"week"`
+	Weekday     uint16 `json:"weekday"`
+	Pair        uint16 `json:"pair"`
 }
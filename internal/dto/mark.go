@@ -3,13 +3,13 @@ package dto
 import "time"
 
 type MarkBySubjectDTO struct {
-	StudentID uint64
-	Mark      uint16
-	Date      time.Time
+	StudentID uint64    `json:"student_id"`
+	Mark      uint16    `json:"mark"`
+	Date      time.Time `json:"date"`
 }
 
 type SortedMarkDTO struct {
-	StudentID uint64
-	Mark      uint16
-	Date      time.Time
+	StudentID uint64    `json:"student_id"`
+	Mark      uint16    `json:"mark"`
+	Date      time.Time `json:"date"`
 }
@@ -1,23 +1,23 @@
 package dto
 
 type StudentNoCuratorDTO struct {
-	Name     string
-	Passport string
-	GroupID  uint64
+	Name     string `json:"name"`
+	Passport string `json:"passport"`
+	GroupID  uint64 `json:"group_id"`
 }
 
 type StudentByNameDTO struct {
-	Name     string
-	Passport string
+	Name     string `json:"name"`
+	Passport string `json:"passport"`
 }
 
 type StudentGroupCombDTO struct {
-	StudentName string
-	GroupNumber uint64
+	StudentName string `json:"student_name"`
+	GroupNumber uint64 `json:"group_number"`
 }
 
 type StudentNameStatDTO struct {
-	ID            uint64
-	UppercaseName string
-	NameLength    uint64
+	ID            uint64 `json:"id"`
+	UppercaseName string `json:"uppercase_name"`
+	NameLength    uint64 `json:"name_length"`
 }
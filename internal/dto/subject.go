@@ -1,5 +1,5 @@
 package dto
 
 type SortedSubjectDTO struct {
-	Name string
+	Name string `json:"name"`
 }
@@ -0,0 +1,187 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+type Format string
+
+const (
+	CSV  Format = "csv"
+	XLSX Format = "xlsx"
+	JSON Format = "json"
+	PDF  Format = "pdf"
+)
+
+var Formats = []Format{CSV, XLSX, JSON, PDF}
+
+// Table is a result view as shown to the user, every row has one value per header
+type Table struct {
+	Title   string
+	Headers []string
+	Rows    [][]string
+}
+
+// returns the format matching the extension of path
+func FormatOf(path string) (Format, error) {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+	for _, f := range Formats {
+		if string(f) == ext {
+			return f, nil
+		}
+	}
+	return "", fmt.Errorf("unsupported export file type %q, expected csv, xlsx, json or pdf", filepath.Ext(path))
+}
+
+func Write(w io.Writer, format Format, t Table) error {
+	switch format {
+	case CSV:
+		return writeCSV(w, t)
+	case XLSX:
+		return writeXLSX(w, t)
+	case JSON:
+		return writeJSON(w, t)
+	case PDF:
+		return writePDF(w, t)
+	}
+	return fmt.Errorf("unsupported export format %q", format)
+}
+
+// writes t to path in the format given by its extension
+func WriteFile(path string, t Table) error {
+	format, err := FormatOf(path)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+
+	if err = Write(f, format, t); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// the byte order mark makes excel read the file as utf-8
+func writeCSV(w io.Writer, t Table) error {
+	if _, err := w.Write([]byte{0xEF, 0xBB, 0xBF}); err != nil {
+		return err
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(t.Headers); err != nil {
+		return err
+	}
+	if err := cw.WriteAll(t.Rows); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+func writeXLSX(w io.Writer, t Table) error {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	sheet := "Sheet1"
+	if t.Title != "" {
+		// sheet names are limited to 31 characters and can't contain some symbols
+		name := strings.NewReplacer(":", " ", "\\", " ", "/", " ", "?", " ", "*", " ", "[", " ", "]", " ").Replace(t.Title)
+		if r := []rune(name); len(r) > 31 {
+			name = string(r[:31])
+		}
+		if err := f.SetSheetName(sheet, name); err != nil {
+			return err
+		}
+		sheet = name
+	}
+
+	sw, err := f.NewStreamWriter(sheet)
+	if err != nil {
+		return err
+	}
+
+	bold, err := f.NewStyle(&excelize.Style{Font: &excelize.Font{Bold: true}})
+	if err != nil {
+		return err
+	}
+
+	header := make([]any, len(t.Headers))
+	for i, h := range t.Headers {
+		header[i] = excelize.Cell{StyleID: bold, Value: h}
+	}
+	if err = sw.SetRow("A1", header); err != nil {
+		return err
+	}
+
+	for i, row := range t.Rows {
+		values := make([]any, len(row))
+		for j, v := range row {
+			values[j] = v
+		}
+
+		cell, err := excelize.CoordinatesToCellName(1, i+2)
+		if err != nil {
+			return err
+		}
+		if err = sw.SetRow(cell, values); err != nil {
+			return err
+		}
+	}
+
+	if err = sw.Flush(); err != nil {
+		return err
+	}
+	return f.Write(w)
+}
+
+// writes an array of objects keyed by header, keys keep the column order
+func writeJSON(w io.Writer, t Table) error {
+	var buf bytes.Buffer
+	buf.WriteString("[")
+	for i, row := range t.Rows {
+		if i > 0 {
+			buf.WriteString(",")
+		}
+		buf.WriteString("\n  {")
+		for j, h := range t.Headers {
+			var value string
+			if j < len(row) {
+				value = row[j]
+			}
+			if j > 0 {
+				buf.WriteString(",")
+			}
+
+			key, err := json.Marshal(h)
+			if err != nil {
+				return err
+			}
+			val, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintf(&buf, "\n    %s: %s", key, val)
+		}
+		buf.WriteString("\n  }")
+	}
+	if len(t.Rows) > 0 {
+		buf.WriteString("\n")
+	}
+	buf.WriteString("]\n")
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
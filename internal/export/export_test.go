@@ -0,0 +1,84 @@
+package export_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"university-db-admin/internal/export"
+
+	"github.com/xuri/excelize/v2"
+)
+
+var marks = export.Table{
+	Title:   "Оценки: группа 101/2024",
+	Headers: []string{"студент", "оценка"},
+	Rows:    [][]string{{"Смирнова Анна", "8"}, {"Орлов, Борис", "10"}},
+}
+
+func write(t *testing.T, format export.Format) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	if err := export.Write(&buf, format, marks); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return buf.Bytes()
+}
+
+func TestWriteCSV(t *testing.T) {
+	want := "\ufeffстудент,оценка\nСмирнова Анна,8\n\"Орлов, Борис\",10\n"
+	if got := string(write(t, export.CSV)); got != want {
+		t.Fatalf("got %q, want %q", got, want)
+	}
+}
+
+// keys keep the order of the columns
+func TestWriteJSON(t *testing.T) {
+	got := write(t, export.JSON)
+	if i, j := bytes.Index(got, []byte("студент")), bytes.Index(got, []byte("оценка")); i > j {
+		t.Fatalf("columns out of order in %s", got)
+	}
+
+	var rows []map[string]string
+	if err := json.Unmarshal(got, &rows); err != nil {
+		t.Fatalf("invalid json %s: %v", got, err)
+	}
+	if len(rows) != 2 || rows[1]["студент"] != "Орлов, Борис" || rows[1]["оценка"] != "10" {
+		t.Fatalf("got rows %v", rows)
+	}
+}
+
+// the title names the sheet without the symbols excel forbids
+func TestWriteXLSX(t *testing.T) {
+	f, err := excelize.OpenReader(bytes.NewReader(write(t, export.XLSX)))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer f.Close()
+
+	sheet := "Оценки  группа 101 2024"
+	if name := f.GetSheetName(0); name != sheet {
+		t.Fatalf("got sheet %q, want %q", name, sheet)
+	}
+	rows, err := f.GetRows(sheet)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rows) != 3 || rows[0][0] != "студент" || rows[2][0] != "Орлов, Борис" {
+		t.Fatalf("got rows %v", rows)
+	}
+}
+
+func TestWritePDF(t *testing.T) {
+	if got := write(t, export.PDF); !bytes.HasPrefix(got, []byte("%PDF-")) {
+		t.Fatalf("got %.20q, want a pdf document", got)
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	if f, err := export.FormatOf("marks.XLSX"); err != nil || f != export.XLSX {
+		t.Fatalf("got %q, %v, want xlsx", f, err)
+	}
+	if _, err := export.FormatOf("marks.txt"); err == nil {
+		t.Fatal("got no error for a txt file")
+	}
+}
@@ -0,0 +1,128 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"time"
+	"unicode/utf8"
+
+	"fyne.io/fyne/v2/theme"
+	"github.com/jung-kurt/gofpdf"
+)
+
+// page layout of pdf exports in millimetres
+const (
+	pdfMargin     = 10.0
+	pdfRowHeight  = 6.0
+	pdfFontSize   = 9.0
+	pdfTitleSize  = 13.0
+	pdfMinColumn  = 15.0
+	pdfCellIndent = 1.0
+)
+
+// the core pdf fonts have no cyrillic glyphs, so the fonts
+// bundled with the ui toolkit are embedded instead
+const pdfFont = "noto"
+
+// writes a landscape A4 table, the header is repeated on every page
+func writePDF(w io.Writer, t Table) error {
+	pdf := gofpdf.New("L", "mm", "A4", "")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(false, pdfMargin)
+	pdf.AddUTF8FontFromBytes(pdfFont, "", theme.DefaultTextFont().Content())
+	pdf.AddUTF8FontFromBytes(pdfFont, "B", theme.DefaultTextBoldFont().Content())
+
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-pdfMargin)
+		pdf.SetFont(pdfFont, "", pdfFontSize)
+		pdf.CellFormat(0, pdfRowHeight, fmt.Sprintf("Страница %d", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+
+	pdf.AddPage()
+	pageWidth, pageHeight := pdf.GetPageSize()
+	widths := columnWidths(pdf, t, pageWidth-2*pdfMargin)
+
+	if t.Title != "" {
+		pdf.SetFont(pdfFont, "B", pdfTitleSize)
+		pdf.CellFormat(0, pdfRowHeight+2, t.Title, "", 1, "L", false, 0, "")
+	}
+	pdf.SetFont(pdfFont, "", pdfFontSize)
+	pdf.CellFormat(0, pdfRowHeight, "Сформировано "+time.Now().Format("2006-01-02 15:04"), "", 1, "L", false, 0, "")
+	pdf.Ln(2)
+
+	header := func() {
+		pdf.SetFont(pdfFont, "B", pdfFontSize)
+		pdf.SetFillColor(230, 230, 230)
+		for i, h := range t.Headers {
+			pdf.CellFormat(widths[i], pdfRowHeight, fitText(pdf, h, widths[i]), "1", 0, "L", true, 0, "")
+		}
+		pdf.Ln(-1)
+		pdf.SetFont(pdfFont, "", pdfFontSize)
+	}
+	header()
+
+	for _, row := range t.Rows {
+		if pdf.GetY()+pdfRowHeight > pageHeight-2*pdfMargin {
+			pdf.AddPage()
+			header()
+		}
+		for i := range t.Headers {
+			var value string
+			if i < len(row) {
+				value = row[i]
+			}
+			pdf.CellFormat(widths[i], pdfRowHeight, fitText(pdf, value, widths[i]), "1", 0, "L", false, 0, "")
+		}
+		pdf.Ln(-1)
+	}
+
+	return pdf.Output(w)
+}
+
+// splits the available width between columns in proportion
+// to their widest value, narrow columns keep a minimum width
+func columnWidths(pdf *gofpdf.Fpdf, t Table, total float64) []float64 {
+	widths := make([]float64, len(t.Headers))
+	if len(widths) == 0 {
+		return widths
+	}
+
+	pdf.SetFont(pdfFont, "B", pdfFontSize)
+	for i, h := range t.Headers {
+		widths[i] = pdf.GetStringWidth(h)
+	}
+	pdf.SetFont(pdfFont, "", pdfFontSize)
+	for _, row := range t.Rows {
+		for i := range widths {
+			if i < len(row) {
+				widths[i] = max(widths[i], pdf.GetStringWidth(row[i]))
+			}
+		}
+	}
+
+	var sum float64
+	for i := range widths {
+		widths[i] = max(widths[i]+2*pdfCellIndent, pdfMinColumn)
+		sum += widths[i]
+	}
+
+	// narrow tables are stretched as well so they span the page
+	for i := range widths {
+		widths[i] = widths[i] / sum * total
+	}
+	return widths
+}
+
+// cuts s so that it fits into a cell of the given width
+func fitText(pdf *gofpdf.Fpdf, s string, width float64) string {
+	width -= 2 * pdfCellIndent
+	if pdf.GetStringWidth(s) <= width {
+		return s
+	}
+
+	for s != "" && pdf.GetStringWidth(s+"…") > width {
+		_, size := utf8.DecodeLastRuneInString(s)
+		s = s[:len(s)-size]
+	}
+	return s + "…"
+}
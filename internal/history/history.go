@@ -0,0 +1,116 @@
+// Package history keeps the statements run in the SQL console, every
+// operator has a file of their own under the user configuration directory.
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+type Entry struct {
+	Statement string    `json:"statement"`
+	Write     bool      `json:"write"`
+	RanAt     time.Time `json:"ran_at"`
+}
+
+// Store keeps the last limit statements of every operator in dir
+type Store struct {
+	dir   string
+	limit int
+	mu    sync.Mutex
+}
+
+func NewStore(dir string, limit int) *Store {
+	return &Store{
+		dir:   dir,
+		limit: limit,
+	}
+}
+
+// directory the history is kept in when nothing else is configured
+func DefaultDir() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "university-db-admin", "history"), nil
+}
+
+// List returns the statements of operator, the latest first
+func (s *Store) List(operator string) ([]Entry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.read(operator)
+}
+
+// Add puts e on top of the history of operator, an earlier run
+// of the same statement is dropped so every statement is listed once
+func (s *Store) Add(operator string, e Entry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entries, err := s.read(operator)
+	if err != nil {
+		return err
+	}
+	entries = slices.DeleteFunc(entries, func(old Entry) bool { return old.Statement == e.Statement })
+	entries = append([]Entry{e}, entries...)
+	if len(entries) > s.limit {
+		entries = entries[:s.limit]
+	}
+
+	content, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(s.dir, 0o700); err != nil {
+		return err
+	}
+
+	// written next to the file and moved over it, so a failed
+	// write never leaves the history half written
+	tmp, err := os.CreateTemp(s.dir, ".history-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(content); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(operator))
+}
+
+func (s *Store) read(operator string) ([]Entry, error) {
+	content, err := os.ReadFile(s.path(operator))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []Entry
+	if err = json.Unmarshal(content, &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// operator names are escaped so any of them makes a valid file name
+func (s *Store) path(operator string) string {
+	if operator == "" {
+		operator = "default"
+	}
+	return filepath.Join(s.dir, url.PathEscape(operator)+".json")
+}
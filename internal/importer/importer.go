@@ -0,0 +1,415 @@
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+	"university-db-admin/internal/service"
+	"university-db-admin/pkg/validation"
+)
+
+type Kind string
+
+const (
+	Students  Kind = "students"
+	Employees Kind = "employees"
+	Marks     Kind = "marks"
+)
+
+var Kinds = []Kind{Students, Employees, Marks}
+
+// column of an import file, headers are matched against aliases ignoring case
+type column struct {
+	field   string
+	aliases []string
+}
+
+var columns = map[Kind][]column{
+	Students: {
+		{"name", []string{"name", "фио", "имя"}},
+		{"passport", []string{"passport", "паспорт", "номер паспорта"}},
+		{"curator_id", []string{"curator_id", "employee_id", "id куратора", "куратор"}},
+		{"group", []string{"group", "группа", "номер группы"}},
+	},
+	Employees: {
+		{"name", []string{"name", "фио", "имя"}},
+		{"passport", []string{"passport", "паспорт", "номер паспорта"}},
+		{"position", []string{"position", "должность"}},
+	},
+	Marks: {
+		{"employee_id", []string{"employee_id", "id преподавателя", "преподаватель"}},
+		{"student_id", []string{"student_id", "id студента", "студент"}},
+		{"subject", []string{"subject", "предмет"}},
+		{"mark", []string{"mark", "оценка"}},
+		{"date", []string{"date", "дата"}},
+	},
+}
+
+// layouts accepted in date columns, each date reads one way only: the year
+// has four digits and comes first or last
+var dateLayouts = []string{"2006-01-02", "02.01.2006"}
+
+// RowResult is the outcome of checking one row, Err is nil for valid rows
+type RowResult struct {
+	Line   int
+	Values []string
+	Err    error
+}
+
+// LineError is returned by Commit when the database rejected a row
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("строка %d: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error {
+	return e.Err
+}
+
+// Preview holds a checked file, nothing is written until Commit
+type Preview struct {
+	Kind   Kind
+	Header []string
+	Rows   []RowResult
+
+	lines  []int // lines of valid rows in insertion order
+	insert func(ctx context.Context) error
+}
+
+func (p *Preview) Valid() int {
+	return len(p.lines)
+}
+
+func (p *Preview) Invalid() int {
+	return len(p.Rows) - len(p.lines)
+}
+
+// inserts all valid rows in one transaction and returns their number
+func (p *Preview) Commit(ctx context.Context) (int, error) {
+	if len(p.lines) == 0 {
+		return 0, nil
+	}
+
+	if err := p.insert(ctx); err != nil {
+		var batchErr *service.BatchError
+		if errors.As(err, &batchErr) && batchErr.Index < len(p.lines) {
+			return 0, &LineError{Line: p.lines[batchErr.Index], Err: batchErr.Err}
+		}
+		return 0, err
+	}
+	return len(p.lines), nil
+}
+
+type Importer struct {
+	s *service.Service
+}
+
+func New(s *service.Service) *Importer {
+	return &Importer{s: s}
+}
+
+// maps the sheet to domain values and checks every row without writing anything;
+// an error is returned only when the file as a whole can't be imported
+func (im *Importer) Preview(ctx context.Context, kind Kind, sheet Sheet) (*Preview, error) {
+	cols, ok := columns[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown import kind %q", kind)
+	}
+
+	index, err := mapHeader(cols, sheet.Header)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &Preview{Kind: kind, Header: sheet.Header}
+	r := &resolver{
+		s:         im.s,
+		groups:    map[uint64]uint64{},
+		positions: map[string]uint64{},
+		subjects:  map[string]uint64{},
+		students:  map[studentLinks]error{},
+		gradings:  map[markLinks]error{},
+	}
+
+	switch kind {
+	case Students:
+		studs, err := collect(ctx, p, sheet, index, r.student, func(s domain.Student) string { return s.Passport })
+		if err != nil {
+			return nil, err
+		}
+		p.insert = func(ctx context.Context) error { return im.s.Students.CreateMany(ctx, studs) }
+	case Employees:
+		emps, err := collect(ctx, p, sheet, index, r.employee, func(e domain.Employee) string { return e.Passport })
+		if err != nil {
+			return nil, err
+		}
+		p.insert = func(ctx context.Context) error { return im.s.Employees.CreateMany(ctx, emps) }
+	case Marks:
+		marks, err := collect(ctx, p, sheet, index, r.mark, nil)
+		if err != nil {
+			return nil, err
+		}
+		p.insert = func(ctx context.Context) error { return im.s.Marks.CreateMany(ctx, marks) }
+	}
+
+	return p, nil
+}
+
+// finds the position of every column in the header
+func mapHeader(cols []column, header []string) (map[string]int, error) {
+	index := make(map[string]int, len(cols))
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(h))
+		for _, c := range cols {
+			for _, alias := range c.aliases {
+				if h == alias {
+					index[c.field] = i
+				}
+			}
+		}
+	}
+
+	var missing []string
+	for _, c := range cols {
+		if _, ok := index[c.field]; !ok {
+			missing = append(missing, c.aliases[0])
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("в файле нет столбцов: %s", strings.Join(missing, ", "))
+	}
+	return index, nil
+}
+
+// row values looked up by field name
+type record func(field string) string
+
+// parses and validates every row, rows sharing a key with an
+// earlier row are rejected; key may be nil
+func collect[T any](
+	ctx context.Context,
+	p *Preview,
+	sheet Sheet,
+	index map[string]int,
+	parse func(ctx context.Context, get record) (T, error),
+	key func(T) string,
+) ([]T, error) {
+	var items []T
+	seen := map[string]int{}
+
+	for _, row := range sheet.Rows {
+		get := func(field string) string {
+			if i := index[field]; i < len(row.Values) {
+				return strings.TrimSpace(row.Values[i])
+			}
+			return ""
+		}
+
+		item, err := parse(ctx, get)
+		if err == nil {
+			err = validation.ValidateStruct(item)
+		}
+		if err == nil && key != nil {
+			if line, ok := seen[key(item)]; ok {
+				err = rowErrorf("повторяет строку %d", line)
+			} else {
+				seen[key(item)] = row.Line
+			}
+		}
+
+		var rowErr *rowError
+		if err != nil && !errors.As(err, &rowErr) && !isValidationError(err) {
+			return nil, err
+		}
+
+		p.Rows = append(p.Rows, RowResult{Line: row.Line, Values: row.Values, Err: err})
+		if err == nil {
+			items = append(items, item)
+			p.lines = append(p.lines, row.Line)
+		}
+	}
+
+	return items, nil
+}
+
+// problem with the contents of a single row
+type rowError struct {
+	msg string
+}
+
+func (e *rowError) Error() string {
+	return e.msg
+}
+
+func rowErrorf(format string, args ...any) error {
+	return &rowError{msg: fmt.Sprintf(format, args...)}
+}
+
+func isValidationError(err error) bool {
+	var valErr *validation.Error
+	return errors.As(err, &valErr)
+}
+
+// looks up ids of referenced records and runs the checks Commit runs,
+// answers are cached for the whole file. Names are looked up as they
+// are written, like the repositories compare them
+type resolver struct {
+	s         *service.Service
+	groups    map[uint64]uint64
+	positions map[string]uint64
+	subjects  map[string]uint64
+	students  map[studentLinks]error // results of StudentsService.Check
+	gradings  map[markLinks]error    // results of MarksService.Check
+}
+
+type studentLinks struct {
+	group, curator uint64
+}
+
+type markLinks struct {
+	employee, subject, student uint64
+}
+
+// reports the rejections of the service checks as problems of the row
+func checkRow(err error) error {
+	if errors.Is(err, service.ErrNotTeacher) || errors.Is(err, service.ErrNotCurator) {
+		return &rowError{msg: err.Error()}
+	}
+	return err
+}
+
+func (r *resolver) student(ctx context.Context, get record) (domain.Student, error) {
+	curator, err := parseID(get("curator_id"), "ID куратора")
+	if err != nil {
+		return domain.Student{}, err
+	}
+
+	number, err := parseID(get("group"), "номер группы")
+	if err != nil {
+		return domain.Student{}, err
+	}
+	group, ok := r.groups[number]
+	if !ok {
+		grp, err := r.s.Groups.FindByNumber(ctx, number)
+		if errors.Is(err, repository.ErrNotFound) {
+			return domain.Student{}, rowErrorf("группа %d не найдена", number)
+		}
+		if err != nil {
+			return domain.Student{}, err
+		}
+		group = grp.ID
+		r.groups[number] = group
+	}
+
+	stud := domain.Student{
+		Name:       get("name"),
+		Passport:   get("passport"),
+		EmployeeID: curator,
+		GroupID:    group,
+	}
+	links := studentLinks{group, curator}
+	checked, ok := r.students[links]
+	if !ok {
+		checked = checkRow(r.s.Students.Check(ctx, stud))
+		r.students[links] = checked
+	}
+	return stud, checked
+}
+
+func (r *resolver) employee(ctx context.Context, get record) (domain.Employee, error) {
+	name := get("position")
+	position, ok := r.positions[name]
+	if !ok {
+		pos, err := r.s.Positions.FindByName(ctx, name)
+		if errors.Is(err, repository.ErrNotFound) {
+			return domain.Employee{}, rowErrorf("должность «%s» не найдена", name)
+		}
+		if err != nil {
+			return domain.Employee{}, err
+		}
+		position = pos.ID
+		r.positions[name] = position
+	}
+
+	return domain.Employee{
+		Name:       get("name"),
+		Passport:   get("passport"),
+		PositionID: position,
+	}, nil
+}
+
+func (r *resolver) mark(ctx context.Context, get record) (domain.Mark, error) {
+	employee, err := parseID(get("employee_id"), "ID преподавателя")
+	if err != nil {
+		return domain.Mark{}, err
+	}
+	student, err := parseID(get("student_id"), "ID студента")
+	if err != nil {
+		return domain.Mark{}, err
+	}
+
+	value, err := strconv.ParseUint(get("mark"), 10, 16)
+	if err != nil {
+		return domain.Mark{}, rowErrorf("оценка «%s» не является числом", get("mark"))
+	}
+
+	date, err := parseDate(get("date"))
+	if err != nil {
+		return domain.Mark{}, err
+	}
+
+	name := get("subject")
+	subject, ok := r.subjects[name]
+	if !ok {
+		sbj, err := r.s.Subjects.FindByName(ctx, name)
+		if errors.Is(err, repository.ErrNotFound) {
+			return domain.Mark{}, rowErrorf("предмет «%s» не найден", name)
+		}
+		if err != nil {
+			return domain.Mark{}, err
+		}
+		subject = sbj.ID
+		r.subjects[name] = subject
+	}
+
+	mark := domain.Mark{
+		EmployeeID: employee,
+		StudentID:  student,
+		SubjectID:  subject,
+		Mark:       uint16(value),
+		Date:       date,
+	}
+	links := markLinks{employee, subject, student}
+	checked, ok := r.gradings[links]
+	if !ok {
+		checked = checkRow(r.s.Marks.Check(ctx, mark))
+		r.gradings[links] = checked
+	}
+	return mark, checked
+}
+
+func parseID(value, name string) (uint64, error) {
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil || id == 0 {
+		return 0, rowErrorf("%s «%s» должен быть положительным числом", name, value)
+	}
+	return id, nil
+}
+
+func parseDate(value string) (time.Time, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, rowErrorf("дата «%s» должна быть в формате ГГГГ-ММ-ДД или ДД.ММ.ГГГГ", value)
+}
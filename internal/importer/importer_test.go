@@ -0,0 +1,147 @@
+package importer_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/importer"
+	"university-db-admin/internal/repository/memory"
+	"university-db-admin/internal/schedule"
+	"university-db-admin/internal/service"
+)
+
+func must(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// a teacher curating nobody yet, an assistant who may not curate and group 101
+func newService(t *testing.T) *service.Service {
+	t.Helper()
+	ctx := context.Background()
+	r := memory.NewRepository()
+
+	teaching, err := r.Positions.Create(ctx, domain.Position{Name: "Преподаватель", CanTeach: true, CanCurate: true})
+	must(t, err)
+	assisting, err := r.Positions.Create(ctx, domain.Position{Name: "Лаборант"})
+	must(t, err)
+	_, err = r.Employees.Create(ctx, domain.Employee{Name: "Ivanov Ivan", Passport: "MP0000001", PositionID: teaching})
+	must(t, err)
+	_, err = r.Employees.Create(ctx, domain.Employee{Name: "Petrova Olga", Passport: "MP0000002", PositionID: assisting})
+	must(t, err)
+	_, err = r.Groups.Create(ctx, domain.Group{Number: 101})
+	must(t, err)
+
+	return service.NewService(r, schedule.MustParseBells(schedule.DefaultBells))
+}
+
+func TestImportStudents(t *testing.T) {
+	ctx := context.Background()
+	s := newService(t)
+
+	// semicolons and the byte order mark of a sheet saved by excel
+	sheet, err := importer.ReadCSV(strings.NewReader("\ufeffФИО;Паспорт;Куратор;Группа\n" +
+		"Anna Smirnova;MP1000001;1;101\n" +
+		"Boris Orlov;MP1000002;1;999\n" +
+		"Vera Pavlova;MP1000003;2;101\n" +
+		"Anna Smirnova;MP1000001;1;101\n" +
+		"Gleb Sokolov;MP1000004;x;101\n" +
+		"Daria Kuznetsova;MP1000005;1;101\n"))
+	must(t, err)
+
+	p, err := importer.New(s).Preview(ctx, importer.Students, sheet)
+	must(t, err)
+	// unknown group, assistant curator, repeated passport and curator id
+	wantInvalid := map[int]bool{3: true, 4: true, 5: true, 6: true}
+	for _, row := range p.Rows {
+		if (row.Err != nil) != wantInvalid[row.Line] {
+			t.Errorf("line %d: got error %v", row.Line, row.Err)
+		}
+	}
+
+	// nothing is written before Commit
+	if studs, err := s.Students.FindAll(ctx); err != nil || len(studs) != 0 {
+		t.Fatalf("got %d students before commit, error %v", len(studs), err)
+	}
+
+	n, err := p.Commit(ctx)
+	must(t, err)
+	if n != 2 {
+		t.Fatalf("committed %d rows, want 2", n)
+	}
+	studs, err := s.Students.FindAll(ctx)
+	must(t, err)
+	if len(studs) != 2 || studs[0].Passport != "MP1000001" || studs[1].Passport != "MP1000005" {
+		t.Fatalf("got students %+v", studs)
+	}
+}
+
+func TestImportMarks(t *testing.T) {
+	ctx := context.Background()
+	s := newService(t)
+
+	// Ivanov teaches both subjects, group 101 has lessons in mathematics only
+	math, err := s.Subjects.Create(ctx, domain.Subject{Name: "Mathematics", Description: "math"})
+	must(t, err)
+	physics, err := s.Subjects.Create(ctx, domain.Subject{Name: "Physics", Description: "physics"})
+	must(t, err)
+	_, err = s.Subjects.Create(ctx, domain.Subject{Name: "Chemistry", Description: "chemistry"})
+	must(t, err)
+	must(t, s.EmployeesSubjects.Create(ctx, domain.EmployeeSubject{EmployeeID: 1, SubjectID: math}))
+	must(t, s.EmployeesSubjects.Create(ctx, domain.EmployeeSubject{EmployeeID: 1, SubjectID: physics}))
+	lt, err := s.LessonTypes.Create(ctx, domain.LessonType{Name: "LK"})
+	must(t, err)
+	_, err = s.Schedule.Create(ctx, domain.Lesson{GroupID: 1, SubjectID: math, LessonTypeID: lt, Week: 1, Weekday: 1, Pair: 1, Room: 101})
+	must(t, err)
+	_, err = s.Students.Create(ctx, domain.Student{Name: "Anna Smirnova", Passport: "MP1000001", EmployeeID: 1, GroupID: 1})
+	must(t, err)
+
+	sheet, err := importer.ReadCSV(strings.NewReader("Преподаватель,Студент,Предмет,Оценка,Дата\n" +
+		"1,1,Mathematics,8,2024-09-02\n" +
+		"1,1,Physics,7,02.09.2024\n" +
+		"1,1,Chemistry,7,2024-09-02\n" +
+		"1,99,Mathematics,7,2024-09-02\n" +
+		"2,1,Mathematics,7,2024-09-02\n" +
+		"1,1,Mathematics,9,09/02/24\n" +
+		"1,1,Mathematics,9,02-09-24\n" +
+		"1,1,Mathematics,10,09.09.2024\n"))
+	must(t, err)
+
+	p, err := importer.New(s).Preview(ctx, importer.Marks, sheet)
+	must(t, err)
+	// no lessons in physics, chemistry not taught, unknown student,
+	// assistant grader and two dates read differently in the US
+	wantErr := map[int]error{
+		3: service.ErrNoLessons,
+		4: service.ErrNotGrader,
+		5: service.ErrUnknownStudent,
+		6: service.ErrNotTeacher,
+	}
+	wantInvalid := map[int]bool{7: true, 8: true}
+	for _, row := range p.Rows {
+		want := wantErr[row.Line]
+		if want != nil && (row.Err == nil || row.Err.Error() != want.Error()) {
+			t.Errorf("line %d: got error %v, want %v", row.Line, row.Err, want)
+		}
+		if want == nil && (row.Err != nil) != wantInvalid[row.Line] {
+			t.Errorf("line %d: got error %v", row.Line, row.Err)
+		}
+	}
+
+	n, err := p.Commit(ctx)
+	must(t, err)
+	if n != 2 {
+		t.Fatalf("committed %d rows, want 2", n)
+	}
+}
+
+func TestImportMissingColumns(t *testing.T) {
+	sheet, err := importer.ReadCSV(strings.NewReader("name,passport\nIvanov Ivan,MP0000003\n"))
+	must(t, err)
+	if _, err := importer.New(newService(t)).Preview(context.Background(), importer.Employees, sheet); err == nil {
+		t.Fatal("got no error for a file without a position column")
+	}
+}
@@ -0,0 +1,110 @@
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Sheet is a spreadsheet read into memory, Header is its first row
+type Sheet struct {
+	Header []string
+	Rows   []Row
+}
+
+type Row struct {
+	Line   int // 1-based line in the file, the header is line 1
+	Values []string
+}
+
+// reads a .csv or .xlsx file depending on its extension
+func ReadFile(path string) (Sheet, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Sheet{}, err
+	}
+	defer f.Close()
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return ReadCSV(f)
+	case ".xlsx":
+		return ReadXLSX(f)
+	}
+	return Sheet{}, fmt.Errorf("unsupported file type %q, expected .csv or .xlsx", filepath.Ext(path))
+}
+
+// reads comma or semicolon separated values, the separator is
+// guessed from the header line as spreadsheet apps use both
+func ReadCSV(r io.Reader) (Sheet, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Sheet{}, err
+	}
+
+	// excel prepends a byte order mark to utf-8 files
+	data = bytes.TrimPrefix(data, []byte{0xEF, 0xBB, 0xBF})
+	header, _, _ := bytes.Cut(data, []byte("\n"))
+
+	cr := csv.NewReader(bytes.NewReader(data))
+	if bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		cr.Comma = ';'
+	}
+	cr.FieldsPerRecord = -1
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return Sheet{}, fmt.Errorf("cant read csv: %w", err)
+	}
+	return newSheet(records)
+}
+
+// reads the first worksheet of an xlsx workbook
+func ReadXLSX(r io.Reader) (Sheet, error) {
+	f, err := excelize.OpenReader(r)
+	if err != nil {
+		return Sheet{}, fmt.Errorf("cant read xlsx: %w", err)
+	}
+	defer f.Close()
+
+	sheets := f.GetSheetList()
+	if len(sheets) == 0 {
+		return Sheet{}, fmt.Errorf("workbook has no sheets")
+	}
+
+	records, err := f.GetRows(sheets[0])
+	if err != nil {
+		return Sheet{}, fmt.Errorf("cant read sheet %s: %w", sheets[0], err)
+	}
+	return newSheet(records)
+}
+
+func newSheet(records [][]string) (Sheet, error) {
+	if len(records) == 0 {
+		return Sheet{}, fmt.Errorf("file is empty")
+	}
+
+	sheet := Sheet{Header: records[0]}
+	for i, record := range records[1:] {
+		if isBlank(record) {
+			continue
+		}
+		sheet.Rows = append(sheet.Rows, Row{Line: i + 2, Values: record})
+	}
+	return sheet, nil
+}
+
+func isBlank(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,302 @@
+package migrations
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+	"university-db-admin/pkg/dbclient"
+
+	"github.com/jackc/pgx/v5"
+)
+
+//go:embed sql/*.sql
+var files embed.FS
+
+// key of the advisory lock that serializes concurrent migration runs
+const lockKey = 7_202_410_011
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migration struct {
+	Version  uint64
+	Name     string
+	Up       string
+	Down     string
+	Checksum string // of both files, a changed revert is caught as well
+}
+
+type Status struct {
+	Version   uint64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+type appliedMigration struct {
+	Version   uint64
+	Checksum  string
+	AppliedAt time.Time
+}
+
+type Migrator struct {
+	db         dbclient.Querier
+	migrations []Migration
+}
+
+func NewMigrator(db dbclient.Querier) (*Migrator, error) {
+	migrations, err := load(files)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+	}, nil
+}
+
+// reads up/down pairs from the embedded sql directory sorted by version
+func load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, "sql")
+	if err != nil {
+		return nil, fmt.Errorf("cant read migrations: %w", err)
+	}
+
+	byVersion := make(map[uint64]*Migration)
+	for _, entry := range entries {
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name: %s", entry.Name())
+		}
+
+		version, err := strconv.ParseUint(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version: %s", entry.Name())
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join("sql", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("cant read migration %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migration %d has different names: %s and %s", version, m.Name, match[2])
+		}
+
+		switch match[3] {
+		case "up":
+			m.Up = string(content)
+		case "down":
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %d must have both up and down files", m.Version)
+		}
+		m.Checksum = checksums(m.Up, m.Down)
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// hashes the up and down files, the zero byte keeps a line moved from
+// the end of one file to the start of the other from going unnoticed
+func checksums(up, down string) string {
+	h := sha256.New()
+	h.Write([]byte(up))
+	h.Write([]byte{0})
+	h.Write([]byte(down))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// applies all pending migrations in a single transaction
+func (m *Migrator) Up(ctx context.Context) error {
+	return m.withLock(ctx, func(tx pgx.Tx, applied map[uint64]appliedMigration) error {
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			log.Printf("applying migration %d_%s\n", migration.Version, migration.Name)
+			if _, err := tx.Exec(ctx, migration.Up); err != nil {
+				return fmt.Errorf("migration %d_%s failed: %w", migration.Version, migration.Name, err)
+			}
+
+			sql := `
+				INSERT INTO public.schema_migrations (version, name, checksum)
+				VALUES ($1, $2, $3)
+			`
+			if _, err := tx.Exec(ctx, sql, migration.Version, migration.Name, migration.Checksum); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// reverts the last steps applied migrations in a single transaction
+func (m *Migrator) Down(ctx context.Context, steps int) error {
+	return m.withLock(ctx, func(tx pgx.Tx, applied map[uint64]appliedMigration) error {
+		for i := len(m.migrations) - 1; i >= 0 && steps > 0; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+
+			log.Printf("reverting migration %d_%s\n", migration.Version, migration.Name)
+			if _, err := tx.Exec(ctx, migration.Down); err != nil {
+				return fmt.Errorf("migration %d_%s revert failed: %w", migration.Version, migration.Name, err)
+			}
+
+			sql := `
+				DELETE FROM public.schema_migrations
+				WHERE version = $1
+			`
+			if _, err := tx.Exec(ctx, sql, migration.Version); err != nil {
+				return err
+			}
+			steps--
+		}
+		return nil
+	})
+}
+
+// reports every known migration and whether it has been applied, it only
+// reads: a database never migrated has every migration pending
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	var exists bool
+	sql := `SELECT to_regclass('public.schema_migrations') IS NOT NULL`
+	if err := m.db.QueryRow(ctx, sql).Scan(&exists); err != nil {
+		return nil, err
+	}
+
+	applied := make(map[uint64]appliedMigration)
+	if exists {
+		var err error
+		if applied, err = m.applied(ctx, m.db); err != nil {
+			return nil, err
+		}
+		if err = m.verify(applied); err != nil {
+			return nil, err
+		}
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if a, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = a.AppliedAt
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// runs fn in a transaction holding the migration lock after the applied
+// migrations were verified against the embedded ones
+func (m *Migrator) withLock(ctx context.Context, fn func(tx pgx.Tx, applied map[uint64]appliedMigration) error) error {
+	tx, err := m.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err = tx.Exec(ctx, "SELECT pg_advisory_xact_lock($1)", lockKey); err != nil {
+		return err
+	}
+
+	sql := `
+		CREATE TABLE IF NOT EXISTS public.schema_migrations (
+			version    BIGINT PRIMARY KEY,
+			name       VARCHAR(255) NOT NULL,
+			checksum   CHAR(64) NOT NULL,
+			applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+		)
+	`
+	if _, err = tx.Exec(ctx, sql); err != nil {
+		return err
+	}
+
+	applied, err := m.applied(ctx, tx)
+	if err != nil {
+		return err
+	}
+
+	if err = m.verify(applied); err != nil {
+		return err
+	}
+
+	if err = fn(tx, applied); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// db is the pool or the transaction holding the migration lock
+func (m *Migrator) applied(ctx context.Context, db dbclient.Querier) (map[uint64]appliedMigration, error) {
+	sql := `
+		SELECT version, checksum, applied_at
+		FROM public.schema_migrations
+	`
+
+	rows, err := db.Query(ctx, sql)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := make(map[uint64]appliedMigration)
+	for rows.Next() {
+		var a appliedMigration
+		if err := rows.Scan(&a.Version, &a.Checksum, &a.AppliedAt); err != nil {
+			return nil, err
+		}
+		applied[a.Version] = a
+	}
+
+	return applied, rows.Err()
+}
+
+// checks that every applied migration is still known and unchanged
+func (m *Migrator) verify(applied map[uint64]appliedMigration) error {
+	known := make(map[uint64]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+
+	for version, a := range applied {
+		migration, ok := known[version]
+		if !ok {
+			return fmt.Errorf("applied migration %d is unknown to this build", version)
+		}
+		if a.Checksum != migration.Checksum {
+			return fmt.Errorf("checksum mismatch for migration %d_%s", version, migration.Name)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+package migrations
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"testing/fstest"
+)
+
+func loadOne(t *testing.T, up, down string) Migration {
+	t.Helper()
+	migrations, err := load(fstest.MapFS{
+		"sql/0001_init.up.sql":   {Data: []byte(up)},
+		"sql/0001_init.down.sql": {Data: []byte(down)},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return migrations[0]
+}
+
+// an edited revert must be caught like an edited migration
+func TestChecksumCoversBothFiles(t *testing.T) {
+	m := loadOne(t, "CREATE TABLE t ();", "DROP TABLE t;")
+
+	if edited := loadOne(t, "CREATE TABLE t ();", "DROP TABLE IF EXISTS t;"); edited.Checksum == m.Checksum {
+		t.Fatal("checksum unchanged by an edited down file")
+	}
+	if moved := loadOne(t, "CREATE TABLE t ();DROP", " TABLE t;"); moved.Checksum == m.Checksum {
+		t.Fatal("checksum unchanged by text moved between the files")
+	}
+
+	applied := map[uint64]appliedMigration{1: {Version: 1, Checksum: m.Checksum}}
+	if err := (&Migrator{migrations: []Migration{m}}).verify(applied); err != nil {
+		t.Fatalf("checksum rejected: %v", err)
+	}
+	upOnly := sha256.Sum256([]byte(m.Up))
+	applied[1] = appliedMigration{Version: 1, Checksum: hex.EncodeToString(upOnly[:])}
+	if err := (&Migrator{migrations: []Migration{m}}).verify(applied); err == nil {
+		t.Fatal("checksum of the up file alone accepted")
+	}
+}
@@ -0,0 +1,9 @@
+DROP TABLE IF EXISTS public.employees_subjects;
+DROP TABLE IF EXISTS public.marks;
+DROP TABLE IF EXISTS public.lessons;
+DROP TABLE IF EXISTS public.lesson_types;
+DROP TABLE IF EXISTS public.subjects;
+DROP TABLE IF EXISTS public.students;
+DROP TABLE IF EXISTS public.groups;
+DROP TABLE IF EXISTS public.employees;
+DROP TABLE IF EXISTS public.positions;
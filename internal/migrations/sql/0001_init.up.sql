@@ -0,0 +1,77 @@
+CREATE TABLE IF NOT EXISTS public.positions (
+    id   BIGSERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    CONSTRAINT positions_name_key UNIQUE (name)
+);
+
+CREATE TABLE IF NOT EXISTS public.employees (
+    id          BIGSERIAL PRIMARY KEY,
+    name        VARCHAR(255) NOT NULL,
+    passport    CHAR(9) NOT NULL,
+    position_id BIGINT NOT NULL REFERENCES public.positions (id),
+    CONSTRAINT employees_passport_key UNIQUE (passport)
+);
+
+CREATE TABLE IF NOT EXISTS public.groups (
+    id     BIGSERIAL PRIMARY KEY,
+    number BIGINT NOT NULL,
+    CONSTRAINT groups_number_key UNIQUE (number),
+    CONSTRAINT groups_number_check CHECK (number > 0)
+);
+
+CREATE TABLE IF NOT EXISTS public.students (
+    id          BIGSERIAL PRIMARY KEY,
+    name        VARCHAR(255) NOT NULL,
+    passport    CHAR(9) NOT NULL,
+    employee_id BIGINT REFERENCES public.employees (id),
+    group_id    BIGINT NOT NULL REFERENCES public.groups (id),
+    CONSTRAINT students_passport_key UNIQUE (passport)
+);
+
+CREATE TABLE IF NOT EXISTS public.subjects (
+    id          BIGSERIAL PRIMARY KEY,
+    name        VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    CONSTRAINT subjects_name_key UNIQUE (name)
+);
+
+CREATE TABLE IF NOT EXISTS public.lesson_types (
+    id   BIGSERIAL PRIMARY KEY,
+    name VARCHAR(2) NOT NULL,
+    CONSTRAINT lesson_types_name_key UNIQUE (name)
+);
+
+CREATE TABLE IF NOT EXISTS public.lessons (
+    id             BIGSERIAL PRIMARY KEY,
+    group_id       BIGINT NOT NULL REFERENCES public.groups (id),
+    subject_id     BIGINT NOT NULL REFERENCES public.subjects (id),
+    lesson_type_id BIGINT NOT NULL REFERENCES public.lesson_types (id),
+    week           SMALLINT NOT NULL,
+    weekday        SMALLINT NOT NULL,
+    room           BIGINT NOT NULL,
+    CONSTRAINT lessons_week_check CHECK (week > 0),
+    CONSTRAINT lessons_weekday_check CHECK (weekday BETWEEN 1 AND 7),
+    CONSTRAINT lessons_room_check CHECK (room > 0)
+);
+
+CREATE TABLE IF NOT EXISTS public.marks (
+    id          BIGSERIAL PRIMARY KEY,
+    employee_id BIGINT NOT NULL REFERENCES public.employees (id),
+    student_id  BIGINT NOT NULL REFERENCES public.students (id),
+    subject_id  BIGINT NOT NULL REFERENCES public.subjects (id),
+    mark        SMALLINT NOT NULL,
+    date        DATE NOT NULL,
+    CONSTRAINT marks_mark_check CHECK (mark BETWEEN 1 AND 10)
+);
+
+CREATE TABLE IF NOT EXISTS public.employees_subjects (
+    employee_id BIGINT NOT NULL REFERENCES public.employees (id) ON DELETE CASCADE,
+    subject_id  BIGINT NOT NULL REFERENCES public.subjects (id) ON DELETE CASCADE,
+    PRIMARY KEY (employee_id, subject_id)
+);
+
+CREATE INDEX IF NOT EXISTS students_group_id_idx ON public.students (group_id);
+CREATE INDEX IF NOT EXISTS students_employee_id_idx ON public.students (employee_id);
+CREATE INDEX IF NOT EXISTS lessons_group_id_idx ON public.lessons (group_id);
+CREATE INDEX IF NOT EXISTS marks_student_id_idx ON public.marks (student_id);
+CREATE INDEX IF NOT EXISTS marks_subject_id_idx ON public.marks (subject_id);
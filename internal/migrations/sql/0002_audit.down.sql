@@ -0,0 +1,11 @@
+DROP TRIGGER IF EXISTS employees_subjects_audit ON public.employees_subjects;
+DROP TRIGGER IF EXISTS marks_audit ON public.marks;
+DROP TRIGGER IF EXISTS lessons_audit ON public.lessons;
+DROP TRIGGER IF EXISTS lesson_types_audit ON public.lesson_types;
+DROP TRIGGER IF EXISTS subjects_audit ON public.subjects;
+DROP TRIGGER IF EXISTS students_audit ON public.students;
+DROP TRIGGER IF EXISTS groups_audit ON public.groups;
+DROP TRIGGER IF EXISTS employees_audit ON public.employees;
+DROP TRIGGER IF EXISTS positions_audit ON public.positions;
+DROP FUNCTION IF EXISTS public.audit_changes();
+DROP TABLE IF EXISTS public.audit_log;
@@ -0,0 +1,73 @@
+CREATE TABLE IF NOT EXISTS public.audit_log (
+    id         BIGSERIAL PRIMARY KEY,
+    entity     VARCHAR(64) NOT NULL,
+    record_id  VARCHAR(64) NOT NULL,
+    operation  VARCHAR(6) NOT NULL,
+    old_values JSONB,
+    new_values JSONB,
+    operator   VARCHAR(255) NOT NULL,
+    changed_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    CONSTRAINT audit_log_operation_check CHECK (operation IN ('create', 'update', 'delete'))
+);
+
+CREATE INDEX IF NOT EXISTS audit_log_entity_record_idx ON public.audit_log (entity, record_id);
+CREATE INDEX IF NOT EXISTS audit_log_changed_at_idx ON public.audit_log (changed_at);
+
+-- records a row change, trigger arguments are the primary key columns;
+-- the operator is set per connection by the application through app.operator
+CREATE OR REPLACE FUNCTION public.audit_changes() RETURNS TRIGGER AS $$
+DECLARE
+    old_values JSONB;
+    new_values JSONB;
+    record_id  TEXT := '';
+    key        TEXT;
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        old_values := to_jsonb(OLD);
+    END IF;
+    IF TG_OP <> 'DELETE' THEN
+        new_values := to_jsonb(NEW);
+    END IF;
+
+    IF old_values = new_values THEN
+        RETURN NULL;
+    END IF;
+
+    FOREACH key IN ARRAY TG_ARGV LOOP
+        IF record_id <> '' THEN
+            record_id := record_id || '/';
+        END IF;
+        record_id := record_id || (COALESCE(new_values, old_values) ->> key);
+    END LOOP;
+
+    INSERT INTO public.audit_log (entity, record_id, operation, old_values, new_values, operator)
+    VALUES (
+        TG_TABLE_NAME,
+        record_id,
+        CASE TG_OP WHEN 'INSERT' THEN 'create' WHEN 'UPDATE' THEN 'update' ELSE 'delete' END,
+        old_values,
+        new_values,
+        COALESCE(NULLIF(current_setting('app.operator', true), ''), session_user)
+    );
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER positions_audit AFTER INSERT OR UPDATE OR DELETE ON public.positions
+    FOR EACH ROW EXECUTE FUNCTION public.audit_changes('id');
+CREATE TRIGGER employees_audit AFTER INSERT OR UPDATE OR DELETE ON public.employees
+    FOR EACH ROW EXECUTE FUNCTION public.audit_changes('id');
+CREATE TRIGGER groups_audit AFTER INSERT OR UPDATE OR DELETE ON public.groups
+    FOR EACH ROW EXECUTE FUNCTION public.audit_changes('id');
+CREATE TRIGGER students_audit AFTER INSERT OR UPDATE OR DELETE ON public.students
+    FOR EACH ROW EXECUTE FUNCTION public.audit_changes('id');
+CREATE TRIGGER subjects_audit AFTER INSERT OR UPDATE OR DELETE ON public.subjects
+    FOR EACH ROW EXECUTE FUNCTION public.audit_changes('id');
+CREATE TRIGGER lesson_types_audit AFTER INSERT OR UPDATE OR DELETE ON public.lesson_types
+    FOR EACH ROW EXECUTE FUNCTION public.audit_changes('id');
+CREATE TRIGGER lessons_audit AFTER INSERT OR UPDATE OR DELETE ON public.lessons
+    FOR EACH ROW EXECUTE FUNCTION public.audit_changes('id');
+CREATE TRIGGER marks_audit AFTER INSERT OR UPDATE OR DELETE ON public.marks
+    FOR EACH ROW EXECUTE FUNCTION public.audit_changes('id');
+CREATE TRIGGER employees_subjects_audit AFTER INSERT OR UPDATE OR DELETE ON public.employees_subjects
+    FOR EACH ROW EXECUTE FUNCTION public.audit_changes('employee_id', 'subject_id');
@@ -0,0 +1,9 @@
+-- records still in the trash become visible again, removing them
+-- could fail on marks and students that reference them
+DROP INDEX IF EXISTS public.groups_deleted_at_idx;
+DROP INDEX IF EXISTS public.employees_deleted_at_idx;
+DROP INDEX IF EXISTS public.students_deleted_at_idx;
+
+ALTER TABLE public.groups DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE public.employees DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE public.students DROP COLUMN IF EXISTS deleted_at;
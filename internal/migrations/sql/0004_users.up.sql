@@ -0,0 +1,12 @@
+-- accounts of the application, not covered by the audit log
+-- so password hashes never end up in it
+CREATE TABLE IF NOT EXISTS public.users (
+    id            BIGSERIAL PRIMARY KEY,
+    login         VARCHAR(64) NOT NULL,
+    password_hash TEXT NOT NULL,
+    role          VARCHAR(16) NOT NULL,
+    employee_id   BIGINT REFERENCES public.employees (id),
+    CONSTRAINT users_login_key UNIQUE (login),
+    CONSTRAINT users_role_check CHECK (role IN ('admin', 'dean', 'teacher', 'readonly')),
+    CONSTRAINT users_teacher_check CHECK (role <> 'teacher' OR employee_id IS NOT NULL)
+);
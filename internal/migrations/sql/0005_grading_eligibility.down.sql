@@ -0,0 +1,2 @@
+DROP TRIGGER IF EXISTS marks_grading_eligibility ON public.marks;
+DROP FUNCTION IF EXISTS public.check_grading_eligibility();
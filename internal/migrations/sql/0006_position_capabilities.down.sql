@@ -0,0 +1,4 @@
+ALTER TABLE public.positions
+    DROP COLUMN IF EXISTS can_teach,
+    DROP COLUMN IF EXISTS can_curate,
+    DROP COLUMN IF EXISTS can_administer;
@@ -0,0 +1,19 @@
+-- what holders of a position may do, previously decided by the
+-- position named "Преподаватель"
+ALTER TABLE public.positions
+    ADD COLUMN IF NOT EXISTS can_teach      BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS can_curate     BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN IF NOT EXISTS can_administer BOOLEAN NOT NULL DEFAULT FALSE;
+
+UPDATE public.positions
+SET can_teach = TRUE, can_curate = TRUE
+WHERE name = 'Преподаватель';
+
+-- employees already curating students keep doing so
+UPDATE public.positions
+SET can_curate = TRUE
+WHERE id IN (
+    SELECT e.position_id
+    FROM public.employees e
+    JOIN public.students s ON s.employee_id = e.id
+);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS public.lessons_employee_id_idx;
+
+ALTER TABLE public.lessons
+    DROP CONSTRAINT IF EXISTS lessons_employee_subject_fkey,
+    DROP CONSTRAINT IF EXISTS lessons_pair_check,
+    DROP COLUMN IF EXISTS employee_id,
+    DROP COLUMN IF EXISTS pair;
@@ -0,0 +1,25 @@
+-- lessons take place in a numbered pair of the bell schedule. The lessons
+-- scheduled before are put into consecutive pairs of their group's day in
+-- the order they were created, so they don't clash with each other.
+-- The teacher must be linked to the subject of the lesson, lessons
+-- without one aren't checked
+ALTER TABLE public.lessons
+    ADD COLUMN IF NOT EXISTS pair        SMALLINT,
+    ADD COLUMN IF NOT EXISTS employee_id BIGINT;
+
+UPDATE public.lessons
+SET pair = numbered.pair
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY group_id, week, weekday ORDER BY id) AS pair
+    FROM public.lessons
+) numbered
+WHERE lessons.id = numbered.id;
+
+ALTER TABLE public.lessons ALTER COLUMN pair SET NOT NULL;
+
+ALTER TABLE public.lessons
+    ADD CONSTRAINT lessons_pair_check CHECK (pair > 0),
+    ADD CONSTRAINT lessons_employee_subject_fkey FOREIGN KEY (employee_id, subject_id)
+        REFERENCES public.employees_subjects (employee_id, subject_id);
+
+CREATE INDEX IF NOT EXISTS lessons_employee_id_idx ON public.lessons (employee_id);
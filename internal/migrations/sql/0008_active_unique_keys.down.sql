@@ -0,0 +1,9 @@
+-- fails while an active and a trashed record share a passport or
+-- number, one of them has to be removed first
+DROP INDEX IF EXISTS public.groups_number_key;
+DROP INDEX IF EXISTS public.students_passport_key;
+DROP INDEX IF EXISTS public.employees_passport_key;
+
+ALTER TABLE public.groups ADD CONSTRAINT groups_number_key UNIQUE (number);
+ALTER TABLE public.students ADD CONSTRAINT students_passport_key UNIQUE (passport);
+ALTER TABLE public.employees ADD CONSTRAINT employees_passport_key UNIQUE (passport);
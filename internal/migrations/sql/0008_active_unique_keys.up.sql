@@ -0,0 +1,10 @@
+-- records in the trash give up their passport or number, so a record
+-- created in their place only has to be unique among the active ones.
+-- the indexes keep the names of the constraints they replace
+ALTER TABLE public.employees DROP CONSTRAINT IF EXISTS employees_passport_key;
+ALTER TABLE public.students DROP CONSTRAINT IF EXISTS students_passport_key;
+ALTER TABLE public.groups DROP CONSTRAINT IF EXISTS groups_number_key;
+
+CREATE UNIQUE INDEX IF NOT EXISTS employees_passport_key ON public.employees (passport) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS students_passport_key ON public.students (passport) WHERE deleted_at IS NULL;
+CREATE UNIQUE INDEX IF NOT EXISTS groups_number_key ON public.groups (number) WHERE deleted_at IS NULL;
@@ -0,0 +1,32 @@
+-- the check of 0005, without the way around it for restores
+CREATE OR REPLACE FUNCTION public.check_grading_eligibility() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP = 'UPDATE'
+        AND NEW.employee_id = OLD.employee_id
+        AND NEW.student_id = OLD.student_id
+        AND NEW.subject_id = OLD.subject_id THEN
+        RETURN NEW;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1
+        FROM public.employees_subjects
+        WHERE employee_id = NEW.employee_id AND subject_id = NEW.subject_id
+    ) THEN
+        RAISE EXCEPTION 'employee % does not teach subject %', NEW.employee_id, NEW.subject_id
+            USING ERRCODE = 'check_violation', TABLE = 'marks', CONSTRAINT = 'marks_grader_subject_check';
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1
+        FROM public.students s
+        JOIN public.lessons l ON l.group_id = s.group_id
+        WHERE s.id = NEW.student_id AND l.subject_id = NEW.subject_id
+    ) THEN
+        RAISE EXCEPTION 'group of student % has no lessons in subject %', NEW.student_id, NEW.subject_id
+            USING ERRCODE = 'check_violation', TABLE = 'marks', CONSTRAINT = 'marks_group_subject_check';
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
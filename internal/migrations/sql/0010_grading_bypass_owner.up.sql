@@ -0,0 +1,41 @@
+-- the way around the grading check is kept for rows copied by the owner
+-- of the marks table, the role the migrations and restores run as. Other
+-- roles setting app.skip_grading_check are checked like everyone else, and
+-- so are statements of the sql console, which can't copy from the client
+CREATE OR REPLACE FUNCTION public.check_grading_eligibility() RETURNS TRIGGER AS $$
+BEGIN
+    IF current_setting('app.skip_grading_check', true) = 'on'
+        AND pg_has_role(current_user, (SELECT relowner FROM pg_class WHERE oid = 'public.marks'::regclass), 'MEMBER')
+        AND current_query() ~* '^\s*copy\s' THEN
+        RETURN NEW;
+    END IF;
+
+    IF TG_OP = 'UPDATE'
+        AND NEW.employee_id = OLD.employee_id
+        AND NEW.student_id = OLD.student_id
+        AND NEW.subject_id = OLD.subject_id THEN
+        RETURN NEW;
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1
+        FROM public.employees_subjects
+        WHERE employee_id = NEW.employee_id AND subject_id = NEW.subject_id
+    ) THEN
+        RAISE EXCEPTION 'employee % does not teach subject %', NEW.employee_id, NEW.subject_id
+            USING ERRCODE = 'check_violation', TABLE = 'marks', CONSTRAINT = 'marks_grader_subject_check';
+    END IF;
+
+    IF NOT EXISTS (
+        SELECT 1
+        FROM public.students s
+        JOIN public.lessons l ON l.group_id = s.group_id
+        WHERE s.id = NEW.student_id AND l.subject_id = NEW.subject_id
+    ) THEN
+        RAISE EXCEPTION 'group of student % has no lessons in subject %', NEW.student_id, NEW.subject_id
+            USING ERRCODE = 'check_violation', TABLE = 'marks', CONSTRAINT = 'marks_group_subject_check';
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS public.lessons_room_slot_key;
+DROP INDEX IF EXISTS public.lessons_teacher_slot_key;
+DROP INDEX IF EXISTS public.lessons_group_slot_key;
@@ -0,0 +1,23 @@
+-- a group, a teacher and a room take part in one lesson per pair. Lessons
+-- stored before conflicts were checked may clash, they have to be moved
+-- first: university_db_cli schedule conflicts lists them
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM public.lessons
+        GROUP BY group_id, week, weekday, pair HAVING COUNT(*) > 1
+    ) OR EXISTS (
+        SELECT 1 FROM public.lessons WHERE employee_id IS NOT NULL
+        GROUP BY employee_id, week, weekday, pair HAVING COUNT(*) > 1
+    ) OR EXISTS (
+        SELECT 1 FROM public.lessons
+        GROUP BY room, week, weekday, pair HAVING COUNT(*) > 1
+    ) THEN
+        RAISE EXCEPTION 'the schedule has conflicting lessons'
+            USING HINT = 'list them with university_db_cli schedule conflicts and move them before migrating';
+    END IF;
+END $$;
+
+CREATE UNIQUE INDEX IF NOT EXISTS lessons_group_slot_key ON public.lessons (group_id, week, weekday, pair);
+CREATE UNIQUE INDEX IF NOT EXISTS lessons_teacher_slot_key ON public.lessons (employee_id, week, weekday, pair);
+CREATE UNIQUE INDEX IF NOT EXISTS lessons_room_slot_key ON public.lessons (room, week, weekday, pair);
@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+)
+
+// errors returned by every repository implementation, match them with errors.Is
+var (
+	ErrNotFound         = errors.New("record not found")
+	ErrDuplicate        = errors.New("duplicate record")
+	ErrReferenced       = errors.New("record is referenced by other records")
+	ErrInvalidReference = errors.New("referenced record does not exist")
+	ErrCheckViolation   = errors.New("value violates a check constraint")
+)
+
+// ErrNotSupported is returned by backends lacking an optional feature,
+// like the console of the memory backend
+var ErrNotSupported = errors.New("operation is not supported by this backend")
+
+// DuplicateError reports a unique constraint violation on Field of Table
+type DuplicateError struct {
+	Table string
+	Field string
+	Value string
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("%s: %s %q already exists", e.Table, e.Field, e.Value)
+}
+
+func (e *DuplicateError) Unwrap() error {
+	return ErrDuplicate
+}
+
+// ReferencedError reports that a record of Table can't be removed
+// because rows of ReferencingTable still point to it
+type ReferencedError struct {
+	Table            string
+	ReferencingTable string
+}
+
+func (e *ReferencedError) Error() string {
+	return fmt.Sprintf("%s: record is still referenced from %s", e.Table, e.ReferencingTable)
+}
+
+func (e *ReferencedError) Unwrap() error {
+	return ErrReferenced
+}
+
+// InvalidReferenceError reports that Field of Table points
+// to a record of ReferencedTable that does not exist
+type InvalidReferenceError struct {
+	Table           string
+	Field           string
+	ReferencedTable string
+}
+
+func (e *InvalidReferenceError) Error() string {
+	return fmt.Sprintf("%s: %s references missing record of %s", e.Table, e.Field, e.ReferencedTable)
+}
+
+func (e *InvalidReferenceError) Unwrap() error {
+	return ErrInvalidReference
+}
+
+// CheckViolationError reports a check or not-null constraint violation
+type CheckViolationError struct {
+	Table      string
+	Constraint string
+}
+
+func (e *CheckViolationError) Error() string {
+	return fmt.Sprintf("%s: constraint %s violated", e.Table, e.Constraint)
+}
+
+func (e *CheckViolationError) Unwrap() error {
+	return ErrCheckViolation
+}
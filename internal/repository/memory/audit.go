@@ -0,0 +1,39 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+)
+
+type auditRepository struct {
+	s *store
+}
+
+var auditList = listSpec[domain.AuditEntry]{
+	table: "audit_log",
+	fields: map[string]column[domain.AuditEntry]{
+		"id":         intColumn(func(e domain.AuditEntry) uint64 { return e.ID }),
+		"entity":     textColumn(func(e domain.AuditEntry) string { return e.Entity }),
+		"record_id":  textColumn(func(e domain.AuditEntry) string { return e.RecordID }),
+		"operation":  textColumn(func(e domain.AuditEntry) string { return e.Operation }),
+		"operator":   textColumn(func(e domain.AuditEntry) string { return e.Operator }),
+		"changed_at": {kind: kindTimestamp, get: func(e domain.AuditEntry) any { return e.ChangedAt }},
+	},
+	// newest first
+	order: func(a, b domain.AuditEntry) int {
+		return cmp.Compare(b.ID, a.ID)
+	},
+	id:     func(e domain.AuditEntry) uint64 { return e.ID },
+	keyset: false,
+}
+
+func (a *auditRepository) List(ctx context.Context, opts repository.ListOptions) (repository.Page[domain.AuditEntry], error) {
+	var page repository.Page[domain.AuditEntry]
+	err := a.s.read(func(t *tables) (err error) {
+		page, err = list(auditList, t.audit, opts)
+		return err
+	})
+	return page, err
+}
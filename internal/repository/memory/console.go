@@ -0,0 +1,17 @@
+package memory
+
+import (
+	"context"
+	"university-db-admin/internal/repository"
+)
+
+// the memory backend has no SQL engine to run statements with
+type consoleRepository struct{}
+
+func (consoleRepository) Exec(ctx context.Context, statement string, write bool, limit int, args ...any) (repository.ConsoleResult, error) {
+	return repository.ConsoleResult{}, repository.ErrNotSupported
+}
+
+func (consoleRepository) Report(ctx context.Context, statement string, args ...any) (repository.ConsoleResult, error) {
+	return repository.ConsoleResult{}, repository.ErrNotSupported
+}
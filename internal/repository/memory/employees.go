@@ -0,0 +1,287 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/dto"
+	"university-db-admin/internal/repository"
+)
+
+type employeesRepository struct {
+	s *store
+}
+
+var employeesList = listSpec[domain.Employee]{
+	table: "employees",
+	fields: map[string]column[domain.Employee]{
+		"id":          intColumn(func(e domain.Employee) uint64 { return e.ID }),
+		"name":        textColumn(func(e domain.Employee) string { return e.Name }),
+		"passport":    textColumn(func(e domain.Employee) string { return e.Passport }),
+		"position_id": intColumn(func(e domain.Employee) uint64 { return e.PositionID }),
+		"deleted_at":  deletedAtColumn(func(e domain.Employee) *time.Time { return e.DeletedAt }),
+	},
+	order:  byID(func(e domain.Employee) uint64 { return e.ID }),
+	id:     func(e domain.Employee) uint64 { return e.ID },
+	keyset: true,
+
+	deletedAt: func(e domain.Employee) *time.Time { return e.DeletedAt },
+}
+
+// checks the constraints of an active employee row, emp.ID is 0 for new
+// rows; like the partial unique index of postgres the passport is only
+// unique among the active employees
+func (t *tables) checkEmployee(emp domain.Employee) error {
+	for _, e := range t.employees {
+		if e.ID != emp.ID && e.DeletedAt == nil && e.Passport == emp.Passport {
+			return &repository.DuplicateError{Table: "employees", Field: "passport", Value: emp.Passport}
+		}
+	}
+	if _, ok := t.positions[emp.PositionID]; !ok {
+		return &repository.InvalidReferenceError{Table: "employees", Field: "position_id", ReferencedTable: "positions"}
+	}
+	return nil
+}
+
+func (t *tables) insertEmployee(ctx context.Context, emp domain.Employee) (uint64, error) {
+	emp.ID, emp.DeletedAt = 0, nil
+	if err := t.checkEmployee(emp); err != nil {
+		return 0, err
+	}
+	emp.ID = t.seq.next("employees")
+	t.employees[emp.ID] = emp
+	t.record(ctx, "employees", recordID(emp.ID), nil, emp)
+	return emp.ID, nil
+}
+
+// returns the employee with id unless it is in the trash
+func (t *tables) activeEmployee(id uint64) (domain.Employee, bool) {
+	emp, ok := t.employees[id]
+	if !ok || emp.DeletedAt != nil {
+		return domain.Employee{}, false
+	}
+	return emp, true
+}
+
+// active employees in id order
+func (t *tables) activeEmployees() []domain.Employee {
+	var emps []domain.Employee
+	for _, emp := range sorted(t.employees) {
+		if emp.DeletedAt == nil {
+			emps = append(emps, emp)
+		}
+	}
+	return emps
+}
+
+func (e *employeesRepository) Create(ctx context.Context, emp domain.Employee) (id uint64, err error) {
+	err = e.s.write(func(t *tables) error {
+		id, err = t.insertEmployee(ctx, emp)
+		return err
+	})
+	return id, err
+}
+
+func (e *employeesRepository) CopyFrom(ctx context.Context, emps []domain.Employee) (int64, error) {
+	err := e.s.atomic(func(t *tables) error {
+		for _, emp := range emps {
+			if _, err := t.insertEmployee(ctx, emp); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(emps)), nil
+}
+
+func (e *employeesRepository) FindOne(ctx context.Context, id uint64) (domain.Employee, error) {
+	var emp domain.Employee
+	err := e.s.read(func(t *tables) error {
+		var ok bool
+		if emp, ok = t.activeEmployee(id); !ok {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+	return emp, err
+}
+
+func (e *employeesRepository) FindAll(ctx context.Context) ([]domain.Employee, error) {
+	return e.find(func(emp domain.Employee) bool { return true })
+}
+
+func (e *employeesRepository) List(ctx context.Context, opts repository.ListOptions) (repository.Page[domain.Employee], error) {
+	var page repository.Page[domain.Employee]
+	err := e.s.read(func(t *tables) (err error) {
+		page, err = list(employeesList, sorted(t.employees), opts)
+		return err
+	})
+	return page, err
+}
+
+// returns the active employees accepted by match
+func (e *employeesRepository) find(match func(emp domain.Employee) bool) ([]domain.Employee, error) {
+	var emps []domain.Employee
+	err := e.s.read(func(t *tables) error {
+		for _, emp := range t.activeEmployees() {
+			if match(emp) {
+				emps = append(emps, emp)
+			}
+		}
+		return nil
+	})
+	return emps, err
+}
+
+func (e *employeesRepository) FindByName(ctx context.Context, name string) ([]domain.Employee, error) {
+	return e.find(func(emp domain.Employee) bool { return emp.Name == name })
+}
+
+func (e *employeesRepository) FindByPassport(ctx context.Context, passport string) (domain.Employee, error) {
+	emps, err := e.find(func(emp domain.Employee) bool { return emp.Passport == passport })
+	if err != nil {
+		return domain.Employee{}, err
+	}
+	if len(emps) == 0 {
+		return domain.Employee{}, repository.ErrNotFound
+	}
+	return emps[0], nil
+}
+
+func (e *employeesRepository) FindByPosition(ctx context.Context, position uint64) ([]domain.Employee, error) {
+	return e.find(func(emp domain.Employee) bool { return emp.PositionID == position })
+}
+
+func (e *employeesRepository) FindAllNamePassport(ctx context.Context) ([]dto.EmployeeDTO, error) {
+	emps, err := e.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []dto.EmployeeDTO
+	for _, emp := range emps {
+		result = append(result, dto.EmployeeDTO{Name: emp.Name, Passport: emp.Passport})
+	}
+	return result, nil
+}
+
+func (e *employeesRepository) FindNamePassportByID(ctx context.Context, id uint64) (dto.EmployeeDTO, error) {
+	emp, err := e.FindOne(ctx, id)
+	if err != nil {
+		return dto.EmployeeDTO{}, err
+	}
+	return dto.EmployeeDTO{Name: emp.Name, Passport: emp.Passport}, nil
+}
+
+func (e *employeesRepository) FindAllByPositions(ctx context.Context, firstID, secondID uint64) ([]dto.EmployeePositionDTO, error) {
+	emps, err := e.find(func(emp domain.Employee) bool {
+		return emp.PositionID == firstID || emp.PositionID == secondID
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var result []dto.EmployeePositionDTO
+	for _, emp := range emps {
+		result = append(result, dto.EmployeePositionDTO{Name: emp.Name})
+	}
+	return result, nil
+}
+
+func (e *employeesRepository) FindByCapability(ctx context.Context, c domain.Capability) ([]domain.Employee, error) {
+	if !slices.Contains(domain.Capabilities, c) {
+		return nil, fmt.Errorf("unknown capability %q", c)
+	}
+	var emps []domain.Employee
+	err := e.s.read(func(t *tables) error {
+		for _, emp := range t.activeEmployees() {
+			if t.positions[emp.PositionID].Can(c) {
+				emps = append(emps, emp)
+			}
+		}
+		return nil
+	})
+	return emps, err
+}
+
+// tells whether the employee is active and holds a position with the capability
+func (e *employeesRepository) can(id uint64, c domain.Capability) (bool, error) {
+	if !slices.Contains(domain.Capabilities, c) {
+		return false, fmt.Errorf("unknown capability %q", c)
+	}
+	var ok bool
+	err := e.s.read(func(t *tables) error {
+		if emp, active := t.activeEmployee(id); active {
+			ok = t.positions[emp.PositionID].Can(c)
+		}
+		return nil
+	})
+	return ok, err
+}
+
+func (e *employeesRepository) IsTeacher(ctx context.Context, id uint64) (dto.EmployeeRoleDTO, error) {
+	var role dto.EmployeeRoleDTO
+	var err error
+	role.IsTeacher, err = e.can(id, domain.CapabilityTeach)
+	return role, err
+}
+
+func (e *employeesRepository) IsCurator(ctx context.Context, id uint64) (dto.EmployeeRoleDTO, error) {
+	var role dto.EmployeeRoleDTO
+	var err error
+	role.IsCurator, err = e.can(id, domain.CapabilityCurate)
+	return role, err
+}
+
+func (e *employeesRepository) Update(ctx context.Context, id uint64, emp domain.Employee) error {
+	return e.s.write(func(t *tables) error {
+		old, ok := t.activeEmployee(id)
+		if !ok {
+			return repository.ErrNotFound
+		}
+		emp.ID, emp.DeletedAt = id, nil
+		if err := t.checkEmployee(emp); err != nil {
+			return err
+		}
+		t.employees[id] = emp
+		t.record(ctx, "employees", recordID(id), old, emp)
+		return nil
+	})
+}
+
+func (e *employeesRepository) Delete(ctx context.Context, id uint64) error {
+	return e.s.write(func(t *tables) error {
+		old, ok := t.activeEmployee(id)
+		if !ok {
+			return repository.ErrNotFound
+		}
+		emp := old
+		now := time.Now()
+		emp.DeletedAt = &now
+		t.employees[id] = emp
+		t.record(ctx, "employees", recordID(id), old, emp)
+		return nil
+	})
+}
+
+func (e *employeesRepository) Restore(ctx context.Context, id uint64) error {
+	return e.s.write(func(t *tables) error {
+		old, ok := t.employees[id]
+		if !ok || old.DeletedAt == nil {
+			return repository.ErrNotFound
+		}
+		emp := old
+		emp.DeletedAt = nil
+		if err := t.checkEmployee(emp); err != nil {
+			return err
+		}
+		t.employees[id] = emp
+		t.record(ctx, "employees", recordID(id), old, emp)
+		return nil
+	})
+}
@@ -0,0 +1,162 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+)
+
+type employeesSubjectsRepository struct {
+	s *store
+}
+
+var employeesSubjectsList = listSpec[domain.EmployeeSubject]{
+	table: "employees_subjects",
+	fields: map[string]column[domain.EmployeeSubject]{
+		"employee_id": intColumn(func(es domain.EmployeeSubject) uint64 { return es.EmployeeID }),
+		"subject_id":  intColumn(func(es domain.EmployeeSubject) uint64 { return es.SubjectID }),
+	},
+	order:  compareEmployeeSubject,
+	keyset: false,
+}
+
+func compareEmployeeSubject(a, b domain.EmployeeSubject) int {
+	if c := cmp.Compare(a.EmployeeID, b.EmployeeID); c != 0 {
+		return c
+	}
+	return cmp.Compare(a.SubjectID, b.SubjectID)
+}
+
+func employeeSubjectID(es domain.EmployeeSubject) string {
+	return fmt.Sprintf("%d/%d", es.EmployeeID, es.SubjectID)
+}
+
+// assignments ordered by employee and subject
+func (t *tables) sortedEmployeesSubjects() []domain.EmployeeSubject {
+	return slices.SortedFunc(maps.Keys(t.employeesSubjects), compareEmployeeSubject)
+}
+
+// checks the constraints of an assignment, old is the key being replaced
+func (t *tables) checkEmployeeSubject(es domain.EmployeeSubject, old *domain.EmployeeSubject) error {
+	if _, ok := t.employeesSubjects[es]; ok && (old == nil || *old != es) {
+		return &repository.DuplicateError{
+			Table: "employees_subjects",
+			Field: "employee_id, subject_id",
+			Value: fmt.Sprintf("%d, %d", es.EmployeeID, es.SubjectID),
+		}
+	}
+	if _, ok := t.employees[es.EmployeeID]; !ok {
+		return &repository.InvalidReferenceError{Table: "employees_subjects", Field: "employee_id", ReferencedTable: "employees"}
+	}
+	if _, ok := t.subjects[es.SubjectID]; !ok {
+		return &repository.InvalidReferenceError{Table: "employees_subjects", Field: "subject_id", ReferencedTable: "subjects"}
+	}
+	return nil
+}
+
+// lessons given by the teacher of the assignment keep it from being
+// changed or removed
+func (t *tables) checkEmployeeSubjectUnused(es domain.EmployeeSubject) error {
+	for _, lsn := range t.lessons {
+		if lsn.EmployeeID != nil && *lsn.EmployeeID == es.EmployeeID && lsn.SubjectID == es.SubjectID {
+			return &repository.ReferencedError{Table: "employees_subjects", ReferencingTable: "lessons"}
+		}
+	}
+	return nil
+}
+
+// removes the assignments accepted by match, the way ON DELETE CASCADE does
+func (t *tables) deleteEmployeesSubjects(ctx context.Context, match func(es domain.EmployeeSubject) bool) {
+	for _, es := range t.sortedEmployeesSubjects() {
+		if match(es) {
+			delete(t.employeesSubjects, es)
+			t.record(ctx, "employees_subjects", employeeSubjectID(es), es, nil)
+		}
+	}
+}
+
+func (s *employeesSubjectsRepository) Create(ctx context.Context, es domain.EmployeeSubject) error {
+	return s.s.write(func(t *tables) error {
+		if err := t.checkEmployeeSubject(es, nil); err != nil {
+			return err
+		}
+		t.employeesSubjects[es] = struct{}{}
+		t.record(ctx, "employees_subjects", employeeSubjectID(es), nil, es)
+		return nil
+	})
+}
+
+func (s *employeesSubjectsRepository) FindAll(ctx context.Context) ([]domain.EmployeeSubject, error) {
+	return s.find(func(es domain.EmployeeSubject) bool { return true })
+}
+
+func (s *employeesSubjectsRepository) List(ctx context.Context, opts repository.ListOptions) (repository.Page[domain.EmployeeSubject], error) {
+	var page repository.Page[domain.EmployeeSubject]
+	err := s.s.read(func(t *tables) (err error) {
+		page, err = list(employeesSubjectsList, t.sortedEmployeesSubjects(), opts)
+		return err
+	})
+	return page, err
+}
+
+// returns the assignments accepted by match
+func (s *employeesSubjectsRepository) find(match func(es domain.EmployeeSubject) bool) ([]domain.EmployeeSubject, error) {
+	var result []domain.EmployeeSubject
+	err := s.s.read(func(t *tables) error {
+		for _, es := range t.sortedEmployeesSubjects() {
+			if match(es) {
+				result = append(result, es)
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+func (s *employeesSubjectsRepository) FindByEmployeeID(ctx context.Context, id uint64) ([]domain.EmployeeSubject, error) {
+	return s.find(func(es domain.EmployeeSubject) bool { return es.EmployeeID == id })
+}
+
+func (s *employeesSubjectsRepository) FindBySubjectID(ctx context.Context, id uint64) ([]domain.EmployeeSubject, error) {
+	return s.find(func(es domain.EmployeeSubject) bool { return es.SubjectID == id })
+}
+
+func (s *employeesSubjectsRepository) Update(ctx context.Context, eid uint64, sid uint64, es domain.EmployeeSubject) error {
+	return s.s.write(func(t *tables) error {
+		old := domain.EmployeeSubject{EmployeeID: eid, SubjectID: sid}
+		if _, ok := t.employeesSubjects[old]; !ok {
+			return repository.ErrNotFound
+		}
+		if err := t.checkEmployeeSubject(es, &old); err != nil {
+			return err
+		}
+		if es != old {
+			if err := t.checkEmployeeSubjectUnused(old); err != nil {
+				return err
+			}
+		}
+		delete(t.employeesSubjects, old)
+		t.employeesSubjects[es] = struct{}{}
+		t.record(ctx, "employees_subjects", employeeSubjectID(es), old, es)
+		return nil
+	})
+}
+
+func (s *employeesSubjectsRepository) Delete(ctx context.Context, eid uint64, sid uint64) error {
+	return s.s.write(func(t *tables) error {
+		old := domain.EmployeeSubject{EmployeeID: eid, SubjectID: sid}
+		if _, ok := t.employeesSubjects[old]; !ok {
+			return repository.ErrNotFound
+		}
+		if err := t.checkEmployeeSubjectUnused(old); err != nil {
+			return err
+		}
+		delete(t.employeesSubjects, old)
+		t.record(ctx, "employees_subjects", employeeSubjectID(old), old, nil)
+		return nil
+	})
+}
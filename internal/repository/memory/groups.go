@@ -0,0 +1,161 @@
+package memory
+
+import (
+	"context"
+	"time"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+)
+
+type groupsRepository struct {
+	s *store
+}
+
+var groupsList = listSpec[domain.Group]{
+	table: "groups",
+	fields: map[string]column[domain.Group]{
+		"id":         intColumn(func(g domain.Group) uint64 { return g.ID }),
+		"number":     intColumn(func(g domain.Group) uint64 { return g.Number }),
+		"deleted_at": deletedAtColumn(func(g domain.Group) *time.Time { return g.DeletedAt }),
+	},
+	order:  byID(func(g domain.Group) uint64 { return g.ID }),
+	id:     func(g domain.Group) uint64 { return g.ID },
+	keyset: true,
+
+	deletedAt: func(g domain.Group) *time.Time { return g.DeletedAt },
+}
+
+// checks the constraints of an active group row, grp.ID is 0 for new
+// rows; the number is only unique among the active groups
+func (t *tables) checkGroup(grp domain.Group) error {
+	if grp.Number == 0 {
+		return &repository.CheckViolationError{Table: "groups", Constraint: "groups_number_check"}
+	}
+	for _, g := range t.groups {
+		if g.ID != grp.ID && g.DeletedAt == nil && g.Number == grp.Number {
+			return &repository.DuplicateError{Table: "groups", Field: "number", Value: recordID(grp.Number)}
+		}
+	}
+	return nil
+}
+
+// returns the group with id unless it is in the trash
+func (t *tables) activeGroup(id uint64) (domain.Group, bool) {
+	grp, ok := t.groups[id]
+	if !ok || grp.DeletedAt != nil {
+		return domain.Group{}, false
+	}
+	return grp, true
+}
+
+func (g *groupsRepository) Create(ctx context.Context, grp domain.Group) (uint64, error) {
+	err := g.s.write(func(t *tables) error {
+		grp.ID, grp.DeletedAt = 0, nil
+		if err := t.checkGroup(grp); err != nil {
+			return err
+		}
+		grp.ID = t.seq.next("groups")
+		t.groups[grp.ID] = grp
+		t.record(ctx, "groups", recordID(grp.ID), nil, grp)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return grp.ID, nil
+}
+
+func (g *groupsRepository) FindOne(ctx context.Context, id uint64) (domain.Group, error) {
+	var grp domain.Group
+	err := g.s.read(func(t *tables) error {
+		var ok bool
+		if grp, ok = t.activeGroup(id); !ok {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+	return grp, err
+}
+
+func (g *groupsRepository) FindAll(ctx context.Context) ([]domain.Group, error) {
+	var groups []domain.Group
+	err := g.s.read(func(t *tables) error {
+		for _, grp := range sorted(t.groups) {
+			if grp.DeletedAt == nil {
+				groups = append(groups, grp)
+			}
+		}
+		return nil
+	})
+	return groups, err
+}
+
+func (g *groupsRepository) List(ctx context.Context, opts repository.ListOptions) (repository.Page[domain.Group], error) {
+	var page repository.Page[domain.Group]
+	err := g.s.read(func(t *tables) (err error) {
+		page, err = list(groupsList, sorted(t.groups), opts)
+		return err
+	})
+	return page, err
+}
+
+func (g *groupsRepository) FindByNumber(ctx context.Context, num uint64) (domain.Group, error) {
+	groups, err := g.FindAll(ctx)
+	if err != nil {
+		return domain.Group{}, err
+	}
+	for _, grp := range groups {
+		if grp.Number == num {
+			return grp, nil
+		}
+	}
+	return domain.Group{}, repository.ErrNotFound
+}
+
+func (g *groupsRepository) Update(ctx context.Context, id uint64, grp domain.Group) error {
+	return g.s.write(func(t *tables) error {
+		old, ok := t.activeGroup(id)
+		if !ok {
+			return repository.ErrNotFound
+		}
+		grp.ID, grp.DeletedAt = id, nil
+		if err := t.checkGroup(grp); err != nil {
+			return err
+		}
+		t.groups[id] = grp
+		t.record(ctx, "groups", recordID(id), old, grp)
+		return nil
+	})
+}
+
+func (g *groupsRepository) Delete(ctx context.Context, id uint64) error {
+	return g.s.write(func(t *tables) error {
+		old, ok := t.activeGroup(id)
+		if !ok {
+			return repository.ErrNotFound
+		}
+		grp := old
+		now := time.Now()
+		grp.DeletedAt = &now
+		t.groups[id] = grp
+		t.record(ctx, "groups", recordID(id), old, grp)
+		return nil
+	})
+}
+
+func (g *groupsRepository) Restore(ctx context.Context, id uint64) error {
+	return g.s.write(func(t *tables) error {
+		old, ok := t.groups[id]
+		if !ok || old.DeletedAt == nil {
+			return repository.ErrNotFound
+		}
+		grp := old
+		grp.DeletedAt = nil
+		if err := t.checkGroup(grp); err != nil {
+			return err
+		}
+		t.groups[id] = grp
+		t.record(ctx, "groups", recordID(id), old, grp)
+		return nil
+	})
+}
@@ -0,0 +1,125 @@
+package memory
+
+import (
+	"context"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+)
+
+type lessonTypesRepository struct {
+	s *store
+}
+
+var lessonTypesList = listSpec[domain.LessonType]{
+	table: "lesson_types",
+	fields: map[string]column[domain.LessonType]{
+		"id":   intColumn(func(l domain.LessonType) uint64 { return l.ID }),
+		"name": textColumn(func(l domain.LessonType) string { return l.Name }),
+	},
+	order:  byID(func(l domain.LessonType) uint64 { return l.ID }),
+	id:     func(l domain.LessonType) uint64 { return l.ID },
+	keyset: true,
+}
+
+// checks the unique constraints of a lesson type row, lsn.ID is 0 for new rows
+func (t *tables) checkLessonType(lsn domain.LessonType) error {
+	for _, l := range t.lessonTypes {
+		if l.ID != lsn.ID && l.Name == lsn.Name {
+			return &repository.DuplicateError{Table: "lesson_types", Field: "name", Value: lsn.Name}
+		}
+	}
+	return nil
+}
+
+func (l *lessonTypesRepository) Create(ctx context.Context, lsn domain.LessonType) (uint64, error) {
+	err := l.s.write(func(t *tables) error {
+		lsn.ID = 0
+		if err := t.checkLessonType(lsn); err != nil {
+			return err
+		}
+		lsn.ID = t.seq.next("lesson_types")
+		t.lessonTypes[lsn.ID] = lsn
+		t.record(ctx, "lesson_types", recordID(lsn.ID), nil, lsn)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return lsn.ID, nil
+}
+
+func (l *lessonTypesRepository) FindOne(ctx context.Context, id uint64) (domain.LessonType, error) {
+	var lsn domain.LessonType
+	err := l.s.read(func(t *tables) error {
+		var ok bool
+		if lsn, ok = t.lessonTypes[id]; !ok {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+	return lsn, err
+}
+
+func (l *lessonTypesRepository) FindAll(ctx context.Context) ([]domain.LessonType, error) {
+	var lessonTypes []domain.LessonType
+	err := l.s.read(func(t *tables) error {
+		lessonTypes = sorted(t.lessonTypes)
+		return nil
+	})
+	return lessonTypes, err
+}
+
+func (l *lessonTypesRepository) List(ctx context.Context, opts repository.ListOptions) (repository.Page[domain.LessonType], error) {
+	var page repository.Page[domain.LessonType]
+	err := l.s.read(func(t *tables) (err error) {
+		page, err = list(lessonTypesList, sorted(t.lessonTypes), opts)
+		return err
+	})
+	return page, err
+}
+
+func (l *lessonTypesRepository) FindByName(ctx context.Context, name string) (domain.LessonType, error) {
+	lessonTypes, err := l.FindAll(ctx)
+	if err != nil {
+		return domain.LessonType{}, err
+	}
+	for _, lsn := range lessonTypes {
+		if lsn.Name == name {
+			return lsn, nil
+		}
+	}
+	return domain.LessonType{}, repository.ErrNotFound
+}
+
+func (l *lessonTypesRepository) Update(ctx context.Context, id uint64, lsn domain.LessonType) error {
+	return l.s.write(func(t *tables) error {
+		old, ok := t.lessonTypes[id]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		lsn.ID = id
+		if err := t.checkLessonType(lsn); err != nil {
+			return err
+		}
+		t.lessonTypes[id] = lsn
+		t.record(ctx, "lesson_types", recordID(id), old, lsn)
+		return nil
+	})
+}
+
+func (l *lessonTypesRepository) Delete(ctx context.Context, id uint64) error {
+	return l.s.write(func(t *tables) error {
+		old, ok := t.lessonTypes[id]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		for _, lsn := range t.lessons {
+			if lsn.LessonTypeID == id {
+				return &repository.ReferencedError{Table: "lesson_types", ReferencingTable: "lessons"}
+			}
+		}
+		delete(t.lessonTypes, id)
+		t.record(ctx, "lesson_types", recordID(id), old, nil)
+		return nil
+	})
+}
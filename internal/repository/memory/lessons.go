@@ -0,0 +1,240 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/dto"
+	"university-db-admin/internal/repository"
+)
+
+type lessonsRepository struct {
+	s *store
+}
+
+var lessonsList = listSpec[domain.Lesson]{
+	table: "lessons",
+	fields: map[string]column[domain.Lesson]{
+		"id":             intColumn(func(l domain.Lesson) uint64 { return l.ID }),
+		"group_id":       intColumn(func(l domain.Lesson) uint64 { return l.GroupID }),
+		"subject_id":     intColumn(func(l domain.Lesson) uint64 { return l.SubjectID }),
+		"lesson_type_id": intColumn(func(l domain.Lesson) uint64 { return l.LessonTypeID }),
+		"week":           intColumn(func(l domain.Lesson) uint16 { return l.Week }),
+		"weekday":        intColumn(func(l domain.Lesson) uint16 { return l.Weekday }),
+		"pair":           intColumn(func(l domain.Lesson) uint16 { return l.Pair }),
+		"room":           intColumn(func(l domain.Lesson) uint64 { return l.Room }),
+		"employee_id":    nullIntColumn(func(l domain.Lesson) *uint64 { return l.EmployeeID }),
+	},
+	order:  byID(func(l domain.Lesson) uint64 { return l.ID }),
+	id:     func(l domain.Lesson) uint64 { return l.ID },
+	keyset: true,
+}
+
+// checks the constraints of a lesson row, id is the row being replaced
+// and 0 for new rows
+func (t *tables) checkLesson(lsn domain.Lesson, id uint64) error {
+	switch {
+	case lsn.Week == 0:
+		return &repository.CheckViolationError{Table: "lessons", Constraint: "lessons_week_check"}
+	case lsn.Weekday < 1 || lsn.Weekday > 7:
+		return &repository.CheckViolationError{Table: "lessons", Constraint: "lessons_weekday_check"}
+	case lsn.Pair == 0:
+		return &repository.CheckViolationError{Table: "lessons", Constraint: "lessons_pair_check"}
+	case lsn.Room == 0:
+		return &repository.CheckViolationError{Table: "lessons", Constraint: "lessons_room_check"}
+	}
+	if err := t.checkSlot(lsn, id); err != nil {
+		return err
+	}
+
+	// the teacher must be linked to the subject, lessons without one aren't checked
+	if lsn.EmployeeID != nil {
+		if _, ok := t.employeesSubjects[domain.EmployeeSubject{EmployeeID: *lsn.EmployeeID, SubjectID: lsn.SubjectID}]; !ok {
+			return &repository.InvalidReferenceError{Table: "lessons", Field: "employee_id, subject_id", ReferencedTable: "employees_subjects"}
+		}
+	}
+	if _, ok := t.groups[lsn.GroupID]; !ok {
+		return &repository.InvalidReferenceError{Table: "lessons", Field: "group_id", ReferencedTable: "groups"}
+	}
+	if _, ok := t.subjects[lsn.SubjectID]; !ok {
+		return &repository.InvalidReferenceError{Table: "lessons", Field: "subject_id", ReferencedTable: "subjects"}
+	}
+	if _, ok := t.lessonTypes[lsn.LessonTypeID]; !ok {
+		return &repository.InvalidReferenceError{Table: "lessons", Field: "lesson_type_id", ReferencedTable: "lesson_types"}
+	}
+	return nil
+}
+
+// the group, the teacher and the room of a lesson are unique per pair,
+// like the lessons_*_slot_key indexes
+func (t *tables) checkSlot(lsn domain.Lesson, id uint64) error {
+	for _, other := range sorted(t.lessons) {
+		if other.ID == id || other.Week != lsn.Week || other.Weekday != lsn.Weekday || other.Pair != lsn.Pair {
+			continue
+		}
+		switch {
+		case other.GroupID == lsn.GroupID:
+			return slotTaken("group_id", lsn.GroupID, lsn)
+		case lsn.EmployeeID != nil && other.EmployeeID != nil && *other.EmployeeID == *lsn.EmployeeID:
+			return slotTaken("employee_id", *lsn.EmployeeID, lsn)
+		case other.Room == lsn.Room:
+			return slotTaken("room", lsn.Room, lsn)
+		}
+	}
+	return nil
+}
+
+func slotTaken(field string, value uint64, lsn domain.Lesson) error {
+	return &repository.DuplicateError{
+		Table: "lessons",
+		Field: field + ", week, weekday, pair",
+		Value: fmt.Sprintf("%d, %d, %d, %d", value, lsn.Week, lsn.Weekday, lsn.Pair),
+	}
+}
+
+func (l *lessonsRepository) Create(ctx context.Context, lsn domain.Lesson) (uint64, error) {
+	err := l.s.write(func(t *tables) error {
+		if err := t.checkLesson(lsn, 0); err != nil {
+			return err
+		}
+		lsn.ID = t.seq.next("lessons")
+		t.lessons[lsn.ID] = lsn
+		t.record(ctx, "lessons", recordID(lsn.ID), nil, lsn)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return lsn.ID, nil
+}
+
+func (l *lessonsRepository) FindOne(ctx context.Context, id uint64) (domain.Lesson, error) {
+	var lsn domain.Lesson
+	err := l.s.read(func(t *tables) error {
+		var ok bool
+		if lsn, ok = t.lessons[id]; !ok {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+	return lsn, err
+}
+
+func (l *lessonsRepository) FindAll(ctx context.Context) ([]domain.Lesson, error) {
+	return l.find(func(lsn domain.Lesson) bool { return true })
+}
+
+func (l *lessonsRepository) List(ctx context.Context, opts repository.ListOptions) (repository.Page[domain.Lesson], error) {
+	var page repository.Page[domain.Lesson]
+	err := l.s.read(func(t *tables) (err error) {
+		page, err = list(lessonsList, sorted(t.lessons), opts)
+		return err
+	})
+	return page, err
+}
+
+// returns the lessons accepted by match
+func (l *lessonsRepository) find(match func(lsn domain.Lesson) bool) ([]domain.Lesson, error) {
+	var lessons []domain.Lesson
+	err := l.s.read(func(t *tables) error {
+		for _, lsn := range sorted(t.lessons) {
+			if match(lsn) {
+				lessons = append(lessons, lsn)
+			}
+		}
+		return nil
+	})
+	return lessons, err
+}
+
+func (l *lessonsRepository) FindByGroupID(ctx context.Context, id uint64) ([]domain.Lesson, error) {
+	return l.find(func(lsn domain.Lesson) bool { return lsn.GroupID == id })
+}
+
+func (l *lessonsRepository) FindBySubjectID(ctx context.Context, id uint64) ([]domain.Lesson, error) {
+	return l.find(func(lsn domain.Lesson) bool { return lsn.SubjectID == id })
+}
+
+func (l *lessonsRepository) FindByLessonTypeID(ctx context.Context, id uint64) ([]domain.Lesson, error) {
+	return l.find(func(lsn domain.Lesson) bool { return lsn.LessonTypeID == id })
+}
+
+func (l *lessonsRepository) FindByWeek(ctx context.Context, week uint16) ([]domain.Lesson, error) {
+	return l.find(func(lsn domain.Lesson) bool { return lsn.Week == week })
+}
+
+func (l *lessonsRepository) FindByWeekday(ctx context.Context, weekday uint16) ([]domain.Lesson, error) {
+	return l.find(func(lsn domain.Lesson) bool { return lsn.Weekday == weekday })
+}
+
+func (l *lessonsRepository) FindByRoom(ctx context.Context, room uint64) ([]domain.Lesson, error) {
+	return l.find(func(lsn domain.Lesson) bool { return lsn.Room == room })
+}
+
+// lessons of trashed groups are left out of the schedule, the teacher is
+// empty for lessons without one
+func (l *lessonsRepository) FindSchedule(ctx context.Context) ([]dto.LessonScheduleDTO, error) {
+	var result []dto.LessonScheduleDTO
+	err := l.s.read(func(t *tables) error {
+		for _, lsn := range sorted(t.lessons) {
+			grp, ok := t.activeGroup(lsn.GroupID)
+			if !ok {
+				continue
+			}
+			var teacher string
+			if lsn.EmployeeID != nil {
+				teacher = t.employees[*lsn.EmployeeID].Name
+			}
+			result = append(result, dto.LessonScheduleDTO{
+				GroupNumber: grp.Number,
+				Subject:     t.subjects[lsn.SubjectID].Name,
+				LessonType:  t.lessonTypes[lsn.LessonTypeID].Name,
+				Teacher:     teacher,
+				Room:        lsn.Room,
+				Week:        lsn.Week,
+				Weekday:     lsn.Weekday,
+				Pair:        lsn.Pair,
+			})
+		}
+		return nil
+	})
+	slices.SortStableFunc(result, func(a, b dto.LessonScheduleDTO) int {
+		return cmp.Or(
+			cmp.Compare(a.Week, b.Week),
+			cmp.Compare(a.Weekday, b.Weekday),
+			cmp.Compare(a.Pair, b.Pair),
+			cmp.Compare(a.GroupNumber, b.GroupNumber),
+		)
+	})
+	return result, err
+}
+
+func (l *lessonsRepository) Update(ctx context.Context, id uint64, lsn domain.Lesson) error {
+	return l.s.write(func(t *tables) error {
+		old, ok := t.lessons[id]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		lsn.ID = id
+		if err := t.checkLesson(lsn, id); err != nil {
+			return err
+		}
+		t.lessons[id] = lsn
+		t.record(ctx, "lessons", recordID(id), old, lsn)
+		return nil
+	})
+}
+
+func (l *lessonsRepository) Delete(ctx context.Context, id uint64) error {
+	return l.s.write(func(t *tables) error {
+		old, ok := t.lessons[id]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		delete(t.lessons, id)
+		t.record(ctx, "lessons", recordID(id), old, nil)
+		return nil
+	})
+}
@@ -0,0 +1,304 @@
+package memory
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"university-db-admin/internal/repository"
+)
+
+// kind is the column type deciding how filter values are parsed and compared
+type kind int
+
+const (
+	kindInt kind = iota
+	kindText
+	kindDate
+	kindTimestamp
+	kindBool
+)
+
+// column reads one column of a row, get returns nil for NULL
+type column[T any] struct {
+	kind kind
+	get  func(row T) any
+}
+
+// listSpec describes how List filters and orders the rows of a table,
+// fields are the columns allowed in sort and filter options
+type listSpec[T any] struct {
+	table  string
+	fields map[string]column[T]
+	order  func(a, b T) int // default ordering
+	id     func(row T) uint64
+
+	keyset    bool                   // table has an id column usable as a keyset cursor
+	deletedAt func(row T) *time.Time // set for tables with soft delete
+}
+
+// timestamps are printed the way postgres casts them to text
+const timestampText = "2006-01-02 15:04:05.999999-07"
+
+// parses a filter value as postgres would cast it to the column type
+func (k kind) parse(value string) (any, error) {
+	switch k {
+	case kindInt:
+		n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid input syntax for type bigint: %q", repository.ErrInvalidOption, value)
+		}
+		return n, nil
+	case kindDate:
+		d, err := time.Parse(time.DateOnly, strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid input syntax for type date: %q", repository.ErrInvalidOption, value)
+		}
+		return d, nil
+	case kindTimestamp:
+		value = strings.TrimSpace(value)
+		for _, layout := range []string{time.RFC3339Nano, timestampText, time.DateTime, time.DateOnly} {
+			if ts, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+				return ts, nil
+			}
+		}
+		return nil, fmt.Errorf("%w: invalid input syntax for type timestamp with time zone: %q", repository.ErrInvalidOption, value)
+	case kindBool:
+		switch strings.ToLower(strings.TrimSpace(value)) {
+		case "t", "true", "y", "yes", "on", "1":
+			return true, nil
+		case "f", "false", "n", "no", "off", "0":
+			return false, nil
+		}
+		return nil, fmt.Errorf("%w: invalid input syntax for type boolean: %q", repository.ErrInvalidOption, value)
+	}
+	return value, nil
+}
+
+// text is the value of CAST(column AS TEXT)
+func (k kind) text(v any) string {
+	switch k {
+	case kindDate:
+		return v.(time.Time).Format(time.DateOnly)
+	case kindTimestamp:
+		return v.(time.Time).Format(timestampText)
+	}
+	return fmt.Sprint(v)
+}
+
+// compares two non-NULL values of the same column
+func compareValues(a, b any) int {
+	switch a := a.(type) {
+	case int64:
+		return cmp.Compare(a, b.(int64))
+	case string:
+		return strings.Compare(a, b.(string))
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	case bool:
+		// false sorts before true
+		if a == b.(bool) {
+			return 0
+		} else if a {
+			return 1
+		}
+		return -1
+	}
+	panic(fmt.Sprintf("memory: unsupported column value %T", a))
+}
+
+// checks one filter, comparisons with NULL never match like in sql
+func matches(k kind, v any, op repository.FilterOp, want any, pattern string) bool {
+	if v == nil {
+		return false
+	}
+	if op == repository.OpContains {
+		return strings.Contains(strings.ToLower(k.text(v)), pattern)
+	}
+
+	c := compareValues(v, want)
+	switch op {
+	case repository.OpEq:
+		return c == 0
+	case repository.OpNe:
+		return c != 0
+	case repository.OpLt:
+		return c < 0
+	case repository.OpLte:
+		return c <= 0
+	case repository.OpGt:
+		return c > 0
+	case repository.OpGte:
+		return c >= 0
+	}
+	return false
+}
+
+// orders NULLs after all values, they come first in descending order
+func compareNullable(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+	return compareValues(a, b)
+}
+
+// lists rows with the same option handling and paging as postgres
+func list[T any](spec listSpec[T], rows []T, opts repository.ListOptions) (repository.Page[T], error) {
+	type condition struct {
+		col     column[T]
+		op      repository.FilterOp
+		value   any
+		pattern string
+	}
+
+	var conditions []condition
+	for _, f := range opts.Filters {
+		col, ok := spec.fields[f.Field]
+		if !ok {
+			return repository.Page[T]{}, fmt.Errorf("%w: filter by %s", repository.ErrInvalidOption, f.Field)
+		}
+
+		c := condition{col: col, op: f.Op}
+		switch f.Op {
+		case repository.OpContains:
+			c.pattern = strings.ToLower(f.Value)
+		case repository.OpEq, repository.OpNe, repository.OpLt, repository.OpLte, repository.OpGt, repository.OpGte:
+			value, err := col.kind.parse(f.Value)
+			if err != nil {
+				return repository.Page[T]{}, err
+			}
+			c.value = value
+		default:
+			return repository.Page[T]{}, fmt.Errorf("%w: operator %s", repository.ErrInvalidOption, f.Op)
+		}
+		conditions = append(conditions, c)
+	}
+
+	switch opts.Deleted {
+	case repository.ExcludeDeleted:
+	case repository.IncludeDeleted, repository.OnlyDeleted:
+		if spec.deletedAt == nil {
+			return repository.Page[T]{}, fmt.Errorf("%w: %s has no deleted rows", repository.ErrInvalidOption, spec.table)
+		}
+	default:
+		return repository.Page[T]{}, fmt.Errorf("%w: deleted %s", repository.ErrInvalidOption, opts.Deleted)
+	}
+
+	order := spec.order
+	if len(opts.Sort) > 0 {
+		for _, s := range opts.Sort {
+			if _, ok := spec.fields[s.Field]; !ok {
+				return repository.Page[T]{}, fmt.Errorf("%w: sort by %s", repository.ErrInvalidOption, s.Field)
+			}
+		}
+		order = func(a, b T) int {
+			for _, s := range opts.Sort {
+				get := spec.fields[s.Field].get
+				c := compareNullable(get(a), get(b))
+				if s.Desc {
+					c = -c
+				}
+				if c != 0 {
+					return c
+				}
+			}
+			if spec.keyset {
+				// rows with equal sort values keep a stable order between pages
+				return cmp.Compare(spec.id(a), spec.id(b))
+			}
+			return 0
+		}
+	}
+
+	if opts.AfterID > 0 && (!spec.keyset || len(opts.Sort) > 0 || opts.Offset > 0) {
+		return repository.Page[T]{}, fmt.Errorf("%w: cursor requires default ordering", repository.ErrInvalidOption)
+	}
+
+	var filtered []T
+rows:
+	for _, row := range rows {
+		if spec.deletedAt != nil {
+			deleted := spec.deletedAt(row) != nil
+			if deleted && opts.Deleted == repository.ExcludeDeleted || !deleted && opts.Deleted == repository.OnlyDeleted {
+				continue
+			}
+		}
+		for _, c := range conditions {
+			if !matches(c.col.kind, c.col.get(row), c.op, c.value, c.pattern) {
+				continue rows
+			}
+		}
+		filtered = append(filtered, row)
+	}
+	slices.SortStableFunc(filtered, order)
+
+	page := repository.Page[T]{Total: uint64(len(filtered))}
+
+	if opts.AfterID > 0 {
+		i := 0
+		for i < len(filtered) && spec.id(filtered[i]) <= opts.AfterID {
+			i++
+		}
+		filtered = filtered[i:]
+	}
+	filtered = filtered[min(opts.Offset, uint64(len(filtered))):]
+
+	page.Items = filtered
+	if opts.Limit > 0 && uint64(len(filtered)) > opts.Limit {
+		page.Items = filtered[:opts.Limit]
+		if spec.keyset && len(opts.Sort) == 0 {
+			page.NextCursor = spec.id(page.Items[len(page.Items)-1])
+		}
+	}
+	if len(page.Items) == 0 {
+		page.Items = nil
+	}
+	return page, nil
+}
+
+// column helpers shared by the table specs
+
+func intColumn[T any, N uint16 | uint64](get func(row T) N) column[T] {
+	return column[T]{kind: kindInt, get: func(row T) any { return int64(get(row)) }}
+}
+
+// nullable integer column, nil reads as NULL
+func nullIntColumn[T any](get func(row T) *uint64) column[T] {
+	return column[T]{kind: kindInt, get: func(row T) any {
+		if n := get(row); n != nil {
+			return int64(*n)
+		}
+		return nil
+	}}
+}
+
+func textColumn[T any](get func(row T) string) column[T] {
+	return column[T]{kind: kindText, get: func(row T) any { return get(row) }}
+}
+
+func boolColumn[T any](get func(row T) bool) column[T] {
+	return column[T]{kind: kindBool, get: func(row T) any { return get(row) }}
+}
+
+func deletedAtColumn[T any](get func(row T) *time.Time) column[T] {
+	return column[T]{kind: kindTimestamp, get: func(row T) any {
+		if ts := get(row); ts != nil {
+			return *ts
+		}
+		return nil
+	}}
+}
+
+// orders rows by id, the default order of most tables
+func byID[T any](id func(row T) uint64) func(a, b T) int {
+	return func(a, b T) int {
+		return cmp.Compare(id(a), id(b))
+	}
+}
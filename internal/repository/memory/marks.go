@@ -0,0 +1,244 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/dto"
+	"university-db-admin/internal/repository"
+)
+
+type marksRepository struct {
+	s *store
+}
+
+var marksList = listSpec[domain.Mark]{
+	table: "marks",
+	fields: map[string]column[domain.Mark]{
+		"id":          intColumn(func(m domain.Mark) uint64 { return m.ID }),
+		"employee_id": intColumn(func(m domain.Mark) uint64 { return m.EmployeeID }),
+		"student_id":  intColumn(func(m domain.Mark) uint64 { return m.StudentID }),
+		"subject_id":  intColumn(func(m domain.Mark) uint64 { return m.SubjectID }),
+		"mark":        intColumn(func(m domain.Mark) uint16 { return m.Mark }),
+		"date":        {kind: kindDate, get: func(m domain.Mark) any { return m.Date }},
+	},
+	order:  byID(func(m domain.Mark) uint64 { return m.ID }),
+	id:     func(m domain.Mark) uint64 { return m.ID },
+	keyset: true,
+}
+
+// the date column keeps only the day, as scanned by the driver
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// checks the constraints of a mark row
+func (t *tables) checkMark(mark domain.Mark) error {
+	if mark.Mark < 1 || mark.Mark > 10 {
+		return &repository.CheckViolationError{Table: "marks", Constraint: "marks_mark_check"}
+	}
+	if _, ok := t.employees[mark.EmployeeID]; !ok {
+		return &repository.InvalidReferenceError{Table: "marks", Field: "employee_id", ReferencedTable: "employees"}
+	}
+	if _, ok := t.students[mark.StudentID]; !ok {
+		return &repository.InvalidReferenceError{Table: "marks", Field: "student_id", ReferencedTable: "students"}
+	}
+	if _, ok := t.subjects[mark.SubjectID]; !ok {
+		return &repository.InvalidReferenceError{Table: "marks", Field: "subject_id", ReferencedTable: "subjects"}
+	}
+	return nil
+}
+
+// checks that the grader teaches the subject and the group of the student
+// has lessons in it, like the marks_grading_eligibility trigger which runs
+// after the constraints above. Copied marks skip it with a context from
+// repository.WithoutGradingCheck
+func (t *tables) checkEligibility(mark domain.Mark) error {
+	if _, ok := t.employeesSubjects[domain.EmployeeSubject{EmployeeID: mark.EmployeeID, SubjectID: mark.SubjectID}]; !ok {
+		return &repository.CheckViolationError{Table: "marks", Constraint: "marks_grader_subject_check"}
+	}
+	group := t.students[mark.StudentID].GroupID
+	for _, lsn := range t.lessons {
+		if lsn.GroupID == group && lsn.SubjectID == mark.SubjectID {
+			return nil
+		}
+	}
+	return &repository.CheckViolationError{Table: "marks", Constraint: "marks_group_subject_check"}
+}
+
+// marks given before the rule keep their grader, student and subject
+func sameGrading(a, b domain.Mark) bool {
+	return a.EmployeeID == b.EmployeeID && a.StudentID == b.StudentID && a.SubjectID == b.SubjectID
+}
+
+// skipGrading lifts the grading check like the copies of the postgres trigger
+func (t *tables) insertMark(ctx context.Context, mark domain.Mark, skipGrading bool) (uint64, error) {
+	mark.Date = dateOf(mark.Date)
+	if err := t.checkMark(mark); err != nil {
+		return 0, err
+	}
+	if !skipGrading {
+		if err := t.checkEligibility(mark); err != nil {
+			return 0, err
+		}
+	}
+	mark.ID = t.seq.next("marks")
+	t.marks[mark.ID] = mark
+	t.record(ctx, "marks", recordID(mark.ID), nil, mark)
+	return mark.ID, nil
+}
+
+func (m *marksRepository) Create(ctx context.Context, mark domain.Mark) (id uint64, err error) {
+	err = m.s.write(func(t *tables) error {
+		id, err = t.insertMark(ctx, mark, false)
+		return err
+	})
+	return id, err
+}
+
+func (m *marksRepository) CopyFrom(ctx context.Context, marks []domain.Mark) (int64, error) {
+	err := m.s.atomic(func(t *tables) error {
+		for _, mark := range marks {
+			if _, err := t.insertMark(ctx, mark, repository.GradingCheckSkipped(ctx)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(marks)), nil
+}
+
+func (m *marksRepository) FindOne(ctx context.Context, id uint64) (domain.Mark, error) {
+	var mark domain.Mark
+	err := m.s.read(func(t *tables) error {
+		var ok bool
+		if mark, ok = t.marks[id]; !ok {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+	return mark, err
+}
+
+func (m *marksRepository) FindAll(ctx context.Context) ([]domain.Mark, error) {
+	return m.find(func(mark domain.Mark) bool { return true })
+}
+
+func (m *marksRepository) List(ctx context.Context, opts repository.ListOptions) (repository.Page[domain.Mark], error) {
+	var page repository.Page[domain.Mark]
+	err := m.s.read(func(t *tables) (err error) {
+		page, err = list(marksList, sorted(t.marks), opts)
+		return err
+	})
+	return page, err
+}
+
+// returns the marks accepted by match
+func (m *marksRepository) find(match func(mark domain.Mark) bool) ([]domain.Mark, error) {
+	var marks []domain.Mark
+	err := m.s.read(func(t *tables) error {
+		for _, mark := range sorted(t.marks) {
+			if match(mark) {
+				marks = append(marks, mark)
+			}
+		}
+		return nil
+	})
+	return marks, err
+}
+
+func (m *marksRepository) FindByEmployeeID(ctx context.Context, id uint64) ([]domain.Mark, error) {
+	return m.find(func(mark domain.Mark) bool { return mark.EmployeeID == id })
+}
+
+func (m *marksRepository) FindByStudentID(ctx context.Context, id uint64) ([]domain.Mark, error) {
+	return m.find(func(mark domain.Mark) bool { return mark.StudentID == id })
+}
+
+func (m *marksRepository) FindBySubjectID(ctx context.Context, id uint64) ([]domain.Mark, error) {
+	return m.find(func(mark domain.Mark) bool { return mark.SubjectID == id })
+}
+
+func (m *marksRepository) FindByMark(ctx context.Context, mark uint16) ([]domain.Mark, error) {
+	return m.find(func(mk domain.Mark) bool { return mk.Mark == mark })
+}
+
+func (m *marksRepository) FindByDate(ctx context.Context, date string) ([]domain.Mark, error) {
+	day, err := time.Parse(time.DateOnly, date)
+	if err != nil {
+		return nil, fmt.Errorf("SQL Error: invalid input syntax for type date: %q, Code: 22007", date)
+	}
+	return m.find(func(mark domain.Mark) bool { return mark.Date.Equal(day) })
+}
+
+func (m *marksRepository) FindAllBySubject(ctx context.Context, id uint64, mk uint16) ([]dto.MarkBySubjectDTO, error) {
+	marks, err := m.find(func(mark domain.Mark) bool { return mark.SubjectID == id && mark.Mark > mk })
+	if err != nil {
+		return nil, err
+	}
+
+	var result []dto.MarkBySubjectDTO
+	for _, mark := range marks {
+		result = append(result, dto.MarkBySubjectDTO{StudentID: mark.StudentID, Mark: mark.Mark, Date: mark.Date})
+	}
+	return result, nil
+}
+
+// marks ordered by date, the best ones first within a day
+func (m *marksRepository) FindAllSorted(ctx context.Context) ([]dto.SortedMarkDTO, error) {
+	marks, err := m.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(marks, func(a, b domain.Mark) int {
+		if c := a.Date.Compare(b.Date); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.Mark, a.Mark)
+	})
+
+	var result []dto.SortedMarkDTO
+	for _, mark := range marks {
+		result = append(result, dto.SortedMarkDTO{StudentID: mark.StudentID, Mark: mark.Mark, Date: mark.Date})
+	}
+	return result, nil
+}
+
+func (m *marksRepository) Update(ctx context.Context, id uint64, mark domain.Mark) error {
+	return m.s.write(func(t *tables) error {
+		old, ok := t.marks[id]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		mark.ID, mark.Date = id, dateOf(mark.Date)
+		if err := t.checkMark(mark); err != nil {
+			return err
+		}
+		if !sameGrading(old, mark) {
+			if err := t.checkEligibility(mark); err != nil {
+				return err
+			}
+		}
+		t.marks[id] = mark
+		t.record(ctx, "marks", recordID(id), old, mark)
+		return nil
+	})
+}
+
+func (m *marksRepository) Delete(ctx context.Context, id uint64) error {
+	return m.s.write(func(t *tables) error {
+		old, ok := t.marks[id]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		delete(t.marks, id)
+		t.record(ctx, "marks", recordID(id), old, nil)
+		return nil
+	})
+}
@@ -0,0 +1,202 @@
+// Package memory keeps every table in process memory. It follows the
+// semantics of the postgres implementation: ids come from sequences that
+// are never rolled back, unique, foreign key and check constraints produce
+// the same repository errors, deleted rows of soft-deleted tables stay
+// in the trash and every change is written to the audit log.
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+	"university-db-admin/pkg/dbclient"
+)
+
+// sequences hands out ids per table, they are shared by all
+// snapshots of a store so rolled back inserts still consume ids
+type sequences map[string]uint64
+
+func (s sequences) next(table string) uint64 {
+	s[table]++
+	return s[table]
+}
+
+// tables is one snapshot of the database
+type tables struct {
+	seq sequences
+
+	positions         map[uint64]domain.Position
+	employees         map[uint64]domain.Employee
+	groups            map[uint64]domain.Group
+	students          map[uint64]domain.Student
+	subjects          map[uint64]domain.Subject
+	lessonTypes       map[uint64]domain.LessonType
+	lessons           map[uint64]domain.Lesson
+	marks             map[uint64]domain.Mark
+	employeesSubjects map[domain.EmployeeSubject]struct{}
+	audit             []domain.AuditEntry
+	users             map[uint64]domain.User
+}
+
+func newTables() *tables {
+	return &tables{
+		seq:               sequences{},
+		positions:         map[uint64]domain.Position{},
+		employees:         map[uint64]domain.Employee{},
+		groups:            map[uint64]domain.Group{},
+		students:          map[uint64]domain.Student{},
+		subjects:          map[uint64]domain.Subject{},
+		lessonTypes:       map[uint64]domain.LessonType{},
+		lessons:           map[uint64]domain.Lesson{},
+		marks:             map[uint64]domain.Mark{},
+		employeesSubjects: map[domain.EmployeeSubject]struct{}{},
+		users:             map[uint64]domain.User{},
+	}
+}
+
+// copies every table, rows are values so the copy can be changed freely
+func (t *tables) clone() *tables {
+	return &tables{
+		seq:               t.seq,
+		positions:         maps.Clone(t.positions),
+		employees:         maps.Clone(t.employees),
+		groups:            maps.Clone(t.groups),
+		students:          maps.Clone(t.students),
+		subjects:          maps.Clone(t.subjects),
+		lessonTypes:       maps.Clone(t.lessonTypes),
+		lessons:           maps.Clone(t.lessons),
+		marks:             maps.Clone(t.marks),
+		employeesSubjects: maps.Clone(t.employeesSubjects),
+		audit:             slices.Clip(t.audit),
+		users:             maps.Clone(t.users),
+	}
+}
+
+// appends a change to the audit log like the audit_changes trigger does,
+// old is nil for created rows and new is nil for deleted ones
+func (t *tables) record(ctx context.Context, entity, recordID string, old, new any) {
+	var entry domain.AuditEntry
+	switch {
+	case old == nil:
+		entry.Operation = "create"
+	case new == nil:
+		entry.Operation = "delete"
+	default:
+		entry.Operation = "update"
+	}
+
+	if old != nil {
+		entry.OldValues, _ = json.Marshal(old)
+	}
+	if new != nil {
+		entry.NewValues, _ = json.Marshal(new)
+	}
+	if old != nil && new != nil && string(entry.OldValues) == string(entry.NewValues) {
+		return
+	}
+
+	entry.ID = t.seq.next("audit_log")
+	entry.Entity = entity
+	entry.RecordID = recordID
+	entry.Operator = dbclient.Operator(ctx)
+	entry.ChangedAt = time.Now()
+	t.audit = append(t.audit, entry)
+}
+
+// store guards the current snapshot; a transaction holds the write lock
+// until it ends and works on a copy that replaces the snapshot on commit
+type store struct {
+	mu   *sync.RWMutex // nil inside a transaction, its lock is already held
+	data *tables
+}
+
+func (s *store) read(fn func(t *tables) error) error {
+	if s.mu != nil {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+	}
+	return fn(s.data)
+}
+
+// runs fn with exclusive access, a failing fn must leave the tables unchanged
+func (s *store) write(fn func(t *tables) error) error {
+	if s.mu != nil {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	}
+	return fn(s.data)
+}
+
+// creates an empty in-memory database
+func NewRepository() *repository.Repository {
+	return newRepository(&store{mu: &sync.RWMutex{}, data: newTables()})
+}
+
+func newRepository(s *store) *repository.Repository {
+	return &repository.Repository{
+		Transactor:        &transactor{s: s},
+		Employees:         &employeesRepository{s: s},
+		Groups:            &groupsRepository{s: s},
+		LessonTypes:       &lessonTypesRepository{s: s},
+		Lessons:           &lessonsRepository{s: s},
+		Marks:             &marksRepository{s: s},
+		Positions:         &positionsRepository{s: s},
+		Students:          &studentsRepository{s: s},
+		Subjects:          &subjectsRepository{s: s},
+		EmployeesSubjects: &employeesSubjectsRepository{s: s},
+		Audit:             &auditRepository{s: s},
+		Console:           consoleRepository{},
+		Users:             &usersRepository{s: s},
+		Purger:            &purgerRepository{s: s},
+	}
+}
+
+type transactor struct {
+	s *store
+}
+
+func (tr *transactor) WithTx(ctx context.Context, fn func(ctx context.Context, r *repository.Repository) error) error {
+	// inside a transaction the copy acts as a savepoint
+	return tr.s.write(func(t *tables) error {
+		tx := &store{data: t.clone()}
+		if err := fn(ctx, newRepository(tx)); err != nil {
+			return err
+		}
+		*t = *tx.data
+		return nil
+	})
+}
+
+// runs fn on a copy of the tables that replaces them only when fn succeeds,
+// used by bulk operations that must not leave half of their rows behind
+func (s *store) atomic(fn func(t *tables) error) error {
+	return s.write(func(t *tables) error {
+		c := t.clone()
+		if err := fn(c); err != nil {
+			return err
+		}
+		*t = *c
+		return nil
+	})
+}
+
+// sorted returns the rows of a table in id order, postgres returns
+// them in insertion order which matches for rows that were never updated
+func sorted[T any](rows map[uint64]T) []T {
+	ids := slices.Sorted(maps.Keys(rows))
+	result := make([]T, 0, len(ids))
+	for _, id := range ids {
+		result = append(result, rows[id])
+	}
+	return result
+}
+
+func recordID(id uint64) string {
+	return fmt.Sprintf("%d", id)
+}
@@ -0,0 +1,14 @@
+package memory_test
+
+import (
+	"testing"
+	"university-db-admin/internal/repository"
+	"university-db-admin/internal/repository/memory"
+	"university-db-admin/internal/repository/repotest"
+)
+
+func TestRepositoryContract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) *repository.Repository {
+		return memory.NewRepository()
+	})
+}
@@ -0,0 +1,129 @@
+package memory
+
+import (
+	"context"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+)
+
+type positionsRepository struct {
+	s *store
+}
+
+var positionsList = listSpec[domain.Position]{
+	table: "positions",
+	fields: map[string]column[domain.Position]{
+		"id":             intColumn(func(p domain.Position) uint64 { return p.ID }),
+		"name":           textColumn(func(p domain.Position) string { return p.Name }),
+		"can_teach":      boolColumn(func(p domain.Position) bool { return p.CanTeach }),
+		"can_curate":     boolColumn(func(p domain.Position) bool { return p.CanCurate }),
+		"can_administer": boolColumn(func(p domain.Position) bool { return p.CanAdminister }),
+	},
+	order:  byID(func(p domain.Position) uint64 { return p.ID }),
+	id:     func(p domain.Position) uint64 { return p.ID },
+	keyset: true,
+}
+
+// checks the unique constraints of a position row, pos.ID is 0 for new rows
+func (t *tables) checkPosition(pos domain.Position) error {
+	for _, p := range t.positions {
+		if p.ID != pos.ID && p.Name == pos.Name {
+			return &repository.DuplicateError{Table: "positions", Field: "name", Value: pos.Name}
+		}
+	}
+	return nil
+}
+
+func (p *positionsRepository) Create(ctx context.Context, pos domain.Position) (uint64, error) {
+	err := p.s.write(func(t *tables) error {
+		pos.ID = 0
+		if err := t.checkPosition(pos); err != nil {
+			return err
+		}
+		pos.ID = t.seq.next("positions")
+		t.positions[pos.ID] = pos
+		t.record(ctx, "positions", recordID(pos.ID), nil, pos)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return pos.ID, nil
+}
+
+func (p *positionsRepository) FindOne(ctx context.Context, id uint64) (domain.Position, error) {
+	var pos domain.Position
+	err := p.s.read(func(t *tables) error {
+		var ok bool
+		if pos, ok = t.positions[id]; !ok {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+	return pos, err
+}
+
+func (p *positionsRepository) FindAll(ctx context.Context) ([]domain.Position, error) {
+	var positions []domain.Position
+	err := p.s.read(func(t *tables) error {
+		positions = sorted(t.positions)
+		return nil
+	})
+	return positions, err
+}
+
+func (p *positionsRepository) List(ctx context.Context, opts repository.ListOptions) (repository.Page[domain.Position], error) {
+	var page repository.Page[domain.Position]
+	err := p.s.read(func(t *tables) (err error) {
+		page, err = list(positionsList, sorted(t.positions), opts)
+		return err
+	})
+	return page, err
+}
+
+func (p *positionsRepository) FindByName(ctx context.Context, name string) (domain.Position, error) {
+	var pos domain.Position
+	err := p.s.read(func(t *tables) error {
+		for _, row := range sorted(t.positions) {
+			if row.Name == name {
+				pos = row
+				return nil
+			}
+		}
+		return repository.ErrNotFound
+	})
+	return pos, err
+}
+
+func (p *positionsRepository) Update(ctx context.Context, id uint64, pos domain.Position) error {
+	return p.s.write(func(t *tables) error {
+		old, ok := t.positions[id]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		pos.ID = id
+		if err := t.checkPosition(pos); err != nil {
+			return err
+		}
+		t.positions[id] = pos
+		t.record(ctx, "positions", recordID(id), old, pos)
+		return nil
+	})
+}
+
+func (p *positionsRepository) Delete(ctx context.Context, id uint64) error {
+	return p.s.write(func(t *tables) error {
+		old, ok := t.positions[id]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		for _, e := range t.employees {
+			if e.PositionID == id {
+				return &repository.ReferencedError{Table: "positions", ReferencingTable: "employees"}
+			}
+		}
+		delete(t.positions, id)
+		t.record(ctx, "positions", recordID(id), old, nil)
+		return nil
+	})
+}
@@ -0,0 +1,138 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"university-db-admin/internal/repository"
+)
+
+type purgerRepository struct {
+	s *store
+}
+
+// like the postgres DELETE statements: deleted rows are written to the audit
+// log, the subjects of purged employees and subjects go with them and a
+// table still referenced by remaining rows stops the purge
+func (p *purgerRepository) Purge(ctx context.Context, names ...string) error {
+	return p.s.atomic(func(t *tables) error {
+		for _, table := range names {
+			if err := t.purge(ctx, table); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (t *tables) purge(ctx context.Context, table string) error {
+	if referencing := t.referencing(table); referencing != "" {
+		return &repository.ReferencedError{Table: table, ReferencingTable: referencing}
+	}
+
+	switch table {
+	case "positions":
+		purgeRows(ctx, t, table, t.positions)
+	case "employees":
+		t.purgeSubjects(ctx)
+		purgeRows(ctx, t, table, t.employees)
+	case "groups":
+		purgeRows(ctx, t, table, t.groups)
+	case "students":
+		purgeRows(ctx, t, table, t.students)
+	case "subjects":
+		t.purgeSubjects(ctx)
+		purgeRows(ctx, t, table, t.subjects)
+	case "lesson_types":
+		purgeRows(ctx, t, table, t.lessonTypes)
+	case "employees_subjects":
+		t.purgeSubjects(ctx)
+	case "lessons":
+		purgeRows(ctx, t, table, t.lessons)
+	case "marks":
+		purgeRows(ctx, t, table, t.marks)
+	case "users":
+		clear(t.users) // not audited, see usersRepository
+	default:
+		return fmt.Errorf("cant purge unknown table %q", table)
+	}
+	return nil
+}
+
+// a table whose rows reference rows of table, empty when there is none;
+// the subjects of employees and subjects are removed with them, so their
+// lessons with a teacher reference those as well
+func (t *tables) referencing(table string) string {
+	switch table {
+	case "positions":
+		if len(t.employees) > 0 {
+			return "employees"
+		}
+	case "employees":
+		switch {
+		case len(t.students) > 0:
+			return "students"
+		case len(t.marks) > 0:
+			return "marks"
+		case t.taught():
+			return "lessons"
+		}
+		for _, u := range t.users {
+			if u.EmployeeID != nil {
+				return "users"
+			}
+		}
+	case "groups":
+		switch {
+		case len(t.students) > 0:
+			return "students"
+		case len(t.lessons) > 0:
+			return "lessons"
+		}
+	case "students":
+		if len(t.marks) > 0 {
+			return "marks"
+		}
+	case "subjects":
+		switch {
+		case len(t.lessons) > 0:
+			return "lessons"
+		case len(t.marks) > 0:
+			return "marks"
+		}
+	case "lesson_types":
+		if len(t.lessons) > 0 {
+			return "lessons"
+		}
+	case "employees_subjects":
+		if t.taught() {
+			return "lessons"
+		}
+	}
+	return ""
+}
+
+// tells whether a lesson has a teacher
+func (t *tables) taught() bool {
+	for _, lsn := range t.lessons {
+		if lsn.EmployeeID != nil {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *tables) purgeSubjects(ctx context.Context) {
+	for es := range t.employeesSubjects {
+		delete(t.employeesSubjects, es)
+		t.record(ctx, "employees_subjects", employeeSubjectID(es), es, nil)
+	}
+}
+
+func purgeRows[T any](ctx context.Context, t *tables, table string, rows map[uint64]T) {
+	for _, id := range slices.Sorted(maps.Keys(rows)) {
+		t.record(ctx, table, recordID(id), rows[id], nil)
+		delete(rows, id)
+	}
+}
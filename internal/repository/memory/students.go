@@ -0,0 +1,363 @@
+package memory
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/dto"
+	"university-db-admin/internal/repository"
+)
+
+type studentsRepository struct {
+	s *store
+}
+
+var studentsList = listSpec[domain.Student]{
+	table: "students",
+	fields: map[string]column[domain.Student]{
+		"id":          intColumn(func(s domain.Student) uint64 { return s.ID }),
+		"name":        textColumn(func(s domain.Student) string { return s.Name }),
+		"passport":    textColumn(func(s domain.Student) string { return s.Passport }),
+		"employee_id": intColumn(func(s domain.Student) uint64 { return s.EmployeeID }),
+		"group_id":    intColumn(func(s domain.Student) uint64 { return s.GroupID }),
+		"deleted_at":  deletedAtColumn(func(s domain.Student) *time.Time { return s.DeletedAt }),
+	},
+	order:  byID(func(s domain.Student) uint64 { return s.ID }),
+	id:     func(s domain.Student) uint64 { return s.ID },
+	keyset: true,
+
+	deletedAt: func(s domain.Student) *time.Time { return s.DeletedAt },
+}
+
+// checks the constraints of an active student row, stud.ID is 0 for new
+// rows; the passport is only unique among the active students and
+// references to trashed curators and groups are valid as in postgres
+func (t *tables) checkStudent(stud domain.Student) error {
+	for _, s := range t.students {
+		if s.ID != stud.ID && s.DeletedAt == nil && s.Passport == stud.Passport {
+			return &repository.DuplicateError{Table: "students", Field: "passport", Value: stud.Passport}
+		}
+	}
+	if _, ok := t.employees[stud.EmployeeID]; !ok {
+		return &repository.InvalidReferenceError{Table: "students", Field: "employee_id", ReferencedTable: "employees"}
+	}
+	if _, ok := t.groups[stud.GroupID]; !ok {
+		return &repository.InvalidReferenceError{Table: "students", Field: "group_id", ReferencedTable: "groups"}
+	}
+	return nil
+}
+
+func (t *tables) insertStudent(ctx context.Context, stud domain.Student) (uint64, error) {
+	stud.ID, stud.DeletedAt = 0, nil
+	if err := t.checkStudent(stud); err != nil {
+		return 0, err
+	}
+	stud.ID = t.seq.next("students")
+	t.students[stud.ID] = stud
+	t.record(ctx, "students", recordID(stud.ID), nil, stud)
+	return stud.ID, nil
+}
+
+// returns the student with id unless it is in the trash
+func (t *tables) activeStudent(id uint64) (domain.Student, bool) {
+	stud, ok := t.students[id]
+	if !ok || stud.DeletedAt != nil {
+		return domain.Student{}, false
+	}
+	return stud, true
+}
+
+// active students in id order
+func (t *tables) activeStudents() []domain.Student {
+	var studs []domain.Student
+	for _, stud := range sorted(t.students) {
+		if stud.DeletedAt == nil {
+			studs = append(studs, stud)
+		}
+	}
+	return studs
+}
+
+func (s *studentsRepository) Create(ctx context.Context, stud domain.Student) (id uint64, err error) {
+	err = s.s.write(func(t *tables) error {
+		id, err = t.insertStudent(ctx, stud)
+		return err
+	})
+	return id, err
+}
+
+func (s *studentsRepository) CopyFrom(ctx context.Context, studs []domain.Student) (int64, error) {
+	err := s.s.atomic(func(t *tables) error {
+		for _, stud := range studs {
+			if _, err := t.insertStudent(ctx, stud); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(studs)), nil
+}
+
+func (s *studentsRepository) FindOne(ctx context.Context, id uint64) (domain.Student, error) {
+	var stud domain.Student
+	err := s.s.read(func(t *tables) error {
+		var ok bool
+		if stud, ok = t.activeStudent(id); !ok {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+	return stud, err
+}
+
+func (s *studentsRepository) FindAll(ctx context.Context) ([]domain.Student, error) {
+	return s.find(func(stud domain.Student) bool { return true })
+}
+
+func (s *studentsRepository) List(ctx context.Context, opts repository.ListOptions) (repository.Page[domain.Student], error) {
+	var page repository.Page[domain.Student]
+	err := s.s.read(func(t *tables) (err error) {
+		page, err = list(studentsList, sorted(t.students), opts)
+		return err
+	})
+	return page, err
+}
+
+// returns the active students accepted by match
+func (s *studentsRepository) find(match func(stud domain.Student) bool) ([]domain.Student, error) {
+	var studs []domain.Student
+	err := s.s.read(func(t *tables) error {
+		for _, stud := range t.activeStudents() {
+			if match(stud) {
+				studs = append(studs, stud)
+			}
+		}
+		return nil
+	})
+	return studs, err
+}
+
+func (s *studentsRepository) FindByName(ctx context.Context, name string) ([]domain.Student, error) {
+	return s.find(func(stud domain.Student) bool { return stud.Name == name })
+}
+
+func (s *studentsRepository) FindByPassport(ctx context.Context, passport string) (domain.Student, error) {
+	studs, err := s.find(func(stud domain.Student) bool { return stud.Passport == passport })
+	if err != nil {
+		return domain.Student{}, err
+	}
+	if len(studs) == 0 {
+		return domain.Student{}, repository.ErrNotFound
+	}
+	return studs[0], nil
+}
+
+func (s *studentsRepository) FindByEmployeeID(ctx context.Context, id uint64) ([]domain.Student, error) {
+	return s.find(func(stud domain.Student) bool { return stud.EmployeeID == id })
+}
+
+func (s *studentsRepository) FindByGroupID(ctx context.Context, id uint64) ([]domain.Student, error) {
+	return s.find(func(stud domain.Student) bool { return stud.GroupID == id })
+}
+
+// a zero curator id stands for the NULL postgres allows in employee_id
+func (s *studentsRepository) FindAllWithNoCurator(ctx context.Context) ([]dto.StudentNoCuratorDTO, error) {
+	studs, err := s.find(func(stud domain.Student) bool { return stud.EmployeeID == 0 })
+	if err != nil {
+		return nil, err
+	}
+
+	var result []dto.StudentNoCuratorDTO
+	for _, stud := range studs {
+		result = append(result, dto.StudentNoCuratorDTO{Name: stud.Name, Passport: stud.Passport, GroupID: stud.GroupID})
+	}
+	return result, nil
+}
+
+func (s *studentsRepository) FindAllByMiddlename(ctx context.Context, m string) ([]dto.StudentByNameDTO, error) {
+	pattern := like("%" + m)
+	studs, err := s.find(func(stud domain.Student) bool { return pattern.MatchString(stud.Name) })
+	if err != nil {
+		return nil, err
+	}
+
+	var result []dto.StudentByNameDTO
+	for _, stud := range studs {
+		result = append(result, dto.StudentByNameDTO{Name: stud.Name, Passport: stud.Passport})
+	}
+	return result, nil
+}
+
+func (s *studentsRepository) FindAllGroupCombs(ctx context.Context) ([]dto.StudentGroupCombDTO, error) {
+	var result []dto.StudentGroupCombDTO
+	err := s.s.read(func(t *tables) error {
+		for _, stud := range t.activeStudents() {
+			for _, grp := range sorted(t.groups) {
+				if grp.DeletedAt == nil {
+					result = append(result, dto.StudentGroupCombDTO{StudentName: stud.Name, GroupNumber: grp.Number})
+				}
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+// students with their curators, students whose curator is in the trash
+// come with an empty curator
+func (s *studentsRepository) FindAllWithCurators(ctx context.Context) ([]dto.StudentCuratorDTO, error) {
+	var result []dto.StudentCuratorDTO
+	err := s.s.read(func(t *tables) error {
+		for _, stud := range t.activeStudents() {
+			emp, _ := t.activeEmployee(stud.EmployeeID)
+			result = append(result, curatorPair(stud, emp))
+		}
+		return nil
+	})
+	return result, err
+}
+
+// employees with the students they curate, employees without
+// students come with an empty student
+func (s *studentsRepository) FindWithAllCurators(ctx context.Context) ([]dto.StudentCuratorDTO, error) {
+	var result []dto.StudentCuratorDTO
+	err := s.s.read(func(t *tables) error {
+		studs := t.activeStudents()
+		for _, emp := range t.activeEmployees() {
+			found := false
+			for _, stud := range studs {
+				if stud.EmployeeID == emp.ID {
+					result = append(result, curatorPair(stud, emp))
+					found = true
+				}
+			}
+			if !found {
+				result = append(result, curatorPair(domain.Student{}, emp))
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+// students with their curators followed by the employees
+// curating nobody, either side may be empty
+func (s *studentsRepository) FindAllPairsWithCurator(ctx context.Context) ([]dto.StudentCuratorDTO, error) {
+	var result []dto.StudentCuratorDTO
+	err := s.s.read(func(t *tables) error {
+		curators := map[uint64]bool{}
+		for _, stud := range t.activeStudents() {
+			emp, ok := t.activeEmployee(stud.EmployeeID)
+			if ok {
+				curators[emp.ID] = true
+			}
+			result = append(result, curatorPair(stud, emp))
+		}
+		for _, emp := range t.activeEmployees() {
+			if !curators[emp.ID] {
+				result = append(result, curatorPair(domain.Student{}, emp))
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+func curatorPair(stud domain.Student, emp domain.Employee) dto.StudentCuratorDTO {
+	return dto.StudentCuratorDTO{
+		StudentName:     stud.Name,
+		StudentPassport: stud.Passport,
+		CuratorName:     emp.Name,
+		CuratorPassport: emp.Passport,
+	}
+}
+
+// LENGTH counts characters, not bytes
+func (s *studentsRepository) FindAllUppercaseWithLength(ctx context.Context) ([]dto.StudentNameStatDTO, error) {
+	studs, err := s.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var result []dto.StudentNameStatDTO
+	for _, stud := range studs {
+		result = append(result, dto.StudentNameStatDTO{
+			ID:            stud.ID,
+			UppercaseName: strings.ToUpper(stud.Name),
+			NameLength:    uint64(utf8.RuneCountInString(stud.Name)),
+		})
+	}
+	return result, nil
+}
+
+func (s *studentsRepository) Update(ctx context.Context, id uint64, stud domain.Student) error {
+	return s.s.write(func(t *tables) error {
+		old, ok := t.activeStudent(id)
+		if !ok {
+			return repository.ErrNotFound
+		}
+		stud.ID, stud.DeletedAt = id, nil
+		if err := t.checkStudent(stud); err != nil {
+			return err
+		}
+		t.students[id] = stud
+		t.record(ctx, "students", recordID(id), old, stud)
+		return nil
+	})
+}
+
+func (s *studentsRepository) Delete(ctx context.Context, id uint64) error {
+	return s.s.write(func(t *tables) error {
+		old, ok := t.activeStudent(id)
+		if !ok {
+			return repository.ErrNotFound
+		}
+		stud := old
+		now := time.Now()
+		stud.DeletedAt = &now
+		t.students[id] = stud
+		t.record(ctx, "students", recordID(id), old, stud)
+		return nil
+	})
+}
+
+func (s *studentsRepository) Restore(ctx context.Context, id uint64) error {
+	return s.s.write(func(t *tables) error {
+		old, ok := t.students[id]
+		if !ok || old.DeletedAt == nil {
+			return repository.ErrNotFound
+		}
+		stud := old
+		stud.DeletedAt = nil
+		if err := t.checkStudent(stud); err != nil {
+			return err
+		}
+		t.students[id] = stud
+		t.record(ctx, "students", recordID(id), old, stud)
+		return nil
+	})
+}
+
+// compiles a sql LIKE pattern, % matches any text and _ a single character
+func like(pattern string) *regexp.Regexp {
+	var b strings.Builder
+	b.WriteString("^(?s:")
+	for _, r := range pattern {
+		switch r {
+		case '%':
+			b.WriteString(".*")
+		case '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString(")$")
+	return regexp.MustCompile(b.String())
+}
@@ -0,0 +1,152 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/dto"
+	"university-db-admin/internal/repository"
+)
+
+type subjectsRepository struct {
+	s *store
+}
+
+var subjectsList = listSpec[domain.Subject]{
+	table: "subjects",
+	fields: map[string]column[domain.Subject]{
+		"id":   intColumn(func(s domain.Subject) uint64 { return s.ID }),
+		"name": textColumn(func(s domain.Subject) string { return s.Name }),
+	},
+	order:  byID(func(s domain.Subject) uint64 { return s.ID }),
+	id:     func(s domain.Subject) uint64 { return s.ID },
+	keyset: true,
+}
+
+// checks the unique constraints of a subject row, sbj.ID is 0 for new rows
+func (t *tables) checkSubject(sbj domain.Subject) error {
+	for _, s := range t.subjects {
+		if s.ID != sbj.ID && s.Name == sbj.Name {
+			return &repository.DuplicateError{Table: "subjects", Field: "name", Value: sbj.Name}
+		}
+	}
+	return nil
+}
+
+func (s *subjectsRepository) Create(ctx context.Context, sbj domain.Subject) (uint64, error) {
+	err := s.s.write(func(t *tables) error {
+		sbj.ID = 0
+		if err := t.checkSubject(sbj); err != nil {
+			return err
+		}
+		sbj.ID = t.seq.next("subjects")
+		t.subjects[sbj.ID] = sbj
+		t.record(ctx, "subjects", recordID(sbj.ID), nil, sbj)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return sbj.ID, nil
+}
+
+func (s *subjectsRepository) FindOne(ctx context.Context, id uint64) (domain.Subject, error) {
+	var sbj domain.Subject
+	err := s.s.read(func(t *tables) error {
+		var ok bool
+		if sbj, ok = t.subjects[id]; !ok {
+			return repository.ErrNotFound
+		}
+		return nil
+	})
+	return sbj, err
+}
+
+func (s *subjectsRepository) FindAll(ctx context.Context) ([]domain.Subject, error) {
+	var subjects []domain.Subject
+	err := s.s.read(func(t *tables) error {
+		subjects = sorted(t.subjects)
+		return nil
+	})
+	return subjects, err
+}
+
+func (s *subjectsRepository) List(ctx context.Context, opts repository.ListOptions) (repository.Page[domain.Subject], error) {
+	var page repository.Page[domain.Subject]
+	err := s.s.read(func(t *tables) (err error) {
+		page, err = list(subjectsList, sorted(t.subjects), opts)
+		return err
+	})
+	return page, err
+}
+
+func (s *subjectsRepository) FindByName(ctx context.Context, name string) (domain.Subject, error) {
+	subjects, err := s.FindAll(ctx)
+	if err != nil {
+		return domain.Subject{}, err
+	}
+	for _, sbj := range subjects {
+		if sbj.Name == name {
+			return sbj, nil
+		}
+	}
+	return domain.Subject{}, repository.ErrNotFound
+}
+
+func (s *subjectsRepository) FindAllSorted(ctx context.Context) ([]dto.SortedSubjectDTO, error) {
+	subjects, err := s.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(subjects, func(a, b domain.Subject) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	var result []dto.SortedSubjectDTO
+	for _, sbj := range subjects {
+		result = append(result, dto.SortedSubjectDTO{Name: sbj.Name})
+	}
+	return result, nil
+}
+
+func (s *subjectsRepository) Update(ctx context.Context, id uint64, sbj domain.Subject) error {
+	return s.s.write(func(t *tables) error {
+		old, ok := t.subjects[id]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		sbj.ID = id
+		if err := t.checkSubject(sbj); err != nil {
+			return err
+		}
+		t.subjects[id] = sbj
+		t.record(ctx, "subjects", recordID(id), old, sbj)
+		return nil
+	})
+}
+
+// lessons and marks keep a subject from being deleted,
+// teacher assignments are removed together with it
+func (s *subjectsRepository) Delete(ctx context.Context, id uint64) error {
+	return s.s.write(func(t *tables) error {
+		old, ok := t.subjects[id]
+		if !ok {
+			return repository.ErrNotFound
+		}
+		for _, l := range t.lessons {
+			if l.SubjectID == id {
+				return &repository.ReferencedError{Table: "subjects", ReferencingTable: "lessons"}
+			}
+		}
+		for _, m := range t.marks {
+			if m.SubjectID == id {
+				return &repository.ReferencedError{Table: "subjects", ReferencingTable: "marks"}
+			}
+		}
+		delete(t.subjects, id)
+		t.record(ctx, "subjects", recordID(id), old, nil)
+		t.deleteEmployeesSubjects(ctx, func(es domain.EmployeeSubject) bool { return es.SubjectID == id })
+		return nil
+	})
+}
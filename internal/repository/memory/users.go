@@ -0,0 +1,107 @@
+package memory
+
+import (
+	"context"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+)
+
+// accounts aren't written to the audit log, like in postgres
+type usersRepository struct {
+	s *store
+}
+
+// checks the constraints of a user row, u.ID is 0 for new rows
+func (t *tables) checkUser(u domain.User) error {
+	switch u.Role {
+	case domain.RoleAdmin, domain.RoleDean, domain.RoleTeacher, domain.RoleReadOnly:
+	default:
+		return &repository.CheckViolationError{Table: "users", Constraint: "users_role_check"}
+	}
+	if u.Role == domain.RoleTeacher && u.EmployeeID == nil {
+		return &repository.CheckViolationError{Table: "users", Constraint: "users_teacher_check"}
+	}
+	if u.EmployeeID != nil {
+		if _, ok := t.employees[*u.EmployeeID]; !ok {
+			return &repository.InvalidReferenceError{Table: "users", Field: "employee_id", ReferencedTable: "employees"}
+		}
+	}
+	for _, row := range t.users {
+		if row.ID != u.ID && row.Login == u.Login {
+			return &repository.DuplicateError{Table: "users", Field: "login", Value: u.Login}
+		}
+	}
+	return nil
+}
+
+func (r *usersRepository) Create(ctx context.Context, u domain.User) (uint64, error) {
+	err := r.s.write(func(t *tables) error {
+		u.ID = 0
+		if err := t.checkUser(u); err != nil {
+			return err
+		}
+		u.ID = t.seq.next("users")
+		t.users[u.ID] = u
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return u.ID, nil
+}
+
+func (r *usersRepository) FindAll(ctx context.Context) ([]domain.User, error) {
+	var users []domain.User
+	err := r.s.read(func(t *tables) error {
+		users = sorted(t.users)
+		return nil
+	})
+	return users, err
+}
+
+func (r *usersRepository) Count(ctx context.Context) (uint64, error) {
+	var n uint64
+	err := r.s.read(func(t *tables) error {
+		n = uint64(len(t.users))
+		return nil
+	})
+	return n, err
+}
+
+func (r *usersRepository) FindByLogin(ctx context.Context, login string) (domain.User, error) {
+	var u domain.User
+	err := r.s.read(func(t *tables) error {
+		for _, row := range t.users {
+			if row.Login == login {
+				u = row
+				return nil
+			}
+		}
+		return repository.ErrNotFound
+	})
+	return u, err
+}
+
+func (r *usersRepository) Update(ctx context.Context, id uint64, u domain.User) error {
+	return r.s.write(func(t *tables) error {
+		if _, ok := t.users[id]; !ok {
+			return repository.ErrNotFound
+		}
+		u.ID = id
+		if err := t.checkUser(u); err != nil {
+			return err
+		}
+		t.users[id] = u
+		return nil
+	})
+}
+
+func (r *usersRepository) Delete(ctx context.Context, id uint64) error {
+	return r.s.write(func(t *tables) error {
+		if _, ok := t.users[id]; !ok {
+			return repository.ErrNotFound
+		}
+		delete(t.users, id)
+		return nil
+	})
+}
@@ -0,0 +1,43 @@
+package postgres
+
+import (
+	"context"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+	"university-db-admin/pkg/dbclient"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type auditRepository struct {
+	db dbclient.Querier
+}
+
+func NewAuditRepository(db dbclient.Querier) repository.Audit {
+	return &auditRepository{
+		db: db,
+	}
+}
+
+var auditList = listSpec{
+	table:   "public.audit_log",
+	columns: "id, entity, record_id, operation, old_values, new_values, operator, changed_at",
+	fields:  []string{"id", "entity", "record_id", "operation", "operator", "changed_at"},
+	order:   "id DESC", // newest first
+	keyset:  false,
+}
+
+func (a *auditRepository) List(ctx context.Context, opts repository.ListOptions) (repository.Page[domain.AuditEntry], error) {
+	return list(ctx, a.db, auditList, opts, func(row pgx.Row, e *domain.AuditEntry) error {
+		return row.Scan(
+			&e.ID,
+			&e.Entity,
+			&e.RecordID,
+			&e.Operation,
+			&e.OldValues,
+			&e.NewValues,
+			&e.Operator,
+			&e.ChangedAt,
+		)
+	}, nil)
+}
@@ -0,0 +1,82 @@
+package postgres
+
+import (
+	"context"
+	"log"
+	"university-db-admin/internal/repository"
+	"university-db-admin/pkg/dbclient"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type consoleRepository struct {
+	db dbclient.Querier
+}
+
+func NewConsoleRepository(db dbclient.Querier) repository.Console {
+	return &consoleRepository{
+		db: db,
+	}
+}
+
+func (c *consoleRepository) Exec(ctx context.Context, statement string, write bool, limit int, args ...any) (repository.ConsoleResult, error) {
+	tx, err := c.db.Begin(ctx)
+	if err != nil {
+		return repository.ConsoleResult{}, handlePgError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	if !write {
+		// has to come first, once a transaction ran a query it can't be made writable
+		if _, err = tx.Exec(ctx, "SET TRANSACTION READ ONLY"); err != nil {
+			return repository.ConsoleResult{}, handlePgError(err)
+		}
+	}
+
+	log.Println("executing sql:", statement)
+
+	// the extended protocol takes a single statement, so a read-only
+	// transaction can't be ended by a COMMIT followed by more statements
+	rows, err := tx.Query(ctx, statement, append([]any{pgx.QueryExecModeExec}, args...)...)
+	if err != nil {
+		return repository.ConsoleResult{}, handlePgError(err)
+	}
+	defer rows.Close()
+
+	var result repository.ConsoleResult
+	for _, fd := range rows.FieldDescriptions() {
+		result.Columns = append(result.Columns, fd.Name)
+	}
+
+	for rows.Next() {
+		if limit > 0 && len(result.Rows) == limit {
+			result.Truncated = true
+			break
+		}
+
+		values, err := rows.Values()
+		if err != nil {
+			return repository.ConsoleResult{}, handlePgError(err)
+		}
+		result.Rows = append(result.Rows, values)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return repository.ConsoleResult{}, handlePgError(err)
+	}
+
+	tag := rows.CommandTag()
+	result.Command = tag.String()
+	result.RowsAffected = tag.RowsAffected()
+
+	if write {
+		if err = tx.Commit(ctx); err != nil {
+			return repository.ConsoleResult{}, handlePgError(err)
+		}
+	}
+	return result, nil
+}
+
+func (c *consoleRepository) Report(ctx context.Context, statement string, args ...any) (repository.ConsoleResult, error) {
+	return c.Exec(ctx, statement, false, 0, args...)
+}
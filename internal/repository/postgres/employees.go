@@ -2,25 +2,38 @@ package postgres
 
 import (
 	"context"
+	"fmt"
 	"log"
+	"slices"
 	"university-db-admin/internal/domain"
 	"university-db-admin/internal/dto"
 	"university-db-admin/internal/repository"
+	"university-db-admin/pkg/dbclient"
 
 	"github.com/jackc/pgx/v5"
 )
 
 type employeesRepository struct {
-	db *pgx.Conn
+	db dbclient.Querier
 }
 
-func NewEmployeesRepository(db *pgx.Conn) repository.Employees {
+func NewEmployeesRepository(db dbclient.Querier) repository.Employees {
 	return &employeesRepository{
 		db: db,
 	}
 }
 
-func (e *employeesRepository) Create(ctx context.Context, emp domain.Employee) error {
+var employeesList = listSpec{
+	table:   "public.employees",
+	columns: "id, name, passport, position_id, deleted_at",
+	fields:  []string{"id", "name", "passport", "position_id", "deleted_at"},
+	order:   "id ASC",
+	keyset:  true,
+
+	softDelete: true,
+}
+
+func (e *employeesRepository) Create(ctx context.Context, emp domain.Employee) (uint64, error) {
 	sql := `
 		INSERT INTO public.employees (name, passport, position_id)
 		VALUES ($1, $2, $3)
@@ -34,18 +47,35 @@ func (e *employeesRepository) Create(ctx context.Context, emp domain.Employee) e
 		emp.PositionID,
 	).Scan(&emp.ID)
 	if err != nil {
-		return handlePgError(err)
+		return 0, handlePgError(err)
 	}
 
 	log.Println("sql result:", emp.ID)
-	return nil
+	return emp.ID, nil
+}
+
+func (e *employeesRepository) CopyFrom(ctx context.Context, emps []domain.Employee) (int64, error) {
+	log.Println("copying", len(emps), "rows into public.employees")
+	n, err := e.db.CopyFrom(ctx,
+		pgx.Identifier{"public", "employees"},
+		[]string{"name", "passport", "position_id"},
+		pgx.CopyFromSlice(len(emps), func(i int) ([]any, error) {
+			return []any{emps[i].Name, emps[i].Passport, emps[i].PositionID}, nil
+		}),
+	)
+	if err != nil {
+		return 0, handlePgError(err)
+	}
+
+	log.Println("sql result:", n, "rows")
+	return n, nil
 }
 
 func (e *employeesRepository) FindOne(ctx context.Context, id uint64) (domain.Employee, error) {
 	sql := `
 		SELECT id, name, passport, position_id 
 		FROM public.employees
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	var emp domain.Employee
@@ -68,6 +98,7 @@ func (e *employeesRepository) FindAll(ctx context.Context) ([]domain.Employee, e
 	sql := `
 		SELECT id, name, passport, position_id 
 		FROM public.employees
+		WHERE deleted_at IS NULL
 	`
 
 	var emps []domain.Employee
@@ -93,15 +124,29 @@ func (e *employeesRepository) FindAll(ctx context.Context) ([]domain.Employee, e
 		emps = append(emps, emp)
 	}
 
-	log.Println("sql result:", emps)
+	log.Println("sql result:", len(emps), "rows")
 	return emps, nil
 }
 
+func (e *employeesRepository) List(ctx context.Context, opts repository.ListOptions) (repository.Page[domain.Employee], error) {
+	return list(ctx, e.db, employeesList, opts, func(row pgx.Row, emp *domain.Employee) error {
+		return row.Scan(
+			&emp.ID,
+			&emp.Name,
+			&emp.Passport,
+			&emp.PositionID,
+			&emp.DeletedAt,
+		)
+	}, func(emp domain.Employee) uint64 {
+		return emp.ID
+	})
+}
+
 func (e *employeesRepository) FindByName(ctx context.Context, name string) ([]domain.Employee, error) {
 	sql := `
 		SELECT id, name, passport, position_id 
 		FROM public.employees
-		WHERE name = $1
+		WHERE name = $1 AND deleted_at IS NULL
 	`
 
 	var emps []domain.Employee
@@ -135,7 +180,7 @@ func (e *employeesRepository) FindByPassport(ctx context.Context, passport strin
 	sql := `
 		SELECT id, name, passport, position_id 
 		FROM public.employees
-		WHERE passport = $1
+		WHERE passport = $1 AND deleted_at IS NULL
 	`
 
 	var emp domain.Employee
@@ -158,7 +203,7 @@ func (e *employeesRepository) FindByPosition(ctx context.Context, position uint6
 	sql := `
 		SELECT id, name, passport, position_id 
 		FROM public.employees
-		WHERE position_id = $1
+		WHERE position_id = $1 AND deleted_at IS NULL
 	`
 
 	var emps []domain.Employee
@@ -192,6 +237,7 @@ func (r *employeesRepository) FindAllNamePassport(ctx context.Context) ([]dto.Em
 	sql := `
         SELECT employees.name, employees.passport
         FROM public.employees
+        WHERE employees.deleted_at IS NULL
     `
 
 	log.Println("executing sql:", sql)
@@ -223,7 +269,7 @@ func (r *employeesRepository) FindNamePassportByID(ctx context.Context, id uint6
 	sql := `
         SELECT employees.name, employees.passport
         FROM public.employees
-        WHERE employees.id = $1
+        WHERE employees.id = $1 AND employees.deleted_at IS NULL
     `
 
 	log.Println("executing sql:", sql)
@@ -247,7 +293,7 @@ func (r *employeesRepository) FindAllByPositions(ctx context.Context, firstID, s
 	sql := `
 		SELECT employees.name
 		FROM public.employees
-		WHERE employees.position_id = $1 OR employees.position_id = $2
+		WHERE (employees.position_id = $1 OR employees.position_id = $2) AND employees.deleted_at IS NULL
 	`
 
 	log.Println("executing sql:", sql)
@@ -272,32 +318,89 @@ func (r *employeesRepository) FindAllByPositions(ctx context.Context, firstID, s
 	return result, nil
 }
 
-func (r *employeesRepository) IsTeacher(ctx context.Context, id uint64) (dto.EmployeeRoleDTO, error) {
-	const teacherName = "Преподаватель"
+func (r *employeesRepository) FindByCapability(ctx context.Context, c domain.Capability) ([]domain.Employee, error) {
+	if !slices.Contains(domain.Capabilities, c) {
+		return nil, fmt.Errorf("unknown capability %q", c)
+	}
+	sql := `
+		SELECT e.id, e.name, e.passport, e.position_id
+		FROM public.employees e
+		INNER JOIN public.positions p ON e.position_id = p.id
+		WHERE p.` + string(c) + ` AND e.deleted_at IS NULL
+		ORDER BY e.id
+	`
+
+	var emps []domain.Employee
+	log.Println("executing sql:", sql)
+
+	rows, err := r.db.Query(ctx, sql)
+	if err != nil {
+		return nil, handlePgError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var emp domain.Employee
+		err := rows.Scan(
+			&emp.ID,
+			&emp.Name,
+			&emp.Passport,
+			&emp.PositionID,
+		)
+		if err != nil {
+			return nil, handlePgError(err)
+		}
+		emps = append(emps, emp)
+	}
+
+	log.Println("sql result:", len(emps), "rows")
+	return emps, nil
+}
+
+// tells whether the employee is active and holds a position with the capability
+func (r *employeesRepository) can(ctx context.Context, id uint64, c domain.Capability) (bool, error) {
+	if !slices.Contains(domain.Capabilities, c) {
+		return false, fmt.Errorf("unknown capability %q", c)
+	}
 	sql := `
 		SELECT EXISTS (
-			SELECT 1 
+			SELECT 1
 			FROM employees e
 			INNER JOIN positions p ON e.position_id = p.id
-			WHERE e.id = $1 AND p.name = $2
+			WHERE e.id = $1 AND p.` + string(c) + ` AND e.deleted_at IS NULL
 		)
 	`
 
-	var dto dto.EmployeeRoleDTO
-	err := r.db.QueryRow(ctx, sql, id, teacherName).Scan(&dto.IsTeacher)
+	var ok bool
+	log.Println("executing sql:", sql)
+	err := r.db.QueryRow(ctx, sql, id).Scan(&ok)
 	if err != nil {
-		return dto, handlePgError(err)
+		return false, handlePgError(err)
 	}
 
-	log.Println("sql result:", dto)
-	return dto, nil
+	log.Println("sql result:", ok)
+	return ok, nil
+}
+
+func (r *employeesRepository) IsTeacher(ctx context.Context, id uint64) (dto.EmployeeRoleDTO, error) {
+	var dto dto.EmployeeRoleDTO
+	var err error
+	dto.IsTeacher, err = r.can(ctx, id, domain.CapabilityTeach)
+	return dto, err
+}
+
+func (r *employeesRepository) IsCurator(ctx context.Context, id uint64) (dto.EmployeeRoleDTO, error) {
+	var dto dto.EmployeeRoleDTO
+	var err error
+	dto.IsCurator, err = r.can(ctx, id, domain.CapabilityCurate)
+	return dto, err
 }
 
 func (e *employeesRepository) Update(ctx context.Context, id uint64, emp domain.Employee) error {
 	sql := `
 		UPDATE public.employees
 		SET name = $1, passport = $2, position_id = $3
-		WHERE id = $4
+		WHERE id = $4 AND deleted_at IS NULL
 		RETURNING id
 	`
 
@@ -316,10 +419,31 @@ func (e *employeesRepository) Update(ctx context.Context, id uint64, emp domain.
 	return nil
 }
 
+// moves the employee to the trash, curated students and marks keep their references
 func (e *employeesRepository) Delete(ctx context.Context, id uint64) error {
 	sql := `
-		DELETE FROM public.employees
-		WHERE id = $1
+		UPDATE public.employees
+		SET deleted_at = now()
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING id
+	`
+
+	log.Println("executing sql:", sql)
+	err := e.db.QueryRow(ctx, sql, id).Scan(&id)
+	if err != nil {
+		return handlePgError(err)
+	}
+
+	log.Println("sql result:", id)
+	return nil
+}
+
+// brings an employee back from the trash
+func (e *employeesRepository) Restore(ctx context.Context, id uint64) error {
+	sql := `
+		UPDATE public.employees
+		SET deleted_at = NULL
+		WHERE id = $1 AND deleted_at IS NOT NULL
 		RETURNING id
 	`
 
@@ -5,20 +5,29 @@ import (
 	"log"
 	"university-db-admin/internal/domain"
 	"university-db-admin/internal/repository"
+	"university-db-admin/pkg/dbclient"
 
 	"github.com/jackc/pgx/v5"
 )
 
 type employeesSubjectsRepository struct {
-	db *pgx.Conn
+	db dbclient.Querier
 }
 
-func NewEmployeesSubjectsRepository(db *pgx.Conn) repository.EmployeesSubjects {
+func NewEmployeesSubjectsRepository(db dbclient.Querier) repository.EmployeesSubjects {
 	return &employeesSubjectsRepository{
 		db: db,
 	}
 }
 
+var employeesSubjectsList = listSpec{
+	table:   "public.employees_subjects",
+	columns: "employee_id, subject_id",
+	fields:  []string{"employee_id", "subject_id"},
+	order:   "employee_id ASC, subject_id ASC",
+	keyset:  false,
+}
+
 func (s *employeesSubjectsRepository) Create(ctx context.Context, es domain.EmployeeSubject) error {
 	sql := `
 		INSERT INTO public.employees_subjects (employee_id, subject_id)
@@ -63,10 +72,19 @@ func (s *employeesSubjectsRepository) FindAll(ctx context.Context) ([]domain.Emp
 		empSbjs = append(empSbjs, es)
 	}
 
-	log.Println("sql result:", empSbjs)
+	log.Println("sql result:", len(empSbjs), "rows")
 	return empSbjs, nil
 }
 
+func (s *employeesSubjectsRepository) List(ctx context.Context, opts repository.ListOptions) (repository.Page[domain.EmployeeSubject], error) {
+	return list(ctx, s.db, employeesSubjectsList, opts, func(row pgx.Row, es *domain.EmployeeSubject) error {
+		return row.Scan(
+			&es.EmployeeID,
+			&es.SubjectID,
+		)
+	}, nil)
+}
+
 func (s *employeesSubjectsRepository) FindByEmployeeID(ctx context.Context, id uint64) ([]domain.EmployeeSubject, error) {
 	sql := `
 		SELECT employee_id, subject_id
@@ -141,7 +159,7 @@ func (s *employeesSubjectsRepository) Update(ctx context.Context, eid uint64, si
 		sid,
 	).Scan(&eid, &sid)
 	if err != nil {
-		return handlePgError(err)
+		return handlePgRemoveError("employees_subjects", err)
 	}
 
 	log.Println("sql result:", eid, sid)
@@ -158,7 +176,7 @@ func (s *employeesSubjectsRepository) Delete(ctx context.Context, eid uint64, si
 	log.Println("executing sql:", sql)
 	err := s.db.QueryRow(ctx, sql, eid, sid).Scan(&eid, &sid)
 	if err != nil {
-		return handlePgError(err)
+		return handlePgRemoveError("employees_subjects", err)
 	}
 
 	log.Println("sql result:", eid, sid)
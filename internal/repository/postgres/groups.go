@@ -5,21 +5,32 @@ import (
 	"log"
 	"university-db-admin/internal/domain"
 	"university-db-admin/internal/repository"
+	"university-db-admin/pkg/dbclient"
 
 	"github.com/jackc/pgx/v5"
 )
 
 type groupsRepository struct {
-	db *pgx.Conn
+	db dbclient.Querier
 }
 
-func NewGroupsRepository(db *pgx.Conn) repository.Groups {
+func NewGroupsRepository(db dbclient.Querier) repository.Groups {
 	return &groupsRepository{
 		db: db,
 	}
 }
 
-func (g *groupsRepository) Create(ctx context.Context, grp domain.Group) error {
+var groupsList = listSpec{
+	table:   "public.groups",
+	columns: "id, number, deleted_at",
+	fields:  []string{"id", "number", "deleted_at"},
+	order:   "id ASC",
+	keyset:  true,
+
+	softDelete: true,
+}
+
+func (g *groupsRepository) Create(ctx context.Context, grp domain.Group) (uint64, error) {
 	sql := `
 		INSERT INTO public.groups (number)
 		VALUES ($1)
@@ -29,18 +40,18 @@ func (g *groupsRepository) Create(ctx context.Context, grp domain.Group) error {
 	log.Println("executing sql: ", sql)
 	err := g.db.QueryRow(ctx, sql, grp.Number).Scan(&grp.ID)
 	if err != nil {
-		return handlePgError(err)
+		return 0, handlePgError(err)
 	}
 
 	log.Println("sql result: ", grp.ID)
-	return nil
+	return grp.ID, nil
 }
 
 func (g *groupsRepository) FindOne(ctx context.Context, id uint64) (domain.Group, error) {
 	sql := `
 		SELECT g.id, g.number
 		FROM public.groups g
-		WHERE g.id = $1
+		WHERE g.id = $1 AND g.deleted_at IS NULL
 	`
 
 	var grp domain.Group
@@ -59,6 +70,7 @@ func (g *groupsRepository) FindAll(ctx context.Context) ([]domain.Group, error)
 	sql := `
 		SELECT g.id, g.number 
 		FROM public.groups g
+		WHERE g.deleted_at IS NULL
 	`
 
 	var groups []domain.Group
@@ -66,7 +78,7 @@ func (g *groupsRepository) FindAll(ctx context.Context) ([]domain.Group, error)
 
 	rows, err := g.db.Query(ctx, sql)
 	if err != nil {
-		return groups, err
+		return nil, handlePgError(err)
 	}
 	defer rows.Close()
 
@@ -79,15 +91,27 @@ func (g *groupsRepository) FindAll(ctx context.Context) ([]domain.Group, error)
 		groups = append(groups, grp)
 	}
 
-	log.Println("sql result: ", groups)
+	log.Println("sql result:", len(groups), "rows")
 	return groups, nil
 }
 
+func (g *groupsRepository) List(ctx context.Context, opts repository.ListOptions) (repository.Page[domain.Group], error) {
+	return list(ctx, g.db, groupsList, opts, func(row pgx.Row, grp *domain.Group) error {
+		return row.Scan(
+			&grp.ID,
+			&grp.Number,
+			&grp.DeletedAt,
+		)
+	}, func(grp domain.Group) uint64 {
+		return grp.ID
+	})
+}
+
 func (g *groupsRepository) FindByNumber(ctx context.Context, num uint64) (domain.Group, error) {
 	sql := `
 		SELECT g.id, g.number
 		FROM public.groups g
-		WHERE g.number = $1
+		WHERE g.number = $1 AND g.deleted_at IS NULL
 	`
 
 	var grp domain.Group
@@ -106,7 +130,7 @@ func (g *groupsRepository) Update(ctx context.Context, id uint64, grp domain.Gro
 	sql := `
 		UPDATE public.groups
 		SET number = $1
-		WHERE id = $2
+		WHERE id = $2 AND deleted_at IS NULL
 		RETURNING id
 	`
 
@@ -120,10 +144,12 @@ func (g *groupsRepository) Update(ctx context.Context, id uint64, grp domain.Gro
 	return nil
 }
 
+// moves the group to the trash, its students and lessons keep their references
 func (g *groupsRepository) Delete(ctx context.Context, id uint64) error {
 	sql := `
-		DELETE FROM public.groups
-		WHERE id = $1
+		UPDATE public.groups
+		SET deleted_at = now()
+		WHERE id = $1 AND deleted_at IS NULL
 		RETURNING id
 	`
 
@@ -136,3 +162,22 @@ func (g *groupsRepository) Delete(ctx context.Context, id uint64) error {
 	log.Println("sql result: ", id)
 	return nil
 }
+
+// brings a group back from the trash
+func (g *groupsRepository) Restore(ctx context.Context, id uint64) error {
+	sql := `
+		UPDATE public.groups
+		SET deleted_at = NULL
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		RETURNING id
+	`
+
+	log.Println("executing sql:", sql)
+	err := g.db.QueryRow(ctx, sql, id).Scan(&id)
+	if err != nil {
+		return handlePgError(err)
+	}
+
+	log.Println("sql result:", id)
+	return nil
+}
@@ -5,21 +5,30 @@ import (
 	"log"
 	"university-db-admin/internal/domain"
 	"university-db-admin/internal/repository"
+	"university-db-admin/pkg/dbclient"
 
 	"github.com/jackc/pgx/v5"
 )
 
 type lessonTypesRepository struct {
-	db *pgx.Conn
+	db dbclient.Querier
 }
 
-func NewLessonTypesRepository(db *pgx.Conn) repository.LessonTypes {
+func NewLessonTypesRepository(db dbclient.Querier) repository.LessonTypes {
 	return &lessonTypesRepository{
 		db: db,
 	}
 }
 
-func (l *lessonTypesRepository) Create(ctx context.Context, lsn domain.LessonType) error {
+var lessonTypesList = listSpec{
+	table:   "public.lesson_types",
+	columns: "id, name",
+	fields:  []string{"id", "name"},
+	order:   "id ASC",
+	keyset:  true,
+}
+
+func (l *lessonTypesRepository) Create(ctx context.Context, lsn domain.LessonType) (uint64, error) {
 	sql := `
 		INSERT INTO public.lesson_types (name)
 		VALUES ($1)
@@ -29,11 +38,11 @@ func (l *lessonTypesRepository) Create(ctx context.Context, lsn domain.LessonTyp
 	log.Println("executing sql:", sql)
 	err := l.db.QueryRow(ctx, sql, lsn.Name).Scan(&lsn.ID)
 	if err != nil {
-		return handlePgError(err)
+		return 0, handlePgError(err)
 	}
 
 	log.Println("sql result:", lsn.ID)
-	return nil
+	return lsn.ID, nil
 }
 
 func (l *lessonTypesRepository) FindOne(ctx context.Context, id uint64) (domain.LessonType, error) {
@@ -78,10 +87,21 @@ func (l *lessonTypesRepository) FindAll(ctx context.Context) ([]domain.LessonTyp
 		lessonTypes = append(lessonTypes, lsn)
 	}
 
-	log.Println("sql result:", lessonTypes)
+	log.Println("sql result:", len(lessonTypes), "rows")
 	return lessonTypes, nil
 }
 
+func (l *lessonTypesRepository) List(ctx context.Context, opts repository.ListOptions) (repository.Page[domain.LessonType], error) {
+	return list(ctx, l.db, lessonTypesList, opts, func(row pgx.Row, lt *domain.LessonType) error {
+		return row.Scan(
+			&lt.ID,
+			&lt.Name,
+		)
+	}, func(lt domain.LessonType) uint64 {
+		return lt.ID
+	})
+}
+
 func (l *lessonTypesRepository) FindByName(ctx context.Context, name string) (domain.LessonType, error) {
 	sql := `
 		SELECT lt.id, lt.name 
@@ -128,7 +148,7 @@ func (l *lessonTypesRepository) Delete(ctx context.Context, id uint64) error {
 	log.Println("executing sql:", sql)
 	err := l.db.QueryRow(ctx, sql, id).Scan(&id)
 	if err != nil {
-		return handlePgError(err)
+		return handlePgRemoveError("lesson_types", err)
 	}
 
 	log.Println("sql result:", id)
@@ -6,24 +6,33 @@ import (
 	"university-db-admin/internal/domain"
 	"university-db-admin/internal/dto"
 	"university-db-admin/internal/repository"
+	"university-db-admin/pkg/dbclient"
 
 	"github.com/jackc/pgx/v5"
 )
 
 type lessonsRepository struct {
-	db *pgx.Conn
+	db dbclient.Querier
 }
 
-func NewLessonsRepository(db *pgx.Conn) repository.Lessons {
+func NewLessonsRepository(db dbclient.Querier) repository.Lessons {
 	return &lessonsRepository{
 		db: db,
 	}
 }
 
-func (l *lessonsRepository) Create(ctx context.Context, lsn domain.Lesson) error {
+var lessonsList = listSpec{
+	table:   "public.lessons",
+	columns: "id, group_id, subject_id, lesson_type_id, week, weekday, pair, room, employee_id",
+	fields:  []string{"id", "group_id", "subject_id", "lesson_type_id", "week", "weekday", "pair", "room", "employee_id"},
+	order:   "id ASC",
+	keyset:  true,
+}
+
+func (l *lessonsRepository) Create(ctx context.Context, lsn domain.Lesson) (uint64, error) {
 	sql := `
-		INSERT INTO public.lessons (group_id, subject_id, lesson_type_id, week, weekday, room)
-		VALUES ($1, $2, $3, $4, $5, $6)
+		INSERT INTO public.lessons (group_id, subject_id, lesson_type_id, week, weekday, pair, room, employee_id)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING id
 	`
 
@@ -34,19 +43,21 @@ func (l *lessonsRepository) Create(ctx context.Context, lsn domain.Lesson) error
 		lsn.LessonTypeID,
 		lsn.Week,
 		lsn.Weekday,
+		lsn.Pair,
 		lsn.Room,
+		lsn.EmployeeID,
 	).Scan(&lsn.ID)
 	if err != nil {
-		return handlePgError(err)
+		return 0, handlePgError(err)
 	}
 
 	log.Println("sql result:", lsn.ID)
-	return nil
+	return lsn.ID, nil
 }
 
 func (l *lessonsRepository) FindOne(ctx context.Context, id uint64) (domain.Lesson, error) {
 	sql := `
-		SELECT id, group_id, subject_id, lesson_type_id, week, weekday, room 
+		SELECT id, group_id, subject_id, lesson_type_id, week, weekday, pair, room, employee_id
 		FROM public.lessons
 		WHERE id = $1
 	`
@@ -60,7 +71,9 @@ func (l *lessonsRepository) FindOne(ctx context.Context, id uint64) (domain.Less
 		&lsn.LessonTypeID,
 		&lsn.Week,
 		&lsn.Weekday,
+		&lsn.Pair,
 		&lsn.Room,
+		&lsn.EmployeeID,
 	)
 	if err != nil {
 		return domain.Lesson{}, handlePgError(err)
@@ -72,7 +85,7 @@ func (l *lessonsRepository) FindOne(ctx context.Context, id uint64) (domain.Less
 
 func (l *lessonsRepository) FindAll(ctx context.Context) ([]domain.Lesson, error) {
 	sql := `
-		SELECT id, group_id, subject_id, lesson_type_id, week, weekday, room 
+		SELECT id, group_id, subject_id, lesson_type_id, week, weekday, pair, room, employee_id
 		FROM public.lessons
 	`
 
@@ -94,7 +107,9 @@ func (l *lessonsRepository) FindAll(ctx context.Context) ([]domain.Lesson, error
 			&lsn.LessonTypeID,
 			&lsn.Week,
 			&lsn.Weekday,
+			&lsn.Pair,
 			&lsn.Room,
+			&lsn.EmployeeID,
 		)
 		if err != nil {
 			return nil, handlePgError(err)
@@ -102,13 +117,31 @@ func (l *lessonsRepository) FindAll(ctx context.Context) ([]domain.Lesson, error
 		lessons = append(lessons, lsn)
 	}
 
-	log.Println("sql result:", lessons)
+	log.Println("sql result:", len(lessons), "rows")
 	return lessons, nil
 }
 
+func (l *lessonsRepository) List(ctx context.Context, opts repository.ListOptions) (repository.Page[domain.Lesson], error) {
+	return list(ctx, l.db, lessonsList, opts, func(row pgx.Row, lsn *domain.Lesson) error {
+		return row.Scan(
+			&lsn.ID,
+			&lsn.GroupID,
+			&lsn.SubjectID,
+			&lsn.LessonTypeID,
+			&lsn.Week,
+			&lsn.Weekday,
+			&lsn.Pair,
+			&lsn.Room,
+			&lsn.EmployeeID,
+		)
+	}, func(lsn domain.Lesson) uint64 {
+		return lsn.ID
+	})
+}
+
 func (l *lessonsRepository) findByField(ctx context.Context, field string, value interface{}) ([]domain.Lesson, error) {
 	sql := `
-		SELECT id, group_id, subject_id, lesson_type_id, week, weekday, room 
+		SELECT id, group_id, subject_id, lesson_type_id, week, weekday, pair, room, employee_id
 		FROM public.lessons
 		WHERE ` + field + ` = $1
 	`
@@ -131,7 +164,9 @@ func (l *lessonsRepository) findByField(ctx context.Context, field string, value
 			&lsn.LessonTypeID,
 			&lsn.Week,
 			&lsn.Weekday,
+			&lsn.Pair,
 			&lsn.Room,
+			&lsn.EmployeeID,
 		)
 		if err != nil {
 			return nil, handlePgError(err)
@@ -167,18 +202,25 @@ func (l *lessonsRepository) FindByRoom(ctx context.Context, room uint64) ([]doma
 	return l.findByField(ctx, "room", room)
 }
 
+// lessons of trashed groups are left out, the teacher is empty
+// for lessons without one
 func (r *lessonsRepository) FindSchedule(ctx context.Context) ([]dto.LessonScheduleDTO, error) {
 	sql := `
 		SELECT groups.number,
 			subjects.name,
 			lesson_types.name,
+			COALESCE(employees.name, ''),
 			lessons.room,
 			lessons.week,
-			lessons.weekday
+			lessons.weekday,
+			lessons.pair
 		FROM public.lessons
 		INNER JOIN public.groups ON lessons.group_id = groups.id
 		INNER JOIN public.subjects ON lessons.subject_id = subjects.id
 		INNER JOIN public.lesson_types ON lessons.lesson_type_id = lesson_types.id
+		LEFT JOIN public.employees ON lessons.employee_id = employees.id
+		WHERE groups.deleted_at IS NULL
+		ORDER BY lessons.week, lessons.weekday, lessons.pair, groups.number
 	`
 
 	log.Println("executing sql:", sql)
@@ -196,9 +238,11 @@ func (r *lessonsRepository) FindSchedule(ctx context.Context) ([]dto.LessonSched
 			&dto.GroupNumber,
 			&dto.Subject,
 			&dto.LessonType,
+			&dto.Teacher,
 			&dto.Room,
 			&dto.Week,
 			&dto.Weekday,
+			&dto.Pair,
 		)
 		if err != nil {
 			return nil, handlePgError(err)
@@ -213,8 +257,9 @@ func (r *lessonsRepository) FindSchedule(ctx context.Context) ([]dto.LessonSched
 func (l *lessonsRepository) Update(ctx context.Context, id uint64, lsn domain.Lesson) error {
 	sql := `
 		UPDATE public.lessons
-		SET group_id = $1, subject_id = $2, lesson_type_id = $3, week = $4, weekday = $5, room = $6
-		WHERE id = $7
+		SET group_id = $1, subject_id = $2, lesson_type_id = $3, week = $4, weekday = $5, pair = $6,
+			room = $7, employee_id = $8
+		WHERE id = $9
 		RETURNING id
 	`
 
@@ -225,7 +270,9 @@ func (l *lessonsRepository) Update(ctx context.Context, id uint64, lsn domain.Le
 		lsn.LessonTypeID,
 		lsn.Week,
 		lsn.Weekday,
+		lsn.Pair,
 		lsn.Room,
+		lsn.EmployeeID,
 		id,
 	).Scan(&id)
 	if err != nil {
@@ -246,7 +293,7 @@ func (l *lessonsRepository) Delete(ctx context.Context, id uint64) error {
 	log.Println("executing sql:", sql)
 	err := l.db.QueryRow(ctx, sql, id).Scan(&id)
 	if err != nil {
-		return handlePgError(err)
+		return handlePgRemoveError("lessons", err)
 	}
 
 	log.Println("sql result:", id)
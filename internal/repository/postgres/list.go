@@ -0,0 +1,204 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"university-db-admin/internal/repository"
+	"university-db-admin/pkg/dbclient"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// comparison operators allowed in filters
+var filterOperators = map[repository.FilterOp]string{
+	repository.OpEq:  "=",
+	repository.OpNe:  "<>",
+	repository.OpLt:  "<",
+	repository.OpLte: "<=",
+	repository.OpGt:  ">",
+	repository.OpGte: ">=",
+}
+
+// listSpec describes how List queries a table
+type listSpec struct {
+	table   string   // qualified table name
+	columns string   // selected columns in scan order
+	fields  []string // columns allowed in sort and filter options
+	order   string   // default ORDER BY clause
+	keyset  bool     // table has an id column usable as a keyset cursor
+
+	softDelete bool // table marks deleted rows with deleted_at instead of removing them
+}
+
+func (l listSpec) allowed(field string) bool {
+	for _, f := range l.fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// builds the page and count queries for opts; field names are checked
+// against the whitelist so only values are passed as arguments
+func (l listSpec) build(opts repository.ListOptions) (string, string, []any, error) {
+	var (
+		conditions []string
+		args       []any
+	)
+
+	for _, f := range opts.Filters {
+		if !l.allowed(f.Field) {
+			return "", "", nil, fmt.Errorf("%w: filter by %s", repository.ErrInvalidOption, f.Field)
+		}
+
+		args = append(args, f.Value)
+		if f.Op == repository.OpContains {
+			conditions = append(conditions, fmt.Sprintf("CAST(%s AS TEXT) ILIKE '%%' || $%d || '%%'", f.Field, len(args)))
+			continue
+		}
+
+		op, ok := filterOperators[f.Op]
+		if !ok {
+			return "", "", nil, fmt.Errorf("%w: operator %s", repository.ErrInvalidOption, f.Op)
+		}
+		conditions = append(conditions, fmt.Sprintf("%s %s $%d", f.Field, op, len(args)))
+	}
+
+	switch opts.Deleted {
+	case repository.ExcludeDeleted:
+		if l.softDelete {
+			conditions = append(conditions, "deleted_at IS NULL")
+		}
+	case repository.IncludeDeleted, repository.OnlyDeleted:
+		if !l.softDelete {
+			return "", "", nil, fmt.Errorf("%w: %s has no deleted rows", repository.ErrInvalidOption, l.table)
+		}
+		if opts.Deleted == repository.OnlyDeleted {
+			conditions = append(conditions, "deleted_at IS NOT NULL")
+		}
+	default:
+		return "", "", nil, fmt.Errorf("%w: deleted %s", repository.ErrInvalidOption, opts.Deleted)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	countSQL := fmt.Sprintf("SELECT COUNT(*) FROM %s %s", l.table, where)
+
+	order := l.order
+	if len(opts.Sort) > 0 {
+		var terms []string
+		for _, s := range opts.Sort {
+			if !l.allowed(s.Field) {
+				return "", "", nil, fmt.Errorf("%w: sort by %s", repository.ErrInvalidOption, s.Field)
+			}
+			if s.Desc {
+				terms = append(terms, s.Field+" DESC")
+			} else {
+				terms = append(terms, s.Field+" ASC")
+			}
+		}
+		if l.keyset {
+			// rows with equal sort values keep a stable order between pages
+			terms = append(terms, "id ASC")
+		}
+		order = strings.Join(terms, ", ")
+	}
+
+	if opts.AfterID > 0 {
+		if !l.keyset || len(opts.Sort) > 0 || opts.Offset > 0 {
+			return "", "", nil, fmt.Errorf("%w: cursor requires default ordering", repository.ErrInvalidOption)
+		}
+		args = append(args, opts.AfterID)
+		if where == "" {
+			where = fmt.Sprintf("WHERE id > $%d", len(args))
+		} else {
+			where += fmt.Sprintf(" AND id > $%d", len(args))
+		}
+	}
+
+	pageSQL := fmt.Sprintf("SELECT %s FROM %s %s ORDER BY %s", l.columns, l.table, where, order)
+	if opts.Limit > 0 {
+		// one extra row tells whether another page follows
+		pageSQL += fmt.Sprintf(" LIMIT %d", opts.Limit+1)
+	}
+	if opts.Offset > 0 {
+		pageSQL += fmt.Sprintf(" OFFSET %d", opts.Offset)
+	}
+
+	return pageSQL, countSQL, args, nil
+}
+
+// runs a list query built from spec, scan reads one row and id returns
+// the keyset cursor value of an item
+func list[T any](
+	ctx context.Context,
+	db dbclient.Querier,
+	spec listSpec,
+	opts repository.ListOptions,
+	scan func(row pgx.Row, item *T) error,
+	id func(item T) uint64,
+) (repository.Page[T], error) {
+	pageSQL, countSQL, args, err := spec.build(opts)
+	if err != nil {
+		return repository.Page[T]{}, err
+	}
+
+	var page repository.Page[T]
+
+	// the cursor argument is always the last one and isn't part of the count query
+	countArgs := args
+	if opts.AfterID > 0 {
+		countArgs = args[:len(args)-1]
+	}
+
+	log.Println("executing sql:", countSQL)
+	if err = db.QueryRow(ctx, countSQL, countArgs...).Scan(&page.Total); err != nil {
+		return repository.Page[T]{}, listError(err)
+	}
+
+	log.Println("executing sql:", pageSQL)
+	rows, err := db.Query(ctx, pageSQL, args...)
+	if err != nil {
+		return repository.Page[T]{}, listError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item T
+		if err := scan(rows, &item); err != nil {
+			return repository.Page[T]{}, handlePgError(err)
+		}
+		page.Items = append(page.Items, item)
+	}
+	if err = rows.Err(); err != nil {
+		return repository.Page[T]{}, listError(err)
+	}
+
+	if opts.Limit > 0 && uint64(len(page.Items)) > opts.Limit {
+		page.Items = page.Items[:opts.Limit]
+		if spec.keyset && id != nil && len(opts.Sort) == 0 {
+			page.NextCursor = id(page.Items[len(page.Items)-1])
+		}
+	}
+
+	log.Println("sql result:", len(page.Items), "of", page.Total, "rows")
+	return page, nil
+}
+
+// filter values are sent as text, so data exceptions mean a value
+// that can't be converted to the column type
+func listError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgerrcode.IsDataException(pgErr.Code) {
+		return fmt.Errorf("%w: %s", repository.ErrInvalidOption, pgErr.Message)
+	}
+	return handlePgError(err)
+}
@@ -5,47 +5,56 @@ import (
 	"log"
 	"university-db-admin/internal/domain"
 	"university-db-admin/internal/repository"
+	"university-db-admin/pkg/dbclient"
 
 	"github.com/jackc/pgx/v5"
 )
 
 type positionsRepository struct {
-	db *pgx.Conn
+	db dbclient.Querier
 }
 
-func NewPositionsRepository(db *pgx.Conn) repository.Positions {
+func NewPositionsRepository(db dbclient.Querier) repository.Positions {
 	return &positionsRepository{
 		db: db,
 	}
 }
 
-func (p *positionsRepository) Create(ctx context.Context, pos domain.Position) error {
+var positionsList = listSpec{
+	table:   "public.positions",
+	columns: "id, name, can_teach, can_curate, can_administer",
+	fields:  []string{"id", "name", "can_teach", "can_curate", "can_administer"},
+	order:   "id ASC",
+	keyset:  true,
+}
+
+func (p *positionsRepository) Create(ctx context.Context, pos domain.Position) (uint64, error) {
 	sql := `
-		INSERT INTO public.positions (name)
-		VALUES ($1)
+		INSERT INTO public.positions (name, can_teach, can_curate, can_administer)
+		VALUES ($1, $2, $3, $4)
 		RETURNING id
 	`
 
 	log.Println("executing sql:", sql)
-	err := p.db.QueryRow(ctx, sql, pos.Name).Scan(&pos.ID)
+	err := p.db.QueryRow(ctx, sql, pos.Name, pos.CanTeach, pos.CanCurate, pos.CanAdminister).Scan(&pos.ID)
 	if err != nil {
-		return handlePgError(err)
+		return 0, handlePgError(err)
 	}
 
 	log.Println("sql result:", pos.ID)
-	return nil
+	return pos.ID, nil
 }
 
 func (p *positionsRepository) FindOne(ctx context.Context, id uint64) (domain.Position, error) {
 	sql := `
-		SELECT id, name
+		SELECT id, name, can_teach, can_curate, can_administer
 		FROM public.positions
 		WHERE id = $1
 	`
 
 	var pos domain.Position
 	log.Println("executing sql:", sql)
-	err := p.db.QueryRow(ctx, sql, id).Scan(&pos.ID, &pos.Name)
+	err := p.db.QueryRow(ctx, sql, id).Scan(&pos.ID, &pos.Name, &pos.CanTeach, &pos.CanCurate, &pos.CanAdminister)
 	if err != nil {
 		return domain.Position{}, handlePgError(err)
 	}
@@ -56,7 +65,7 @@ func (p *positionsRepository) FindOne(ctx context.Context, id uint64) (domain.Po
 
 func (p *positionsRepository) FindAll(ctx context.Context) ([]domain.Position, error) {
 	sql := `
-		SELECT id, name
+		SELECT id, name, can_teach, can_curate, can_administer
 		FROM public.positions
 	`
 
@@ -71,20 +80,34 @@ func (p *positionsRepository) FindAll(ctx context.Context) ([]domain.Position, e
 
 	for rows.Next() {
 		var pos domain.Position
-		err := rows.Scan(&pos.ID, &pos.Name)
+		err := rows.Scan(&pos.ID, &pos.Name, &pos.CanTeach, &pos.CanCurate, &pos.CanAdminister)
 		if err != nil {
 			return nil, handlePgError(err)
 		}
 		positions = append(positions, pos)
 	}
 
-	log.Println("sql result:", positions)
+	log.Println("sql result:", len(positions), "rows")
 	return positions, nil
 }
 
+func (p *positionsRepository) List(ctx context.Context, opts repository.ListOptions) (repository.Page[domain.Position], error) {
+	return list(ctx, p.db, positionsList, opts, func(row pgx.Row, pos *domain.Position) error {
+		return row.Scan(
+			&pos.ID,
+			&pos.Name,
+			&pos.CanTeach,
+			&pos.CanCurate,
+			&pos.CanAdminister,
+		)
+	}, func(pos domain.Position) uint64 {
+		return pos.ID
+	})
+}
+
 func (p *positionsRepository) FindByName(ctx context.Context, name string) (domain.Position, error) {
 	sql := `
-		SELECT id, name
+		SELECT id, name, can_teach, can_curate, can_administer
 		FROM public.positions
 		WHERE name = $1
 	`
@@ -92,7 +115,7 @@ func (p *positionsRepository) FindByName(ctx context.Context, name string) (doma
 	var pos domain.Position
 
 	log.Println("executing sql:", sql)
-	err := p.db.QueryRow(ctx, sql, name).Scan(&pos.ID, &pos.Name)
+	err := p.db.QueryRow(ctx, sql, name).Scan(&pos.ID, &pos.Name, &pos.CanTeach, &pos.CanCurate, &pos.CanAdminister)
 	if err != nil {
 		return domain.Position{}, handlePgError(err)
 	}
@@ -104,13 +127,13 @@ func (p *positionsRepository) FindByName(ctx context.Context, name string) (doma
 func (p *positionsRepository) Update(ctx context.Context, id uint64, pos domain.Position) error {
 	sql := `
 		UPDATE public.positions
-		SET name = $1
-		WHERE id = $2
+		SET name = $1, can_teach = $2, can_curate = $3, can_administer = $4
+		WHERE id = $5
 		RETURNING id
 	`
 
 	log.Println("executing sql:", sql)
-	err := p.db.QueryRow(ctx, sql, pos.Name, id).Scan(&id)
+	err := p.db.QueryRow(ctx, sql, pos.Name, pos.CanTeach, pos.CanCurate, pos.CanAdminister, id).Scan(&id)
 	if err != nil {
 		return handlePgError(err)
 	}
@@ -129,7 +152,7 @@ func (p *positionsRepository) Delete(ctx context.Context, id uint64) error {
 	log.Println("executing sql:", sql)
 	err := p.db.QueryRow(ctx, sql, id).Scan(&id)
 	if err != nil {
-		return handlePgError(err)
+		return handlePgRemoveError("positions", err)
 	}
 
 	log.Println("sql result:", id)
@@ -0,0 +1,92 @@
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+	"university-db-admin/internal/config"
+	"university-db-admin/internal/migrations"
+	"university-db-admin/internal/repository"
+	"university-db-admin/internal/repository/postgres"
+	"university-db-admin/internal/repository/repotest"
+	"university-db-admin/pkg/dbclient"
+
+	"github.com/ilyakaznacheev/cleanenv"
+	"github.com/jackc/pgx/v5"
+)
+
+// tables emptied before every test, the migrations table is kept
+const tables = `
+	public.positions, public.employees, public.groups, public.students,
+	public.subjects, public.lesson_types, public.lessons, public.marks,
+	public.employees_subjects, public.audit_log, public.users
+`
+
+// TestRepositoryContract runs the contract suite against a throwaway database
+// created on the server given by the DB_* variables and dropped afterwards.
+// It is skipped when the server can't be reached.
+func TestRepositoryContract(t *testing.T) {
+	if testing.Short() {
+		t.Skip("needs a PostgreSQL server")
+	}
+
+	var cfg config.DatabaseConfig
+	if err := cleanenv.ReadEnv(&cfg); err != nil {
+		t.Fatalf("reading database config: %v", err)
+	}
+	if cfg.Host == "" {
+		cfg.Host = "localhost"
+	}
+	if cfg.Port == "" {
+		cfg.Port = "5432"
+	}
+	if cfg.User == "" {
+		cfg.User = "postgres"
+	}
+
+	ctx := context.Background()
+	admin, err := connect(ctx, cfg, "postgres")
+	if err != nil {
+		t.Skipf("PostgreSQL is not available: %v", err)
+	}
+	defer admin.Close(ctx)
+
+	cfg.Name = fmt.Sprintf("university_db_test_%d", time.Now().UnixNano())
+	if _, err = admin.Exec(ctx, "CREATE DATABASE "+cfg.Name); err != nil {
+		t.Fatalf("creating test database: %v", err)
+	}
+	t.Logf("using database %s", cfg.Name)
+
+	pool := dbclient.NewClientPG(cfg)
+	defer func() {
+		pool.Close()
+		if _, err := admin.Exec(ctx, "DROP DATABASE "+cfg.Name+" WITH (FORCE)"); err != nil {
+			t.Errorf("dropping test database: %v", err)
+		}
+	}()
+
+	migrator, err := migrations.NewMigrator(pool)
+	if err != nil {
+		t.Fatalf("loading migrations: %v", err)
+	}
+	if err = migrator.Up(ctx); err != nil {
+		t.Fatalf("applying migrations: %v", err)
+	}
+
+	repotest.Run(t, func(t *testing.T) *repository.Repository {
+		t.Helper()
+		if _, err := pool.Exec(ctx, "TRUNCATE "+tables+" RESTART IDENTITY CASCADE"); err != nil {
+			t.Fatalf("emptying test database: %v", err)
+		}
+		return postgres.NewRepository(pool)
+	})
+}
+
+func connect(ctx context.Context, cfg config.DatabaseConfig, name string) (*pgx.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, cfg.ConnectTimeout)
+	defer cancel()
+
+	dsn := fmt.Sprintf("postgresql://%s:%s@%s:%s/%s", cfg.User, cfg.Password, cfg.Host, cfg.Port, name)
+	return pgx.Connect(ctx, dsn)
+}
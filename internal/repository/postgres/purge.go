@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"university-db-admin/internal/repository"
+	"university-db-admin/pkg/dbclient"
+)
+
+// tables Purge may empty, their names go into the statement
+var purgeable = map[string]bool{
+	"positions":          true,
+	"employees":          true,
+	"groups":             true,
+	"students":           true,
+	"subjects":           true,
+	"lesson_types":       true,
+	"employees_subjects": true,
+	"lessons":            true,
+	"marks":              true,
+	"users":              true,
+}
+
+type purgerRepository struct {
+	db dbclient.Querier
+}
+
+func NewPurgerRepository(db dbclient.Querier) repository.Purger {
+	return &purgerRepository{
+		db: db,
+	}
+}
+
+// rows are deleted rather than truncated so the audit triggers record them
+func (p *purgerRepository) Purge(ctx context.Context, tables ...string) error {
+	for _, table := range tables {
+		if !purgeable[table] {
+			return fmt.Errorf("cant purge unknown table %q", table)
+		}
+
+		sql := `DELETE FROM public.` + table
+		log.Println("executing sql:", sql)
+
+		tag, err := p.db.Exec(ctx, sql)
+		if err != nil {
+			return handlePgRemoveError(table, err)
+		}
+		log.Println("sql result:", tag.RowsAffected(), "rows")
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package postgres
+
+import (
+	"context"
+	"university-db-admin/internal/repository"
+	"university-db-admin/pkg/dbclient"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// creates all repositories on top of db, which is either
+// a connection pool or an open transaction
+func NewRepository(db dbclient.Querier) *repository.Repository {
+	return &repository.Repository{
+		Transactor:        &transactor{db: db},
+		Employees:         NewEmployeesRepository(db),
+		Groups:            NewGroupsRepository(db),
+		LessonTypes:       NewLessonTypesRepository(db),
+		Lessons:           NewLessonsRepository(db),
+		Marks:             NewMarksRepository(db),
+		Positions:         NewPositionsRepository(db),
+		Students:          NewStudentsRepository(db),
+		Subjects:          NewSubjectsRepository(db),
+		EmployeesSubjects: NewEmployeesSubjectsRepository(db),
+		Audit:             NewAuditRepository(db),
+		Console:           NewConsoleRepository(db),
+		Users:             NewUsersRepository(db),
+		Purger:            NewPurgerRepository(db),
+	}
+}
+
+type transactor struct {
+	db dbclient.Querier
+}
+
+func (t *transactor) WithTx(ctx context.Context, fn func(ctx context.Context, r *repository.Repository) error) error {
+	// on a pool Begin starts a transaction, on a transaction it creates a savepoint
+	tx, err := t.begin(ctx)
+	if err != nil {
+		return handlePgError(err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err = fn(ctx, NewRepository(tx)); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return handlePgError(err)
+	}
+	return nil
+}
+
+// the pool starts a snapshot as a read only repeatable read transaction,
+// read committed would give every statement a snapshot of its own
+func (t *transactor) begin(ctx context.Context) (pgx.Tx, error) {
+	pool, ok := t.db.(interface {
+		BeginTx(ctx context.Context, opts pgx.TxOptions) (pgx.Tx, error)
+	})
+	if !ok || !repository.SnapshotRequested(ctx) {
+		return t.db.Begin(ctx)
+	}
+	return pool.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+}
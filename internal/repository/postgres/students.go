@@ -6,21 +6,32 @@ import (
 	"university-db-admin/internal/domain"
 	"university-db-admin/internal/dto"
 	"university-db-admin/internal/repository"
+	"university-db-admin/pkg/dbclient"
 
 	"github.com/jackc/pgx/v5"
 )
 
 type studentsRepository struct {
-	db *pgx.Conn
+	db dbclient.Querier
 }
 
-func NewStudentsRepository(db *pgx.Conn) repository.Students {
+func NewStudentsRepository(db dbclient.Querier) repository.Students {
 	return &studentsRepository{
 		db: db,
 	}
 }
 
-func (s *studentsRepository) Create(ctx context.Context, stud domain.Student) error {
+var studentsList = listSpec{
+	table:   "public.students",
+	columns: "id, name, passport, employee_id, group_id, deleted_at",
+	fields:  []string{"id", "name", "passport", "employee_id", "group_id", "deleted_at"},
+	order:   "id ASC",
+	keyset:  true,
+
+	softDelete: true,
+}
+
+func (s *studentsRepository) Create(ctx context.Context, stud domain.Student) (uint64, error) {
 	sql := `
 		INSERT INTO public.students (name, passport, employee_id, group_id)
 		VALUES ($1, $2, $3, $4)
@@ -35,18 +46,35 @@ func (s *studentsRepository) Create(ctx context.Context, stud domain.Student) er
 		stud.GroupID,
 	).Scan(&stud.ID)
 	if err != nil {
-		return handlePgError(err)
+		return 0, handlePgError(err)
 	}
 
 	log.Println("sql result:", stud.ID)
-	return nil
+	return stud.ID, nil
+}
+
+func (s *studentsRepository) CopyFrom(ctx context.Context, studs []domain.Student) (int64, error) {
+	log.Println("copying", len(studs), "rows into public.students")
+	n, err := s.db.CopyFrom(ctx,
+		pgx.Identifier{"public", "students"},
+		[]string{"name", "passport", "employee_id", "group_id"},
+		pgx.CopyFromSlice(len(studs), func(i int) ([]any, error) {
+			return []any{studs[i].Name, studs[i].Passport, studs[i].EmployeeID, studs[i].GroupID}, nil
+		}),
+	)
+	if err != nil {
+		return 0, handlePgError(err)
+	}
+
+	log.Println("sql result:", n, "rows")
+	return n, nil
 }
 
 func (s *studentsRepository) FindOne(ctx context.Context, id uint64) (domain.Student, error) {
 	sql := `
 		SELECT id, name, passport, employee_id, group_id
 		FROM public.students
-		WHERE id = $1
+		WHERE id = $1 AND deleted_at IS NULL
 	`
 
 	var stud domain.Student
@@ -70,6 +98,7 @@ func (s *studentsRepository) FindAll(ctx context.Context) ([]domain.Student, err
 	sql := `
 		SELECT id, name, passport, employee_id, group_id
 		FROM public.students
+		WHERE deleted_at IS NULL
 	`
 
 	var students []domain.Student
@@ -96,15 +125,30 @@ func (s *studentsRepository) FindAll(ctx context.Context) ([]domain.Student, err
 		students = append(students, stud)
 	}
 
-	log.Println("sql result:", students)
+	log.Println("sql result:", len(students), "rows")
 	return students, nil
 }
 
+func (s *studentsRepository) List(ctx context.Context, opts repository.ListOptions) (repository.Page[domain.Student], error) {
+	return list(ctx, s.db, studentsList, opts, func(row pgx.Row, stud *domain.Student) error {
+		return row.Scan(
+			&stud.ID,
+			&stud.Name,
+			&stud.Passport,
+			&stud.EmployeeID,
+			&stud.GroupID,
+			&stud.DeletedAt,
+		)
+	}, func(stud domain.Student) uint64 {
+		return stud.ID
+	})
+}
+
 func (s *studentsRepository) FindByName(ctx context.Context, name string) ([]domain.Student, error) {
 	sql := `
 		SELECT id, name, passport, employee_id, group_id
 		FROM public.students
-		WHERE name = $1
+		WHERE name = $1 AND deleted_at IS NULL
 	`
 
 	var students []domain.Student
@@ -139,7 +183,7 @@ func (s *studentsRepository) FindByPassport(ctx context.Context, passport string
 	sql := `
 		SELECT id, name, passport, employee_id, group_id
 		FROM public.students
-		WHERE passport = $1
+		WHERE passport = $1 AND deleted_at IS NULL
 	`
 
 	var stud domain.Student
@@ -163,7 +207,7 @@ func (s *studentsRepository) FindByEmployeeID(ctx context.Context, id uint64) ([
 	sql := `
 		SELECT id, name, passport, employee_id, group_id
 		FROM public.students
-		WHERE employee_id = $1
+		WHERE employee_id = $1 AND deleted_at IS NULL
 	`
 
 	var students []domain.Student
@@ -198,7 +242,7 @@ func (s *studentsRepository) FindByGroupID(ctx context.Context, id uint64) ([]do
 	sql := `
 		SELECT id, name, passport, employee_id, group_id
 		FROM public.students
-		WHERE group_id = $1
+		WHERE group_id = $1 AND deleted_at IS NULL
 	`
 
 	var students []domain.Student
@@ -235,7 +279,7 @@ func (r *studentsRepository) FindAllWithNoCurator(ctx context.Context) ([]dto.St
             students.passport,
             students.group_id
         FROM public.students
-        WHERE NOT students.employee_id IS NOT NULL
+        WHERE NOT students.employee_id IS NOT NULL AND students.deleted_at IS NULL
     `
 
 	log.Println("executing sql:", sql)
@@ -268,7 +312,7 @@ func (r *studentsRepository) FindAllByMiddlename(ctx context.Context, m string)
 	sql := `
 		SELECT students.name, students.passport
 		FROM public.students
-		WHERE students.name LIKE $1
+		WHERE students.name LIKE $1 AND students.deleted_at IS NULL
 	`
 
 	log.Println("executing sql:", sql)
@@ -302,6 +346,7 @@ func (r *studentsRepository) FindAllGroupCombs(ctx context.Context) ([]dto.Stude
 		SELECT students.name, groups.number
 		FROM public.students
 		CROSS JOIN public.groups
+		WHERE students.deleted_at IS NULL AND groups.deleted_at IS NULL
 	`
 
 	log.Println("executing sql:", sql)
@@ -329,14 +374,16 @@ func (r *studentsRepository) FindAllGroupCombs(ctx context.Context) ([]dto.Stude
 	return result, nil
 }
 
+// students without a curator get empty curator fields
 func (r *studentsRepository) FindAllWithCurators(ctx context.Context) ([]dto.StudentCuratorDTO, error) {
 	sql := `
-		SELECT students.name,
-			students.passport,
-			employees.name,
-			employees.passport
+		SELECT COALESCE(students.name, ''),
+			COALESCE(students.passport, ''),
+			COALESCE(employees.name, ''),
+			COALESCE(employees.passport, '')
 		FROM public.students
-		LEFT OUTER JOIN employees ON students.employee_id = employees.id;
+		LEFT OUTER JOIN employees ON students.employee_id = employees.id AND employees.deleted_at IS NULL
+		WHERE students.deleted_at IS NULL;
 	`
 
 	log.Println("executing sql:", sql)
@@ -366,14 +413,16 @@ func (r *studentsRepository) FindAllWithCurators(ctx context.Context) ([]dto.Stu
 	return result, nil
 }
 
+// curators without students get empty student fields
 func (r *studentsRepository) FindWithAllCurators(ctx context.Context) ([]dto.StudentCuratorDTO, error) {
 	sql := `
-		SELECT students.name,
-			students.passport,
-			employees.name,
-			employees.passport
+		SELECT COALESCE(students.name, ''),
+			COALESCE(students.passport, ''),
+			COALESCE(employees.name, ''),
+			COALESCE(employees.passport, '')
 		FROM public.students
-		RIGHT OUTER JOIN employees ON students.employee_id = employees.id;
+		RIGHT OUTER JOIN employees ON students.employee_id = employees.id AND students.deleted_at IS NULL
+		WHERE employees.deleted_at IS NULL;
 	`
 
 	log.Println("executing sql:", sql)
@@ -403,14 +452,16 @@ func (r *studentsRepository) FindWithAllCurators(ctx context.Context) ([]dto.Stu
 	return result, nil
 }
 
+// the side missing from a pair is returned as empty strings
 func (r *studentsRepository) FindAllPairsWithCurator(ctx context.Context) ([]dto.StudentCuratorDTO, error) {
 	sql := `
-		SELECT students.name,
-			students.passport,
-			employees.name,
-			employees.passport
-		FROM public.students
-		FULL OUTER JOIN employees ON students.employee_id = employees.id;
+		SELECT COALESCE(students.name, ''),
+			COALESCE(students.passport, ''),
+			COALESCE(employees.name, ''),
+			COALESCE(employees.passport, '')
+		FROM (SELECT * FROM public.students WHERE deleted_at IS NULL) students
+		FULL OUTER JOIN (SELECT * FROM public.employees WHERE deleted_at IS NULL) employees
+			ON students.employee_id = employees.id;
 	`
 
 	log.Println("executing sql:", sql)
@@ -446,6 +497,7 @@ func (r *studentsRepository) FindAllUppercaseWithLength(ctx context.Context) ([]
 			UPPER(students.name),
 			LENGTH(students.name)
 		FROM public.students
+		WHERE students.deleted_at IS NULL
 	`
 
 	log.Println("executing sql:", sql)
@@ -478,7 +530,7 @@ func (s *studentsRepository) Update(ctx context.Context, id uint64, stud domain.
 	sql := `
 		UPDATE public.students
 		SET name = $1, passport = $2, employee_id = $3, group_id = $4
-		WHERE id = $5
+		WHERE id = $5 AND deleted_at IS NULL
 		RETURNING id
 	`
 
@@ -498,10 +550,31 @@ func (s *studentsRepository) Update(ctx context.Context, id uint64, stud domain.
 	return nil
 }
 
+// moves the student to the trash, marks and other references are kept
 func (s *studentsRepository) Delete(ctx context.Context, id uint64) error {
 	sql := `
-		DELETE FROM public.students
-		WHERE id = $1
+		UPDATE public.students
+		SET deleted_at = now()
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING id
+	`
+
+	log.Println("executing sql:", sql)
+	err := s.db.QueryRow(ctx, sql, id).Scan(&id)
+	if err != nil {
+		return handlePgError(err)
+	}
+
+	log.Println("sql result:", id)
+	return nil
+}
+
+// brings a student back from the trash
+func (s *studentsRepository) Restore(ctx context.Context, id uint64) error {
+	sql := `
+		UPDATE public.students
+		SET deleted_at = NULL
+		WHERE id = $1 AND deleted_at IS NOT NULL
 		RETURNING id
 	`
 
@@ -6,21 +6,30 @@ import (
 	"university-db-admin/internal/domain"
 	"university-db-admin/internal/dto"
 	"university-db-admin/internal/repository"
+	"university-db-admin/pkg/dbclient"
 
 	"github.com/jackc/pgx/v5"
 )
 
 type subjectsRepository struct {
-	db *pgx.Conn
+	db dbclient.Querier
 }
 
-func NewSubjectsRepository(db *pgx.Conn) repository.Subjects {
+func NewSubjectsRepository(db dbclient.Querier) repository.Subjects {
 	return &subjectsRepository{
 		db: db,
 	}
 }
 
-func (s *subjectsRepository) Create(ctx context.Context, sbj domain.Subject) error {
+var subjectsList = listSpec{
+	table:   "public.subjects",
+	columns: "id, name, description",
+	fields:  []string{"id", "name"},
+	order:   "id ASC",
+	keyset:  true,
+}
+
+func (s *subjectsRepository) Create(ctx context.Context, sbj domain.Subject) (uint64, error) {
 	sql := `
 		INSERT INTO public.subjects (name, description)
 		VALUES ($1, $2)
@@ -30,11 +39,11 @@ func (s *subjectsRepository) Create(ctx context.Context, sbj domain.Subject) err
 	log.Println("executing sql:", sql)
 	err := s.db.QueryRow(ctx, sql, sbj.Name, sbj.Description).Scan(&sbj.ID)
 	if err != nil {
-		return handlePgError(err)
+		return 0, handlePgError(err)
 	}
 
 	log.Println("sql result:", sbj.ID)
-	return nil
+	return sbj.ID, nil
 }
 
 func (s *subjectsRepository) FindOne(ctx context.Context, id uint64) (domain.Subject, error) {
@@ -79,10 +88,22 @@ func (s *subjectsRepository) FindAll(ctx context.Context) ([]domain.Subject, err
 		subjects = append(subjects, sbj)
 	}
 
-	log.Println("sql result:", subjects)
+	log.Println("sql result:", len(subjects), "rows")
 	return subjects, nil
 }
 
+func (s *subjectsRepository) List(ctx context.Context, opts repository.ListOptions) (repository.Page[domain.Subject], error) {
+	return list(ctx, s.db, subjectsList, opts, func(row pgx.Row, sbj *domain.Subject) error {
+		return row.Scan(
+			&sbj.ID,
+			&sbj.Name,
+			&sbj.Description,
+		)
+	}, func(sbj domain.Subject) uint64 {
+		return sbj.ID
+	})
+}
+
 func (s *subjectsRepository) FindByName(ctx context.Context, name string) (domain.Subject, error) {
 	sql := `
 		SELECT id, name, description
@@ -159,7 +180,7 @@ func (s *subjectsRepository) Delete(ctx context.Context, id uint64) error {
 	log.Println("executing sql:", sql)
 	err := s.db.QueryRow(ctx, sql, id).Scan(&id)
 	if err != nil {
-		return handlePgError(err)
+		return handlePgRemoveError("subjects", err)
 	}
 
 	log.Println("sql result:", id)
@@ -0,0 +1,133 @@
+package postgres
+
+import (
+	"context"
+	"log"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+	"university-db-admin/pkg/dbclient"
+)
+
+type usersRepository struct {
+	db dbclient.Querier
+}
+
+func NewUsersRepository(db dbclient.Querier) repository.Users {
+	return &usersRepository{
+		db: db,
+	}
+}
+
+// the password hash is never logged
+func (r *usersRepository) Create(ctx context.Context, u domain.User) (uint64, error) {
+	sql := `
+		INSERT INTO public.users (login, password_hash, role, employee_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	log.Println("executing sql:", sql)
+	err := r.db.QueryRow(ctx, sql, u.Login, u.PasswordHash, u.Role, u.EmployeeID).Scan(&u.ID)
+	if err != nil {
+		return 0, handlePgError(err)
+	}
+
+	log.Println("sql result:", u.ID)
+	return u.ID, nil
+}
+
+func (r *usersRepository) FindAll(ctx context.Context) ([]domain.User, error) {
+	sql := `
+		SELECT id, login, password_hash, role, employee_id
+		FROM public.users
+		ORDER BY id
+	`
+
+	var users []domain.User
+	log.Println("executing sql:", sql)
+
+	rows, err := r.db.Query(ctx, sql)
+	if err != nil {
+		return nil, handlePgError(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u domain.User
+		err := rows.Scan(&u.ID, &u.Login, &u.PasswordHash, &u.Role, &u.EmployeeID)
+		if err != nil {
+			return nil, handlePgError(err)
+		}
+		users = append(users, u)
+	}
+
+	log.Println("sql result:", len(users), "rows")
+	return users, nil
+}
+
+func (r *usersRepository) Count(ctx context.Context) (uint64, error) {
+	sql := `SELECT COUNT(*) FROM public.users`
+
+	var n uint64
+	log.Println("executing sql:", sql)
+	if err := r.db.QueryRow(ctx, sql).Scan(&n); err != nil {
+		return 0, handlePgError(err)
+	}
+
+	log.Println("sql result:", n)
+	return n, nil
+}
+
+func (r *usersRepository) FindByLogin(ctx context.Context, login string) (domain.User, error) {
+	sql := `
+		SELECT id, login, password_hash, role, employee_id
+		FROM public.users
+		WHERE login = $1
+	`
+
+	var u domain.User
+
+	log.Println("executing sql:", sql)
+	err := r.db.QueryRow(ctx, sql, login).Scan(&u.ID, &u.Login, &u.PasswordHash, &u.Role, &u.EmployeeID)
+	if err != nil {
+		return domain.User{}, handlePgError(err)
+	}
+
+	log.Println("sql result:", u.ID)
+	return u, nil
+}
+
+func (r *usersRepository) Update(ctx context.Context, id uint64, u domain.User) error {
+	sql := `
+		UPDATE public.users
+		SET login = $1, password_hash = $2, role = $3, employee_id = $4
+		WHERE id = $5
+		RETURNING id
+	`
+
+	log.Println("executing sql:", sql)
+	err := r.db.QueryRow(ctx, sql, u.Login, u.PasswordHash, u.Role, u.EmployeeID, id).Scan(&id)
+	if err != nil {
+		return handlePgError(err)
+	}
+
+	log.Println("sql result:", id)
+	return nil
+}
+
+func (r *usersRepository) Delete(ctx context.Context, id uint64) error {
+	sql := `
+		DELETE FROM public.users
+		WHERE id = $1
+		RETURNING id
+	`
+
+	log.Println("executing sql:", sql)
+	err := r.db.QueryRow(ctx, sql, id).Scan(&id)
+	if err != nil {
+		return handlePgRemoveError("users", err)
+	}
+
+	log.Println("sql result:", id)
+	return nil
+}
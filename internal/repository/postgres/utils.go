@@ -4,17 +4,140 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"strings"
+	"university-db-admin/internal/repository"
 
-	"github.com/jackc/pgconn"
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
 )
 
+// a unique constraint or index of the migrations and the columns it keeps unique
+type uniqueKey struct {
+	table, field string
+}
+
+// a foreign key of the migrations, field of table points at referenced
+type foreignKey struct {
+	table, field, referenced string
+}
+
+// constraints named by the migrations or by postgres after the
+// <table>_<column>_fkey and <table>_pkey convention
+var (
+	uniqueKeys = map[string]uniqueKey{
+		"positions_name_key":       {"positions", "name"},
+		"employees_passport_key":   {"employees", "passport"},
+		"groups_number_key":        {"groups", "number"},
+		"students_passport_key":    {"students", "passport"},
+		"subjects_name_key":        {"subjects", "name"},
+		"lesson_types_name_key":    {"lesson_types", "name"},
+		"employees_subjects_pkey":  {"employees_subjects", "employee_id, subject_id"},
+		"users_login_key":          {"users", "login"},
+		"lessons_group_slot_key":   {"lessons", "group_id, week, weekday, pair"},
+		"lessons_teacher_slot_key": {"lessons", "employee_id, week, weekday, pair"},
+		"lessons_room_slot_key":    {"lessons", "room, week, weekday, pair"},
+	}
+	foreignKeys = map[string]foreignKey{
+		"employees_position_id_fkey":          {"employees", "position_id", "positions"},
+		"students_employee_id_fkey":           {"students", "employee_id", "employees"},
+		"students_group_id_fkey":              {"students", "group_id", "groups"},
+		"lessons_group_id_fkey":               {"lessons", "group_id", "groups"},
+		"lessons_subject_id_fkey":             {"lessons", "subject_id", "subjects"},
+		"lessons_lesson_type_id_fkey":         {"lessons", "lesson_type_id", "lesson_types"},
+		"lessons_employee_subject_fkey":       {"lessons", "employee_id, subject_id", "employees_subjects"},
+		"marks_employee_id_fkey":              {"marks", "employee_id", "employees"},
+		"marks_student_id_fkey":               {"marks", "student_id", "students"},
+		"marks_subject_id_fkey":               {"marks", "subject_id", "subjects"},
+		"employees_subjects_employee_id_fkey": {"employees_subjects", "employee_id", "employees"},
+		"employees_subjects_subject_id_fkey":  {"employees_subjects", "subject_id", "subjects"},
+		"users_employee_id_fkey":              {"users", "employee_id", "employees"},
+	}
+)
+
+// maps driver errors to the repository error taxonomy, a violated
+// foreign key is taken for a reference to a missing row
 func handlePgError(err error) error {
+	return handlePgRemoveError("", err)
+}
+
+// handlePgRemoveError is handlePgError for statements removing rows of
+// table or changing their key: a violated foreign key held by another
+// table means rows of table are still referenced. The errors are told
+// apart by code and constraint, the messages depend on lc_messages
+func handlePgRemoveError(table string, err error) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return repository.ErrNotFound
+	}
+
 	var pgErr *pgconn.PgError
-	if errors.As(err, &pgErr) {
-		newErr := fmt.Errorf("SQL Error: %s, Detail: %s, Where: %s, Code: %s, SQLState: %s",
-			pgErr.Message, pgErr.Detail, pgErr.Where, pgErr.Code, pgErr.SQLState())
-		log.Println(newErr)
-		return newErr
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	log.Println(fmt.Errorf("SQL Error: %s, Detail: %s, Where: %s, Code: %s, SQLState: %s",
+		pgErr.Message, pgErr.Detail, pgErr.Where, pgErr.Code, pgErr.SQLState()))
+
+	switch pgErr.Code {
+	case pgerrcode.UniqueViolation:
+		key, ok := uniqueKeys[pgErr.ConstraintName]
+		if !ok {
+			key = uniqueKey{pgErr.TableName, constraintField(pgErr.TableName, pgErr.ConstraintName)}
+		}
+		return &repository.DuplicateError{
+			Table: key.table,
+			Field: key.field,
+			Value: keyValue(pgErr.Detail, key.field),
+		}
+	case pgerrcode.ForeignKeyViolation:
+		// the reported table is always the one holding the foreign key
+		fk, ok := foreignKeys[pgErr.ConstraintName]
+		if !ok {
+			fk = foreignKey{table: pgErr.TableName}
+		}
+		if table == "" || fk.table == table {
+			return &repository.InvalidReferenceError{
+				Table:           fk.table,
+				Field:           fk.field,
+				ReferencedTable: fk.referenced,
+			}
+		}
+		return &repository.ReferencedError{Table: table, ReferencingTable: fk.table}
+	case pgerrcode.CheckViolation:
+		return &repository.CheckViolationError{
+			Table:      pgErr.TableName,
+			Constraint: pgErr.ConstraintName,
+		}
+	case pgerrcode.NotNullViolation:
+		return &repository.CheckViolationError{
+			Table:      pgErr.TableName,
+			Constraint: pgErr.ColumnName + "_not_null",
+		}
 	}
-	return err
+
+	return fmt.Errorf("SQL Error: %s, Code: %s", pgErr.Message, pgErr.Code)
+}
+
+// finds the value of a duplicate key in the detail of the error, the
+// (field)=(value) part of it is the same in every language. Empty when
+// the detail holds no such part
+func keyValue(detail, field string) string {
+	prefix := "(" + field + ")=("
+	i := strings.Index(detail, prefix)
+	if i < 0 {
+		return ""
+	}
+	value := detail[i+len(prefix):]
+	if j := strings.LastIndex(value, ")"); j >= 0 {
+		return value[:j]
+	}
+	return ""
+}
+
+// extracts the column from constraint names following the
+// <table>_<column>_key convention used by the migrations
+func constraintField(table, constraint string) string {
+	field := strings.TrimPrefix(constraint, table+"_")
+	field = strings.TrimSuffix(field, "_key")
+	return strings.TrimSuffix(field, "_pkey")
 }
@@ -0,0 +1,52 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+	"university-db-admin/internal/repository"
+
+	"github.com/jackc/pgerrcode"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// errors are told apart by code and constraint, the details below are
+// reported by a server running with lc_messages set to russian
+func TestHandlePgError(t *testing.T) {
+	duplicate := &pgconn.PgError{
+		Code:           pgerrcode.UniqueViolation,
+		TableName:      "employees_subjects",
+		ConstraintName: "employees_subjects_pkey",
+		Detail:         `Ключ "(employee_id, subject_id)=(1, 2)" уже существует.`,
+	}
+	var dupErr *repository.DuplicateError
+	if !errors.As(handlePgError(duplicate), &dupErr) {
+		t.Fatalf("got %v, want a duplicate error", handlePgError(duplicate))
+	}
+	if want := (repository.DuplicateError{Table: "employees_subjects", Field: "employee_id, subject_id", Value: "1, 2"}); *dupErr != want {
+		t.Fatalf("got %+v, want %+v", *dupErr, want)
+	}
+
+	foreign := &pgconn.PgError{
+		Code:           pgerrcode.ForeignKeyViolation,
+		TableName:      "lessons",
+		ConstraintName: "lessons_employee_subject_fkey",
+		Detail:         `Ключ (employee_id, subject_id)=(1, 2) отсутствует в таблице "employees_subjects".`,
+	}
+	var invErr *repository.InvalidReferenceError
+	if !errors.As(handlePgError(foreign), &invErr) {
+		t.Fatalf("got %v, want an invalid reference error", handlePgError(foreign))
+	}
+	if want := (repository.InvalidReferenceError{Table: "lessons", Field: "employee_id, subject_id", ReferencedTable: "employees_subjects"}); *invErr != want {
+		t.Fatalf("got %+v, want %+v", *invErr, want)
+	}
+
+	// removing a subject cascades to the subjects of the teachers and
+	// trips the foreign key of their lessons
+	var refErr *repository.ReferencedError
+	if !errors.As(handlePgRemoveError("subjects", foreign), &refErr) {
+		t.Fatalf("got %v, want a referenced error", handlePgRemoveError("subjects", foreign))
+	}
+	if want := (repository.ReferencedError{Table: "subjects", ReferencingTable: "lessons"}); *refErr != want {
+		t.Fatalf("got %+v, want %+v", *refErr, want)
+	}
+}
@@ -0,0 +1,63 @@
+package repository
+
+import "errors"
+
+// ErrInvalidOption is returned by List when options reference a field
+// that can't be sorted or filtered on, or combine incompatible settings
+var ErrInvalidOption = errors.New("invalid list option")
+
+type FilterOp string
+
+const (
+	OpEq       FilterOp = "eq"
+	OpNe       FilterOp = "ne"
+	OpLt       FilterOp = "lt"
+	OpLte      FilterOp = "lte"
+	OpGt       FilterOp = "gt"
+	OpGte      FilterOp = "gte"
+	OpContains FilterOp = "contains" // case-insensitive substring match
+)
+
+// Filter restricts a list to rows whose Field compares to Value with Op.
+// Field is a column name of the listed table, Value is its text representation.
+type Filter struct {
+	Field string
+	Op    FilterOp
+	Value string
+}
+
+// Deleted selects which rows of soft-deleted tables are listed,
+// the zero value hides deleted rows
+type Deleted string
+
+const (
+	ExcludeDeleted Deleted = ""
+	IncludeDeleted Deleted = "include"
+	OnlyDeleted    Deleted = "only"
+)
+
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// ListOptions are accepted by every List method. Filters are combined with AND.
+// Either Offset or AfterID may be used for paging; AfterID is a keyset cursor
+// that requires the default ordering by id. Deleted applies to students,
+// employees and groups, other tables only accept its zero value.
+type ListOptions struct {
+	Limit   uint64 // 0 means no limit
+	Offset  uint64
+	AfterID uint64
+	Sort    []Sort
+	Filters []Filter
+	Deleted Deleted
+}
+
+// Page is one page of a list together with the number of
+// rows matching the filters regardless of paging
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	Total      uint64 `json:"total"`
+	NextCursor uint64 `json:"next_cursor"` // id of the last item when more rows follow it, 0 otherwise
+}
@@ -7,6 +7,7 @@ import (
 )
 
 type Repository struct {
+	Transactor
 	Employees         Employees
 	Groups            Groups
 	LessonTypes       LessonTypes
@@ -16,45 +17,101 @@ type Repository struct {
 	Students          Students
 	Subjects          Subjects
 	EmployeesSubjects EmployeesSubjects
+	Audit             Audit
+	Console           Console
+	Users             Users
+	Purger            Purger
+}
+
+type gradingCheckKey struct{}
+
+// WithoutGradingCheck returns a context whose marks written by
+// Marks.CopyFrom skip checking that the grader teaches the subject to the
+// group of the student. Restoring a backup uses it for marks given before
+// the rule existed. In postgres the trigger only lets through rows copied
+// in a transaction by the owner of the marks table, so the sql console,
+// which can't copy from the client, can't lift the check by setting
+// app.skip_grading_check itself
+func WithoutGradingCheck(ctx context.Context) context.Context {
+	return context.WithValue(ctx, gradingCheckKey{}, true)
+}
+
+// GradingCheckSkipped tells whether ctx comes from WithoutGradingCheck
+func GradingCheckSkipped(ctx context.Context) bool {
+	skip, _ := ctx.Value(gradingCheckKey{}).(bool)
+	return skip
+}
+
+type snapshotKey struct{}
+
+// WithSnapshot returns a context whose transactions only read and see
+// the database as it was when they started, every statement of the
+// transaction reads the same snapshot. In postgres it applies to
+// transactions started on the pool, nested ones share the outer snapshot
+func WithSnapshot(ctx context.Context) context.Context {
+	return context.WithValue(ctx, snapshotKey{}, true)
+}
+
+// SnapshotRequested tells whether ctx comes from WithSnapshot
+func SnapshotRequested(ctx context.Context) bool {
+	snapshot, _ := ctx.Value(snapshotKey{}).(bool)
+	return snapshot
+}
+
+// Transactor runs fn in a transaction. The Repository passed to fn is scoped to
+// that transaction: it is committed when fn returns nil and rolled back
+// otherwise. Calling WithTx on the scoped Repository opens a savepoint, so a
+// failing nested call rolls back only its own changes.
+type Transactor interface {
+	WithTx(ctx context.Context, fn func(ctx context.Context, r *Repository) error) error
 }
 
 type Employees interface {
-	Create(ctx context.Context, emp domain.Employee) error
+	Create(ctx context.Context, emp domain.Employee) (uint64, error)
+	CopyFrom(ctx context.Context, emps []domain.Employee) (int64, error)
 	FindOne(ctx context.Context, id uint64) (domain.Employee, error)
 	FindAll(ctx context.Context) ([]domain.Employee, error)
+	List(ctx context.Context, opts ListOptions) (Page[domain.Employee], error)
 	FindByName(ctx context.Context, name string) ([]domain.Employee, error)
 	FindByPassport(ctx context.Context, passport string) (domain.Employee, error)
 	FindByPosition(ctx context.Context, position uint64) ([]domain.Employee, error)
 	FindAllNamePassport(ctx context.Context) ([]dto.EmployeeDTO, error)
 	FindNamePassportByID(ctx context.Context, id uint64) (dto.EmployeeDTO, error)
 	FindAllByPositions(ctx context.Context, firstID, secondID uint64) ([]dto.EmployeePositionDTO, error)
-	IsTeacher(ctx context.Context, id uint64) (dto.EmployeeRoleDTO, error)
+	FindByCapability(ctx context.Context, c domain.Capability) ([]domain.Employee, error) // active holders of positions with c
+	IsTeacher(ctx context.Context, id uint64) (dto.EmployeeRoleDTO, error)                // decided by can_teach of the position
+	IsCurator(ctx context.Context, id uint64) (dto.EmployeeRoleDTO, error)                // decided by can_curate of the position
 	Update(ctx context.Context, id uint64, emp domain.Employee) error
-	Delete(ctx context.Context, id uint64) error
+	Delete(ctx context.Context, id uint64) error // moves the record to the trash
+	Restore(ctx context.Context, id uint64) error
 }
 
 type Groups interface {
-	Create(ctx context.Context, grp domain.Group) error
+	Create(ctx context.Context, grp domain.Group) (uint64, error)
 	FindOne(ctx context.Context, id uint64) (domain.Group, error)
 	FindAll(ctx context.Context) ([]domain.Group, error)
+	List(ctx context.Context, opts ListOptions) (Page[domain.Group], error)
 	FindByNumber(ctx context.Context, num uint64) (domain.Group, error)
 	Update(ctx context.Context, id uint64, grp domain.Group) error
-	Delete(ctx context.Context, id uint64) error
+	Delete(ctx context.Context, id uint64) error // moves the record to the trash
+	Restore(ctx context.Context, id uint64) error
 }
 
 type LessonTypes interface {
-	Create(ctx context.Context, lsn domain.LessonType) error
+	Create(ctx context.Context, lsn domain.LessonType) (uint64, error)
 	FindOne(ctx context.Context, id uint64) (domain.LessonType, error)
 	FindAll(ctx context.Context) ([]domain.LessonType, error)
+	List(ctx context.Context, opts ListOptions) (Page[domain.LessonType], error)
 	FindByName(ctx context.Context, name string) (domain.LessonType, error)
 	Update(ctx context.Context, id uint64, lsn domain.LessonType) error
 	Delete(ctx context.Context, id uint64) error
 }
 
 type Lessons interface {
-	Create(ctx context.Context, lsn domain.Lesson) error
+	Create(ctx context.Context, lsn domain.Lesson) (uint64, error)
 	FindOne(ctx context.Context, id uint64) (domain.Lesson, error)
 	FindAll(ctx context.Context) ([]domain.Lesson, error)
+	List(ctx context.Context, opts ListOptions) (Page[domain.Lesson], error)
 	FindByGroupID(ctx context.Context, id uint64) ([]domain.Lesson, error)
 	FindBySubjectID(ctx context.Context, id uint64) ([]domain.Lesson, error)
 	FindByLessonTypeID(ctx context.Context, id uint64) ([]domain.Lesson, error)
@@ -67,9 +124,11 @@ type Lessons interface {
 }
 
 type Marks interface {
-	Create(ctx context.Context, mark domain.Mark) error
+	Create(ctx context.Context, mark domain.Mark) (uint64, error)
+	CopyFrom(ctx context.Context, marks []domain.Mark) (int64, error)
 	FindOne(ctx context.Context, id uint64) (domain.Mark, error)
 	FindAll(ctx context.Context) ([]domain.Mark, error)
+	List(ctx context.Context, opts ListOptions) (Page[domain.Mark], error)
 	FindByEmployeeID(ctx context.Context, id uint64) ([]domain.Mark, error)
 	FindByStudentID(ctx context.Context, id uint64) ([]domain.Mark, error)
 	FindBySubjectID(ctx context.Context, id uint64) ([]domain.Mark, error)
@@ -82,18 +141,21 @@ type Marks interface {
 }
 
 type Positions interface {
-	Create(ctx context.Context, pos domain.Position) error
+	Create(ctx context.Context, pos domain.Position) (uint64, error)
 	FindOne(ctx context.Context, id uint64) (domain.Position, error)
 	FindAll(ctx context.Context) ([]domain.Position, error)
+	List(ctx context.Context, opts ListOptions) (Page[domain.Position], error)
 	FindByName(ctx context.Context, name string) (domain.Position, error)
 	Update(ctx context.Context, id uint64, pos domain.Position) error
 	Delete(ctx context.Context, id uint64) error
 }
 
 type Students interface {
-	Create(ctx context.Context, stud domain.Student) error
+	Create(ctx context.Context, stud domain.Student) (uint64, error)
+	CopyFrom(ctx context.Context, studs []domain.Student) (int64, error)
 	FindOne(ctx context.Context, id uint64) (domain.Student, error)
 	FindAll(ctx context.Context) ([]domain.Student, error)
+	List(ctx context.Context, opts ListOptions) (Page[domain.Student], error)
 	FindByName(ctx context.Context, name string) ([]domain.Student, error)
 	FindByPassport(ctx context.Context, passport string) (domain.Student, error)
 	FindByEmployeeID(ctx context.Context, id uint64) ([]domain.Student, error)
@@ -106,13 +168,15 @@ type Students interface {
 	FindAllPairsWithCurator(ctx context.Context) ([]dto.StudentCuratorDTO, error)
 	FindAllUppercaseWithLength(ctx context.Context) ([]dto.StudentNameStatDTO, error)
 	Update(ctx context.Context, id uint64, stud domain.Student) error
-	Delete(ctx context.Context, id uint64) error
+	Delete(ctx context.Context, id uint64) error // moves the record to the trash
+	Restore(ctx context.Context, id uint64) error
 }
 
 type Subjects interface {
-	Create(ctx context.Context, sbj domain.Subject) error
+	Create(ctx context.Context, sbj domain.Subject) (uint64, error)
 	FindOne(ctx context.Context, id uint64) (domain.Subject, error)
 	FindAll(ctx context.Context) ([]domain.Subject, error)
+	List(ctx context.Context, opts ListOptions) (Page[domain.Subject], error)
 	FindByName(ctx context.Context, name string) (domain.Subject, error)
 	FindAllSorted(ctx context.Context) ([]dto.SortedSubjectDTO, error)
 	Update(ctx context.Context, id uint64, sbj domain.Subject) error
@@ -122,8 +186,56 @@ type Subjects interface {
 type EmployeesSubjects interface {
 	Create(ctx context.Context, es domain.EmployeeSubject) error
 	FindAll(ctx context.Context) ([]domain.EmployeeSubject, error)
+	List(ctx context.Context, opts ListOptions) (Page[domain.EmployeeSubject], error)
 	FindByEmployeeID(ctx context.Context, id uint64) ([]domain.EmployeeSubject, error)
 	FindBySubjectID(ctx context.Context, id uint64) ([]domain.EmployeeSubject, error)
 	Update(ctx context.Context, eid uint64, sid uint64, es domain.EmployeeSubject) error
 	Delete(ctx context.Context, eid uint64, sid uint64) error
 }
+
+// Audit reads the change log written by database triggers, entries can't be modified
+type Audit interface {
+	List(ctx context.Context, opts ListOptions) (Page[domain.AuditEntry], error)
+}
+
+// Users stores the accounts of the application, a teacher account
+// references its employee record
+type Users interface {
+	Create(ctx context.Context, u domain.User) (uint64, error)
+	FindAll(ctx context.Context) ([]domain.User, error)
+	FindByLogin(ctx context.Context, login string) (domain.User, error)
+	Count(ctx context.Context) (uint64, error)
+	Update(ctx context.Context, id uint64, u domain.User) error
+	Delete(ctx context.Context, id uint64) error
+}
+
+// Purger deletes every row of the given tables for good, trash included,
+// emptying them in the order given. A table still referenced by rows of a
+// table that isn't purged fails with ReferencedError; callers run it in a
+// transaction so a failure leaves every table as it was
+type Purger interface {
+	Purge(ctx context.Context, tables ...string) error
+}
+
+// Console runs statements typed by the user. Without write the statement runs
+// in a read-only transaction that is rolled back. At most limit rows are read,
+// ConsoleResult.Truncated tells whether there were more. A limit of 0 reads
+// all rows, args are the values of the statement parameters $1, $2...
+type Console interface {
+	Exec(ctx context.Context, statement string, write bool, limit int, args ...any) (ConsoleResult, error)
+
+	// Report runs a statement of the special queries file like Exec without
+	// write, reading all rows. The statements come from the configuration
+	// rather than the user, so unlike Exec it's open to every role
+	Report(ctx context.Context, statement string, args ...any) (ConsoleResult, error)
+}
+
+// ConsoleResult holds the rows returned by a statement with the names of their
+// columns and the command tag reported by the database, e.g. "UPDATE 3"
+type ConsoleResult struct {
+	Columns      []string
+	Rows         [][]any
+	Truncated    bool
+	Command      string
+	RowsAffected int64
+}
@@ -0,0 +1,108 @@
+package repotest
+
+import (
+	"testing"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+)
+
+// entry fields every implementation fills the same way
+type change struct {
+	entity, recordID, operation, operator string
+	hasOld, hasNew                        bool
+}
+
+func changes(entries []domain.AuditEntry) []change {
+	result := []change{}
+	for _, entry := range entries {
+		result = append(result, change{
+			entity:    entry.Entity,
+			recordID:  entry.RecordID,
+			operation: entry.Operation,
+			operator:  entry.Operator,
+			hasOld:    entry.OldValues != nil,
+			hasNew:    entry.NewValues != nil,
+		})
+	}
+	return result
+}
+
+func testAudit(t *testing.T, open Open) {
+	run(t, open, "List", func(e *env) {
+		page, err := e.r.Audit.List(e.ctx, repository.ListOptions{})
+		e.must(err)
+		equal(e.t, page.Total, 0)
+
+		id := e.position("Lecturer")
+		e.must(e.r.Positions.Update(e.ctx, id, domain.Position{Name: "Docent"}))
+		// updates changing nothing are not recorded
+		e.must(e.r.Positions.Update(e.ctx, id, domain.Position{Name: "Docent"}))
+		e.must(e.r.Positions.Delete(e.ctx, id))
+
+		// newest first
+		page, err = e.r.Audit.List(e.ctx, repository.ListOptions{})
+		e.must(err)
+		equal(e.t, page.Total, 3)
+		equalSlices(e.t, changes(page.Items), []change{
+			{entity: "positions", recordID: idText(id), operation: "delete", operator: operator, hasOld: true},
+			{entity: "positions", recordID: idText(id), operation: "update", operator: operator, hasOld: true, hasNew: true},
+			{entity: "positions", recordID: idText(id), operation: "create", operator: operator, hasNew: true},
+		})
+		if page.Items[0].ChangedAt.IsZero() {
+			e.t.Fatal("change time is not set")
+		}
+	})
+
+	run(t, open, "SoftDelete", func(e *env) {
+		id := e.group(101)
+		e.must(e.r.Groups.Delete(e.ctx, id))
+		e.must(e.r.Groups.Restore(e.ctx, id))
+
+		// moving to the trash and back are updates of the record
+		page, err := e.r.Audit.List(e.ctx, repository.ListOptions{
+			Filters: []repository.Filter{{Field: "operation", Op: repository.OpEq, Value: "update"}},
+		})
+		e.must(err)
+		equal(e.t, page.Total, 2)
+	})
+
+	run(t, open, "CompositeKey", func(e *env) {
+		f := e.faculty()
+		e.assign(f.ivanov, f.math)
+		e.must(e.r.EmployeesSubjects.Update(e.ctx, f.ivanov, f.math, domain.EmployeeSubject{EmployeeID: f.petrova, SubjectID: f.math}))
+
+		// the record id joins the key columns, updates report the new key
+		page, err := e.r.Audit.List(e.ctx, repository.ListOptions{
+			Filters: []repository.Filter{{Field: "entity", Op: repository.OpEq, Value: "employees_subjects"}},
+		})
+		e.must(err)
+		equalSlices(e.t, changes(page.Items), []change{
+			{entity: "employees_subjects", recordID: idText(f.petrova) + "/" + idText(f.math), operation: "update", operator: operator, hasOld: true, hasNew: true},
+			{entity: "employees_subjects", recordID: idText(f.ivanov) + "/" + idText(f.math), operation: "create", operator: operator, hasNew: true},
+		})
+	})
+
+	run(t, open, "Options", func(e *env) {
+		e.position("Lecturer")
+
+		page, err := e.r.Audit.List(e.ctx, repository.ListOptions{
+			Filters: []repository.Filter{{Field: "operator", Op: repository.OpEq, Value: operator}},
+			Sort:    []repository.Sort{{Field: "changed_at"}},
+		})
+		e.must(err)
+		equal(e.t, page.Total, 1)
+
+		// the log is neither soft deleted nor paged by cursor
+		_, err = e.r.Audit.List(e.ctx, repository.ListOptions{Deleted: repository.OnlyDeleted})
+		wantError(e.t, err, repository.ErrInvalidOption)
+
+		_, err = e.r.Audit.List(e.ctx, repository.ListOptions{AfterID: page.Items[0].ID})
+		wantError(e.t, err, repository.ErrInvalidOption)
+
+		// values aren't filterable
+		_, err = e.r.Audit.List(e.ctx, repository.ListOptions{
+			Filters: []repository.Filter{{Field: "new_values", Op: repository.OpContains, Value: "Lecturer"}},
+		})
+		wantError(e.t, err, repository.ErrInvalidOption)
+	})
+}
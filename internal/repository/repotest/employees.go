@@ -0,0 +1,318 @@
+package repotest
+
+import (
+	"testing"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/dto"
+	"university-db-admin/internal/repository"
+)
+
+func testEmployees(t *testing.T, open Open) {
+	run(t, open, "Create", func(e *env) {
+		f := e.faculty()
+
+		emp, err := e.r.Employees.FindOne(e.ctx, f.ivanov)
+		e.must(err)
+		equal(e.t, emp, domain.Employee{ID: f.ivanov, Name: "Ivanov Ivan Petrovich", Passport: "MP0000001", PositionID: f.teacher})
+
+		_, err = e.r.Employees.Create(e.ctx, domain.Employee{Name: "Someone Else", Passport: "MP0000001", PositionID: f.teacher})
+		wantDuplicate(e.t, err, "employees", "passport", "MP0000001")
+
+		_, err = e.r.Employees.Create(e.ctx, domain.Employee{Name: "Someone Else", Passport: "MP0000009", PositionID: f.teacher + 100})
+		wantInvalidReference(e.t, err, "employees", "position_id", "positions")
+	})
+
+	run(t, open, "CopyFrom", func(e *env) {
+		pos := e.position("Professor")
+
+		n, err := e.r.Employees.CopyFrom(e.ctx, []domain.Employee{
+			{Name: "First Employee", Passport: passport(1), PositionID: pos},
+			{Name: "Second Employee", Passport: passport(2), PositionID: pos},
+		})
+		e.must(err)
+		equal(e.t, n, 2)
+
+		// a rejected row discards the whole batch
+		_, err = e.r.Employees.CopyFrom(e.ctx, []domain.Employee{
+			{Name: "Third Employee", Passport: passport(3), PositionID: pos},
+			{Name: "Fourth Employee", Passport: passport(1), PositionID: pos},
+		})
+		wantError(e.t, err, repository.ErrDuplicate)
+
+		emps, err := e.r.Employees.FindAll(e.ctx)
+		e.must(err)
+		equal(e.t, len(emps), 2)
+	})
+
+	run(t, open, "FindOne", func(e *env) {
+		f := e.faculty()
+
+		_, err := e.r.Employees.FindOne(e.ctx, f.ivanov+100)
+		wantError(e.t, err, repository.ErrNotFound)
+
+		e.must(e.r.Employees.Delete(e.ctx, f.ivanov))
+		_, err = e.r.Employees.FindOne(e.ctx, f.ivanov)
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+
+	run(t, open, "FindAll", func(e *env) {
+		emps, err := e.r.Employees.FindAll(e.ctx)
+		e.must(err)
+		equal(e.t, len(emps), 0)
+
+		f := e.faculty()
+		e.must(e.r.Employees.Delete(e.ctx, f.ivanov))
+
+		emps, err = e.r.Employees.FindAll(e.ctx)
+		e.must(err)
+		sameElements(e.t, emps, []domain.Employee{
+			{ID: f.petrova, Name: "Petrova Olga Ivanovna", Passport: "MP0000002", PositionID: f.assistant},
+		})
+	})
+
+	run(t, open, "List", func(e *env) {
+		f := e.faculty()
+		e.must(e.r.Employees.Delete(e.ctx, f.ivanov))
+
+		page, err := e.r.Employees.List(e.ctx, repository.ListOptions{})
+		e.must(err)
+		equalSlices(e.t, ids(page.Items, employeeID), []uint64{f.petrova})
+
+		page, err = e.r.Employees.List(e.ctx, repository.ListOptions{Deleted: repository.OnlyDeleted})
+		e.must(err)
+		equalSlices(e.t, ids(page.Items, employeeID), []uint64{f.ivanov})
+		if page.Items[0].DeletedAt == nil {
+			e.t.Fatal("deleted employee is listed without deleted_at")
+		}
+
+		page, err = e.r.Employees.List(e.ctx, repository.ListOptions{
+			Filters: []repository.Filter{{Field: "position_id", Op: repository.OpEq, Value: idText(f.teacher)}},
+			Deleted: repository.IncludeDeleted,
+		})
+		e.must(err)
+		equalSlices(e.t, ids(page.Items, employeeID), []uint64{f.ivanov})
+	})
+
+	run(t, open, "FindByName", func(e *env) {
+		f := e.faculty()
+		namesake := e.employee("Ivanov Ivan Petrovich", passport(9), f.assistant)
+
+		emps, err := e.r.Employees.FindByName(e.ctx, "Ivanov Ivan Petrovich")
+		e.must(err)
+		sameElements(e.t, ids(emps, employeeID), []uint64{f.ivanov, namesake})
+
+		emps, err = e.r.Employees.FindByName(e.ctx, "Ivanov")
+		e.must(err)
+		equal(e.t, len(emps), 0)
+	})
+
+	run(t, open, "FindByPassport", func(e *env) {
+		f := e.faculty()
+
+		emp, err := e.r.Employees.FindByPassport(e.ctx, "MP0000002")
+		e.must(err)
+		equal(e.t, emp.ID, f.petrova)
+
+		_, err = e.r.Employees.FindByPassport(e.ctx, passport(9))
+		wantError(e.t, err, repository.ErrNotFound)
+
+		e.must(e.r.Employees.Delete(e.ctx, f.petrova))
+		_, err = e.r.Employees.FindByPassport(e.ctx, "MP0000002")
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+
+	run(t, open, "FindByPosition", func(e *env) {
+		f := e.faculty()
+
+		emps, err := e.r.Employees.FindByPosition(e.ctx, f.assistant)
+		e.must(err)
+		equalSlices(e.t, ids(emps, employeeID), []uint64{f.petrova})
+
+		emps, err = e.r.Employees.FindByPosition(e.ctx, f.assistant+100)
+		e.must(err)
+		equal(e.t, len(emps), 0)
+	})
+
+	run(t, open, "FindAllNamePassport", func(e *env) {
+		f := e.faculty()
+		e.must(e.r.Employees.Delete(e.ctx, f.petrova))
+
+		result, err := e.r.Employees.FindAllNamePassport(e.ctx)
+		e.must(err)
+		sameElements(e.t, result, []dto.EmployeeDTO{{Name: "Ivanov Ivan Petrovich", Passport: "MP0000001"}})
+	})
+
+	run(t, open, "FindNamePassportByID", func(e *env) {
+		f := e.faculty()
+
+		result, err := e.r.Employees.FindNamePassportByID(e.ctx, f.petrova)
+		e.must(err)
+		equal(e.t, result, dto.EmployeeDTO{Name: "Petrova Olga Ivanovna", Passport: "MP0000002"})
+
+		_, err = e.r.Employees.FindNamePassportByID(e.ctx, f.petrova+100)
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+
+	run(t, open, "FindAllByPositions", func(e *env) {
+		f := e.faculty()
+		dean := e.position("Dean")
+		e.employee("Sidorov Petr Olegovich", passport(9), dean)
+
+		result, err := e.r.Employees.FindAllByPositions(e.ctx, f.teacher, f.assistant)
+		e.must(err)
+		sameElements(e.t, result, []dto.EmployeePositionDTO{{Name: "Ivanov Ivan Petrovich"}, {Name: "Petrova Olga Ivanovna"}})
+
+		result, err = e.r.Employees.FindAllByPositions(e.ctx, dean, dean)
+		e.must(err)
+		sameElements(e.t, result, []dto.EmployeePositionDTO{{Name: "Sidorov Petr Olegovich"}})
+	})
+
+	run(t, open, "IsTeacher", func(e *env) {
+		f := e.faculty()
+
+		role, err := e.r.Employees.IsTeacher(e.ctx, f.ivanov)
+		e.must(err)
+		equal(e.t, role.IsTeacher, true)
+
+		role, err = e.r.Employees.IsTeacher(e.ctx, f.petrova)
+		e.must(err)
+		equal(e.t, role.IsTeacher, false)
+
+		// unknown and trashed employees aren't teachers rather than missing
+		role, err = e.r.Employees.IsTeacher(e.ctx, f.ivanov+100)
+		e.must(err)
+		equal(e.t, role.IsTeacher, false)
+
+		e.must(e.r.Employees.Delete(e.ctx, f.ivanov))
+		role, err = e.r.Employees.IsTeacher(e.ctx, f.ivanov)
+		e.must(err)
+		equal(e.t, role.IsTeacher, false)
+
+		// decided by the capability, not by the name of the position
+		e.must(e.r.Positions.Update(e.ctx, f.assistant, domain.Position{Name: "Преподаватель", CanCurate: true}))
+		role, err = e.r.Employees.IsTeacher(e.ctx, f.petrova)
+		e.must(err)
+		equal(e.t, role.IsTeacher, false)
+
+		e.must(e.r.Positions.Update(e.ctx, f.assistant, domain.Position{Name: "Ассистент", CanTeach: true}))
+		role, err = e.r.Employees.IsTeacher(e.ctx, f.petrova)
+		e.must(err)
+		equal(e.t, role.IsTeacher, true)
+	})
+
+	run(t, open, "IsCurator", func(e *env) {
+		f := e.faculty()
+		lab := e.employee("Sidorov Petr Olegovich", "MP0000003", e.position("Лаборант"))
+
+		role, err := e.r.Employees.IsCurator(e.ctx, f.petrova)
+		e.must(err)
+		equal(e.t, role.IsCurator, true)
+
+		role, err = e.r.Employees.IsCurator(e.ctx, lab)
+		e.must(err)
+		equal(e.t, role.IsCurator, false)
+
+		e.must(e.r.Employees.Delete(e.ctx, f.petrova))
+		role, err = e.r.Employees.IsCurator(e.ctx, f.petrova)
+		e.must(err)
+		equal(e.t, role.IsCurator, false)
+	})
+
+	run(t, open, "FindByCapability", func(e *env) {
+		f := e.faculty()
+		e.employee("Sidorov Petr Olegovich", "MP0000003", e.position("Лаборант"))
+
+		emps, err := e.r.Employees.FindByCapability(e.ctx, domain.CapabilityTeach)
+		e.must(err)
+		equalSlices(e.t, ids(emps, employeeID), []uint64{f.ivanov})
+
+		emps, err = e.r.Employees.FindByCapability(e.ctx, domain.CapabilityCurate)
+		e.must(err)
+		equalSlices(e.t, ids(emps, employeeID), []uint64{f.ivanov, f.petrova})
+
+		emps, err = e.r.Employees.FindByCapability(e.ctx, domain.CapabilityAdminister)
+		e.must(err)
+		equal(e.t, len(emps), 0)
+
+		// trashed employees are left out
+		e.must(e.r.Employees.Delete(e.ctx, f.ivanov))
+		emps, err = e.r.Employees.FindByCapability(e.ctx, domain.CapabilityCurate)
+		e.must(err)
+		equalSlices(e.t, ids(emps, employeeID), []uint64{f.petrova})
+
+		_, err = e.r.Employees.FindByCapability(e.ctx, "can_fly")
+		if err == nil {
+			e.t.Fatal("got no error for an unknown capability")
+		}
+	})
+
+	run(t, open, "Update", func(e *env) {
+		f := e.faculty()
+
+		updated := domain.Employee{Name: "Ivanova Maria Petrovna", Passport: "MP0000003", PositionID: f.assistant}
+		e.must(e.r.Employees.Update(e.ctx, f.ivanov, updated))
+		emp, err := e.r.Employees.FindOne(e.ctx, f.ivanov)
+		e.must(err)
+		updated.ID = f.ivanov
+		equal(e.t, emp, updated)
+
+		err = e.r.Employees.Update(e.ctx, f.ivanov, domain.Employee{Name: updated.Name, Passport: "MP0000002", PositionID: f.assistant})
+		wantDuplicate(e.t, err, "employees", "passport", "MP0000002")
+
+		err = e.r.Employees.Update(e.ctx, f.ivanov, domain.Employee{Name: updated.Name, Passport: updated.Passport, PositionID: f.assistant + 100})
+		wantInvalidReference(e.t, err, "employees", "position_id", "positions")
+
+		err = e.r.Employees.Update(e.ctx, f.ivanov+100, updated)
+		wantError(e.t, err, repository.ErrNotFound)
+
+		// employees in the trash can't be changed
+		e.must(e.r.Employees.Delete(e.ctx, f.petrova))
+		err = e.r.Employees.Update(e.ctx, f.petrova, domain.Employee{Name: "Petrova Olga", Passport: "MP0000002", PositionID: f.assistant})
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+
+	run(t, open, "Delete", func(e *env) {
+		f := e.faculty()
+
+		e.must(e.r.Employees.Delete(e.ctx, f.ivanov))
+		err := e.r.Employees.Delete(e.ctx, f.ivanov)
+		wantError(e.t, err, repository.ErrNotFound)
+
+		err = e.r.Employees.Delete(e.ctx, f.ivanov+100)
+		wantError(e.t, err, repository.ErrNotFound)
+
+		// the passport is given up while its owner is in the trash
+		_, err = e.r.Employees.Create(e.ctx, domain.Employee{Name: "Someone Else", Passport: "MP0000001", PositionID: f.teacher})
+		e.must(err)
+
+		// students keep their curator
+		stud, err := e.r.Students.FindOne(e.ctx, f.anna)
+		e.must(err)
+		equal(e.t, stud.EmployeeID, f.ivanov)
+	})
+
+	run(t, open, "Restore", func(e *env) {
+		f := e.faculty()
+
+		err := e.r.Employees.Restore(e.ctx, f.ivanov)
+		wantError(e.t, err, repository.ErrNotFound)
+
+		e.must(e.r.Employees.Delete(e.ctx, f.ivanov))
+		e.must(e.r.Employees.Restore(e.ctx, f.ivanov))
+		emp, err := e.r.Employees.FindOne(e.ctx, f.ivanov)
+		e.must(err)
+		equal(e.t, emp.DeletedAt == nil, true)
+
+		err = e.r.Employees.Restore(e.ctx, f.ivanov+100)
+		wantError(e.t, err, repository.ErrNotFound)
+
+		// an active employee took the passport in the meantime
+		e.must(e.r.Employees.Delete(e.ctx, f.ivanov))
+		_, err = e.r.Employees.Create(e.ctx, domain.Employee{Name: "Someone Else", Passport: "MP0000001", PositionID: f.teacher})
+		e.must(err)
+		err = e.r.Employees.Restore(e.ctx, f.ivanov)
+		wantDuplicate(e.t, err, "employees", "passport", "MP0000001")
+	})
+}
+
+func employeeID(emp domain.Employee) uint64 { return emp.ID }
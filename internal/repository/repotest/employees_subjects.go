@@ -0,0 +1,148 @@
+package repotest
+
+import (
+	"testing"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+)
+
+func testEmployeesSubjects(t *testing.T, open Open) {
+	run(t, open, "Create", func(e *env) {
+		f := e.faculty()
+		e.assign(f.ivanov, f.math)
+
+		err := e.r.EmployeesSubjects.Create(e.ctx, domain.EmployeeSubject{EmployeeID: f.ivanov, SubjectID: f.math})
+		wantDuplicate(e.t, err, "employees_subjects", "employee_id, subject_id", idText(f.ivanov)+", "+idText(f.math))
+
+		err = e.r.EmployeesSubjects.Create(e.ctx, domain.EmployeeSubject{EmployeeID: f.ivanov + 100, SubjectID: f.math})
+		wantInvalidReference(e.t, err, "employees_subjects", "employee_id", "employees")
+
+		err = e.r.EmployeesSubjects.Create(e.ctx, domain.EmployeeSubject{EmployeeID: f.ivanov, SubjectID: f.math + 100})
+		wantInvalidReference(e.t, err, "employees_subjects", "subject_id", "subjects")
+	})
+
+	run(t, open, "FindAll", func(e *env) {
+		assignments, err := e.r.EmployeesSubjects.FindAll(e.ctx)
+		e.must(err)
+		equal(e.t, len(assignments), 0)
+
+		f := e.faculty()
+		e.assign(f.ivanov, f.math)
+		e.assign(f.petrova, f.physics)
+		assignments, err = e.r.EmployeesSubjects.FindAll(e.ctx)
+		e.must(err)
+		sameElements(e.t, assignments, []domain.EmployeeSubject{
+			{EmployeeID: f.ivanov, SubjectID: f.math},
+			{EmployeeID: f.petrova, SubjectID: f.physics},
+		})
+	})
+
+	run(t, open, "List", func(e *env) {
+		f := e.faculty()
+		e.assign(f.petrova, f.physics)
+		e.assign(f.ivanov, f.physics)
+		e.assign(f.ivanov, f.math)
+
+		// ordered by employee, then subject
+		page, err := e.r.EmployeesSubjects.List(e.ctx, repository.ListOptions{})
+		e.must(err)
+		equalSlices(e.t, page.Items, []domain.EmployeeSubject{
+			{EmployeeID: f.ivanov, SubjectID: f.math},
+			{EmployeeID: f.ivanov, SubjectID: f.physics},
+			{EmployeeID: f.petrova, SubjectID: f.physics},
+		})
+
+		page, err = e.r.EmployeesSubjects.List(e.ctx, repository.ListOptions{
+			Filters: []repository.Filter{{Field: "subject_id", Op: repository.OpEq, Value: idText(f.physics)}},
+			Limit:   1,
+		})
+		e.must(err)
+		equal(e.t, page.Total, 2)
+		equalSlices(e.t, page.Items, []domain.EmployeeSubject{{EmployeeID: f.ivanov, SubjectID: f.physics}})
+
+		// the table has no id to page by
+		_, err = e.r.EmployeesSubjects.List(e.ctx, repository.ListOptions{AfterID: 1})
+		wantError(e.t, err, repository.ErrInvalidOption)
+	})
+
+	run(t, open, "FindByEmployeeID", func(e *env) {
+		f := e.faculty()
+		e.assign(f.ivanov, f.math)
+		e.assign(f.ivanov, f.physics)
+		e.assign(f.petrova, f.physics)
+
+		assignments, err := e.r.EmployeesSubjects.FindByEmployeeID(e.ctx, f.ivanov)
+		e.must(err)
+		sameElements(e.t, assignments, []domain.EmployeeSubject{
+			{EmployeeID: f.ivanov, SubjectID: f.math},
+			{EmployeeID: f.ivanov, SubjectID: f.physics},
+		})
+	})
+
+	run(t, open, "FindBySubjectID", func(e *env) {
+		f := e.faculty()
+		e.assign(f.ivanov, f.math)
+		e.assign(f.ivanov, f.physics)
+		e.assign(f.petrova, f.physics)
+
+		assignments, err := e.r.EmployeesSubjects.FindBySubjectID(e.ctx, f.physics)
+		e.must(err)
+		sameElements(e.t, assignments, []domain.EmployeeSubject{
+			{EmployeeID: f.ivanov, SubjectID: f.physics},
+			{EmployeeID: f.petrova, SubjectID: f.physics},
+		})
+	})
+
+	run(t, open, "Update", func(e *env) {
+		f := e.faculty()
+		e.assign(f.ivanov, f.math)
+		e.assign(f.petrova, f.physics)
+
+		// the key itself is replaced
+		e.must(e.r.EmployeesSubjects.Update(e.ctx, f.ivanov, f.math, domain.EmployeeSubject{EmployeeID: f.ivanov, SubjectID: f.physics}))
+		assignments, err := e.r.EmployeesSubjects.FindByEmployeeID(e.ctx, f.ivanov)
+		e.must(err)
+		equalSlices(e.t, assignments, []domain.EmployeeSubject{{EmployeeID: f.ivanov, SubjectID: f.physics}})
+
+		// keeping the key is not a conflict with itself
+		e.must(e.r.EmployeesSubjects.Update(e.ctx, f.ivanov, f.physics, domain.EmployeeSubject{EmployeeID: f.ivanov, SubjectID: f.physics}))
+
+		err = e.r.EmployeesSubjects.Update(e.ctx, f.ivanov, f.physics, domain.EmployeeSubject{EmployeeID: f.petrova, SubjectID: f.physics})
+		wantDuplicate(e.t, err, "employees_subjects", "employee_id, subject_id", idText(f.petrova)+", "+idText(f.physics))
+
+		err = e.r.EmployeesSubjects.Update(e.ctx, f.ivanov, f.physics, domain.EmployeeSubject{EmployeeID: f.ivanov, SubjectID: f.physics + 100})
+		wantInvalidReference(e.t, err, "employees_subjects", "subject_id", "subjects")
+
+		err = e.r.EmployeesSubjects.Update(e.ctx, f.ivanov, f.math, domain.EmployeeSubject{EmployeeID: f.ivanov, SubjectID: f.math})
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+
+	run(t, open, "Delete", func(e *env) {
+		f := e.faculty()
+		e.assign(f.ivanov, f.math)
+		e.assign(f.ivanov, f.physics)
+
+		e.must(e.r.EmployeesSubjects.Delete(e.ctx, f.ivanov, f.math))
+		assignments, err := e.r.EmployeesSubjects.FindAll(e.ctx)
+		e.must(err)
+		equalSlices(e.t, assignments, []domain.EmployeeSubject{{EmployeeID: f.ivanov, SubjectID: f.physics}})
+
+		err = e.r.EmployeesSubjects.Delete(e.ctx, f.ivanov, f.math)
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+
+	run(t, open, "Taught", func(e *env) {
+		f := e.faculty()
+		e.assign(f.ivanov, f.math)
+		e.lesson(domain.Lesson{GroupID: f.group1, SubjectID: f.math, LessonTypeID: f.lecture, Week: 1, Weekday: 1, Pair: 1, Room: 101, EmployeeID: &f.ivanov})
+
+		// lessons given by the teacher keep the assignment in place
+		err := e.r.EmployeesSubjects.Delete(e.ctx, f.ivanov, f.math)
+		wantReferenced(e.t, err, "employees_subjects", "lessons")
+
+		err = e.r.EmployeesSubjects.Update(e.ctx, f.ivanov, f.math, domain.EmployeeSubject{EmployeeID: f.petrova, SubjectID: f.math})
+		wantReferenced(e.t, err, "employees_subjects", "lessons")
+
+		e.must(e.r.EmployeesSubjects.Update(e.ctx, f.ivanov, f.math, domain.EmployeeSubject{EmployeeID: f.ivanov, SubjectID: f.math}))
+	})
+}
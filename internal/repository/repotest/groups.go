@@ -0,0 +1,146 @@
+package repotest
+
+import (
+	"testing"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+)
+
+func testGroups(t *testing.T, open Open) {
+	run(t, open, "Create", func(e *env) {
+		id := e.group(101)
+
+		grp, err := e.r.Groups.FindOne(e.ctx, id)
+		e.must(err)
+		equal(e.t, grp, domain.Group{ID: id, Number: 101})
+
+		_, err = e.r.Groups.Create(e.ctx, domain.Group{Number: 101})
+		wantDuplicate(e.t, err, "groups", "number", "101")
+
+		_, err = e.r.Groups.Create(e.ctx, domain.Group{Number: 0})
+		wantCheckViolation(e.t, err, "groups", "groups_number_check")
+	})
+
+	run(t, open, "FindOne", func(e *env) {
+		id := e.group(101)
+
+		_, err := e.r.Groups.FindOne(e.ctx, id+100)
+		wantError(e.t, err, repository.ErrNotFound)
+
+		e.must(e.r.Groups.Delete(e.ctx, id))
+		_, err = e.r.Groups.FindOne(e.ctx, id)
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+
+	run(t, open, "FindAll", func(e *env) {
+		groups, err := e.r.Groups.FindAll(e.ctx)
+		e.must(err)
+		equal(e.t, len(groups), 0)
+
+		a := e.group(101)
+		b := e.group(102)
+		e.must(e.r.Groups.Delete(e.ctx, a))
+
+		groups, err = e.r.Groups.FindAll(e.ctx)
+		e.must(err)
+		sameElements(e.t, groups, []domain.Group{{ID: b, Number: 102}})
+	})
+
+	run(t, open, "List", func(e *env) {
+		a := e.group(101)
+		b := e.group(102)
+		c := e.group(103)
+		e.must(e.r.Groups.Delete(e.ctx, b))
+
+		page, err := e.r.Groups.List(e.ctx, repository.ListOptions{})
+		e.must(err)
+		equalSlices(e.t, ids(page.Items, groupID), []uint64{a, c})
+
+		page, err = e.r.Groups.List(e.ctx, repository.ListOptions{Deleted: repository.IncludeDeleted})
+		e.must(err)
+		equalSlices(e.t, ids(page.Items, groupID), []uint64{a, b, c})
+
+		page, err = e.r.Groups.List(e.ctx, repository.ListOptions{
+			Filters: []repository.Filter{{Field: "number", Op: repository.OpGte, Value: "102"}},
+		})
+		e.must(err)
+		equalSlices(e.t, ids(page.Items, groupID), []uint64{c})
+	})
+
+	run(t, open, "FindByNumber", func(e *env) {
+		id := e.group(101)
+
+		grp, err := e.r.Groups.FindByNumber(e.ctx, 101)
+		e.must(err)
+		equal(e.t, grp.ID, id)
+
+		_, err = e.r.Groups.FindByNumber(e.ctx, 999)
+		wantError(e.t, err, repository.ErrNotFound)
+
+		e.must(e.r.Groups.Delete(e.ctx, id))
+		_, err = e.r.Groups.FindByNumber(e.ctx, 101)
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+
+	run(t, open, "Update", func(e *env) {
+		id := e.group(101)
+		e.group(102)
+
+		e.must(e.r.Groups.Update(e.ctx, id, domain.Group{Number: 201}))
+		grp, err := e.r.Groups.FindOne(e.ctx, id)
+		e.must(err)
+		equal(e.t, grp.Number, 201)
+
+		err = e.r.Groups.Update(e.ctx, id, domain.Group{Number: 102})
+		wantDuplicate(e.t, err, "groups", "number", "102")
+
+		err = e.r.Groups.Update(e.ctx, id, domain.Group{Number: 0})
+		wantCheckViolation(e.t, err, "groups", "groups_number_check")
+
+		err = e.r.Groups.Update(e.ctx, id+100, domain.Group{Number: 301})
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+
+	run(t, open, "Delete", func(e *env) {
+		f := e.faculty()
+
+		e.must(e.r.Groups.Delete(e.ctx, f.group1))
+		err := e.r.Groups.Delete(e.ctx, f.group1)
+		wantError(e.t, err, repository.ErrNotFound)
+
+		err = e.r.Groups.Delete(e.ctx, f.group1+100)
+		wantError(e.t, err, repository.ErrNotFound)
+
+		// the number is given up while the group is in the trash
+		_, err = e.r.Groups.Create(e.ctx, domain.Group{Number: 101})
+		e.must(err)
+
+		// students of a trashed group can still be found
+		stud, err := e.r.Students.FindOne(e.ctx, f.anna)
+		e.must(err)
+		equal(e.t, stud.GroupID, f.group1)
+	})
+
+	run(t, open, "Restore", func(e *env) {
+		id := e.group(101)
+
+		err := e.r.Groups.Restore(e.ctx, id)
+		wantError(e.t, err, repository.ErrNotFound)
+
+		e.must(e.r.Groups.Delete(e.ctx, id))
+		e.must(e.r.Groups.Restore(e.ctx, id))
+		_, err = e.r.Groups.FindOne(e.ctx, id)
+		e.must(err)
+
+		err = e.r.Groups.Restore(e.ctx, id+100)
+		wantError(e.t, err, repository.ErrNotFound)
+
+		// an active group took the number in the meantime
+		e.must(e.r.Groups.Delete(e.ctx, id))
+		e.group(101)
+		err = e.r.Groups.Restore(e.ctx, id)
+		wantDuplicate(e.t, err, "groups", "number", "101")
+	})
+}
+
+func groupID(grp domain.Group) uint64 { return grp.ID }
@@ -0,0 +1,93 @@
+package repotest
+
+import (
+	"testing"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+)
+
+func testLessonTypes(t *testing.T, open Open) {
+	run(t, open, "Create", func(e *env) {
+		id := e.lessonType("LK")
+
+		lsn, err := e.r.LessonTypes.FindOne(e.ctx, id)
+		e.must(err)
+		equal(e.t, lsn, domain.LessonType{ID: id, Name: "LK"})
+
+		_, err = e.r.LessonTypes.Create(e.ctx, domain.LessonType{Name: "LK"})
+		wantDuplicate(e.t, err, "lesson_types", "name", "LK")
+	})
+
+	run(t, open, "FindOne", func(e *env) {
+		id := e.lessonType("LK")
+
+		_, err := e.r.LessonTypes.FindOne(e.ctx, id+100)
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+
+	run(t, open, "FindAll", func(e *env) {
+		lessonTypes, err := e.r.LessonTypes.FindAll(e.ctx)
+		e.must(err)
+		equal(e.t, len(lessonTypes), 0)
+
+		a := e.lessonType("LK")
+		b := e.lessonType("PZ")
+		lessonTypes, err = e.r.LessonTypes.FindAll(e.ctx)
+		e.must(err)
+		sameElements(e.t, lessonTypes, []domain.LessonType{{ID: a, Name: "LK"}, {ID: b, Name: "PZ"}})
+	})
+
+	run(t, open, "List", func(e *env) {
+		e.lessonType("LK")
+		b := e.lessonType("PZ")
+
+		page, err := e.r.LessonTypes.List(e.ctx, repository.ListOptions{
+			Filters: []repository.Filter{{Field: "name", Op: repository.OpContains, Value: "z"}},
+		})
+		e.must(err)
+		equal(e.t, page.Total, 1)
+		equalSlices(e.t, page.Items, []domain.LessonType{{ID: b, Name: "PZ"}})
+	})
+
+	run(t, open, "FindByName", func(e *env) {
+		id := e.lessonType("LK")
+
+		lsn, err := e.r.LessonTypes.FindByName(e.ctx, "LK")
+		e.must(err)
+		equal(e.t, lsn.ID, id)
+
+		_, err = e.r.LessonTypes.FindByName(e.ctx, "LR")
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+
+	run(t, open, "Update", func(e *env) {
+		id := e.lessonType("LK")
+		e.lessonType("PZ")
+
+		e.must(e.r.LessonTypes.Update(e.ctx, id, domain.LessonType{Name: "LR"}))
+		lsn, err := e.r.LessonTypes.FindOne(e.ctx, id)
+		e.must(err)
+		equal(e.t, lsn.Name, "LR")
+
+		err = e.r.LessonTypes.Update(e.ctx, id, domain.LessonType{Name: "PZ"})
+		wantDuplicate(e.t, err, "lesson_types", "name", "PZ")
+
+		err = e.r.LessonTypes.Update(e.ctx, id+100, domain.LessonType{Name: "SR"})
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+
+	run(t, open, "Delete", func(e *env) {
+		f := e.faculty()
+		e.lesson(domain.Lesson{GroupID: f.group1, SubjectID: f.math, LessonTypeID: f.lecture, Week: 1, Weekday: 1, Pair: 1, Room: 101})
+
+		err := e.r.LessonTypes.Delete(e.ctx, f.lecture)
+		wantReferenced(e.t, err, "lesson_types", "lessons")
+
+		e.must(e.r.LessonTypes.Delete(e.ctx, f.practice))
+		_, err = e.r.LessonTypes.FindOne(e.ctx, f.practice)
+		wantError(e.t, err, repository.ErrNotFound)
+
+		err = e.r.LessonTypes.Delete(e.ctx, f.practice)
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+}
@@ -0,0 +1,302 @@
+package repotest
+
+import (
+	"testing"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/dto"
+	"university-db-admin/internal/repository"
+)
+
+// creates three lessons without teachers: math lecture and physics
+// practice of group1, math lecture of group2 on the second week
+func (e *env) timetable(f faculty) (a, b, c uint64) {
+	e.t.Helper()
+	a = e.lesson(domain.Lesson{GroupID: f.group1, SubjectID: f.math, LessonTypeID: f.lecture, Week: 1, Weekday: 1, Pair: 1, Room: 101})
+	b = e.lesson(domain.Lesson{GroupID: f.group1, SubjectID: f.physics, LessonTypeID: f.practice, Week: 1, Weekday: 3, Pair: 2, Room: 214})
+	c = e.lesson(domain.Lesson{GroupID: f.group2, SubjectID: f.math, LessonTypeID: f.lecture, Week: 2, Weekday: 3, Pair: 1, Room: 101})
+	return a, b, c
+}
+
+func testLessons(t *testing.T, open Open) {
+	run(t, open, "Create", func(e *env) {
+		f := e.faculty()
+		valid := domain.Lesson{GroupID: f.group1, SubjectID: f.math, LessonTypeID: f.lecture, Week: 1, Weekday: 7, Pair: 3, Room: 101}
+		id := e.lesson(valid)
+
+		lsn, err := e.r.Lessons.FindOne(e.ctx, id)
+		e.must(err)
+		valid.ID = id
+		equal(e.t, lsn, valid)
+
+		broken := valid
+		broken.Week = 0
+		_, err = e.r.Lessons.Create(e.ctx, broken)
+		wantCheckViolation(e.t, err, "lessons", "lessons_week_check")
+
+		broken = valid
+		broken.Weekday = 8
+		_, err = e.r.Lessons.Create(e.ctx, broken)
+		wantCheckViolation(e.t, err, "lessons", "lessons_weekday_check")
+
+		broken = valid
+		broken.Pair = 0
+		_, err = e.r.Lessons.Create(e.ctx, broken)
+		wantCheckViolation(e.t, err, "lessons", "lessons_pair_check")
+
+		broken = valid
+		broken.Room = 0
+		_, err = e.r.Lessons.Create(e.ctx, broken)
+		wantCheckViolation(e.t, err, "lessons", "lessons_room_check")
+
+		// the pair of valid is taken now
+		free := valid
+		free.Pair = 4
+
+		broken = free
+		broken.GroupID += 100
+		_, err = e.r.Lessons.Create(e.ctx, broken)
+		wantInvalidReference(e.t, err, "lessons", "group_id", "groups")
+
+		broken = free
+		broken.SubjectID += 100
+		_, err = e.r.Lessons.Create(e.ctx, broken)
+		wantInvalidReference(e.t, err, "lessons", "subject_id", "subjects")
+
+		broken = free
+		broken.LessonTypeID += 100
+		_, err = e.r.Lessons.Create(e.ctx, broken)
+		wantInvalidReference(e.t, err, "lessons", "lesson_type_id", "lesson_types")
+
+		// trashed groups can still be scheduled
+		e.must(e.r.Groups.Delete(e.ctx, f.group2))
+		free.GroupID = f.group2
+		e.lesson(free)
+	})
+
+	run(t, open, "Slots", func(e *env) {
+		f := e.faculty()
+		e.assign(f.ivanov, f.math)
+		e.assign(f.ivanov, f.physics)
+		lsn := domain.Lesson{GroupID: f.group1, SubjectID: f.math, LessonTypeID: f.lecture, Week: 1, Weekday: 2, Pair: 1, Room: 101, EmployeeID: &f.ivanov}
+		id := e.lesson(lsn)
+
+		// a group, a teacher and a room take one lesson per pair
+		clash := lsn
+		clash.Room = 102
+		clash.EmployeeID = nil
+		_, err := e.r.Lessons.Create(e.ctx, clash)
+		wantDuplicate(e.t, err, "lessons", "group_id, week, weekday, pair", idText(f.group1)+", 1, 2, 1")
+
+		clash = lsn
+		clash.GroupID, clash.SubjectID, clash.Room = f.group2, f.physics, 102
+		_, err = e.r.Lessons.Create(e.ctx, clash)
+		wantDuplicate(e.t, err, "lessons", "employee_id, week, weekday, pair", idText(f.ivanov)+", 1, 2, 1")
+
+		clash = lsn
+		clash.GroupID, clash.EmployeeID = f.group2, nil
+		_, err = e.r.Lessons.Create(e.ctx, clash)
+		wantDuplicate(e.t, err, "lessons", "room, week, weekday, pair", "101, 1, 2, 1")
+
+		// lessons without a teacher only take their group and room
+		free := clash
+		free.Room = 102
+		other := e.lesson(free)
+		free.Room, free.Pair = 101, 2
+		e.lesson(free)
+
+		// a lesson keeps its own slot on update, but can't take another one
+		lsn.Room = 103
+		e.must(e.r.Lessons.Update(e.ctx, id, lsn))
+		free.Room, free.Pair = 103, 1
+		err = e.r.Lessons.Update(e.ctx, other, free)
+		wantDuplicate(e.t, err, "lessons", "room, week, weekday, pair", "103, 1, 2, 1")
+	})
+
+	run(t, open, "Teacher", func(e *env) {
+		f := e.faculty()
+		e.assign(f.ivanov, f.math)
+		lsn := domain.Lesson{GroupID: f.group1, SubjectID: f.math, LessonTypeID: f.lecture, Week: 1, Weekday: 2, Pair: 1, Room: 101, EmployeeID: &f.ivanov}
+		id := e.lesson(lsn)
+
+		got, err := e.r.Lessons.FindOne(e.ctx, id)
+		e.must(err)
+		if got.EmployeeID == nil {
+			e.t.Fatal("lesson lost its teacher")
+		}
+		equal(e.t, *got.EmployeeID, f.ivanov)
+
+		// the teacher must be linked to the subject of the lesson
+		broken := lsn
+		broken.Pair = 2
+		broken.EmployeeID = &f.petrova
+		_, err = e.r.Lessons.Create(e.ctx, broken)
+		wantInvalidReference(e.t, err, "lessons", "employee_id, subject_id", "employees_subjects")
+
+		broken = lsn
+		broken.SubjectID = f.physics
+		err = e.r.Lessons.Update(e.ctx, id, broken)
+		wantInvalidReference(e.t, err, "lessons", "employee_id, subject_id", "employees_subjects")
+
+		page, err := e.r.Lessons.List(e.ctx, repository.ListOptions{
+			Filters: []repository.Filter{{Field: "employee_id", Op: repository.OpEq, Value: idText(f.ivanov)}},
+		})
+		e.must(err)
+		equalSlices(e.t, ids(page.Items, lessonID), []uint64{id})
+
+		// the lesson can be left without a teacher
+		lsn.EmployeeID = nil
+		e.must(e.r.Lessons.Update(e.ctx, id, lsn))
+		got, err = e.r.Lessons.FindOne(e.ctx, id)
+		e.must(err)
+		if got.EmployeeID != nil {
+			e.t.Fatalf("lesson kept teacher %d", *got.EmployeeID)
+		}
+	})
+
+	run(t, open, "FindOne", func(e *env) {
+		f := e.faculty()
+		a, _, _ := e.timetable(f)
+
+		_, err := e.r.Lessons.FindOne(e.ctx, a+100)
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+
+	run(t, open, "FindAll", func(e *env) {
+		lessons, err := e.r.Lessons.FindAll(e.ctx)
+		e.must(err)
+		equal(e.t, len(lessons), 0)
+
+		a, b, c := e.timetable(e.faculty())
+		lessons, err = e.r.Lessons.FindAll(e.ctx)
+		e.must(err)
+		sameElements(e.t, ids(lessons, lessonID), []uint64{a, b, c})
+	})
+
+	run(t, open, "List", func(e *env) {
+		a, b, c := e.timetable(e.faculty())
+
+		page, err := e.r.Lessons.List(e.ctx, repository.ListOptions{
+			Sort: []repository.Sort{{Field: "room", Desc: true}, {Field: "week"}},
+		})
+		e.must(err)
+		equalSlices(e.t, ids(page.Items, lessonID), []uint64{b, a, c})
+	})
+
+	run(t, open, "FindByGroupID", func(e *env) {
+		f := e.faculty()
+		a, b, _ := e.timetable(f)
+
+		lessons, err := e.r.Lessons.FindByGroupID(e.ctx, f.group1)
+		e.must(err)
+		sameElements(e.t, ids(lessons, lessonID), []uint64{a, b})
+	})
+
+	run(t, open, "FindBySubjectID", func(e *env) {
+		f := e.faculty()
+		a, _, c := e.timetable(f)
+
+		lessons, err := e.r.Lessons.FindBySubjectID(e.ctx, f.math)
+		e.must(err)
+		sameElements(e.t, ids(lessons, lessonID), []uint64{a, c})
+	})
+
+	run(t, open, "FindByLessonTypeID", func(e *env) {
+		f := e.faculty()
+		_, b, _ := e.timetable(f)
+
+		lessons, err := e.r.Lessons.FindByLessonTypeID(e.ctx, f.practice)
+		e.must(err)
+		sameElements(e.t, ids(lessons, lessonID), []uint64{b})
+	})
+
+	run(t, open, "FindByWeek", func(e *env) {
+		_, _, c := e.timetable(e.faculty())
+
+		lessons, err := e.r.Lessons.FindByWeek(e.ctx, 2)
+		e.must(err)
+		sameElements(e.t, ids(lessons, lessonID), []uint64{c})
+
+		lessons, err = e.r.Lessons.FindByWeek(e.ctx, 3)
+		e.must(err)
+		equal(e.t, len(lessons), 0)
+	})
+
+	run(t, open, "FindByWeekday", func(e *env) {
+		_, b, c := e.timetable(e.faculty())
+
+		lessons, err := e.r.Lessons.FindByWeekday(e.ctx, 3)
+		e.must(err)
+		sameElements(e.t, ids(lessons, lessonID), []uint64{b, c})
+	})
+
+	run(t, open, "FindByRoom", func(e *env) {
+		a, _, c := e.timetable(e.faculty())
+
+		lessons, err := e.r.Lessons.FindByRoom(e.ctx, 101)
+		e.must(err)
+		sameElements(e.t, ids(lessons, lessonID), []uint64{a, c})
+	})
+
+	run(t, open, "FindSchedule", func(e *env) {
+		f := e.faculty()
+		e.timetable(f)
+
+		// lessons of trashed groups are left out
+		e.must(e.r.Groups.Delete(e.ctx, f.group2))
+		result, err := e.r.Lessons.FindSchedule(e.ctx)
+		e.must(err)
+		sameElements(e.t, result, []dto.LessonScheduleDTO{
+			{GroupNumber: 101, Subject: "Mathematics", LessonType: "LK", Room: 101, Week: 1, Weekday: 1, Pair: 1},
+			{GroupNumber: 101, Subject: "Physics", LessonType: "PZ", Room: 214, Week: 1, Weekday: 3, Pair: 2},
+		})
+
+		// lessons are ordered by time and name their teacher
+		e.assign(f.ivanov, f.math)
+		e.lesson(domain.Lesson{GroupID: f.group1, SubjectID: f.math, LessonTypeID: f.practice, Week: 1, Weekday: 1, Pair: 2, Room: 305, EmployeeID: &f.ivanov})
+		result, err = e.r.Lessons.FindSchedule(e.ctx)
+		e.must(err)
+		equalSlices(e.t, result, []dto.LessonScheduleDTO{
+			{GroupNumber: 101, Subject: "Mathematics", LessonType: "LK", Room: 101, Week: 1, Weekday: 1, Pair: 1},
+			{GroupNumber: 101, Subject: "Mathematics", LessonType: "PZ", Teacher: "Ivanov Ivan Petrovich", Room: 305, Week: 1, Weekday: 1, Pair: 2},
+			{GroupNumber: 101, Subject: "Physics", LessonType: "PZ", Room: 214, Week: 1, Weekday: 3, Pair: 2},
+		})
+	})
+
+	run(t, open, "Update", func(e *env) {
+		f := e.faculty()
+		a, _, _ := e.timetable(f)
+
+		updated := domain.Lesson{GroupID: f.group2, SubjectID: f.physics, LessonTypeID: f.practice, Week: 4, Weekday: 6, Pair: 5, Room: 305}
+		e.must(e.r.Lessons.Update(e.ctx, a, updated))
+		lsn, err := e.r.Lessons.FindOne(e.ctx, a)
+		e.must(err)
+		updated.ID = a
+		equal(e.t, lsn, updated)
+
+		broken := updated
+		broken.Weekday = 0
+		err = e.r.Lessons.Update(e.ctx, a, broken)
+		wantCheckViolation(e.t, err, "lessons", "lessons_weekday_check")
+
+		broken = updated
+		broken.SubjectID += 100
+		err = e.r.Lessons.Update(e.ctx, a, broken)
+		wantInvalidReference(e.t, err, "lessons", "subject_id", "subjects")
+
+		err = e.r.Lessons.Update(e.ctx, a+100, updated)
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+
+	run(t, open, "Delete", func(e *env) {
+		a, _, _ := e.timetable(e.faculty())
+
+		e.must(e.r.Lessons.Delete(e.ctx, a))
+		_, err := e.r.Lessons.FindOne(e.ctx, a)
+		wantError(e.t, err, repository.ErrNotFound)
+
+		err = e.r.Lessons.Delete(e.ctx, a)
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+}
+
+func lessonID(lsn domain.Lesson) uint64 { return lsn.ID }
@@ -0,0 +1,142 @@
+package repotest
+
+import (
+	"testing"
+	"university-db-admin/internal/repository"
+)
+
+// testList checks the list options shared by every List method on students
+func testList(t *testing.T, open Open) {
+	// adds students to the faculty: carl and dora in group2, eva in group1
+	roster := func(e *env) (f faculty, all []uint64) {
+		e.t.Helper()
+		f = e.faculty()
+		carl := e.student("Carl Novak", passport(1), f.ivanov, f.group2)
+		dora := e.student("Dora Novakova", passport(2), f.petrova, f.group2)
+		eva := e.student("Eva Lind", passport(3), f.ivanov, f.group1)
+		return f, []uint64{f.anna, f.boris, carl, dora, eva}
+	}
+
+	run(t, open, "Default", func(e *env) {
+		_, all := roster(e)
+
+		page, err := e.r.Students.List(e.ctx, repository.ListOptions{})
+		e.must(err)
+		equalSlices(e.t, ids(page.Items, studentID), all)
+		equal(e.t, page.Total, 5)
+		equal(e.t, page.NextCursor, 0)
+	})
+
+	run(t, open, "Filters", func(e *env) {
+		f, all := roster(e)
+		carl, dora := all[2], all[3]
+
+		page, err := e.r.Students.List(e.ctx, repository.ListOptions{
+			Filters: []repository.Filter{{Field: "group_id", Op: repository.OpEq, Value: idText(f.group2)}},
+		})
+		e.must(err)
+		equalSlices(e.t, ids(page.Items, studentID), []uint64{carl, dora})
+
+		// contains ignores case, filters are combined with AND
+		page, err = e.r.Students.List(e.ctx, repository.ListOptions{
+			Filters: []repository.Filter{
+				{Field: "name", Op: repository.OpContains, Value: "NOVAK"},
+				{Field: "employee_id", Op: repository.OpNe, Value: idText(f.ivanov)},
+			},
+		})
+		e.must(err)
+		equalSlices(e.t, ids(page.Items, studentID), []uint64{dora})
+
+		page, err = e.r.Students.List(e.ctx, repository.ListOptions{
+			Filters: []repository.Filter{{Field: "id", Op: repository.OpGt, Value: idText(carl)}},
+		})
+		e.must(err)
+		equalSlices(e.t, ids(page.Items, studentID), all[3:])
+
+		page, err = e.r.Students.List(e.ctx, repository.ListOptions{
+			Filters: []repository.Filter{{Field: "passport", Op: repository.OpLte, Value: passport(2)}},
+		})
+		e.must(err)
+		equalSlices(e.t, ids(page.Items, studentID), []uint64{carl, dora})
+	})
+
+	run(t, open, "Sort", func(e *env) {
+		_, all := roster(e)
+		anna, boris, carl, dora, eva := all[0], all[1], all[2], all[3], all[4]
+
+		page, err := e.r.Students.List(e.ctx, repository.ListOptions{
+			Sort: []repository.Sort{{Field: "name", Desc: true}},
+		})
+		e.must(err)
+		equalSlices(e.t, ids(page.Items, studentID), []uint64{eva, dora, carl, boris, anna})
+
+		// ties are broken by the following fields
+		page, err = e.r.Students.List(e.ctx, repository.ListOptions{
+			Sort: []repository.Sort{{Field: "group_id", Desc: true}, {Field: "passport"}},
+		})
+		e.must(err)
+		equalSlices(e.t, ids(page.Items, studentID), []uint64{carl, dora, eva, anna, boris})
+	})
+
+	run(t, open, "Offset", func(e *env) {
+		_, all := roster(e)
+
+		page, err := e.r.Students.List(e.ctx, repository.ListOptions{Limit: 2, Offset: 1})
+		e.must(err)
+		equalSlices(e.t, ids(page.Items, studentID), all[1:3])
+		equal(e.t, page.Total, 5)
+
+		page, err = e.r.Students.List(e.ctx, repository.ListOptions{Offset: 10})
+		e.must(err)
+		equal(e.t, len(page.Items), 0)
+		equal(e.t, page.Total, 5)
+	})
+
+	run(t, open, "Cursor", func(e *env) {
+		_, all := roster(e)
+
+		var got []uint64
+		opts := repository.ListOptions{Limit: 2}
+		for pages := 0; ; pages++ {
+			if pages == len(all) {
+				e.t.Fatal("paging doesn't stop")
+			}
+			page, err := e.r.Students.List(e.ctx, opts)
+			e.must(err)
+			// the total ignores the cursor
+			equal(e.t, page.Total, 5)
+			got = append(got, ids(page.Items, studentID)...)
+			if page.NextCursor == 0 {
+				break
+			}
+			opts.AfterID = page.NextCursor
+		}
+		equalSlices(e.t, got, all)
+
+		// no cursor when the page ends exactly at the last row
+		page, err := e.r.Students.List(e.ctx, repository.ListOptions{Limit: 5})
+		e.must(err)
+		equal(e.t, page.NextCursor, 0)
+	})
+
+	run(t, open, "Invalid", func(e *env) {
+		roster(e)
+
+		invalid := map[string]repository.ListOptions{
+			"unknown filter field": {Filters: []repository.Filter{{Field: "curator", Op: repository.OpEq, Value: "1"}}},
+			"unknown operator":     {Filters: []repository.Filter{{Field: "name", Op: "like", Value: "A%"}}},
+			"malformed value":      {Filters: []repository.Filter{{Field: "group_id", Op: repository.OpEq, Value: "first"}}},
+			"unknown sort field":   {Sort: []repository.Sort{{Field: "age"}}},
+			"unknown deleted":      {Deleted: "all"},
+			"cursor with sort":     {AfterID: 1, Sort: []repository.Sort{{Field: "name"}}},
+			"cursor with offset":   {AfterID: 1, Offset: 1},
+		}
+		for name, opts := range invalid {
+			_, err := e.r.Students.List(e.ctx, opts)
+			if err == nil {
+				e.t.Fatalf("%s: got no error", name)
+			}
+			wantError(e.t, err, repository.ErrInvalidOption)
+		}
+	})
+}
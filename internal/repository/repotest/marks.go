@@ -0,0 +1,333 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/dto"
+	"university-db-admin/internal/repository"
+)
+
+// lets ivanov grade math and physics and petrova physics, group1 gets
+// lessons in both subjects and group2 in math only, see timetable
+func (e *env) curriculum(f faculty) {
+	e.t.Helper()
+	e.assign(f.ivanov, f.math)
+	e.assign(f.ivanov, f.physics)
+	e.assign(f.petrova, f.physics)
+	e.timetable(f)
+}
+
+// creates three marks: anna's 9 in math on the 2nd, boris' 5 in math
+// on the 2nd and anna's 7 in physics on the 5th, all given by ivanov
+func (e *env) gradebook(f faculty) (a, b, c uint64) {
+	e.t.Helper()
+	e.curriculum(f)
+	a = e.mark(domain.Mark{EmployeeID: f.ivanov, StudentID: f.anna, SubjectID: f.math, Mark: 9, Date: day(2)})
+	b = e.mark(domain.Mark{EmployeeID: f.ivanov, StudentID: f.boris, SubjectID: f.math, Mark: 5, Date: day(2)})
+	c = e.mark(domain.Mark{EmployeeID: f.ivanov, StudentID: f.anna, SubjectID: f.physics, Mark: 7, Date: day(5)})
+	return a, b, c
+}
+
+func testMarks(t *testing.T, open Open) {
+	run(t, open, "Create", func(e *env) {
+		f := e.faculty()
+		e.curriculum(f)
+		valid := domain.Mark{EmployeeID: f.petrova, StudentID: f.boris, SubjectID: f.physics, Mark: 10, Date: day(3)}
+		id := e.mark(valid)
+
+		mark, err := e.r.Marks.FindOne(e.ctx, id)
+		e.must(err)
+		valid.ID = id
+		equal(e.t, mark, valid)
+
+		broken := valid
+		broken.Mark = 11
+		_, err = e.r.Marks.Create(e.ctx, broken)
+		wantCheckViolation(e.t, err, "marks", "marks_mark_check")
+
+		broken = valid
+		broken.EmployeeID += 100
+		_, err = e.r.Marks.Create(e.ctx, broken)
+		wantInvalidReference(e.t, err, "marks", "employee_id", "employees")
+
+		broken = valid
+		broken.StudentID += 100
+		_, err = e.r.Marks.Create(e.ctx, broken)
+		wantInvalidReference(e.t, err, "marks", "student_id", "students")
+
+		broken = valid
+		broken.SubjectID += 100
+		_, err = e.r.Marks.Create(e.ctx, broken)
+		wantInvalidReference(e.t, err, "marks", "subject_id", "subjects")
+
+		// petrova doesn't teach math
+		broken = valid
+		broken.SubjectID = f.math
+		_, err = e.r.Marks.Create(e.ctx, broken)
+		wantCheckViolation(e.t, err, "marks", "marks_grader_subject_check")
+
+		// group2 has no physics lessons
+		broken = valid
+		broken.StudentID = e.student("Vera Orlova", "MP1000003", f.petrova, f.group2)
+		_, err = e.r.Marks.Create(e.ctx, broken)
+		wantCheckViolation(e.t, err, "marks", "marks_group_subject_check")
+
+		// marks keep pointing at trashed students
+		e.must(e.r.Students.Delete(e.ctx, f.anna))
+		valid.StudentID = f.anna
+		e.mark(valid)
+	})
+
+	run(t, open, "CopyFrom", func(e *env) {
+		f := e.faculty()
+		e.curriculum(f)
+		marks := []domain.Mark{
+			{EmployeeID: f.ivanov, StudentID: f.anna, SubjectID: f.math, Mark: 6, Date: day(1)},
+			{EmployeeID: f.petrova, StudentID: f.boris, SubjectID: f.physics, Mark: 8, Date: day(4)},
+		}
+
+		n, err := e.r.Marks.CopyFrom(e.ctx, marks)
+		e.must(err)
+		equal(e.t, n, 2)
+
+		found, err := e.r.Marks.FindAll(e.ctx)
+		e.must(err)
+		for i := range found {
+			found[i].ID = 0
+		}
+		sameElements(e.t, found, marks)
+
+		// a single broken row rejects the whole batch
+		broken := append(marks, domain.Mark{EmployeeID: f.ivanov, StudentID: f.anna + 100, SubjectID: f.math, Mark: 6, Date: day(1)})
+		_, err = e.r.Marks.CopyFrom(e.ctx, broken)
+		wantInvalidReference(e.t, err, "marks", "student_id", "students")
+
+		broken = append(marks, domain.Mark{EmployeeID: f.petrova, StudentID: f.anna, SubjectID: f.math, Mark: 6, Date: day(1)})
+		_, err = e.r.Marks.CopyFrom(e.ctx, broken)
+		wantCheckViolation(e.t, err, "marks", "marks_grader_subject_check")
+
+		found, err = e.r.Marks.FindAll(e.ctx)
+		e.must(err)
+		equal(e.t, len(found), 2)
+	})
+
+	run(t, open, "FindOne", func(e *env) {
+		a, _, _ := e.gradebook(e.faculty())
+
+		_, err := e.r.Marks.FindOne(e.ctx, a+100)
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+
+	run(t, open, "FindAll", func(e *env) {
+		marks, err := e.r.Marks.FindAll(e.ctx)
+		e.must(err)
+		equal(e.t, len(marks), 0)
+
+		a, b, c := e.gradebook(e.faculty())
+		marks, err = e.r.Marks.FindAll(e.ctx)
+		e.must(err)
+		sameElements(e.t, ids(marks, markID), []uint64{a, b, c})
+	})
+
+	run(t, open, "List", func(e *env) {
+		a, b, c := e.gradebook(e.faculty())
+
+		page, err := e.r.Marks.List(e.ctx, repository.ListOptions{
+			Sort: []repository.Sort{{Field: "date", Desc: true}, {Field: "mark"}},
+		})
+		e.must(err)
+		equalSlices(e.t, ids(page.Items, markID), []uint64{c, b, a})
+
+		page, err = e.r.Marks.List(e.ctx, repository.ListOptions{
+			Filters: []repository.Filter{{Field: "date", Op: repository.OpEq, Value: "2024-09-02"}},
+		})
+		e.must(err)
+		equalSlices(e.t, ids(page.Items, markID), []uint64{a, b})
+
+		_, err = e.r.Marks.List(e.ctx, repository.ListOptions{
+			Filters: []repository.Filter{{Field: "date", Op: repository.OpEq, Value: "yesterday"}},
+		})
+		wantError(e.t, err, repository.ErrInvalidOption)
+
+		// marks are never soft deleted
+		_, err = e.r.Marks.List(e.ctx, repository.ListOptions{Deleted: repository.IncludeDeleted})
+		wantError(e.t, err, repository.ErrInvalidOption)
+	})
+
+	run(t, open, "FindByEmployeeID", func(e *env) {
+		f := e.faculty()
+		a, b, c := e.gradebook(f)
+
+		marks, err := e.r.Marks.FindByEmployeeID(e.ctx, f.ivanov)
+		e.must(err)
+		sameElements(e.t, ids(marks, markID), []uint64{a, b, c})
+
+		marks, err = e.r.Marks.FindByEmployeeID(e.ctx, f.petrova)
+		e.must(err)
+		equal(e.t, len(marks), 0)
+	})
+
+	run(t, open, "FindByStudentID", func(e *env) {
+		f := e.faculty()
+		a, _, c := e.gradebook(f)
+
+		marks, err := e.r.Marks.FindByStudentID(e.ctx, f.anna)
+		e.must(err)
+		sameElements(e.t, ids(marks, markID), []uint64{a, c})
+	})
+
+	run(t, open, "FindBySubjectID", func(e *env) {
+		f := e.faculty()
+		_, _, c := e.gradebook(f)
+
+		marks, err := e.r.Marks.FindBySubjectID(e.ctx, f.physics)
+		e.must(err)
+		sameElements(e.t, ids(marks, markID), []uint64{c})
+	})
+
+	run(t, open, "FindByMark", func(e *env) {
+		_, b, _ := e.gradebook(e.faculty())
+
+		marks, err := e.r.Marks.FindByMark(e.ctx, 5)
+		e.must(err)
+		sameElements(e.t, ids(marks, markID), []uint64{b})
+	})
+
+	run(t, open, "FindByDate", func(e *env) {
+		a, b, _ := e.gradebook(e.faculty())
+
+		marks, err := e.r.Marks.FindByDate(e.ctx, "2024-09-02")
+		e.must(err)
+		sameElements(e.t, ids(marks, markID), []uint64{a, b})
+
+		marks, err = e.r.Marks.FindByDate(e.ctx, "2024-09-03")
+		e.must(err)
+		equal(e.t, len(marks), 0)
+
+		_, err = e.r.Marks.FindByDate(e.ctx, "second of september")
+		if err == nil {
+			e.t.Fatal("got no error for a malformed date")
+		}
+	})
+
+	run(t, open, "FindAllBySubject", func(e *env) {
+		f := e.faculty()
+		e.gradebook(f)
+
+		// only marks strictly above the threshold
+		result, err := e.r.Marks.FindAllBySubject(e.ctx, f.math, 5)
+		e.must(err)
+		sameElements(e.t, result, []dto.MarkBySubjectDTO{{StudentID: f.anna, Mark: 9, Date: day(2)}})
+
+		result, err = e.r.Marks.FindAllBySubject(e.ctx, f.math, 9)
+		e.must(err)
+		equal(e.t, len(result), 0)
+	})
+
+	run(t, open, "FindAllSorted", func(e *env) {
+		f := e.faculty()
+		e.gradebook(f)
+
+		// by date, higher marks first within a day
+		result, err := e.r.Marks.FindAllSorted(e.ctx)
+		e.must(err)
+		equalSlices(e.t, result, []dto.SortedMarkDTO{
+			{StudentID: f.anna, Mark: 9, Date: day(2)},
+			{StudentID: f.boris, Mark: 5, Date: day(2)},
+			{StudentID: f.anna, Mark: 7, Date: day(5)},
+		})
+	})
+
+	run(t, open, "Update", func(e *env) {
+		f := e.faculty()
+		a, _, _ := e.gradebook(f)
+
+		updated := domain.Mark{EmployeeID: f.petrova, StudentID: f.boris, SubjectID: f.physics, Mark: 4, Date: day(9)}
+		e.must(e.r.Marks.Update(e.ctx, a, updated))
+		mark, err := e.r.Marks.FindOne(e.ctx, a)
+		e.must(err)
+		updated.ID = a
+		equal(e.t, mark, updated)
+
+		broken := updated
+		broken.Mark = 0
+		err = e.r.Marks.Update(e.ctx, a, broken)
+		wantCheckViolation(e.t, err, "marks", "marks_mark_check")
+
+		broken = updated
+		broken.EmployeeID += 100
+		err = e.r.Marks.Update(e.ctx, a, broken)
+		wantInvalidReference(e.t, err, "marks", "employee_id", "employees")
+
+		broken = updated
+		broken.SubjectID = f.math
+		err = e.r.Marks.Update(e.ctx, a, broken)
+		wantCheckViolation(e.t, err, "marks", "marks_grader_subject_check")
+
+		err = e.r.Marks.Update(e.ctx, a+100, updated)
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+
+	run(t, open, "UpdateGivenBefore", func(e *env) {
+		f := e.faculty()
+		a, _, _ := e.gradebook(f)
+		e.must(e.r.EmployeesSubjects.Delete(e.ctx, f.ivanov, f.math))
+
+		// a mark given before ivanov stopped teaching math may still be corrected
+		mark, err := e.r.Marks.FindOne(e.ctx, a)
+		e.must(err)
+		mark.Mark, mark.Date = 10, day(12)
+		e.must(e.r.Marks.Update(e.ctx, a, mark))
+
+		// but not moved to another student
+		moved := mark
+		moved.StudentID = f.boris
+		err = e.r.Marks.Update(e.ctx, a, moved)
+		wantCheckViolation(e.t, err, "marks", "marks_grader_subject_check")
+
+		// or to a subject the group of the student has no lessons in
+		moved = mark
+		moved.StudentID = e.student("Vera Orlova", "MP1000003", f.petrova, f.group2)
+		moved.SubjectID = f.physics
+		err = e.r.Marks.Update(e.ctx, a, moved)
+		wantCheckViolation(e.t, err, "marks", "marks_group_subject_check")
+	})
+
+	run(t, open, "WithoutGradingCheck", func(e *env) {
+		f := e.faculty()
+		e.curriculum(f)
+		given := domain.Mark{EmployeeID: f.petrova, StudentID: f.anna, SubjectID: f.math, Mark: 7, Date: day(3)}
+		skip := repository.WithoutGradingCheck(e.ctx)
+
+		// only copies skip the check
+		err := e.r.WithTx(skip, func(ctx context.Context, tx *repository.Repository) error {
+			_, err := tx.Marks.Create(ctx, given)
+			return err
+		})
+		wantCheckViolation(e.t, err, "marks", "marks_grader_subject_check")
+
+		e.must(e.r.WithTx(skip, func(ctx context.Context, tx *repository.Repository) error {
+			_, err := tx.Marks.CopyFrom(ctx, []domain.Mark{given})
+			return err
+		}))
+		marks, err := e.r.Marks.FindAll(e.ctx)
+		e.must(err)
+		if len(marks) != 1 || marks[0].EmployeeID != f.petrova {
+			e.t.Fatalf("got marks %+v, want the copied one", marks)
+		}
+	})
+
+	run(t, open, "Delete", func(e *env) {
+		a, _, _ := e.gradebook(e.faculty())
+
+		e.must(e.r.Marks.Delete(e.ctx, a))
+		_, err := e.r.Marks.FindOne(e.ctx, a)
+		wantError(e.t, err, repository.ErrNotFound)
+
+		err = e.r.Marks.Delete(e.ctx, a)
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+}
+
+func markID(mark domain.Mark) uint64 { return mark.ID }
@@ -0,0 +1,135 @@
+package repotest
+
+import (
+	"testing"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+)
+
+func testPositions(t *testing.T, open Open) {
+	run(t, open, "Create", func(e *env) {
+		first := e.position("Professor")
+		second := e.position("Dean")
+		if second <= first {
+			e.t.Fatalf("ids are not increasing: %d then %d", first, second)
+		}
+
+		_, err := e.r.Positions.Create(e.ctx, domain.Position{Name: "Professor"})
+		wantDuplicate(e.t, err, "positions", "name", "Professor")
+	})
+
+	run(t, open, "FindOne", func(e *env) {
+		id := e.position("Professor")
+
+		pos, err := e.r.Positions.FindOne(e.ctx, id)
+		e.must(err)
+		equal(e.t, pos, domain.Position{ID: id, Name: "Professor"})
+
+		capable := domain.Position{Name: "Docent", CanTeach: true, CanCurate: true, CanAdminister: true}
+		capable.ID = e.capablePosition(capable)
+		pos, err = e.r.Positions.FindOne(e.ctx, capable.ID)
+		e.must(err)
+		equal(e.t, pos, capable)
+
+		_, err = e.r.Positions.FindOne(e.ctx, id+100)
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+
+	run(t, open, "FindAll", func(e *env) {
+		positions, err := e.r.Positions.FindAll(e.ctx)
+		e.must(err)
+		equal(e.t, len(positions), 0)
+
+		a := e.position("Professor")
+		b := e.position("Dean")
+		positions, err = e.r.Positions.FindAll(e.ctx)
+		e.must(err)
+		sameElements(e.t, positions, []domain.Position{{ID: a, Name: "Professor"}, {ID: b, Name: "Dean"}})
+	})
+
+	run(t, open, "List", func(e *env) {
+		a := e.position("Professor")
+		b := e.position("Dean")
+
+		page, err := e.r.Positions.List(e.ctx, repository.ListOptions{})
+		e.must(err)
+		equal(e.t, page.Total, 2)
+		equalSlices(e.t, page.Items, []domain.Position{{ID: a, Name: "Professor"}, {ID: b, Name: "Dean"}})
+
+		c := e.capablePosition(domain.Position{Name: "Docent", CanTeach: true})
+		page, err = e.r.Positions.List(e.ctx, repository.ListOptions{
+			Filters: []repository.Filter{{Field: "can_teach", Op: repository.OpEq, Value: "true"}},
+		})
+		e.must(err)
+		equalSlices(e.t, ids(page.Items, positionID), []uint64{c})
+
+		page, err = e.r.Positions.List(e.ctx, repository.ListOptions{
+			Sort: []repository.Sort{{Field: "can_teach", Desc: true}, {Field: "id"}},
+		})
+		e.must(err)
+		equalSlices(e.t, ids(page.Items, positionID), []uint64{c, a, b})
+
+		_, err = e.r.Positions.List(e.ctx, repository.ListOptions{
+			Filters: []repository.Filter{{Field: "can_teach", Op: repository.OpEq, Value: "maybe"}},
+		})
+		wantError(e.t, err, repository.ErrInvalidOption)
+
+		_, err = e.r.Positions.List(e.ctx, repository.ListOptions{Deleted: repository.OnlyDeleted})
+		wantError(e.t, err, repository.ErrInvalidOption)
+	})
+
+	run(t, open, "FindByName", func(e *env) {
+		id := e.position("Professor")
+
+		pos, err := e.r.Positions.FindByName(e.ctx, "Professor")
+		e.must(err)
+		equal(e.t, pos.ID, id)
+
+		_, err = e.r.Positions.FindByName(e.ctx, "professor")
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+
+	run(t, open, "Update", func(e *env) {
+		id := e.position("Professor")
+		e.position("Dean")
+
+		e.must(e.r.Positions.Update(e.ctx, id, domain.Position{Name: "Lecturer", CanTeach: true}))
+		pos, err := e.r.Positions.FindOne(e.ctx, id)
+		e.must(err)
+		equal(e.t, pos, domain.Position{ID: id, Name: "Lecturer", CanTeach: true})
+
+		// keeping the own name is not a duplicate
+		e.must(e.r.Positions.Update(e.ctx, id, domain.Position{Name: "Lecturer"}))
+
+		err = e.r.Positions.Update(e.ctx, id, domain.Position{Name: "Dean"})
+		wantDuplicate(e.t, err, "positions", "name", "Dean")
+
+		err = e.r.Positions.Update(e.ctx, id+100, domain.Position{Name: "Rector"})
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+
+	run(t, open, "Delete", func(e *env) {
+		id := e.position("Professor")
+		e.must(e.r.Positions.Delete(e.ctx, id))
+
+		_, err := e.r.Positions.FindOne(e.ctx, id)
+		wantError(e.t, err, repository.ErrNotFound)
+
+		err = e.r.Positions.Delete(e.ctx, id)
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+
+	run(t, open, "DeleteReferenced", func(e *env) {
+		f := e.faculty()
+
+		err := e.r.Positions.Delete(e.ctx, f.teacher)
+		wantReferenced(e.t, err, "positions", "employees")
+
+		// employees in the trash still hold their position
+		e.must(e.r.Employees.Delete(e.ctx, f.petrova))
+		err = e.r.Positions.Delete(e.ctx, f.assistant)
+		wantReferenced(e.t, err, "positions", "employees")
+	})
+}
+
+func positionID(pos domain.Position) uint64 { return pos.ID }
@@ -0,0 +1,45 @@
+package repotest
+
+import (
+	"testing"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+)
+
+func testPurge(t *testing.T, open Open) {
+	run(t, open, "Purge", func(e *env) {
+		f := e.faculty()
+		e.must(e.r.EmployeesSubjects.Create(e.ctx, domain.EmployeeSubject{EmployeeID: f.ivanov, SubjectID: f.math}))
+		e.lesson(domain.Lesson{GroupID: f.group1, SubjectID: f.math, LessonTypeID: f.lecture, Week: 1, Weekday: 1, Pair: 1, Room: 101, EmployeeID: &f.ivanov})
+		e.mark(domain.Mark{EmployeeID: f.ivanov, StudentID: f.anna, SubjectID: f.math, Mark: 8, Date: day(2)})
+		e.must(e.r.Groups.Delete(e.ctx, f.group2))
+		_, err := e.r.Users.Create(e.ctx, domain.User{Login: "ivanov", PasswordHash: "hash", Role: domain.RoleTeacher, EmployeeID: &f.ivanov})
+		e.must(err)
+
+		wantReferenced(e.t, e.r.Purger.Purge(e.ctx, "groups"), "groups", "students")
+
+		e.must(e.r.Purger.Purge(e.ctx, "users", "marks", "lessons", "students", "employees", "positions", "groups"))
+		groups, err := e.r.Groups.List(e.ctx, repository.ListOptions{Deleted: repository.IncludeDeleted})
+		e.must(err)
+		if len(groups.Items) != 0 {
+			e.t.Fatalf("got groups %+v after the purge, trashed ones included, want none", groups.Items)
+		}
+		known, err := e.r.EmployeesSubjects.FindAll(e.ctx)
+		e.must(err)
+		if len(known) != 0 {
+			e.t.Fatalf("got subjects of employees %+v, want them purged with the employees", known)
+		}
+		users, err := e.r.Users.FindAll(e.ctx)
+		e.must(err)
+		if len(users) != 0 {
+			e.t.Fatalf("got users %+v after the purge, want none", users)
+		}
+
+		// the rows that were not purged stay
+		subjects, err := e.r.Subjects.FindAll(e.ctx)
+		e.must(err)
+		if len(subjects) != 2 {
+			e.t.Fatalf("got %d subjects, want 2", len(subjects))
+		}
+	})
+}
@@ -0,0 +1,293 @@
+// Package repotest is a contract test suite for implementations of
+// repository.Repository. It pins down the behaviour callers rely on:
+// returned errors, soft deletes, NULL handling of the join queries,
+// list options and transactions.
+//
+// The suite only uses values every implementation stores unchanged:
+// passports are exactly 9 characters, lesson type names 2 and dates have
+// no time of day. Text compared by order or case uses latin letters so
+// the result doesn't depend on the locale of a database.
+package repotest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+	"university-db-admin/pkg/dbclient"
+)
+
+// Open returns an empty repository for a single test
+type Open func(t *testing.T) *repository.Repository
+
+// operator the suite's changes are attributed to in the audit log
+const operator = "repotest"
+
+// runs the whole suite, open is called once per test
+func Run(t *testing.T, open Open) {
+	t.Run("Transactor", func(t *testing.T) { testTransactor(t, open) })
+	t.Run("Positions", func(t *testing.T) { testPositions(t, open) })
+	t.Run("Employees", func(t *testing.T) { testEmployees(t, open) })
+	t.Run("Groups", func(t *testing.T) { testGroups(t, open) })
+	t.Run("Students", func(t *testing.T) { testStudents(t, open) })
+	t.Run("Subjects", func(t *testing.T) { testSubjects(t, open) })
+	t.Run("LessonTypes", func(t *testing.T) { testLessonTypes(t, open) })
+	t.Run("Lessons", func(t *testing.T) { testLessons(t, open) })
+	t.Run("Marks", func(t *testing.T) { testMarks(t, open) })
+	t.Run("EmployeesSubjects", func(t *testing.T) { testEmployeesSubjects(t, open) })
+	t.Run("Audit", func(t *testing.T) { testAudit(t, open) })
+	t.Run("Users", func(t *testing.T) { testUsers(t, open) })
+	t.Run("List", func(t *testing.T) { testList(t, open) })
+	t.Run("Purge", func(t *testing.T) { testPurge(t, open) })
+}
+
+// env is the repository under test together with the context of its calls
+type env struct {
+	t   *testing.T
+	ctx context.Context
+	r   *repository.Repository
+}
+
+func newEnv(t *testing.T, open Open) *env {
+	t.Helper()
+	return &env{
+		t:   t,
+		ctx: dbclient.WithOperator(context.Background(), operator),
+		r:   open(t),
+	}
+}
+
+// runs test as a subtest on a fresh repository
+func run(t *testing.T, open Open, name string, test func(e *env)) {
+	t.Run(name, func(t *testing.T) {
+		test(newEnv(t, open))
+	})
+}
+
+// fails the test on an unexpected error
+func (e *env) must(err error) {
+	e.t.Helper()
+	if err != nil {
+		e.t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+// helpers creating rows, they fail the test when the row is rejected
+
+func (e *env) position(name string) uint64 {
+	e.t.Helper()
+	id, err := e.r.Positions.Create(e.ctx, domain.Position{Name: name})
+	e.must(err)
+	return id
+}
+
+func (e *env) capablePosition(pos domain.Position) uint64 {
+	e.t.Helper()
+	id, err := e.r.Positions.Create(e.ctx, pos)
+	e.must(err)
+	return id
+}
+
+func (e *env) employee(name, passport string, positionID uint64) uint64 {
+	e.t.Helper()
+	id, err := e.r.Employees.Create(e.ctx, domain.Employee{Name: name, Passport: passport, PositionID: positionID})
+	e.must(err)
+	return id
+}
+
+func (e *env) group(number uint64) uint64 {
+	e.t.Helper()
+	id, err := e.r.Groups.Create(e.ctx, domain.Group{Number: number})
+	e.must(err)
+	return id
+}
+
+func (e *env) student(name, passport string, employeeID, groupID uint64) uint64 {
+	e.t.Helper()
+	id, err := e.r.Students.Create(e.ctx, domain.Student{Name: name, Passport: passport, EmployeeID: employeeID, GroupID: groupID})
+	e.must(err)
+	return id
+}
+
+func (e *env) subject(name string) uint64 {
+	e.t.Helper()
+	id, err := e.r.Subjects.Create(e.ctx, domain.Subject{Name: name, Description: name + " course"})
+	e.must(err)
+	return id
+}
+
+func (e *env) lessonType(name string) uint64 {
+	e.t.Helper()
+	id, err := e.r.LessonTypes.Create(e.ctx, domain.LessonType{Name: name})
+	e.must(err)
+	return id
+}
+
+func (e *env) lesson(lsn domain.Lesson) uint64 {
+	e.t.Helper()
+	id, err := e.r.Lessons.Create(e.ctx, lsn)
+	e.must(err)
+	return id
+}
+
+func (e *env) mark(mark domain.Mark) uint64 {
+	e.t.Helper()
+	id, err := e.r.Marks.Create(e.ctx, mark)
+	e.must(err)
+	return id
+}
+
+func (e *env) assign(employeeID, subjectID uint64) {
+	e.t.Helper()
+	e.must(e.r.EmployeesSubjects.Create(e.ctx, domain.EmployeeSubject{EmployeeID: employeeID, SubjectID: subjectID}))
+}
+
+// faculty is a small dataset most tests start from
+type faculty struct {
+	teacher, assistant uint64 // positions, both may curate and only the teacher teach
+	ivanov, petrova    uint64 // employees, ivanov is a teacher and petrova an assistant
+	group1, group2     uint64
+	anna, boris        uint64 // students of group1, curated by ivanov and petrova
+	math, physics      uint64 // subjects
+	lecture, practice  uint64 // lesson types
+}
+
+func (e *env) faculty() faculty {
+	e.t.Helper()
+	var f faculty
+	f.teacher = e.capablePosition(domain.Position{Name: "Доцент", CanTeach: true, CanCurate: true})
+	f.assistant = e.capablePosition(domain.Position{Name: "Ассистент", CanCurate: true})
+	f.ivanov = e.employee("Ivanov Ivan Petrovich", "MP0000001", f.teacher)
+	f.petrova = e.employee("Petrova Olga Ivanovna", "MP0000002", f.assistant)
+	f.group1 = e.group(101)
+	f.group2 = e.group(102)
+	f.anna = e.student("Anna Smirnova", "MP1000001", f.ivanov, f.group1)
+	f.boris = e.student("Boris Volkov", "MP1000002", f.petrova, f.group1)
+	f.math = e.subject("Mathematics")
+	f.physics = e.subject("Physics")
+	f.lecture = e.lessonType("LK")
+	f.practice = e.lessonType("PZ")
+	return f
+}
+
+// day returns a date as stored in date columns
+func day(d int) time.Time {
+	return time.Date(2024, time.September, d, 0, 0, 0, 0, time.UTC)
+}
+
+// assertions
+
+func wantError(t *testing.T, err, target error) {
+	t.Helper()
+	if !errors.Is(err, target) {
+		t.Fatalf("got error %v, want %v", err, target)
+	}
+}
+
+func wantDuplicate(t *testing.T, err error, table, field, value string) {
+	t.Helper()
+	var dupErr *repository.DuplicateError
+	if !errors.As(err, &dupErr) {
+		t.Fatalf("got error %v, want a duplicate error", err)
+	}
+	want := repository.DuplicateError{Table: table, Field: field, Value: value}
+	if *dupErr != want {
+		t.Fatalf("got %+v, want %+v", *dupErr, want)
+	}
+}
+
+func wantInvalidReference(t *testing.T, err error, table, field, referencedTable string) {
+	t.Helper()
+	var refErr *repository.InvalidReferenceError
+	if !errors.As(err, &refErr) {
+		t.Fatalf("got error %v, want an invalid reference error", err)
+	}
+	want := repository.InvalidReferenceError{Table: table, Field: field, ReferencedTable: referencedTable}
+	if *refErr != want {
+		t.Fatalf("got %+v, want %+v", *refErr, want)
+	}
+}
+
+func wantReferenced(t *testing.T, err error, table, referencingTable string) {
+	t.Helper()
+	var refErr *repository.ReferencedError
+	if !errors.As(err, &refErr) {
+		t.Fatalf("got error %v, want a referenced error", err)
+	}
+	want := repository.ReferencedError{Table: table, ReferencingTable: referencingTable}
+	if *refErr != want {
+		t.Fatalf("got %+v, want %+v", *refErr, want)
+	}
+}
+
+func wantCheckViolation(t *testing.T, err error, table, constraint string) {
+	t.Helper()
+	var checkErr *repository.CheckViolationError
+	if !errors.As(err, &checkErr) {
+		t.Fatalf("got error %v, want a check violation", err)
+	}
+	want := repository.CheckViolationError{Table: table, Constraint: constraint}
+	if *checkErr != want {
+		t.Fatalf("got %+v, want %+v", *checkErr, want)
+	}
+}
+
+func equal[T comparable](t *testing.T, got, want T) {
+	t.Helper()
+	if got != want {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+// compares slices in order
+func equalSlices[T comparable](t *testing.T, got, want []T) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d items %+v, want %d items %+v", len(got), got, len(want), want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("item %d: got %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+// compares slices ignoring order, queries without ORDER BY
+// return rows in an unspecified order
+func sameElements[T comparable](t *testing.T, got, want []T) {
+	t.Helper()
+	count := map[T]int{}
+	for _, v := range want {
+		count[v]++
+	}
+	for _, v := range got {
+		count[v]--
+	}
+	for v, n := range count {
+		if n != 0 {
+			t.Fatalf("got %+v, want the same items as %+v (mismatch on %+v)", got, want, v)
+		}
+	}
+}
+
+// ids of the items of a list in order
+func ids[T any](items []T, id func(T) uint64) []uint64 {
+	result := []uint64{}
+	for _, item := range items {
+		result = append(result, id(item))
+	}
+	return result
+}
+
+// id as a filter value
+func idText(id uint64) string {
+	return strconv.FormatUint(id, 10)
+}
+
+func passport(n int) string {
+	return fmt.Sprintf("AB%07d", n)
+}
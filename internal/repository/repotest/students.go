@@ -0,0 +1,329 @@
+package repotest
+
+import (
+	"testing"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/dto"
+	"university-db-admin/internal/repository"
+)
+
+func testStudents(t *testing.T, open Open) {
+	run(t, open, "Create", func(e *env) {
+		f := e.faculty()
+
+		stud, err := e.r.Students.FindOne(e.ctx, f.anna)
+		e.must(err)
+		equal(e.t, stud, domain.Student{ID: f.anna, Name: "Anna Smirnova", Passport: "MP1000001", EmployeeID: f.ivanov, GroupID: f.group1})
+
+		_, err = e.r.Students.Create(e.ctx, domain.Student{Name: "Other Student", Passport: "MP1000001", EmployeeID: f.ivanov, GroupID: f.group1})
+		wantDuplicate(e.t, err, "students", "passport", "MP1000001")
+
+		_, err = e.r.Students.Create(e.ctx, domain.Student{Name: "Other Student", Passport: passport(1), EmployeeID: f.ivanov + 100, GroupID: f.group1})
+		wantInvalidReference(e.t, err, "students", "employee_id", "employees")
+
+		_, err = e.r.Students.Create(e.ctx, domain.Student{Name: "Other Student", Passport: passport(1), EmployeeID: f.ivanov, GroupID: f.group1 + 100})
+		wantInvalidReference(e.t, err, "students", "group_id", "groups")
+
+		// the zero id is not a missing curator but a reference to nothing
+		_, err = e.r.Students.Create(e.ctx, domain.Student{Name: "Other Student", Passport: passport(1), GroupID: f.group1})
+		wantInvalidReference(e.t, err, "students", "employee_id", "employees")
+	})
+
+	run(t, open, "CopyFrom", func(e *env) {
+		f := e.faculty()
+
+		n, err := e.r.Students.CopyFrom(e.ctx, []domain.Student{
+			{Name: "First Student", Passport: passport(1), EmployeeID: f.ivanov, GroupID: f.group2},
+			{Name: "Second Student", Passport: passport(2), EmployeeID: f.petrova, GroupID: f.group2},
+		})
+		e.must(err)
+		equal(e.t, n, 2)
+
+		studs, err := e.r.Students.FindByGroupID(e.ctx, f.group2)
+		e.must(err)
+		equal(e.t, len(studs), 2)
+
+		// a rejected row discards the whole batch
+		_, err = e.r.Students.CopyFrom(e.ctx, []domain.Student{
+			{Name: "Third Student", Passport: passport(3), EmployeeID: f.ivanov, GroupID: f.group2},
+			{Name: "Fourth Student", Passport: passport(4), EmployeeID: f.ivanov, GroupID: f.group2 + 100},
+		})
+		wantError(e.t, err, repository.ErrInvalidReference)
+
+		studs, err = e.r.Students.FindByGroupID(e.ctx, f.group2)
+		e.must(err)
+		equal(e.t, len(studs), 2)
+	})
+
+	run(t, open, "FindOne", func(e *env) {
+		f := e.faculty()
+
+		_, err := e.r.Students.FindOne(e.ctx, f.anna+100)
+		wantError(e.t, err, repository.ErrNotFound)
+
+		e.must(e.r.Students.Delete(e.ctx, f.anna))
+		_, err = e.r.Students.FindOne(e.ctx, f.anna)
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+
+	run(t, open, "FindAll", func(e *env) {
+		studs, err := e.r.Students.FindAll(e.ctx)
+		e.must(err)
+		equal(e.t, len(studs), 0)
+
+		f := e.faculty()
+		e.must(e.r.Students.Delete(e.ctx, f.anna))
+
+		studs, err = e.r.Students.FindAll(e.ctx)
+		e.must(err)
+		sameElements(e.t, ids(studs, studentID), []uint64{f.boris})
+	})
+
+	run(t, open, "List", func(e *env) {
+		f := e.faculty()
+		e.must(e.r.Students.Delete(e.ctx, f.anna))
+
+		page, err := e.r.Students.List(e.ctx, repository.ListOptions{})
+		e.must(err)
+		equalSlices(e.t, ids(page.Items, studentID), []uint64{f.boris})
+
+		page, err = e.r.Students.List(e.ctx, repository.ListOptions{Deleted: repository.OnlyDeleted})
+		e.must(err)
+		equalSlices(e.t, ids(page.Items, studentID), []uint64{f.anna})
+
+		_, err = e.r.Students.List(e.ctx, repository.ListOptions{Deleted: "trash"})
+		wantError(e.t, err, repository.ErrInvalidOption)
+	})
+
+	run(t, open, "FindByName", func(e *env) {
+		f := e.faculty()
+
+		studs, err := e.r.Students.FindByName(e.ctx, "Anna Smirnova")
+		e.must(err)
+		equalSlices(e.t, ids(studs, studentID), []uint64{f.anna})
+
+		studs, err = e.r.Students.FindByName(e.ctx, "anna smirnova")
+		e.must(err)
+		equal(e.t, len(studs), 0)
+	})
+
+	run(t, open, "FindByPassport", func(e *env) {
+		f := e.faculty()
+
+		stud, err := e.r.Students.FindByPassport(e.ctx, "MP1000002")
+		e.must(err)
+		equal(e.t, stud.ID, f.boris)
+
+		_, err = e.r.Students.FindByPassport(e.ctx, passport(9))
+		wantError(e.t, err, repository.ErrNotFound)
+
+		e.must(e.r.Students.Delete(e.ctx, f.boris))
+		_, err = e.r.Students.FindByPassport(e.ctx, "MP1000002")
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+
+	run(t, open, "FindByEmployeeID", func(e *env) {
+		f := e.faculty()
+
+		studs, err := e.r.Students.FindByEmployeeID(e.ctx, f.petrova)
+		e.must(err)
+		equalSlices(e.t, ids(studs, studentID), []uint64{f.boris})
+
+		studs, err = e.r.Students.FindByEmployeeID(e.ctx, f.petrova+100)
+		e.must(err)
+		equal(e.t, len(studs), 0)
+	})
+
+	run(t, open, "FindByGroupID", func(e *env) {
+		f := e.faculty()
+
+		studs, err := e.r.Students.FindByGroupID(e.ctx, f.group1)
+		e.must(err)
+		sameElements(e.t, ids(studs, studentID), []uint64{f.anna, f.boris})
+
+		studs, err = e.r.Students.FindByGroupID(e.ctx, f.group2)
+		e.must(err)
+		equal(e.t, len(studs), 0)
+	})
+
+	run(t, open, "FindAllWithNoCurator", func(e *env) {
+		f := e.faculty()
+
+		// every student created through the repository has a curator,
+		// even one that is in the trash
+		e.must(e.r.Employees.Delete(e.ctx, f.petrova))
+		result, err := e.r.Students.FindAllWithNoCurator(e.ctx)
+		e.must(err)
+		equal(e.t, len(result), 0)
+	})
+
+	run(t, open, "FindAllByMiddlename", func(e *env) {
+		f := e.faculty()
+		e.student("Sergeev Petr Ivanovich", passport(1), f.ivanov, f.group2)
+		e.student("Orlova Maria Petrovna", passport(2), f.ivanov, f.group2)
+
+		// the sequence matches the end of the name, case-sensitive
+		result, err := e.r.Students.FindAllByMiddlename(e.ctx, "ovich")
+		e.must(err)
+		sameElements(e.t, result, []dto.StudentByNameDTO{{Name: "Sergeev Petr Ivanovich", Passport: passport(1)}})
+
+		result, err = e.r.Students.FindAllByMiddlename(e.ctx, "Ovich")
+		e.must(err)
+		equal(e.t, len(result), 0)
+
+		result, err = e.r.Students.FindAllByMiddlename(e.ctx, "")
+		e.must(err)
+		equal(e.t, len(result), 4)
+	})
+
+	run(t, open, "FindAllGroupCombs", func(e *env) {
+		f := e.faculty()
+		e.group(103)
+		e.must(e.r.Groups.Delete(e.ctx, f.group2))
+		e.must(e.r.Students.Delete(e.ctx, f.boris))
+
+		result, err := e.r.Students.FindAllGroupCombs(e.ctx)
+		e.must(err)
+		sameElements(e.t, result, []dto.StudentGroupCombDTO{
+			{StudentName: "Anna Smirnova", GroupNumber: 101},
+			{StudentName: "Anna Smirnova", GroupNumber: 103},
+		})
+	})
+
+	run(t, open, "FindAllWithCurators", func(e *env) {
+		f := e.faculty()
+		e.employee("Sidorov Petr Olegovich", passport(1), f.teacher)
+		e.must(e.r.Employees.Delete(e.ctx, f.petrova))
+
+		// a trashed curator shows as empty strings rather than NULL
+		result, err := e.r.Students.FindAllWithCurators(e.ctx)
+		e.must(err)
+		sameElements(e.t, result, []dto.StudentCuratorDTO{
+			{StudentName: "Anna Smirnova", StudentPassport: "MP1000001", CuratorName: "Ivanov Ivan Petrovich", CuratorPassport: "MP0000001"},
+			{StudentName: "Boris Volkov", StudentPassport: "MP1000002"},
+		})
+	})
+
+	run(t, open, "FindWithAllCurators", func(e *env) {
+		f := e.faculty()
+		e.employee("Sidorov Petr Olegovich", passport(1), f.teacher)
+		e.must(e.r.Students.Delete(e.ctx, f.boris))
+
+		// curators without students show an empty student
+		result, err := e.r.Students.FindWithAllCurators(e.ctx)
+		e.must(err)
+		sameElements(e.t, result, []dto.StudentCuratorDTO{
+			{StudentName: "Anna Smirnova", StudentPassport: "MP1000001", CuratorName: "Ivanov Ivan Petrovich", CuratorPassport: "MP0000001"},
+			{CuratorName: "Petrova Olga Ivanovna", CuratorPassport: "MP0000002"},
+			{CuratorName: "Sidorov Petr Olegovich", CuratorPassport: passport(1)},
+		})
+	})
+
+	run(t, open, "FindAllPairsWithCurator", func(e *env) {
+		f := e.faculty()
+		e.employee("Sidorov Petr Olegovich", passport(1), f.teacher)
+		e.must(e.r.Employees.Delete(e.ctx, f.petrova))
+
+		// either side of a pair may be empty, trashed rows take no part
+		result, err := e.r.Students.FindAllPairsWithCurator(e.ctx)
+		e.must(err)
+		sameElements(e.t, result, []dto.StudentCuratorDTO{
+			{StudentName: "Anna Smirnova", StudentPassport: "MP1000001", CuratorName: "Ivanov Ivan Petrovich", CuratorPassport: "MP0000001"},
+			{StudentName: "Boris Volkov", StudentPassport: "MP1000002"},
+			{CuratorName: "Sidorov Petr Olegovich", CuratorPassport: passport(1)},
+		})
+
+		e.must(e.r.Students.Delete(e.ctx, f.anna))
+		result, err = e.r.Students.FindAllPairsWithCurator(e.ctx)
+		e.must(err)
+		sameElements(e.t, result, []dto.StudentCuratorDTO{
+			{StudentName: "Boris Volkov", StudentPassport: "MP1000002"},
+			{CuratorName: "Ivanov Ivan Petrovich", CuratorPassport: "MP0000001"},
+			{CuratorName: "Sidorov Petr Olegovich", CuratorPassport: passport(1)},
+		})
+	})
+
+	run(t, open, "FindAllUppercaseWithLength", func(e *env) {
+		f := e.faculty()
+		cyrillic := e.student("Пётр Петров", passport(1), f.ivanov, f.group2)
+		e.must(e.r.Students.Delete(e.ctx, f.boris))
+
+		result, err := e.r.Students.FindAllUppercaseWithLength(e.ctx)
+		e.must(err)
+		equal(e.t, len(result), 2)
+		for _, stat := range result {
+			switch stat.ID {
+			case f.anna:
+				equal(e.t, stat, dto.StudentNameStatDTO{ID: f.anna, UppercaseName: "ANNA SMIRNOVA", NameLength: 13})
+			case cyrillic:
+				// the length counts characters, not bytes
+				equal(e.t, stat.NameLength, 11)
+			default:
+				e.t.Fatalf("unexpected student %+v", stat)
+			}
+		}
+	})
+
+	run(t, open, "Update", func(e *env) {
+		f := e.faculty()
+
+		updated := domain.Student{Name: "Anna Orlova", Passport: "MP1000003", EmployeeID: f.petrova, GroupID: f.group2}
+		e.must(e.r.Students.Update(e.ctx, f.anna, updated))
+		stud, err := e.r.Students.FindOne(e.ctx, f.anna)
+		e.must(err)
+		updated.ID = f.anna
+		equal(e.t, stud, updated)
+
+		err = e.r.Students.Update(e.ctx, f.anna, domain.Student{Name: "Anna Orlova", Passport: "MP1000002", EmployeeID: f.petrova, GroupID: f.group2})
+		wantDuplicate(e.t, err, "students", "passport", "MP1000002")
+
+		err = e.r.Students.Update(e.ctx, f.anna, domain.Student{Name: "Anna Orlova", Passport: "MP1000003", EmployeeID: f.petrova, GroupID: f.group2 + 100})
+		wantInvalidReference(e.t, err, "students", "group_id", "groups")
+
+		err = e.r.Students.Update(e.ctx, f.anna+100, updated)
+		wantError(e.t, err, repository.ErrNotFound)
+
+		e.must(e.r.Students.Delete(e.ctx, f.boris))
+		err = e.r.Students.Update(e.ctx, f.boris, domain.Student{Name: "Boris Volkov", Passport: "MP1000002", EmployeeID: f.petrova, GroupID: f.group1})
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+
+	run(t, open, "Delete", func(e *env) {
+		f := e.faculty()
+
+		e.must(e.r.Students.Delete(e.ctx, f.anna))
+		err := e.r.Students.Delete(e.ctx, f.anna)
+		wantError(e.t, err, repository.ErrNotFound)
+
+		err = e.r.Students.Delete(e.ctx, f.anna+100)
+		wantError(e.t, err, repository.ErrNotFound)
+
+		// the passport is given up while its owner is in the trash
+		_, err = e.r.Students.Create(e.ctx, domain.Student{Name: "Other Student", Passport: "MP1000001", EmployeeID: f.ivanov, GroupID: f.group1})
+		e.must(err)
+	})
+
+	run(t, open, "Restore", func(e *env) {
+		f := e.faculty()
+
+		err := e.r.Students.Restore(e.ctx, f.anna)
+		wantError(e.t, err, repository.ErrNotFound)
+
+		e.must(e.r.Students.Delete(e.ctx, f.anna))
+		e.must(e.r.Students.Restore(e.ctx, f.anna))
+		_, err = e.r.Students.FindOne(e.ctx, f.anna)
+		e.must(err)
+
+		err = e.r.Students.Restore(e.ctx, f.anna+100)
+		wantError(e.t, err, repository.ErrNotFound)
+
+		// an active student took the passport in the meantime
+		e.must(e.r.Students.Delete(e.ctx, f.anna))
+		_, err = e.r.Students.Create(e.ctx, domain.Student{Name: "Other Student", Passport: "MP1000001", EmployeeID: f.ivanov, GroupID: f.group1})
+		e.must(err)
+		err = e.r.Students.Restore(e.ctx, f.anna)
+		wantDuplicate(e.t, err, "students", "passport", "MP1000001")
+	})
+}
+
+func studentID(stud domain.Student) uint64 { return stud.ID }
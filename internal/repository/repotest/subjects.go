@@ -0,0 +1,129 @@
+package repotest
+
+import (
+	"testing"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/dto"
+	"university-db-admin/internal/repository"
+)
+
+func testSubjects(t *testing.T, open Open) {
+	run(t, open, "Create", func(e *env) {
+		id := e.subject("Physics")
+
+		sbj, err := e.r.Subjects.FindOne(e.ctx, id)
+		e.must(err)
+		equal(e.t, sbj, domain.Subject{ID: id, Name: "Physics", Description: "Physics course"})
+
+		_, err = e.r.Subjects.Create(e.ctx, domain.Subject{Name: "Physics", Description: "Again"})
+		wantDuplicate(e.t, err, "subjects", "name", "Physics")
+	})
+
+	run(t, open, "FindOne", func(e *env) {
+		id := e.subject("Physics")
+
+		_, err := e.r.Subjects.FindOne(e.ctx, id+100)
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+
+	run(t, open, "FindAll", func(e *env) {
+		subjects, err := e.r.Subjects.FindAll(e.ctx)
+		e.must(err)
+		equal(e.t, len(subjects), 0)
+
+		a := e.subject("Physics")
+		b := e.subject("Chemistry")
+		subjects, err = e.r.Subjects.FindAll(e.ctx)
+		e.must(err)
+		sameElements(e.t, subjects, []domain.Subject{
+			{ID: a, Name: "Physics", Description: "Physics course"},
+			{ID: b, Name: "Chemistry", Description: "Chemistry course"},
+		})
+	})
+
+	run(t, open, "List", func(e *env) {
+		a := e.subject("Physics")
+		b := e.subject("Chemistry")
+
+		page, err := e.r.Subjects.List(e.ctx, repository.ListOptions{Sort: []repository.Sort{{Field: "name"}}})
+		e.must(err)
+		equalSlices(e.t, ids(page.Items, subjectID), []uint64{b, a})
+
+		// descriptions are neither sortable nor filterable
+		_, err = e.r.Subjects.List(e.ctx, repository.ListOptions{Sort: []repository.Sort{{Field: "description"}}})
+		wantError(e.t, err, repository.ErrInvalidOption)
+	})
+
+	run(t, open, "FindByName", func(e *env) {
+		id := e.subject("Physics")
+
+		sbj, err := e.r.Subjects.FindByName(e.ctx, "Physics")
+		e.must(err)
+		equal(e.t, sbj.ID, id)
+
+		_, err = e.r.Subjects.FindByName(e.ctx, "Chemistry")
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+
+	run(t, open, "FindAllSorted", func(e *env) {
+		e.subject("physics")
+		e.subject("algebra")
+		e.subject("chemistry")
+
+		result, err := e.r.Subjects.FindAllSorted(e.ctx)
+		e.must(err)
+		equalSlices(e.t, result, []dto.SortedSubjectDTO{{Name: "algebra"}, {Name: "chemistry"}, {Name: "physics"}})
+	})
+
+	run(t, open, "Update", func(e *env) {
+		id := e.subject("Physics")
+		e.subject("Chemistry")
+
+		e.must(e.r.Subjects.Update(e.ctx, id, domain.Subject{Name: "Optics", Description: "Light"}))
+		sbj, err := e.r.Subjects.FindOne(e.ctx, id)
+		e.must(err)
+		equal(e.t, sbj, domain.Subject{ID: id, Name: "Optics", Description: "Light"})
+
+		err = e.r.Subjects.Update(e.ctx, id, domain.Subject{Name: "Chemistry", Description: "Light"})
+		wantDuplicate(e.t, err, "subjects", "name", "Chemistry")
+
+		err = e.r.Subjects.Update(e.ctx, id+100, domain.Subject{Name: "Biology", Description: "Life"})
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+
+	run(t, open, "Delete", func(e *env) {
+		f := e.faculty()
+		e.assign(f.ivanov, f.physics)
+		e.assign(f.petrova, f.math)
+
+		// teacher assignments are removed together with the subject
+		e.must(e.r.Subjects.Delete(e.ctx, f.physics))
+		_, err := e.r.Subjects.FindOne(e.ctx, f.physics)
+		wantError(e.t, err, repository.ErrNotFound)
+
+		assignments, err := e.r.EmployeesSubjects.FindAll(e.ctx)
+		e.must(err)
+		equalSlices(e.t, assignments, []domain.EmployeeSubject{{EmployeeID: f.petrova, SubjectID: f.math}})
+
+		err = e.r.Subjects.Delete(e.ctx, f.physics)
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+
+	run(t, open, "DeleteReferenced", func(e *env) {
+		f := e.faculty()
+		e.lesson(domain.Lesson{GroupID: f.group1, SubjectID: f.math, LessonTypeID: f.lecture, Week: 1, Weekday: 1, Pair: 1, Room: 101})
+		e.assign(f.ivanov, f.physics)
+		practice := e.lesson(domain.Lesson{GroupID: f.group1, SubjectID: f.physics, LessonTypeID: f.practice, Week: 1, Weekday: 3, Pair: 1, Room: 214})
+		e.mark(domain.Mark{EmployeeID: f.ivanov, StudentID: f.anna, SubjectID: f.physics, Mark: 8, Date: day(2)})
+		// marks outlive the lessons they were given for
+		e.must(e.r.Lessons.Delete(e.ctx, practice))
+
+		err := e.r.Subjects.Delete(e.ctx, f.math)
+		wantReferenced(e.t, err, "subjects", "lessons")
+
+		err = e.r.Subjects.Delete(e.ctx, f.physics)
+		wantReferenced(e.t, err, "subjects", "marks")
+	})
+}
+
+func subjectID(sbj domain.Subject) uint64 { return sbj.ID }
@@ -0,0 +1,119 @@
+package repotest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+)
+
+var errAbort = errors.New("abort")
+
+func testTransactor(t *testing.T, open Open) {
+	run(t, open, "Commit", func(e *env) {
+		var id uint64
+		err := e.r.WithTx(e.ctx, func(ctx context.Context, r *repository.Repository) (err error) {
+			id, err = r.Groups.Create(ctx, domain.Group{Number: 101})
+			if err != nil {
+				return err
+			}
+
+			// the transaction sees its own writes
+			_, err = r.Groups.FindOne(ctx, id)
+			return err
+		})
+		e.must(err)
+
+		grp, err := e.r.Groups.FindOne(e.ctx, id)
+		e.must(err)
+		equal(e.t, grp.Number, 101)
+	})
+
+	run(t, open, "Rollback", func(e *env) {
+		var id uint64
+		err := e.r.WithTx(e.ctx, func(ctx context.Context, r *repository.Repository) (err error) {
+			id, err = r.Groups.Create(ctx, domain.Group{Number: 101})
+			if err != nil {
+				return err
+			}
+			return errAbort
+		})
+		wantError(e.t, err, errAbort)
+
+		_, err = e.r.Groups.FindOne(e.ctx, id)
+		wantError(e.t, err, repository.ErrNotFound)
+		groups, err := e.r.Groups.FindAll(e.ctx)
+		e.must(err)
+		equal(e.t, len(groups), 0)
+
+		// the number is free again
+		e.group(101)
+
+		// nothing of the rolled back changes is in the log
+		page, err := e.r.Audit.List(e.ctx, repository.ListOptions{})
+		e.must(err)
+		equal(e.t, page.Total, 1)
+	})
+
+	run(t, open, "Savepoint", func(e *env) {
+		var outer uint64
+		err := e.r.WithTx(e.ctx, func(ctx context.Context, r *repository.Repository) (err error) {
+			outer, err = r.Groups.Create(ctx, domain.Group{Number: 101})
+			if err != nil {
+				return err
+			}
+
+			// a failing nested call only undoes its own changes
+			err = r.WithTx(ctx, func(ctx context.Context, r *repository.Repository) error {
+				if _, err := r.Groups.Create(ctx, domain.Group{Number: 102}); err != nil {
+					return err
+				}
+				_, err := r.Groups.Create(ctx, domain.Group{Number: 101})
+				return err
+			})
+			wantDuplicate(e.t, err, "groups", "number", "101")
+
+			err = r.WithTx(ctx, func(ctx context.Context, r *repository.Repository) error {
+				_, err := r.Groups.Create(ctx, domain.Group{Number: 103})
+				return err
+			})
+			if err != nil {
+				return err
+			}
+
+			_, err = r.Groups.Create(ctx, domain.Group{Number: 104})
+			return err
+		})
+		e.must(err)
+
+		groups, err := e.r.Groups.FindAll(e.ctx)
+		e.must(err)
+		numbers := []uint64{}
+		for _, grp := range groups {
+			numbers = append(numbers, grp.Number)
+		}
+		sameElements(e.t, numbers, []uint64{101, 103, 104})
+
+		_, err = e.r.Groups.FindOne(e.ctx, outer)
+		e.must(err)
+	})
+
+	run(t, open, "IDs", func(e *env) {
+		// ids handed out by a rolled back transaction are not reused
+		var rolledBack uint64
+		err := e.r.WithTx(e.ctx, func(ctx context.Context, r *repository.Repository) (err error) {
+			rolledBack, err = r.Groups.Create(ctx, domain.Group{Number: 102})
+			if err != nil {
+				return err
+			}
+			return errAbort
+		})
+		wantError(e.t, err, errAbort)
+
+		id := e.group(103)
+		if id <= rolledBack {
+			e.t.Fatalf("got id %d, want one after the rolled back %d", id, rolledBack)
+		}
+	})
+}
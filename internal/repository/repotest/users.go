@@ -0,0 +1,106 @@
+package repotest
+
+import (
+	"testing"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+)
+
+func testUsers(t *testing.T, open Open) {
+	run(t, open, "Create", func(e *env) {
+		f := e.faculty()
+		admin, err := e.r.Users.Create(e.ctx, domain.User{Login: "admin", PasswordHash: "hash", Role: domain.RoleAdmin})
+		e.must(err)
+		teacher, err := e.r.Users.Create(e.ctx, domain.User{Login: "ivanov", PasswordHash: "hash", Role: domain.RoleTeacher, EmployeeID: &f.ivanov})
+		e.must(err)
+		if teacher <= admin {
+			e.t.Fatalf("ids are not increasing: %d then %d", admin, teacher)
+		}
+
+		_, err = e.r.Users.Create(e.ctx, domain.User{Login: "admin", PasswordHash: "hash", Role: domain.RoleDean})
+		wantDuplicate(e.t, err, "users", "login", "admin")
+
+		_, err = e.r.Users.Create(e.ctx, domain.User{Login: "root", PasswordHash: "hash", Role: "root"})
+		wantCheckViolation(e.t, err, "users", "users_role_check")
+
+		_, err = e.r.Users.Create(e.ctx, domain.User{Login: "petrova", PasswordHash: "hash", Role: domain.RoleTeacher})
+		wantCheckViolation(e.t, err, "users", "users_teacher_check")
+
+		missing := f.petrova + 100
+		_, err = e.r.Users.Create(e.ctx, domain.User{Login: "petrova", PasswordHash: "hash", Role: domain.RoleTeacher, EmployeeID: &missing})
+		wantInvalidReference(e.t, err, "users", "employee_id", "employees")
+	})
+
+	run(t, open, "FindByLogin", func(e *env) {
+		f := e.faculty()
+		id, err := e.r.Users.Create(e.ctx, domain.User{Login: "ivanov", PasswordHash: "hash", Role: domain.RoleTeacher, EmployeeID: &f.ivanov})
+		e.must(err)
+
+		u, err := e.r.Users.FindByLogin(e.ctx, "ivanov")
+		e.must(err)
+		equalUser(e.t, u, domain.User{ID: id, Login: "ivanov", PasswordHash: "hash", Role: domain.RoleTeacher, EmployeeID: &f.ivanov})
+
+		_, err = e.r.Users.FindByLogin(e.ctx, "petrova")
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+
+	run(t, open, "Update", func(e *env) {
+		f := e.faculty()
+		id, err := e.r.Users.Create(e.ctx, domain.User{Login: "ivanov", PasswordHash: "hash", Role: domain.RoleTeacher, EmployeeID: &f.ivanov})
+		e.must(err)
+		_, err = e.r.Users.Create(e.ctx, domain.User{Login: "admin", PasswordHash: "hash", Role: domain.RoleAdmin})
+		e.must(err)
+
+		updated := domain.User{ID: id, Login: "ivanov", PasswordHash: "new hash", Role: domain.RoleDean}
+		e.must(e.r.Users.Update(e.ctx, id, updated))
+		u, err := e.r.Users.FindByLogin(e.ctx, "ivanov")
+		e.must(err)
+		equalUser(e.t, u, updated)
+
+		err = e.r.Users.Update(e.ctx, id, domain.User{Login: "admin", PasswordHash: "hash", Role: domain.RoleDean})
+		wantDuplicate(e.t, err, "users", "login", "admin")
+
+		err = e.r.Users.Update(e.ctx, id+100, updated)
+		wantError(e.t, err, repository.ErrNotFound)
+	})
+
+	run(t, open, "Count", func(e *env) {
+		n, err := e.r.Users.Count(e.ctx)
+		e.must(err)
+		equal(e.t, n, uint64(0))
+
+		_, err = e.r.Users.Create(e.ctx, domain.User{Login: "admin", PasswordHash: "hash", Role: domain.RoleAdmin})
+		e.must(err)
+		_, err = e.r.Users.Create(e.ctx, domain.User{Login: "reader", PasswordHash: "hash", Role: domain.RoleReadOnly})
+		e.must(err)
+		n, err = e.r.Users.Count(e.ctx)
+		e.must(err)
+		equal(e.t, n, uint64(2))
+	})
+
+	run(t, open, "Delete", func(e *env) {
+		id, err := e.r.Users.Create(e.ctx, domain.User{Login: "admin", PasswordHash: "hash", Role: domain.RoleAdmin})
+		e.must(err)
+
+		e.must(e.r.Users.Delete(e.ctx, id))
+		users, err := e.r.Users.FindAll(e.ctx)
+		e.must(err)
+		equal(e.t, len(users), 0)
+
+		wantError(e.t, e.r.Users.Delete(e.ctx, id), repository.ErrNotFound)
+	})
+}
+
+// users hold a pointer, so they are compared field by field
+func equalUser(t *testing.T, got, want domain.User) {
+	t.Helper()
+	same := got.ID == want.ID && got.Login == want.Login && got.PasswordHash == want.PasswordHash && got.Role == want.Role
+	if got.EmployeeID == nil || want.EmployeeID == nil {
+		same = same && got.EmployeeID == want.EmployeeID
+	} else {
+		same = same && *got.EmployeeID == *want.EmployeeID
+	}
+	if !same {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
@@ -0,0 +1,101 @@
+package schedule
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Bell is the time a pair starts and ends at, as offsets from midnight
+type Bell struct {
+	Start, End time.Duration
+}
+
+func (b Bell) String() string {
+	return Clock(b.Start) + "-" + Clock(b.End)
+}
+
+// Clock formats an offset from midnight as hh:mm
+func Clock(d time.Duration) string {
+	return fmt.Sprintf("%02d:%02d", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// BellSchedule lists the pairs of a day, pair n rings at BellSchedule[n-1]
+type BellSchedule []Bell
+
+// DefaultBells are the pairs of the day unless BELL_SCHEDULE sets others
+const DefaultBells = "08:00-09:35,09:45-11:20,11:35-13:10,13:25-15:00,15:15-16:50,17:00-18:35"
+
+// Bell returns the time of a pair, false for a pair outside the schedule
+func (s BellSchedule) Bell(pair uint16) (Bell, bool) {
+	if pair == 0 || int(pair) > len(s) {
+		return Bell{}, false
+	}
+	return s[pair-1], true
+}
+
+// Time returns the time of a pair as shown to the user, empty for a pair
+// outside the schedule
+func (s BellSchedule) Time(pair uint16) string {
+	if b, ok := s.Bell(pair); ok {
+		return b.String()
+	}
+	return ""
+}
+
+func (s BellSchedule) String() string {
+	bells := make([]string, len(s))
+	for i, b := range s {
+		bells[i] = b.String()
+	}
+	return strings.Join(bells, ",")
+}
+
+// ParseBells reads a comma separated list of pairs like 08:00-09:35,
+// pairs go in the order of the day and don't overlap
+func ParseBells(s string) (BellSchedule, error) {
+	var bells BellSchedule
+	for i, item := range strings.Split(s, ",") {
+		start, end, ok := strings.Cut(strings.TrimSpace(item), "-")
+		if !ok {
+			return nil, fmt.Errorf("pair %d: %q is not a start-end range", i+1, item)
+		}
+
+		var (
+			b   Bell
+			err error
+		)
+		if b.Start, err = parseClock(start); err != nil {
+			return nil, fmt.Errorf("pair %d: %w", i+1, err)
+		}
+		if b.End, err = parseClock(end); err != nil {
+			return nil, fmt.Errorf("pair %d: %w", i+1, err)
+		}
+		if b.End <= b.Start {
+			return nil, fmt.Errorf("pair %d: %s ends before it starts", i+1, b)
+		}
+		if i > 0 && b.Start < bells[i-1].End {
+			return nil, fmt.Errorf("pair %d: %s starts before pair %d ends", i+1, b, i)
+		}
+		bells = append(bells, b)
+	}
+	return bells, nil
+}
+
+// MustParseBells is ParseBells panicking on an invalid schedule
+func MustParseBells(s string) BellSchedule {
+	bells, err := ParseBells(s)
+	if err != nil {
+		panic(err)
+	}
+	return bells
+}
+
+// reads a time of day like 08:00
+func parseClock(s string) (time.Duration, error) {
+	t, err := time.Parse("15:04", strings.TrimSpace(s))
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q, expected hh:mm", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,146 @@
+// Package schedule finds lessons that can't take place together: two
+// groups booked into one room, one group or one teacher booked into two
+// lessons in the same pair. Lessons without a teacher only clash by room
+// and group.
+package schedule
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"university-db-admin/internal/domain"
+)
+
+// Kind is the resource booked twice
+type Kind string
+
+const (
+	KindRoom    Kind = "room"    // two lessons in one room
+	KindGroup   Kind = "group"   // one group in two lessons
+	KindTeacher Kind = "teacher" // one teacher in two lessons
+)
+
+// Conflict is a pair of lessons held at the same time that share a room,
+// a group or a teacher
+type Conflict struct {
+	Kind       Kind   `json:"kind"`
+	LessonID   uint64 `json:"lesson_id"`
+	OtherID    uint64 `json:"other_id"` // the lesson clashing with LessonID
+	Week       uint16 `json:"week"`
+	Weekday    uint16 `json:"weekday"`
+	Pair       uint16 `json:"pair"`
+	Room       uint64 `json:"room,omitempty"`        // set for room conflicts
+	GroupID    uint64 `json:"group_id,omitempty"`    // set for group conflicts
+	EmployeeID uint64 `json:"employee_id,omitempty"` // set for teacher conflicts
+}
+
+// String explains the conflict to the user, LessonID is 0 for
+// a lesson that isn't scheduled yet
+func (c Conflict) String() string {
+	when := fmt.Sprintf("неделя %d, день %d, пара %d", c.Week, c.Weekday, c.Pair)
+	switch {
+	case c.Kind == KindRoom && c.LessonID == 0:
+		return fmt.Sprintf("аудитория %d уже занята занятием %d (%s)", c.Room, c.OtherID, when)
+	case c.Kind == KindRoom:
+		return fmt.Sprintf("аудитория %d занята занятиями %d и %d в одно время (%s)", c.Room, c.LessonID, c.OtherID, when)
+	case c.Kind == KindTeacher && c.LessonID == 0:
+		return fmt.Sprintf("у преподавателя с ID %d в это время уже есть занятие %d (%s)", c.EmployeeID, c.OtherID, when)
+	case c.Kind == KindTeacher:
+		return fmt.Sprintf("преподаватель с ID %d ведёт занятия %d и %d одновременно (%s)", c.EmployeeID, c.LessonID, c.OtherID, when)
+	case c.LessonID == 0:
+		return fmt.Sprintf("у группы с ID %d в это время уже есть занятие %d (%s)", c.GroupID, c.OtherID, when)
+	}
+	return fmt.Sprintf("у группы с ID %d одновременно занятия %d и %d (%s)", c.GroupID, c.LessonID, c.OtherID, when)
+}
+
+// ErrConflict is matched by every ConflictError
+var ErrConflict = errors.New("занятие пересекается с другими занятиями")
+
+// ConflictError rejects a lesson clashing with the ones already scheduled
+type ConflictError struct {
+	Conflicts []Conflict
+}
+
+func (e *ConflictError) Error() string {
+	explained := make([]string, len(e.Conflicts))
+	for i, c := range e.Conflicts {
+		explained[i] = c.String()
+	}
+	return ErrConflict.Error() + ": " + strings.Join(explained, "; ")
+}
+
+func (e *ConflictError) Unwrap() error {
+	return ErrConflict
+}
+
+// slot is the time a lesson takes place at
+type slot struct {
+	week, weekday, pair uint16
+}
+
+func slotOf(lsn domain.Lesson) slot {
+	return slot{lsn.Week, lsn.Weekday, lsn.Pair}
+}
+
+// conflicts between two lessons, a first
+func clash(a, b domain.Lesson) []Conflict {
+	if slotOf(a) != slotOf(b) {
+		return nil
+	}
+	at := Conflict{LessonID: a.ID, OtherID: b.ID, Week: a.Week, Weekday: a.Weekday, Pair: a.Pair}
+	var conflicts []Conflict
+	if a.Room == b.Room {
+		c := at
+		c.Kind, c.Room = KindRoom, a.Room
+		conflicts = append(conflicts, c)
+	}
+	if a.GroupID == b.GroupID {
+		c := at
+		c.Kind, c.GroupID = KindGroup, a.GroupID
+		conflicts = append(conflicts, c)
+	}
+	if a.EmployeeID != nil && b.EmployeeID != nil && *a.EmployeeID == *b.EmployeeID {
+		c := at
+		c.Kind, c.EmployeeID = KindTeacher, *a.EmployeeID
+		conflicts = append(conflicts, c)
+	}
+	return conflicts
+}
+
+// Check returns the conflicts of lsn with the scheduled lessons, the
+// stored version of lsn itself is skipped so an update doesn't clash
+// with the lesson it replaces. lsn.ID is 0 for new lessons
+func Check(lsn domain.Lesson, scheduled []domain.Lesson) []Conflict {
+	var conflicts []Conflict
+	for _, other := range scheduled {
+		if lsn.ID != 0 && other.ID == lsn.ID {
+			continue
+		}
+		conflicts = append(conflicts, clash(lsn, other)...)
+	}
+	return conflicts
+}
+
+// Detect returns every conflict of the schedule once, ordered by the
+// lessons involved, the lesson with the lower id comes first
+func Detect(lessons []domain.Lesson) []Conflict {
+	lessons = slices.Clone(lessons)
+	slices.SortFunc(lessons, func(a, b domain.Lesson) int { return cmp.Compare(a.ID, b.ID) })
+
+	bySlot := map[slot][]domain.Lesson{}
+	for _, lsn := range lessons {
+		bySlot[slotOf(lsn)] = append(bySlot[slotOf(lsn)], lsn)
+	}
+
+	var conflicts []Conflict
+	for _, lsn := range lessons {
+		for _, other := range bySlot[slotOf(lsn)] {
+			if other.ID > lsn.ID {
+				conflicts = append(conflicts, clash(lsn, other)...)
+			}
+		}
+	}
+	return conflicts
+}
@@ -0,0 +1,109 @@
+package schedule_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/schedule"
+)
+
+func teacher(id uint64) *uint64 {
+	return &id
+}
+
+// lessons of three groups: 1 and 2 share room 101, 1 and 3 group 10,
+// 4 takes place in another week, 5 in the next pair by the teacher of 3
+var lessons = []domain.Lesson{
+	{ID: 3, GroupID: 10, SubjectID: 2, LessonTypeID: 1, Week: 1, Weekday: 1, Pair: 1, Room: 214, EmployeeID: teacher(7)},
+	{ID: 1, GroupID: 10, SubjectID: 1, LessonTypeID: 1, Week: 1, Weekday: 1, Pair: 1, Room: 101},
+	{ID: 2, GroupID: 20, SubjectID: 1, LessonTypeID: 1, Week: 1, Weekday: 1, Pair: 1, Room: 101},
+	{ID: 4, GroupID: 20, SubjectID: 1, LessonTypeID: 1, Week: 2, Weekday: 1, Pair: 1, Room: 101},
+	{ID: 5, GroupID: 30, SubjectID: 2, LessonTypeID: 1, Week: 1, Weekday: 1, Pair: 2, Room: 214, EmployeeID: teacher(7)},
+}
+
+func TestDetect(t *testing.T) {
+	got := schedule.Detect(lessons)
+	want := []schedule.Conflict{
+		{Kind: schedule.KindRoom, LessonID: 1, OtherID: 2, Week: 1, Weekday: 1, Pair: 1, Room: 101},
+		{Kind: schedule.KindGroup, LessonID: 1, OtherID: 3, Week: 1, Weekday: 1, Pair: 1, GroupID: 10},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	// a new lesson booking both room 214 and group 20 at once
+	lsn := domain.Lesson{GroupID: 20, SubjectID: 2, LessonTypeID: 1, Week: 1, Weekday: 1, Pair: 1, Room: 214}
+	got := schedule.Check(lsn, lessons)
+	want := []schedule.Conflict{
+		{Kind: schedule.KindRoom, OtherID: 3, Week: 1, Weekday: 1, Pair: 1, Room: 214},
+		{Kind: schedule.KindGroup, OtherID: 2, Week: 1, Weekday: 1, Pair: 1, GroupID: 20},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	// an update doesn't clash with the lesson it replaces
+	moved := lessons[3]
+	if got := schedule.Check(moved, lessons); len(got) != 0 {
+		t.Fatalf("got conflicts %+v for an unchanged lesson", got)
+	}
+	// moved next to lesson 1 in room 101 and to lesson 2 of the same group in it
+	moved.Week = 1
+	if got := schedule.Check(moved, lessons); len(got) != 3 {
+		t.Fatalf("got %d conflicts, want 3", len(got))
+	}
+}
+
+func TestCheckTeacher(t *testing.T) {
+	// lesson 5 moved into the first pair, its teacher already gives lesson 3
+	lsn := lessons[4]
+	lsn.Pair, lsn.Room = 1, 305
+	got := schedule.Check(lsn, lessons)
+	want := []schedule.Conflict{
+		{Kind: schedule.KindTeacher, LessonID: 5, OtherID: 3, Week: 1, Weekday: 1, Pair: 1, EmployeeID: 7},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	// lessons without a teacher don't clash by teacher
+	lsn.EmployeeID = nil
+	if got := schedule.Check(lsn, lessons); len(got) != 0 {
+		t.Fatalf("got conflicts %+v for a lesson without a teacher", got)
+	}
+}
+
+func TestParseBells(t *testing.T) {
+	bells, err := schedule.ParseBells(schedule.DefaultBells)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(bells) != 6 {
+		t.Fatalf("got %d pairs, want 6", len(bells))
+	}
+	if got := bells.String(); got != schedule.DefaultBells {
+		t.Fatalf("got %q, want %q", got, schedule.DefaultBells)
+	}
+	if got := bells.Time(2); got != "09:45-11:20" {
+		t.Fatalf("got time %q of pair 2", got)
+	}
+	if _, ok := bells.Bell(7); ok {
+		t.Fatal("pair 7 is outside the default schedule")
+	}
+
+	for _, s := range []string{"", "08:00", "08:00-07:00", "08:00-09:35,09:00-10:35", "8-9"} {
+		if _, err := schedule.ParseBells(s); err == nil {
+			t.Errorf("%q parsed without an error", s)
+		}
+	}
+}
+
+func TestConflictError(t *testing.T) {
+	var err error = &schedule.ConflictError{Conflicts: schedule.Detect(lessons)}
+	if !errors.Is(err, schedule.ErrConflict) {
+		t.Fatalf("%v doesn't match ErrConflict", err)
+	}
+}
@@ -0,0 +1,243 @@
+package seeder
+
+import (
+	"context"
+	"strconv"
+	"time"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+)
+
+// generated lessons and marks have no natural key of their own,
+// they match stored ones with the same references
+
+type lessonKey struct {
+	group, subject, lessonType uint64
+	week, weekday              uint16
+}
+
+type markKey struct {
+	student, subject uint64
+	date             time.Time
+}
+
+// writes the records of the plan missing from r
+func (p *plan) apply(ctx context.Context, r *repository.Repository) (Stats, error) {
+	var stats Stats
+	all := repository.ListOptions{Deleted: repository.IncludeDeleted}
+
+	storedPositions, err := r.Positions.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	positionIDs, err := ensure(&stats, "positions", p.positions,
+		func(pos domain.Position) string { return pos.Name },
+		index(storedPositions, func(pos domain.Position) (string, uint64) { return pos.Name, pos.ID }),
+		func(pos domain.Position) (uint64, error) {
+			return r.Positions.Create(ctx, pos)
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	storedEmployees, err := r.Employees.List(ctx, all)
+	if err != nil {
+		return nil, err
+	}
+	employeeIDs, err := ensure(&stats, "employees", p.employees,
+		func(emp employee) string { return emp.passport },
+		index(storedEmployees.Items, func(emp domain.Employee) (string, uint64) { return emp.Passport, emp.ID }),
+		func(emp employee) (uint64, error) {
+			return r.Employees.Create(ctx, domain.Employee{Name: emp.name, Passport: emp.passport, PositionID: positionIDs[emp.position]})
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	storedGroups, err := r.Groups.List(ctx, all)
+	if err != nil {
+		return nil, err
+	}
+	groupIDs, err := ensure(&stats, "groups", p.groups,
+		func(number uint64) string { return strconv.FormatUint(number, 10) },
+		index(storedGroups.Items, func(grp domain.Group) (string, uint64) { return strconv.FormatUint(grp.Number, 10), grp.ID }),
+		func(number uint64) (uint64, error) {
+			return r.Groups.Create(ctx, domain.Group{Number: number})
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	storedStudents, err := r.Students.List(ctx, all)
+	if err != nil {
+		return nil, err
+	}
+	studentIDs, err := ensure(&stats, "students", p.students,
+		func(stud student) string { return stud.passport },
+		index(storedStudents.Items, func(stud domain.Student) (string, uint64) { return stud.Passport, stud.ID }),
+		func(stud student) (uint64, error) {
+			return r.Students.Create(ctx, domain.Student{
+				Name:       stud.name,
+				Passport:   stud.passport,
+				EmployeeID: employeeIDs[stud.curator],
+				GroupID:    groupIDs[stud.group],
+			})
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	storedSubjects, err := r.Subjects.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	subjectIDs, err := ensure(&stats, "subjects", p.subjects,
+		func(sbj [2]string) string { return sbj[0] },
+		index(storedSubjects, func(sbj domain.Subject) (string, uint64) { return sbj.Name, sbj.ID }),
+		func(sbj [2]string) (uint64, error) {
+			return r.Subjects.Create(ctx, domain.Subject{Name: sbj[0], Description: sbj[1]})
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	storedLessonTypes, err := r.LessonTypes.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	lessonTypeIDs, err := ensure(&stats, "lesson_types", p.lessonTypes,
+		func(name string) string { return name },
+		index(storedLessonTypes, func(lt domain.LessonType) (string, uint64) { return lt.Name, lt.ID }),
+		func(name string) (uint64, error) {
+			return r.LessonTypes.Create(ctx, domain.LessonType{Name: name})
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	storedAssignments, err := r.EmployeesSubjects.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	assigned := set(storedAssignments)
+	count := Count{Table: "employees_subjects"}
+	for _, a := range p.assignments {
+		es := domain.EmployeeSubject{EmployeeID: employeeIDs[a.employee], SubjectID: subjectIDs[a.subject]}
+		if assigned[es] {
+			count.Existing++
+			continue
+		}
+		if err = r.EmployeesSubjects.Create(ctx, es); err != nil {
+			return nil, err
+		}
+		count.Created++
+	}
+	stats = append(stats, count)
+
+	storedLessons, err := r.Lessons.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	scheduled := map[lessonKey]bool{}
+	for _, lsn := range storedLessons {
+		scheduled[lessonKey{lsn.GroupID, lsn.SubjectID, lsn.LessonTypeID, lsn.Week, lsn.Weekday}] = true
+	}
+	count = Count{Table: "lessons"}
+	for _, l := range p.lessons {
+		lsn := domain.Lesson{
+			GroupID:      groupIDs[l.group],
+			SubjectID:    subjectIDs[l.subject],
+			LessonTypeID: lessonTypeIDs[l.lessonType],
+			Week:         l.week,
+			Weekday:      l.weekday,
+			Pair:         l.pair,
+			Room:         l.room,
+		}
+		if l.teacher >= 0 {
+			lsn.EmployeeID = &employeeIDs[l.teacher]
+		}
+		if scheduled[lessonKey{lsn.GroupID, lsn.SubjectID, lsn.LessonTypeID, lsn.Week, lsn.Weekday}] {
+			count.Existing++
+			continue
+		}
+		if _, err = r.Lessons.Create(ctx, lsn); err != nil {
+			return nil, err
+		}
+		count.Created++
+	}
+	stats = append(stats, count)
+
+	storedMarks, err := r.Marks.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	given := map[markKey]bool{}
+	for _, mark := range storedMarks {
+		given[markKey{mark.StudentID, mark.SubjectID, mark.Date}] = true
+	}
+	count = Count{Table: "marks"}
+	var marks []domain.Mark
+	for _, m := range p.marks {
+		mark := domain.Mark{
+			EmployeeID: employeeIDs[m.employee],
+			StudentID:  studentIDs[m.student],
+			SubjectID:  subjectIDs[m.subject],
+			Mark:       m.mark,
+			Date:       m.date,
+		}
+		if given[markKey{mark.StudentID, mark.SubjectID, mark.Date}] {
+			count.Existing++
+			continue
+		}
+		marks = append(marks, mark)
+	}
+	if len(marks) > 0 {
+		if _, err = r.Marks.CopyFrom(ctx, marks); err != nil {
+			return nil, err
+		}
+	}
+	count.Created = len(marks)
+	stats = append(stats, count)
+
+	return stats, nil
+}
+
+// returns the ids of items in order, creating the ones whose key isn't stored yet
+func ensure[T any](stats *Stats, table string, items []T, key func(T) string, stored map[string]uint64, create func(T) (uint64, error)) ([]uint64, error) {
+	count := Count{Table: table}
+	ids := make([]uint64, len(items))
+	for i, item := range items {
+		if id, ok := stored[key(item)]; ok {
+			ids[i] = id
+			count.Existing++
+			continue
+		}
+
+		id, err := create(item)
+		if err != nil {
+			return nil, err
+		}
+		ids[i] = id
+		count.Created++
+	}
+	*stats = append(*stats, count)
+	return ids, nil
+}
+
+// maps the keys of stored records to their ids
+func index[T any](items []T, entry func(T) (string, uint64)) map[string]uint64 {
+	result := make(map[string]uint64, len(items))
+	for _, item := range items {
+		key, id := entry(item)
+		result[key] = id
+	}
+	return result
+}
+
+func set[T comparable](items []T) map[T]bool {
+	result := make(map[T]bool, len(items))
+	for _, item := range items {
+		result[item] = true
+	}
+	return result
+}
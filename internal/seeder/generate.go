@@ -0,0 +1,300 @@
+package seeder
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+	"university-db-admin/internal/domain"
+)
+
+// records reference each other by their index in the plan,
+// ids are only known once the records are written
+
+type employee struct {
+	name, passport string
+	position       int
+}
+
+type student struct {
+	name, passport string
+	curator        int // employee
+	group          int
+}
+
+type assignment struct {
+	employee, subject int
+}
+
+type lesson struct {
+	group, subject, lessonType int
+	teacher                    int // employee, -1 for a lesson without one
+	week, weekday, pair        uint16
+	room                       uint64
+}
+
+type mark struct {
+	employee, student, subject int
+	mark                       uint16
+	date                       time.Time
+}
+
+// plan is the complete generated dataset
+type plan struct {
+	positions   []domain.Position
+	employees   []employee // teachers come first
+	groups      []uint64
+	students    []student
+	subjects    [][2]string
+	lessonTypes []string
+	assignments []assignment
+	lessons     []lesson
+	marks       []mark
+}
+
+// first day of the semester the marks are given in
+var semesterStart = time.Date(2024, time.September, 2, 0, 0, 0, 0, time.UTC)
+
+// working days of a sixteen week semester
+const semesterDays = 16 * 6
+
+// lessons take the first pairs of the day
+const pairsPerDay = 4
+
+// booking is a weekday and pair a group, room or teacher is busy in,
+// generated lessons repeat in it the whole semester
+type booking struct {
+	resource string // group, room or teacher
+	id       int
+	weekday  uint16
+	pair     uint16
+}
+
+type generator struct {
+	rnd       *rand.Rand
+	passports map[string]bool
+	busy      map[booking]bool
+}
+
+func generate(preset Preset, seed uint64) *plan {
+	g := &generator{
+		rnd:       rand.New(rand.NewPCG(seed, seed^0x5eed)),
+		passports: map[string]bool{},
+		busy:      map[booking]bool{},
+	}
+	p := &plan{
+		positions:   positions,
+		subjects:    subjects[:min(preset.Subjects, len(subjects))],
+		lessonTypes: lessonTypes,
+	}
+
+	for range preset.Teachers {
+		name := g.person()
+		p.employees = append(p.employees, employee{name: name, passport: g.passport(), position: 0})
+	}
+	for range preset.Staff {
+		name := g.person()
+		position := 1 + g.rnd.IntN(len(positions)-1)
+		p.employees = append(p.employees, employee{name: name, passport: g.passport(), position: position})
+	}
+
+	// every subject has one to three teachers and every teacher
+	// at least one subject while there are enough subjects
+	teachers := make([][]int, len(p.subjects))
+	for s := range p.subjects {
+		teachers[s] = []int{s % preset.Teachers}
+	}
+	for t := len(p.subjects); t < preset.Teachers; t++ {
+		s := g.rnd.IntN(len(p.subjects))
+		teachers[s] = append(teachers[s], t)
+	}
+	for s := range p.subjects {
+		for len(teachers[s]) < 3 && g.rnd.IntN(3) == 0 {
+			t := g.rnd.IntN(preset.Teachers)
+			if !slices.Contains(teachers[s], t) {
+				teachers[s] = append(teachers[s], t)
+			}
+		}
+		for _, t := range teachers[s] {
+			p.assignments = append(p.assignments, assignment{employee: t, subject: s})
+		}
+	}
+
+	numbers := map[uint64]bool{}
+	for gr := range preset.Groups {
+		p.groups = append(p.groups, g.groupNumber(numbers))
+		curator := gr % preset.Teachers
+
+		studied := g.groupSubjects(len(p.subjects), preset.GroupSubjects)
+		first := len(p.students)
+		for range preset.StudentsPerGroup {
+			name := g.person()
+			p.students = append(p.students, student{name: name, passport: g.passport(), curator: curator, group: gr})
+		}
+
+		for _, s := range studied {
+			p.lessons = append(p.lessons, g.lessons(gr, s, teachers[s], preset.Weeks)...)
+		}
+
+		for st := first; st < len(p.students); st++ {
+			// a student's marks gather around their own level
+			level := 4 + g.rnd.Float64()*5
+			for _, s := range studied {
+				teacher := teachers[s][g.rnd.IntN(len(teachers[s]))]
+				days := g.rnd.Perm(semesterDays)[:preset.MarksPerSubject]
+				for _, d := range days {
+					p.marks = append(p.marks, mark{
+						employee: teacher,
+						student:  st,
+						subject:  s,
+						mark:     g.mark(level),
+						date:     studyDay(d),
+					})
+				}
+			}
+		}
+	}
+
+	return p
+}
+
+// full name in the surname, name, patronymic order
+func (g *generator) person() string {
+	surname := surnames[g.rnd.IntN(len(surnames))]
+	patronymic := patronymics[g.rnd.IntN(len(patronymics))]
+	if g.rnd.IntN(2) == 0 {
+		return surname + " " + maleNames[g.rnd.IntN(len(maleNames))] + " " + patronymic[0]
+	}
+	return feminine(surname) + " " + femaleNames[g.rnd.IntN(len(femaleNames))] + " " + patronymic[1]
+}
+
+func feminine(surname string) string {
+	if base, ok := strings.CutSuffix(surname, "ский"); ok {
+		return base + "ская"
+	}
+	return surname + "а"
+}
+
+// a passport number not handed out before: series and seven digits
+func (g *generator) passport() string {
+	for {
+		passport := fmt.Sprintf("%s%07d", passportSeries[g.rnd.IntN(len(passportSeries))], 1+g.rnd.IntN(9_999_999))
+		if !g.passports[passport] {
+			g.passports[passport] = true
+			return passport
+		}
+	}
+}
+
+// group numbers are made of the faculty, the speciality, the year of
+// admission and the number of the group, e.g. 153502
+func (g *generator) groupNumber(taken map[uint64]bool) uint64 {
+	for {
+		faculty := 1 + g.rnd.Uint64N(7)
+		speciality := g.rnd.Uint64N(10)
+		year := 1 + g.rnd.Uint64N(4)
+		number := faculty*100_000 + speciality*10_000 + year*1_000 + 500 + 1 + g.rnd.Uint64N(9)
+		if !taken[number] {
+			taken[number] = true
+			return number
+		}
+	}
+}
+
+// the common subjects followed by random others
+func (g *generator) groupSubjects(total, n int) []int {
+	n = min(n, total)
+	common := min(3, n)
+	studied := make([]int, 0, n)
+	for s := range common {
+		studied = append(studied, s)
+	}
+	for _, s := range g.rnd.Perm(total - common)[:n-common] {
+		studied = append(studied, common+s)
+	}
+	return studied
+}
+
+// a lecture every week and practice or laboratory work every other week,
+// the weekday, pair, room and teacher stay the same all semester
+func (g *generator) lessons(group, subject int, teachers []int, weeks uint16) []lesson {
+	lecture := lesson{group: group, subject: subject, lessonType: 0}
+	g.book(&lecture, teachers, func() uint64 { return uint64(100*(1+g.rnd.IntN(5)) + 1 + g.rnd.IntN(3)) })
+	class := lesson{group: group, subject: subject, lessonType: 1 + g.rnd.IntN(2)}
+	g.book(&class, teachers, func() uint64 { return uint64(100*(1+g.rnd.IntN(5)) + 10 + g.rnd.IntN(30)) })
+	offset := uint16(g.rnd.IntN(2))
+
+	var result []lesson
+	for week := uint16(1); week <= weeks; week++ {
+		lecture.week = week
+		result = append(result, lecture)
+		if week%2 == offset {
+			class.week = week
+			result = append(result, class)
+		}
+	}
+	return result
+}
+
+// picks a weekday, pair, room and teacher of the subject for lsn none of
+// them is busy in. A group always has a free pair, when every teacher of
+// the subject is busy whenever the group and a room are free the lesson
+// is left without a teacher
+func (g *generator) book(lsn *lesson, teachers []int, room func() uint64) {
+	free := func(resource string, id int, weekday, pair uint16) bool {
+		return !g.busy[booking{resource, id, weekday, pair}]
+	}
+
+	fallback, fallbackRoom := -1, room()
+	for _, n := range g.rnd.Perm(6 * pairsPerDay) {
+		weekday, pair := uint16(1+n/pairsPerDay), uint16(1+n%pairsPerDay)
+		if !free("group", lsn.group, weekday, pair) {
+			continue
+		}
+
+		r := room()
+		if !free("room", int(r), weekday, pair) {
+			continue
+		}
+		if fallback < 0 {
+			fallback, fallbackRoom = n, r
+		}
+
+		for _, i := range g.rnd.Perm(len(teachers)) {
+			if free("teacher", teachers[i], weekday, pair) {
+				lsn.weekday, lsn.pair, lsn.room, lsn.teacher = weekday, pair, r, teachers[i]
+				g.reserve(*lsn)
+				return
+			}
+		}
+	}
+
+	if fallback < 0 {
+		// no pair has both the group and a room free, the lesson clashes
+		fallback = g.rnd.IntN(6 * pairsPerDay)
+	}
+	lsn.weekday, lsn.pair = uint16(1+fallback/pairsPerDay), uint16(1+fallback%pairsPerDay)
+	lsn.room, lsn.teacher = fallbackRoom, -1
+	g.reserve(*lsn)
+}
+
+// marks the group, room and teacher of lsn busy in its pair
+func (g *generator) reserve(lsn lesson) {
+	g.busy[booking{"group", lsn.group, lsn.weekday, lsn.pair}] = true
+	g.busy[booking{"room", int(lsn.room), lsn.weekday, lsn.pair}] = true
+	if lsn.teacher >= 0 {
+		g.busy[booking{"teacher", lsn.teacher, lsn.weekday, lsn.pair}] = true
+	}
+}
+
+// a mark near level within the 1 to 10 range
+func (g *generator) mark(level float64) uint16 {
+	m := int(level + g.rnd.NormFloat64()*1.5 + 0.5)
+	return uint16(max(1, min(10, m)))
+}
+
+// the n-th working day of the semester, Sundays are skipped
+func studyDay(n int) time.Time {
+	return semesterStart.AddDate(0, 0, n/6*7+n%6)
+}
@@ -0,0 +1,114 @@
+package seeder
+
+import "university-db-admin/internal/domain"
+
+// surnames in the masculine form, feminine() derives the other one
+var surnames = []string{
+	"Иванов", "Смирнов", "Кузнецов", "Попов", "Васильев", "Петров", "Соколов",
+	"Михайлов", "Новиков", "Фёдоров", "Морозов", "Волков", "Алексеев", "Лебедев",
+	"Семёнов", "Егоров", "Павлов", "Козлов", "Степанов", "Николаев", "Орлов",
+	"Андреев", "Макаров", "Никитин", "Захаров", "Зайцев", "Соловьёв", "Борисов",
+	"Яковлев", "Григорьев", "Романов", "Воробьёв", "Сергеев", "Кузьмин", "Фролов",
+	"Александров", "Дмитриев", "Королёв", "Гусев", "Киселёв", "Ильин", "Максимов",
+	"Поляков", "Сорокин", "Виноградов", "Ковалёв", "Белов", "Медведев", "Антонов",
+	"Тарасов", "Жуков", "Баранов", "Филиппов", "Комаров", "Давыдов", "Беляев",
+	"Герасимов", "Богданов", "Осипов", "Сидоров", "Матвеев", "Титов", "Марков",
+	"Миронов", "Крылов", "Куликов", "Карпов", "Власов", "Мельников", "Денисов",
+	"Гаврилов", "Тихонов", "Казаков", "Афанасьев", "Данилов", "Савельев",
+	"Тимофеев", "Фомин", "Чернов", "Абрамов", "Мартынов", "Ефимов", "Федотов",
+	"Щербаков", "Назаров", "Калинин", "Исаев", "Чернышёв", "Быков", "Маслов",
+	"Родионов", "Коновалов", "Лазарев", "Воронин", "Климов", "Филатов",
+	"Пономарёв", "Голубев", "Кудрявцев", "Прохоров", "Наумов", "Потапов",
+	"Журавлёв", "Овчинников", "Трофимов", "Леонов", "Соболев", "Ермаков",
+	"Колесников", "Гончаров", "Емельянов", "Никифоров", "Грачёв", "Котов",
+	"Гришин", "Ефремов", "Архипов", "Громов", "Кириллов", "Малышев", "Панов",
+	"Моисеев", "Румянцев", "Акимов", "Кондратьев", "Бирюков", "Горбунов",
+	"Анисимов", "Еремин", "Тихомиров", "Галкин", "Лукьянов", "Михеев",
+	"Скворцов", "Юдин", "Белоусов", "Нестеров", "Симонов", "Прокофьев",
+	"Харитонов", "Князев", "Цветков", "Левин", "Митрофанов", "Воронов",
+	"Аксёнов", "Софронов", "Мальцев", "Логинов", "Горшков", "Савин",
+	"Краснов", "Майоров", "Демидов", "Елисеев", "Рыбаков", "Сафонов",
+	"Плотников", "Дёмин", "Хохлов", "Жданов", "Шестаков", "Ширяев",
+	"Жаров", "Ситников", "Шубин", "Виноградский", "Островский", "Ковальский",
+}
+
+var maleNames = []string{
+	"Александр", "Алексей", "Андрей", "Антон", "Артём", "Борис", "Вадим",
+	"Валентин", "Василий", "Виктор", "Виталий", "Владимир", "Владислав",
+	"Всеволод", "Геннадий", "Георгий", "Глеб", "Григорий", "Даниил", "Денис",
+	"Дмитрий", "Евгений", "Егор", "Иван", "Игорь", "Илья", "Кирилл",
+	"Константин", "Лев", "Леонид", "Максим", "Марк", "Матвей", "Михаил",
+	"Никита", "Николай", "Олег", "Павел", "Пётр", "Роман", "Сергей",
+	"Станислав", "Степан", "Тимофей", "Фёдор", "Юрий", "Ярослав",
+}
+
+var femaleNames = []string{
+	"Алёна", "Алина", "Алиса", "Анастасия", "Анна", "Валерия", "Вера",
+	"Вероника", "Виктория", "Галина", "Дарья", "Диана", "Ева", "Евгения",
+	"Екатерина", "Елена", "Елизавета", "Жанна", "Зоя", "Ирина", "Кира",
+	"Ксения", "Лариса", "Любовь", "Людмила", "Маргарита", "Марина", "Мария",
+	"Надежда", "Наталья", "Нина", "Оксана", "Ольга", "Полина", "Светлана",
+	"София", "Тамара", "Татьяна", "Ульяна", "Юлия", "Яна",
+}
+
+// patronymics derived from the father's name, masculine and feminine
+var patronymics = [][2]string{
+	{"Александрович", "Александровна"}, {"Алексеевич", "Алексеевна"},
+	{"Андреевич", "Андреевна"}, {"Антонович", "Антоновна"},
+	{"Борисович", "Борисовна"}, {"Викторович", "Викторовна"},
+	{"Владимирович", "Владимировна"}, {"Геннадьевич", "Геннадьевна"},
+	{"Георгиевич", "Георгиевна"}, {"Григорьевич", "Григорьевна"},
+	{"Дмитриевич", "Дмитриевна"}, {"Евгеньевич", "Евгеньевна"},
+	{"Иванович", "Ивановна"}, {"Игоревич", "Игоревна"},
+	{"Ильич", "Ильинична"}, {"Константинович", "Константиновна"},
+	{"Леонидович", "Леонидовна"}, {"Максимович", "Максимовна"},
+	{"Михайлович", "Михайловна"}, {"Николаевич", "Николаевна"},
+	{"Олегович", "Олеговна"}, {"Павлович", "Павловна"},
+	{"Петрович", "Петровна"}, {"Романович", "Романовна"},
+	{"Сергеевич", "Сергеевна"}, {"Степанович", "Степановна"},
+	{"Юрьевич", "Юрьевна"}, {"Ярославович", "Ярославовна"},
+}
+
+// letters of passport series
+var passportSeries = []string{"AB", "BM", "HB", "KH", "MC", "MP", "KB"}
+
+// positions created by the seeder, teachers hold the first one
+// and the other staff the rest
+var positions = []domain.Position{
+	{Name: "Преподаватель", CanTeach: true, CanCurate: true},
+	{Name: "Ассистент"},
+	{Name: "Заведующий кафедрой", CanCurate: true, CanAdminister: true},
+	{Name: "Лаборант"},
+	{Name: "Методист", CanAdminister: true},
+}
+
+// the first subjects are the ones every group studies
+var subjects = [][2]string{
+	{"Высшая математика", "Линейная алгебра, аналитическая геометрия и математический анализ"},
+	{"Программирование", "Основы алгоритмизации и программирования на языке высокого уровня"},
+	{"Физика", "Механика, электричество и магнетизм, оптика"},
+	{"Базы данных", "Реляционная модель, проектирование схем и язык SQL"},
+	{"Иностранный язык", "Профессионально ориентированный английский язык"},
+	{"Дискретная математика", "Множества, графы, булевы функции и комбинаторика"},
+	{"Операционные системы", "Процессы, потоки, память и файловые системы"},
+	{"Компьютерные сети", "Модель OSI, стек TCP/IP и маршрутизация"},
+	{"Теория вероятностей", "Случайные величины, распределения и математическая статистика"},
+	{"Объектно-ориентированное программирование", "Классы, наследование, полиморфизм и шаблоны проектирования"},
+	{"Архитектура компьютеров", "Устройство процессора, памяти и систем ввода-вывода"},
+	{"Философия", "История философской мысли и основы логики"},
+	{"Экономика", "Микро- и макроэкономика, основы предпринимательства"},
+	{"Схемотехника", "Аналоговые и цифровые электронные схемы"},
+	{"Численные методы", "Приближённое решение уравнений и интерполяция"},
+	{"Защита информации", "Криптография, аутентификация и управление доступом"},
+	{"Web-технологии", "HTML, CSS, JavaScript и разработка серверных приложений"},
+	{"Компьютерная графика", "Растровая и векторная графика, трёхмерная визуализация"},
+	{"Машинное обучение", "Регрессия, классификация, кластеризация и нейронные сети"},
+	{"Тестирование программного обеспечения", "Методы и автоматизация тестирования"},
+	{"Системное программирование", "Взаимодействие программ с ядром операционной системы"},
+	{"Физическая культура", "Общая физическая подготовка"},
+	{"История", "История Беларуси в контексте мировой цивилизации"},
+	{"Инженерная графика", "Чертежи, проекции и системы автоматизированного проектирования"},
+}
+
+// lecture, practice and laboratory work
+var lessonTypes = []string{"ЛК", "ПЗ", "ЛР"}
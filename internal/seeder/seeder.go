@@ -0,0 +1,114 @@
+// Package seeder fills a database with generated but plausible data: a
+// faculty with teachers, groups of students, a weekly schedule and marks.
+//
+// The data is derived only from the preset and the seed, so running the
+// seeder again with the same options finds every record in place and adds
+// nothing. Records are matched by their natural keys: names, passports,
+// group numbers and, for lessons and marks, the combination of their
+// references.
+package seeder
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"university-db-admin/internal/repository"
+)
+
+// Preset sets the size of the generated faculty
+type Preset struct {
+	Name             string
+	Teachers         int // employees with the teaching position
+	Staff            int // employees with other positions
+	Groups           int
+	StudentsPerGroup int
+	Subjects         int // subjects taught in the faculty
+	GroupSubjects    int // subjects studied by each group
+	Weeks            uint16
+	MarksPerSubject  int // marks of a student in each subject
+}
+
+var Presets = map[string]Preset{
+	"small": {
+		Name:             "small",
+		Teachers:         5,
+		Staff:            3,
+		Groups:           3,
+		StudentsPerGroup: 8,
+		Subjects:         6,
+		GroupSubjects:    4,
+		Weeks:            2,
+		MarksPerSubject:  2,
+	},
+	"medium": {
+		Name:             "medium",
+		Teachers:         15,
+		Staff:            6,
+		Groups:           8,
+		StudentsPerGroup: 20,
+		Subjects:         12,
+		GroupSubjects:    6,
+		Weeks:            4,
+		MarksPerSubject:  3,
+	},
+	"large": {
+		Name:             "large",
+		Teachers:         40,
+		Staff:            15,
+		Groups:           30,
+		StudentsPerGroup: 25,
+		Subjects:         len(subjects),
+		GroupSubjects:    8,
+		Weeks:            4,
+		MarksPerSubject:  4,
+	},
+}
+
+// names of the presets from the smallest to the largest
+func PresetNames() []string {
+	names := make([]string, 0, len(Presets))
+	for name := range Presets {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return Presets[names[i]].Groups*Presets[names[i]].StudentsPerGroup <
+			Presets[names[j]].Groups*Presets[names[j]].StudentsPerGroup
+	})
+	return names
+}
+
+// Count tells how many generated records of a table were added
+// and how many were already there
+type Count struct {
+	Table    string
+	Created  int
+	Existing int
+}
+
+type Stats []Count
+
+func (s Stats) String() string {
+	var b strings.Builder
+	for _, c := range s {
+		fmt.Fprintf(&b, "%-20s %6d created %6d existing\n", c.Table, c.Created, c.Existing)
+	}
+	return b.String()
+}
+
+// Seed generates the data of the preset from seed and writes the records
+// missing from r in a single transaction
+func Seed(ctx context.Context, r *repository.Repository, preset Preset, seed uint64) (Stats, error) {
+	p := generate(preset, seed)
+
+	var stats Stats
+	err := r.WithTx(ctx, func(ctx context.Context, tx *repository.Repository) error {
+		var err error
+		stats, err = p.apply(ctx, tx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,64 @@
+package seeder_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"university-db-admin/internal/backup"
+	"university-db-admin/internal/repository"
+	"university-db-admin/internal/repository/memory"
+	"university-db-admin/internal/seeder"
+)
+
+func seed(t *testing.T, r *repository.Repository, seed uint64) seeder.Stats {
+	t.Helper()
+	stats, err := seeder.Seed(context.Background(), r, seeder.Presets["small"], seed)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return stats
+}
+
+func dump(t *testing.T, r *repository.Repository) *backup.Data {
+	t.Helper()
+	d, err := backup.Dump(context.Background(), r)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return d
+}
+
+// seeding again with the same options finds everything in place
+func TestSeedIsIdempotent(t *testing.T) {
+	r := memory.NewRepository()
+	for _, c := range seed(t, r, 1) {
+		if c.Created == 0 || c.Existing != 0 {
+			t.Errorf("%s: got %d created and %d existing on an empty database", c.Table, c.Created, c.Existing)
+		}
+	}
+	first := dump(t, r)
+
+	for _, c := range seed(t, r, 1) {
+		if c.Created != 0 {
+			t.Errorf("%s: got %d records created by seeding again", c.Table, c.Created)
+		}
+	}
+	if !reflect.DeepEqual(dump(t, r), first) {
+		t.Fatal("seeding again changed the database")
+	}
+}
+
+// the data only depends on the preset and the seed
+func TestSeedIsDeterministic(t *testing.T) {
+	a, b, c := memory.NewRepository(), memory.NewRepository(), memory.NewRepository()
+	seed(t, a, 1)
+	seed(t, b, 1)
+	seed(t, c, 2)
+
+	if !reflect.DeepEqual(dump(t, a), dump(t, b)) {
+		t.Fatal("the same seed generated different data")
+	}
+	if reflect.DeepEqual(dump(t, a), dump(t, c)) {
+		t.Fatal("different seeds generated the same data")
+	}
+}
@@ -0,0 +1,15 @@
+package service
+
+import "university-db-admin/internal/repository"
+
+// AuditService gives read access to the change log, entries are
+// written by the database itself on every change
+type AuditService struct {
+	repository.Audit
+}
+
+func NewAuditService(r *repository.Repository) *AuditService {
+	return &AuditService{
+		Audit: r.Audit,
+	}
+}
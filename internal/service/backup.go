@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"university-db-admin/internal/backup"
+	"university-db-admin/internal/repository"
+)
+
+// BackupService saves the whole database to an archive and loads it back
+type BackupService struct {
+	repo *repository.Repository
+}
+
+func NewBackupService(r *repository.Repository) *BackupService {
+	return &BackupService{
+		repo: r,
+	}
+}
+
+// writes every record, trash included, to an archive at path
+func (s *BackupService) Create(ctx context.Context, path string) (backup.Manifest, error) {
+	return backup.WriteFile(ctx, s.repo, path)
+}
+
+// restores the archive at path into an empty database or in place of its
+// records, the archive is checked against its manifest before anything is written
+func (s *BackupService) Restore(ctx context.Context, path string, opts backup.RestoreOptions) (backup.Manifest, error) {
+	d, m, err := backup.ReadFile(path)
+	if err != nil {
+		return backup.Manifest{}, err
+	}
+	if err = backup.Restore(ctx, s.repo, d, opts); err != nil {
+		return backup.Manifest{}, err
+	}
+	return m, nil
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"log"
+	"strings"
+	"time"
+	"university-db-admin/internal/history"
+	"university-db-admin/internal/repository"
+	"university-db-admin/pkg/dbclient"
+	"university-db-admin/pkg/validation"
+)
+
+const (
+	// rows of a console result read from the database, the rest are dropped
+	consoleRowLimit = 1000
+	// statements kept in the history of an operator
+	consoleHistoryLimit = 200
+)
+
+// ConsoleService runs statements typed by the operator and remembers them
+type ConsoleService struct {
+	repository.Console
+	history *history.Store // nil when there's no place to keep it
+}
+
+func NewConsoleService(r *repository.Repository) *ConsoleService {
+	s := &ConsoleService{
+		Console: r.Console,
+	}
+	if dir, err := history.DefaultDir(); err == nil {
+		s.history = history.NewStore(dir, consoleHistoryLimit)
+	}
+	return s
+}
+
+// ConsoleRun is the result of a statement with the time it took
+type ConsoleRun struct {
+	repository.ConsoleResult
+	Duration time.Duration
+}
+
+// runs statement, in a read-only transaction unless write is set,
+// and adds it to the history of the operator of ctx
+func (s *ConsoleService) Run(ctx context.Context, statement string, write bool) (ConsoleRun, error) {
+	statement = strings.TrimSpace(statement)
+	if err := validation.ValidateEmptyStrings(statement); err != nil {
+		return ConsoleRun{}, err
+	}
+
+	start := time.Now()
+	res, err := s.Console.Exec(ctx, statement, write, consoleRowLimit)
+	run := ConsoleRun{ConsoleResult: res, Duration: time.Since(start)}
+
+	// failed statements are kept too, they are usually the ones to be fixed
+	if s.history != nil {
+		entry := history.Entry{Statement: statement, Write: write, RanAt: start}
+		if herr := s.history.Add(dbclient.Operator(ctx), entry); herr != nil {
+			log.Println("cant save console history:", herr)
+		}
+	}
+	return run, err
+}
+
+// statements run by the operator of ctx, the latest first
+func (s *ConsoleService) History(ctx context.Context) ([]history.Entry, error) {
+	if s.history == nil {
+		return nil, nil
+	}
+	return s.history.List(dbclient.Operator(ctx))
+}
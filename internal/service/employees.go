@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+	"university-db-admin/pkg/validation"
+)
+
+type EmployeesService struct {
+	repository.Employees
+	repo *repository.Repository
+}
+
+func NewEmployeesService(r *repository.Repository) *EmployeesService {
+	return &EmployeesService{
+		Employees: r.Employees,
+		repo:      r,
+	}
+}
+
+func (s *EmployeesService) Create(ctx context.Context, emp domain.Employee) (uint64, error) {
+	if err := validation.ValidateStruct(emp); err != nil {
+		return 0, err
+	}
+	return s.Employees.Create(ctx, emp)
+}
+
+// creates all employees in one transaction or none of them
+func (s *EmployeesService) CreateMany(ctx context.Context, emps []domain.Employee) error {
+	if err := validateBatch(emps); err != nil {
+		return err
+	}
+
+	return s.repo.WithTx(ctx, func(ctx context.Context, tx *repository.Repository) error {
+		return insertBatch(ctx, emps, tx.Employees.CopyFrom, tx.Employees.Create)
+	})
+}
+
+func (s *EmployeesService) Update(ctx context.Context, id uint64, emp domain.Employee) error {
+	if err := validation.ValidatePositiveNumbers(id); err != nil {
+		return err
+	}
+	if err := validation.ValidateStruct(emp); err != nil {
+		return err
+	}
+	return s.Employees.Update(ctx, id, emp)
+}
+
+func (s *EmployeesService) Delete(ctx context.Context, id uint64) error {
+	if err := validation.ValidatePositiveNumbers(id); err != nil {
+		return err
+	}
+	return s.Employees.Delete(ctx, id)
+}
+
+func (s *EmployeesService) Restore(ctx context.Context, id uint64) error {
+	if err := validation.ValidatePositiveNumbers(id); err != nil {
+		return err
+	}
+	return s.Employees.Restore(ctx, id)
+}
+
+// employees whose position can teach
+func (s *EmployeesService) FindTeachers(ctx context.Context) ([]domain.Employee, error) {
+	return s.Employees.FindByCapability(ctx, domain.CapabilityTeach)
+}
+
+// employees whose position can curate students
+func (s *EmployeesService) FindCurators(ctx context.Context) ([]domain.Employee, error) {
+	return s.Employees.FindByCapability(ctx, domain.CapabilityCurate)
+}
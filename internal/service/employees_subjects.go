@@ -0,0 +1,56 @@
+package service
+
+import (
+	"context"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+	"university-db-admin/pkg/validation"
+)
+
+type EmployeesSubjectsService struct {
+	repository.EmployeesSubjects
+	repo *repository.Repository
+}
+
+func NewEmployeesSubjectsService(r *repository.Repository) *EmployeesSubjectsService {
+	return &EmployeesSubjectsService{
+		EmployeesSubjects: r.EmployeesSubjects,
+		repo:              r,
+	}
+}
+
+func (s *EmployeesSubjectsService) Create(ctx context.Context, es domain.EmployeeSubject) error {
+	if err := validation.ValidateStruct(es); err != nil {
+		return err
+	}
+
+	return s.repo.WithTx(ctx, func(ctx context.Context, tx *repository.Repository) error {
+		if err := checkTeacher(ctx, tx, es.EmployeeID); err != nil {
+			return err
+		}
+		return tx.EmployeesSubjects.Create(ctx, es)
+	})
+}
+
+func (s *EmployeesSubjectsService) Update(ctx context.Context, eid uint64, sid uint64, es domain.EmployeeSubject) error {
+	if err := validation.ValidatePositiveNumbers(eid, sid); err != nil {
+		return err
+	}
+	if err := validation.ValidateStruct(es); err != nil {
+		return err
+	}
+
+	return s.repo.WithTx(ctx, func(ctx context.Context, tx *repository.Repository) error {
+		if err := checkTeacher(ctx, tx, es.EmployeeID); err != nil {
+			return err
+		}
+		return tx.EmployeesSubjects.Update(ctx, eid, sid, es)
+	})
+}
+
+func (s *EmployeesSubjectsService) Delete(ctx context.Context, eid uint64, sid uint64) error {
+	if err := validation.ValidatePositiveNumbers(eid, sid); err != nil {
+		return err
+	}
+	return s.EmployeesSubjects.Delete(ctx, eid, sid)
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+	"university-db-admin/pkg/validation"
+)
+
+var ErrNotTeacher = errors.New("указанный сотрудник не является преподавателем")
+var ErrNotCurator = errors.New("должность указанного сотрудника не позволяет курировать студентов")
+var ErrUnknownPair = errors.New("пары с таким номером нет в расписании звонков")
+var ErrNotAdministrator = errors.New("должность указанного сотрудника не позволяет работать в деканате")
+
+// the foreign keys of students accept groups and curators in the trash
+var (
+	ErrTrashedGroup   = validation.NewError("группа не найдена или находится в корзине")
+	ErrTrashedCurator = validation.NewError("куратор не найден или находится в корзине")
+)
+
+// the grading rule of the marks_grading_eligibility trigger, checked
+// before writing by imports
+var (
+	ErrUnknownStudent = validation.NewError("студент не найден или находится в корзине")
+	ErrNotGrader      = validation.NewError("преподаватель не ведёт этот предмет, добавьте его в «Знание предметов»")
+	ErrNoLessons      = validation.NewError("у группы студента нет занятий по этому предмету")
+)
+
+// BatchError reports which item of a CreateMany batch was rejected
+type BatchError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("запись %d: %v", e.Index+1, e.Err)
+}
+
+func (e *BatchError) Unwrap() error {
+	return e.Err
+}
+
+// only employees whose position can teach may give marks or know subjects
+func checkTeacher(ctx context.Context, tx *repository.Repository, id uint64) error {
+	res, err := tx.Employees.IsTeacher(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !res.IsTeacher {
+		return ErrNotTeacher
+	}
+	return nil
+}
+
+// only employees whose position can curate may be curators of students
+func checkCurator(ctx context.Context, tx *repository.Repository, id uint64) error {
+	res, err := tx.Employees.IsCurator(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !res.IsCurator {
+		return ErrNotCurator
+	}
+	return nil
+}
+
+// only active employees whose position can administer may work in the
+// dean's office or administer the application
+func checkAdministrator(ctx context.Context, tx *repository.Repository, id uint64) error {
+	emp, err := tx.Employees.FindOne(ctx, id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrNotAdministrator
+	} else if err != nil {
+		return err
+	}
+	pos, err := tx.Positions.FindOne(ctx, emp.PositionID)
+	if err != nil {
+		return err
+	}
+	if !pos.CanAdminister {
+		return ErrNotAdministrator
+	}
+	return nil
+}
+
+// students may only join an active group and be curated by an active
+// employee whose position can curate
+func checkStudentLinks(ctx context.Context, tx *repository.Repository, stud domain.Student) error {
+	if _, err := tx.Groups.FindOne(ctx, stud.GroupID); errors.Is(err, repository.ErrNotFound) {
+		return ErrTrashedGroup
+	} else if err != nil {
+		return err
+	}
+	if _, err := tx.Employees.FindOne(ctx, stud.EmployeeID); errors.Is(err, repository.ErrNotFound) {
+		return ErrTrashedCurator
+	} else if err != nil {
+		return err
+	}
+	return checkCurator(ctx, tx, stud.EmployeeID)
+}
+
+// a grader must teach the subject to an active student whose group has
+// lessons in it, the rule the database applies to new marks
+func checkGrading(ctx context.Context, tx *repository.Repository, mark domain.Mark) error {
+	stud, err := tx.Students.FindOne(ctx, mark.StudentID)
+	if errors.Is(err, repository.ErrNotFound) {
+		return ErrUnknownStudent
+	} else if err != nil {
+		return err
+	}
+
+	known, err := tx.EmployeesSubjects.FindByEmployeeID(ctx, mark.EmployeeID)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(known, func(es domain.EmployeeSubject) bool { return es.SubjectID == mark.SubjectID }) {
+		return ErrNotGrader
+	}
+
+	lessons, err := tx.Lessons.FindByGroupID(ctx, stud.GroupID)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(lessons, func(lsn domain.Lesson) bool { return lsn.SubjectID == mark.SubjectID }) {
+		return ErrNoLessons
+	}
+	return nil
+}
+
+// batches of at least this size are inserted with COPY, smaller ones row by
+// row so a failing row can be reported by its index
+const copyThreshold = 500
+
+// validates every item of a batch before anything is written
+func validateBatch[T any](items []T) error {
+	for i, item := range items {
+		if err := validation.ValidateStruct(item); err != nil {
+			return &BatchError{Index: i, Err: err}
+		}
+	}
+	return nil
+}
+
+// inserts items with COPY or one by one depending on the batch size
+func insertBatch[T any](
+	ctx context.Context,
+	items []T,
+	copyFrom func(ctx context.Context, items []T) (int64, error),
+	create func(ctx context.Context, item T) (uint64, error),
+) error {
+	if len(items) >= copyThreshold {
+		_, err := copyFrom(ctx, items)
+		return err
+	}
+
+	for i, item := range items {
+		if _, err := create(ctx, item); err != nil {
+			return &BatchError{Index: i, Err: err}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+	"university-db-admin/pkg/validation"
+)
+
+type GroupsService struct {
+	repository.Groups
+}
+
+func NewGroupsService(r *repository.Repository) *GroupsService {
+	return &GroupsService{
+		Groups: r.Groups,
+	}
+}
+
+func (s *GroupsService) Create(ctx context.Context, grp domain.Group) (uint64, error) {
+	if err := validation.ValidateStruct(grp); err != nil {
+		return 0, err
+	}
+	return s.Groups.Create(ctx, grp)
+}
+
+func (s *GroupsService) Update(ctx context.Context, id uint64, grp domain.Group) error {
+	if err := validation.ValidatePositiveNumbers(id); err != nil {
+		return err
+	}
+	if err := validation.ValidateStruct(grp); err != nil {
+		return err
+	}
+	return s.Groups.Update(ctx, id, grp)
+}
+
+func (s *GroupsService) Delete(ctx context.Context, id uint64) error {
+	if err := validation.ValidatePositiveNumbers(id); err != nil {
+		return err
+	}
+	return s.Groups.Delete(ctx, id)
+}
+
+func (s *GroupsService) Restore(ctx context.Context, id uint64) error {
+	if err := validation.ValidatePositiveNumbers(id); err != nil {
+		return err
+	}
+	return s.Groups.Restore(ctx, id)
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+	"university-db-admin/pkg/validation"
+)
+
+type LessonTypesService struct {
+	repository.LessonTypes
+}
+
+func NewLessonTypesService(r *repository.Repository) *LessonTypesService {
+	return &LessonTypesService{
+		LessonTypes: r.LessonTypes,
+	}
+}
+
+func (s *LessonTypesService) Create(ctx context.Context, lt domain.LessonType) (uint64, error) {
+	if err := validation.ValidateStruct(lt); err != nil {
+		return 0, err
+	}
+	return s.LessonTypes.Create(ctx, lt)
+}
+
+func (s *LessonTypesService) Update(ctx context.Context, id uint64, lt domain.LessonType) error {
+	if err := validation.ValidatePositiveNumbers(id); err != nil {
+		return err
+	}
+	if err := validation.ValidateStruct(lt); err != nil {
+		return err
+	}
+	return s.LessonTypes.Update(ctx, id, lt)
+}
+
+func (s *LessonTypesService) Delete(ctx context.Context, id uint64) error {
+	if err := validation.ValidatePositiveNumbers(id); err != nil {
+		return err
+	}
+	return s.LessonTypes.Delete(ctx, id)
+}
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+	"university-db-admin/internal/schedule"
+	"university-db-admin/pkg/validation"
+)
+
+type ScheduleService struct {
+	repository.Lessons
+	repo  *repository.Repository
+	bells schedule.BellSchedule
+}
+
+func NewScheduleService(r *repository.Repository, bells schedule.BellSchedule) *ScheduleService {
+	return &ScheduleService{
+		Lessons: r.Lessons,
+		repo:    r,
+		bells:   bells,
+	}
+}
+
+// Bells returns the bell schedule lessons are checked against
+func (s *ScheduleService) Bells() schedule.BellSchedule {
+	return s.bells
+}
+
+// the pair must ring in the bell schedule and the teacher, if any, must be
+// able to teach; the link of the teacher to the subject is a foreign key
+func (s *ScheduleService) checkLesson(ctx context.Context, tx *repository.Repository, lsn domain.Lesson) error {
+	if _, ok := s.bells.Bell(lsn.Pair); !ok {
+		return ErrUnknownPair
+	}
+	if lsn.EmployeeID != nil {
+		return checkTeacher(ctx, tx, *lsn.EmployeeID)
+	}
+	return nil
+}
+
+// rejects a lesson clashing with the scheduled ones with a schedule.ConflictError
+func checkConflicts(ctx context.Context, tx *repository.Repository, lsn domain.Lesson) error {
+	scheduled, err := tx.Lessons.FindByWeek(ctx, lsn.Week)
+	if err != nil {
+		return err
+	}
+	if conflicts := schedule.Check(lsn, scheduled); len(conflicts) > 0 {
+		return &schedule.ConflictError{Conflicts: conflicts}
+	}
+	return nil
+}
+
+// a lesson scheduled by another transaction after the check is caught by
+// the slot keys of the lessons table, the clash is looked up again once
+// that lesson is committed and reported like a checked one
+func (s *ScheduleService) slotTaken(ctx context.Context, lsn domain.Lesson, err error) error {
+	var dupErr *repository.DuplicateError
+	if !errors.As(err, &dupErr) || dupErr.Table != "lessons" {
+		return err
+	}
+	if conflictErr := checkConflicts(ctx, s.repo, lsn); errors.Is(conflictErr, schedule.ErrConflict) {
+		return conflictErr
+	}
+	return fmt.Errorf("%w: %w", schedule.ErrConflict, err)
+}
+
+// creates a lesson unless its room, group or teacher is taken at that time
+func (s *ScheduleService) Create(ctx context.Context, lsn domain.Lesson) (uint64, error) {
+	if err := validation.ValidateStruct(lsn); err != nil {
+		return 0, err
+	}
+
+	var id uint64
+	err := s.repo.WithTx(ctx, func(ctx context.Context, tx *repository.Repository) error {
+		lsn.ID = 0
+		if err := s.checkLesson(ctx, tx, lsn); err != nil {
+			return err
+		}
+		if err := checkConflicts(ctx, tx, lsn); err != nil {
+			return err
+		}
+
+		var err error
+		id, err = tx.Lessons.Create(ctx, lsn)
+		return err
+	})
+	return id, s.slotTaken(ctx, lsn, err)
+}
+
+// updates a lesson, the new time, room, group and teacher are checked like on create
+func (s *ScheduleService) Update(ctx context.Context, id uint64, lsn domain.Lesson) error {
+	if err := validation.ValidatePositiveNumbers(id); err != nil {
+		return err
+	}
+	if err := validation.ValidateStruct(lsn); err != nil {
+		return err
+	}
+
+	lsn.ID = id
+	err := s.repo.WithTx(ctx, func(ctx context.Context, tx *repository.Repository) error {
+		if err := s.checkLesson(ctx, tx, lsn); err != nil {
+			return err
+		}
+		if err := checkConflicts(ctx, tx, lsn); err != nil {
+			return err
+		}
+		return tx.Lessons.Update(ctx, id, lsn)
+	})
+	return s.slotTaken(ctx, lsn, err)
+}
+
+func (s *ScheduleService) Delete(ctx context.Context, id uint64) error {
+	if err := validation.ValidatePositiveNumbers(id); err != nil {
+		return err
+	}
+	return s.Lessons.Delete(ctx, id)
+}
+
+// Conflicts returns every conflict of the whole schedule, lessons
+// stored before conflicts were checked may clash until they are moved
+// for the slot keys of migration 0011
+func (s *ScheduleService) Conflicts(ctx context.Context) ([]schedule.Conflict, error) {
+	lessons, err := s.Lessons.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return schedule.Detect(lessons), nil
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+	"university-db-admin/pkg/validation"
+)
+
+type MarksService struct {
+	repository.Marks
+	repo *repository.Repository
+}
+
+func NewMarksService(r *repository.Repository) *MarksService {
+	return &MarksService{
+		Marks: r.Marks,
+		repo:  r,
+	}
+}
+
+// creates a mark given by a teacher
+func (s *MarksService) Create(ctx context.Context, mark domain.Mark) (uint64, error) {
+	if err := validation.ValidateStruct(mark); err != nil {
+		return 0, err
+	}
+
+	var id uint64
+	err := s.repo.WithTx(ctx, func(ctx context.Context, tx *repository.Repository) error {
+		if err := checkTeacher(ctx, tx, mark.EmployeeID); err != nil {
+			return err
+		}
+
+		var err error
+		id, err = tx.Marks.Create(ctx, mark)
+		return err
+	})
+	return id, err
+}
+
+// Check runs the checks of Create on a mark without writing it, the
+// grading rule included, imports show their result for every row before
+// anything is written
+func (s *MarksService) Check(ctx context.Context, mark domain.Mark) error {
+	if err := checkTeacher(ctx, s.repo, mark.EmployeeID); err != nil {
+		return err
+	}
+	return checkGrading(ctx, s.repo, mark)
+}
+
+// creates all marks in one transaction or none of them,
+// every grader must be a teacher
+func (s *MarksService) CreateMany(ctx context.Context, marks []domain.Mark) error {
+	if err := validateBatch(marks); err != nil {
+		return err
+	}
+
+	return s.repo.WithTx(ctx, func(ctx context.Context, tx *repository.Repository) error {
+		checked := make(map[uint64]bool)
+		for i, mark := range marks {
+			if checked[mark.EmployeeID] {
+				continue
+			}
+			if err := checkTeacher(ctx, tx, mark.EmployeeID); err != nil {
+				return &BatchError{Index: i, Err: err}
+			}
+			checked[mark.EmployeeID] = true
+		}
+
+		return insertBatch(ctx, marks, tx.Marks.CopyFrom, tx.Marks.Create)
+	})
+}
+
+// updates a mark, the new grader must be a teacher as well
+func (s *MarksService) Update(ctx context.Context, id uint64, mark domain.Mark) error {
+	if err := validation.ValidatePositiveNumbers(id); err != nil {
+		return err
+	}
+	if err := validation.ValidateStruct(mark); err != nil {
+		return err
+	}
+
+	return s.repo.WithTx(ctx, func(ctx context.Context, tx *repository.Repository) error {
+		if err := checkTeacher(ctx, tx, mark.EmployeeID); err != nil {
+			return err
+		}
+		return tx.Marks.Update(ctx, id, mark)
+	})
+}
+
+func (s *MarksService) Delete(ctx context.Context, id uint64) error {
+	if err := validation.ValidatePositiveNumbers(id); err != nil {
+		return err
+	}
+	return s.Marks.Delete(ctx, id)
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+	"university-db-admin/pkg/validation"
+)
+
+type PositionsService struct {
+	repository.Positions
+}
+
+func NewPositionsService(r *repository.Repository) *PositionsService {
+	return &PositionsService{
+		Positions: r.Positions,
+	}
+}
+
+func (s *PositionsService) Create(ctx context.Context, pos domain.Position) (uint64, error) {
+	if err := validation.ValidateStruct(pos); err != nil {
+		return 0, err
+	}
+	return s.Positions.Create(ctx, pos)
+}
+
+func (s *PositionsService) Update(ctx context.Context, id uint64, pos domain.Position) error {
+	if err := validation.ValidatePositiveNumbers(id); err != nil {
+		return err
+	}
+	if err := validation.ValidateStruct(pos); err != nil {
+		return err
+	}
+	return s.Positions.Update(ctx, id, pos)
+}
+
+func (s *PositionsService) Delete(ctx context.Context, id uint64) error {
+	if err := validation.ValidatePositiveNumbers(id); err != nil {
+		return err
+	}
+	return s.Positions.Delete(ctx, id)
+}
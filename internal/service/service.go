@@ -0,0 +1,44 @@
+package service
+
+import (
+	"university-db-admin/internal/repository"
+	"university-db-admin/internal/schedule"
+)
+
+// Service is the single entry point for the UI and other clients: it
+// validates input and enforces business rules before touching repositories.
+// Read methods are promoted from the embedded repositories unchanged.
+type Service struct {
+	Employees         *EmployeesService
+	Groups            *GroupsService
+	LessonTypes       *LessonTypesService
+	Schedule          *ScheduleService
+	Marks             *MarksService
+	Positions         *PositionsService
+	Students          *StudentsService
+	Subjects          *SubjectsService
+	EmployeesSubjects *EmployeesSubjectsService
+	Audit             *AuditService
+	Backup            *BackupService
+	Console           *ConsoleService
+	Users             *UsersService
+}
+
+// bells is the bell schedule the pairs of lessons ring in
+func NewService(r *repository.Repository, bells schedule.BellSchedule) *Service {
+	return &Service{
+		Employees:         NewEmployeesService(r),
+		Groups:            NewGroupsService(r),
+		LessonTypes:       NewLessonTypesService(r),
+		Schedule:          NewScheduleService(r, bells),
+		Marks:             NewMarksService(r),
+		Positions:         NewPositionsService(r),
+		Students:          NewStudentsService(r),
+		Subjects:          NewSubjectsService(r),
+		EmployeesSubjects: NewEmployeesSubjectsService(r),
+		Audit:             NewAuditService(r),
+		Backup:            NewBackupService(r),
+		Console:           NewConsoleService(r),
+		Users:             NewUsersService(r),
+	}
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+	"university-db-admin/pkg/validation"
+)
+
+type StudentsService struct {
+	repository.Students
+	repo *repository.Repository
+}
+
+func NewStudentsService(r *repository.Repository) *StudentsService {
+	return &StudentsService{
+		Students: r.Students,
+		repo:     r,
+	}
+}
+
+// creates a student of an active group curated by an active employee who may curate
+func (s *StudentsService) Create(ctx context.Context, stud domain.Student) (uint64, error) {
+	if err := validation.ValidateStruct(stud); err != nil {
+		return 0, err
+	}
+
+	var id uint64
+	err := s.repo.WithTx(ctx, func(ctx context.Context, tx *repository.Repository) error {
+		if err := checkStudentLinks(ctx, tx, stud); err != nil {
+			return err
+		}
+
+		var err error
+		id, err = tx.Students.Create(ctx, stud)
+		return err
+	})
+	return id, err
+}
+
+// Check runs the checks of Create on a student without writing it,
+// imports show their result for every row before anything is written
+func (s *StudentsService) Check(ctx context.Context, stud domain.Student) error {
+	return checkStudentLinks(ctx, s.repo, stud)
+}
+
+// creates all students in one transaction or none of them, every
+// group and curator is checked like by Create
+func (s *StudentsService) CreateMany(ctx context.Context, studs []domain.Student) error {
+	if err := validateBatch(studs); err != nil {
+		return err
+	}
+
+	return s.repo.WithTx(ctx, func(ctx context.Context, tx *repository.Repository) error {
+		type link struct{ group, curator uint64 }
+		checked := make(map[link]bool)
+		for i, stud := range studs {
+			l := link{stud.GroupID, stud.EmployeeID}
+			if checked[l] {
+				continue
+			}
+			if err := checkStudentLinks(ctx, tx, stud); err != nil {
+				return &BatchError{Index: i, Err: err}
+			}
+			checked[l] = true
+		}
+
+		return insertBatch(ctx, studs, tx.Students.CopyFrom, tx.Students.Create)
+	})
+}
+
+// updates a student, the group and the curator are checked like by Create
+func (s *StudentsService) Update(ctx context.Context, id uint64, stud domain.Student) error {
+	if err := validation.ValidatePositiveNumbers(id); err != nil {
+		return err
+	}
+	if err := validation.ValidateStruct(stud); err != nil {
+		return err
+	}
+
+	return s.repo.WithTx(ctx, func(ctx context.Context, tx *repository.Repository) error {
+		if err := checkStudentLinks(ctx, tx, stud); err != nil {
+			return err
+		}
+		return tx.Students.Update(ctx, id, stud)
+	})
+}
+
+func (s *StudentsService) Delete(ctx context.Context, id uint64) error {
+	if err := validation.ValidatePositiveNumbers(id); err != nil {
+		return err
+	}
+	return s.Students.Delete(ctx, id)
+}
+
+func (s *StudentsService) Restore(ctx context.Context, id uint64) error {
+	if err := validation.ValidatePositiveNumbers(id); err != nil {
+		return err
+	}
+	return s.Students.Restore(ctx, id)
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+	"university-db-admin/pkg/validation"
+)
+
+type SubjectsService struct {
+	repository.Subjects
+}
+
+func NewSubjectsService(r *repository.Repository) *SubjectsService {
+	return &SubjectsService{
+		Subjects: r.Subjects,
+	}
+}
+
+func (s *SubjectsService) Create(ctx context.Context, sbj domain.Subject) (uint64, error) {
+	if err := validation.ValidateStruct(sbj); err != nil {
+		return 0, err
+	}
+	return s.Subjects.Create(ctx, sbj)
+}
+
+func (s *SubjectsService) Update(ctx context.Context, id uint64, sbj domain.Subject) error {
+	if err := validation.ValidatePositiveNumbers(id); err != nil {
+		return err
+	}
+	if err := validation.ValidateStruct(sbj); err != nil {
+		return err
+	}
+	return s.Subjects.Update(ctx, id, sbj)
+}
+
+func (s *SubjectsService) Delete(ctx context.Context, id uint64) error {
+	if err := validation.ValidatePositiveNumbers(id); err != nil {
+		return err
+	}
+	return s.Subjects.Delete(ctx, id)
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/repository"
+	"university-db-admin/pkg/validation"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("неверный логин или пароль")
+	ErrShortPassword      = errors.New("пароль должен содержать не менее 8 символов")
+	ErrNoUsers            = errors.New("нет ни одной учётной записи, создайте администратора командой university_db_cli user add")
+	ErrNoDeanEmployee     = validation.NewError("учётная запись деканата должна быть связана с сотрудником")
+)
+
+// passwords shorter than this are rejected
+const minPasswordLength = 8
+
+// compared with the password given for an unknown login, so it takes as
+// long to reject as a wrong password; made with bcrypt.DefaultCost like
+// the hashes of the accounts
+var dummyHash = []byte("$2a$10$YTbCC900R8HEsQfCqcTlf.DcajyLziNZQqoc6voQ1ko3D0BD46VOK")
+
+// UsersService manages the accounts of the application and checks their passwords
+type UsersService struct {
+	repository.Users
+	repo *repository.Repository
+}
+
+func NewUsersService(r *repository.Repository) *UsersService {
+	return &UsersService{
+		Users: r.Users,
+		repo:  r,
+	}
+}
+
+// creates an account, see checkRole for the employee record it needs
+func (s *UsersService) Create(ctx context.Context, login, password string, role domain.Role, employeeID *uint64) (uint64, error) {
+	hash, err := hashPassword(password)
+	if err != nil {
+		return 0, err
+	}
+
+	u := domain.User{Login: login, PasswordHash: hash, Role: role, EmployeeID: employeeID}
+	if err := validation.ValidateStruct(u); err != nil {
+		return 0, err
+	}
+
+	var id uint64
+	err = s.repo.WithTx(ctx, func(ctx context.Context, tx *repository.Repository) error {
+		if err := checkRole(ctx, tx, role, employeeID); err != nil {
+			return err
+		}
+
+		var err error
+		id, err = tx.Users.Create(ctx, u)
+		return err
+	})
+	return id, err
+}
+
+// changes the role of an account keeping its password
+func (s *UsersService) SetRole(ctx context.Context, login string, role domain.Role, employeeID *uint64) error {
+	return s.repo.WithTx(ctx, func(ctx context.Context, tx *repository.Repository) error {
+		u, err := tx.Users.FindByLogin(ctx, login)
+		if err != nil {
+			return err
+		}
+
+		u.Role, u.EmployeeID = role, employeeID
+		if err := validation.ValidateStruct(u); err != nil {
+			return err
+		}
+		if err := checkRole(ctx, tx, role, employeeID); err != nil {
+			return err
+		}
+		return tx.Users.Update(ctx, u.ID, u)
+	})
+}
+
+func (s *UsersService) SetPassword(ctx context.Context, login, password string) error {
+	hash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+
+	return s.repo.WithTx(ctx, func(ctx context.Context, tx *repository.Repository) error {
+		u, err := tx.Users.FindByLogin(ctx, login)
+		if err != nil {
+			return err
+		}
+		u.PasswordHash = hash
+		return tx.Users.Update(ctx, u.ID, u)
+	})
+}
+
+func (s *UsersService) DeleteByLogin(ctx context.Context, login string) error {
+	u, err := s.Users.FindByLogin(ctx, login)
+	if err != nil {
+		return err
+	}
+	return s.Users.Delete(ctx, u.ID)
+}
+
+// returns the account with the given login and password, an unknown
+// login and a wrong password are reported the same way and take as long.
+// ErrNoUsers is returned while the database has no accounts at all
+func (s *UsersService) Authenticate(ctx context.Context, login, password string) (domain.User, error) {
+	n, err := s.Users.Count(ctx)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if n == 0 {
+		return domain.User{}, ErrNoUsers
+	}
+
+	u, err := s.Users.FindByLogin(ctx, login)
+	if errors.Is(err, repository.ErrNotFound) {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return domain.User{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return domain.User{}, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(u.PasswordHash), []byte(password)); err != nil {
+		return domain.User{}, ErrInvalidCredentials
+	}
+	return u, nil
+}
+
+// a teacher account needs the employee record of a teacher and a dean's
+// office account the one of an employee whose position can administer,
+// so does an administrator linked to an employee; administrators without
+// one are kept for setting up a database that has no employees yet
+func checkRole(ctx context.Context, tx *repository.Repository, role domain.Role, employeeID *uint64) error {
+	switch {
+	case role == domain.RoleDean && employeeID == nil:
+		return ErrNoDeanEmployee
+	case employeeID == nil:
+		return nil
+	case role == domain.RoleTeacher:
+		return checkTeacher(ctx, tx, *employeeID)
+	case role == domain.RoleDean, role == domain.RoleAdmin:
+		return checkAdministrator(ctx, tx, *employeeID)
+	}
+	return nil
+}
+
+func hashPassword(password string) (string, error) {
+	if len([]rune(password)) < minPasswordLength {
+		return "", ErrShortPassword
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	return string(hash), err
+}
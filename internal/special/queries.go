@@ -0,0 +1,276 @@
+package special
+
+import (
+	"context"
+	"university-db-admin/internal/dto"
+	"university-db-admin/internal/service"
+)
+
+const dateLayout = "2006-01-02"
+
+// Queries lists the special queries in the order they are offered to users
+var Queries = []Query{
+	{
+		Name:    "employees-passports",
+		Title:   "Получить ФИО и номер паспорта всех сотрудников",
+		Caption: "Сотрудники и паспорта",
+		Help:    "names and passports of all employees",
+		Columns: []Column{
+			{Name: "name", Header: "ФИО"},
+			{Name: "passport", Header: "Номер паспорта"},
+		},
+		run: func(ctx context.Context, s *service.Service, args Args) ([][]any, error) {
+			data, err := s.Employees.FindAllNamePassport(ctx)
+			var rows [][]any
+			for _, d := range data {
+				rows = append(rows, []any{d.Name, d.Passport})
+			}
+			return rows, err
+		},
+	},
+	{
+		Name:    "employee-passport",
+		Title:   "Получить ФИО и номер паспорта сотрудника по id",
+		Caption: "Сотрудник",
+		Help:    "name and passport of one employee",
+		Params:  []Param{{Name: "id", Label: "ID сотрудника", Help: "employee id", Kind: Uint}},
+		Columns: []Column{
+			{Name: "name", Header: "ФИО"},
+			{Name: "passport", Header: "Номер паспорта"},
+		},
+		run: func(ctx context.Context, s *service.Service, args Args) ([][]any, error) {
+			d, err := s.Employees.FindNamePassportByID(ctx, args.uint64("id"))
+			if err != nil {
+				return nil, err
+			}
+			return [][]any{{d.Name, d.Passport}}, nil
+		},
+	},
+	{
+		Name:    "students-no-curator",
+		Title:   "Получить информацию о студентах у которых нет куратора",
+		Caption: "Студенты без куратора",
+		Help:    "students without a curator",
+		Columns: []Column{
+			{Name: "name", Header: "ФИО"},
+			{Name: "passport", Header: "Номер паспорта"},
+			{Name: "group_id", Header: "ID группы"},
+		},
+		run: func(ctx context.Context, s *service.Service, args Args) ([][]any, error) {
+			data, err := s.Students.FindAllWithNoCurator(ctx)
+			var rows [][]any
+			for _, d := range data {
+				rows = append(rows, []any{d.Name, d.Passport, d.GroupID})
+			}
+			return rows, err
+		},
+	},
+	{
+		Name:    "employees-by-positions",
+		Title:   "Получить ФИО сотрудников либо с одной должностью, либо другой по id",
+		Caption: "Сотрудники по должностям",
+		Help:    "employees holding either of two positions",
+		Params: []Param{
+			{Name: "first", Label: "ID первой должности", Help: "first position id", Kind: Uint},
+			{Name: "second", Label: "ID второй должности", Help: "second position id", Kind: Uint},
+		},
+		Columns: []Column{
+			{Name: "name", Header: "ФИО"},
+		},
+		run: func(ctx context.Context, s *service.Service, args Args) ([][]any, error) {
+			data, err := s.Employees.FindAllByPositions(ctx, args.uint64("first"), args.uint64("second"))
+			var rows [][]any
+			for _, d := range data {
+				rows = append(rows, []any{d.Name})
+			}
+			return rows, err
+		},
+	},
+	{
+		Name:    "marks-by-subject",
+		Title:   "Получить информацию об оценках выше заданной и выставленных по предмету с заданным id",
+		Caption: "Оценки по предмету",
+		Help:    "marks of a subject equal to the given one",
+		Params: []Param{
+			{Name: "subject", Label: "ID предмета", Help: "subject id", Kind: Uint},
+			{Name: "mark", Label: "Оценка", Help: "mark", Kind: Uint},
+		},
+		Columns: marksColumns,
+		run: func(ctx context.Context, s *service.Service, args Args) ([][]any, error) {
+			data, err := s.Marks.FindAllBySubject(ctx, args.uint64("subject"), args.uint16("mark"))
+			return marksResult(data, err)
+		},
+	},
+	{
+		Name:    "students-by-middlename",
+		Title:   "Получить ФИО и номер паспорта студентов c отчествами, заканчивающимися на заданную последовательность",
+		Caption: "Студенты по отчеству",
+		Help:    "students whose middle name contains a sequence",
+		Params:  []Param{{Name: "seq", Label: "Последовательность", Help: "character sequence", Kind: String}},
+		Columns: []Column{
+			{Name: "name", Header: "ФИО"},
+			{Name: "passport", Header: "Номер паспорта"},
+		},
+		run: func(ctx context.Context, s *service.Service, args Args) ([][]any, error) {
+			data, err := s.Students.FindAllByMiddlename(ctx, args["seq"])
+			var rows [][]any
+			for _, d := range data {
+				rows = append(rows, []any{d.Name, d.Passport})
+			}
+			return rows, err
+		},
+	},
+	{
+		Name:    "sorted-subjects",
+		Title:   "Получить список предметов отсортированных в алфавитном порядке",
+		Caption: "Предметы по алфавиту",
+		Help:    "subject names in alphabetical order",
+		Columns: []Column{
+			{Name: "name", Header: "Название"},
+		},
+		run: func(ctx context.Context, s *service.Service, args Args) ([][]any, error) {
+			data, err := s.Subjects.FindAllSorted(ctx)
+			var rows [][]any
+			for _, d := range data {
+				rows = append(rows, []any{d.Name})
+			}
+			return rows, err
+		},
+	},
+	{
+		Name:    "sorted-marks",
+		Title:   "Получить информацию об оценках отсортированных по дате в порядке возрастания и по значению в порядке убывания",
+		Caption: "Оценки по дате",
+		Help:    "all marks sorted by value",
+		Columns: marksColumns,
+		run: func(ctx context.Context, s *service.Service, args Args) ([][]any, error) {
+			data, err := s.Marks.FindAllSorted(ctx)
+			marks := make([]dto.MarkBySubjectDTO, len(data))
+			for i, d := range data {
+				marks[i] = dto.MarkBySubjectDTO(d)
+			}
+			return marksResult(marks, err)
+		},
+	},
+	{
+		Name:    "student-groups",
+		Title:   "Получить все возможные сочетания студентов и групп",
+		Caption: "Сочетания студентов и групп",
+		Help:    "every combination of student and group",
+		Columns: []Column{
+			{Name: "student_name", Header: "ФИО студента"},
+			{Name: "group_number", Header: "Номер группы"},
+		},
+		run: func(ctx context.Context, s *service.Service, args Args) ([][]any, error) {
+			data, err := s.Students.FindAllGroupCombs(ctx)
+			var rows [][]any
+			for _, d := range data {
+				rows = append(rows, []any{d.StudentName, d.GroupNumber})
+			}
+			return rows, err
+		},
+	},
+	{
+		Name:    "lessons-schedule",
+		Title:   "Получить расписание занятий по группам",
+		Caption: "Расписание занятий",
+		Help:    "lessons with group numbers, subjects, teachers and pair times",
+		Columns: []Column{
+			{Name: "group_number", Header: "Номер группы"},
+			{Name: "subject", Header: "Название предмета"},
+			{Name: "lesson_type", Header: "Тип занятия"},
+			{Name: "teacher", Header: "Преподаватель"},
+			{Name: "room", Header: "Аудитория"},
+			{Name: "week", Header: "Неделя"},
+			{Name: "weekday", Header: "День недели"},
+			{Name: "pair", Header: "Пара"},
+			{Name: "time", Header: "Время"},
+		},
+		run: func(ctx context.Context, s *service.Service, args Args) ([][]any, error) {
+			data, err := s.Schedule.FindSchedule(ctx)
+			var rows [][]any
+			for _, d := range data {
+				rows = append(rows, []any{d.GroupNumber, d.Subject, d.LessonType, d.Teacher, d.Room, d.Week, d.Weekday, d.Pair, s.Schedule.Bells().Time(d.Pair)})
+			}
+			return rows, err
+		},
+	},
+	{
+		Name:    "students-with-curators",
+		Title:   "Получить всех студентов и их кураторов, включая студентов без куратора",
+		Caption: "Студенты и кураторы",
+		Help:    "students that have a curator",
+		Columns: curatorsColumns,
+		run: func(ctx context.Context, s *service.Service, args Args) ([][]any, error) {
+			return curatorsResult(s.Students.FindAllWithCurators(ctx))
+		},
+	},
+	{
+		Name:    "curators-with-students",
+		Title:   "Получить всех кураторов и их студентов, включая кураторов без студентов",
+		Caption: "Кураторы и студенты",
+		Help:    "all curators with their students",
+		Columns: curatorsColumns,
+		run: func(ctx context.Context, s *service.Service, args Args) ([][]any, error) {
+			return curatorsResult(s.Students.FindWithAllCurators(ctx))
+		},
+	},
+	{
+		Name:    "student-curator-pairs",
+		Title:   "Получить всех студентов и их кураторов, включая студентов без куратора и кураторов без студентов",
+		Caption: "Пары студентов и кураторов",
+		Help:    "all students and all curators paired",
+		Columns: curatorsColumns,
+		run: func(ctx context.Context, s *service.Service, args Args) ([][]any, error) {
+			return curatorsResult(s.Students.FindAllPairsWithCurator(ctx))
+		},
+	},
+	{
+		Name:    "students-uppercase",
+		Title:   "Получить ФИО студентов в верхнем регистре и посчитать в них количество символов",
+		Caption: "ФИО студентов в верхнем регистре",
+		Help:    "student names in upper case with their length",
+		Columns: []Column{
+			{Name: "id", Header: "ID студента"},
+			{Name: "uppercase_name", Header: "ФИО в верхнем регистре"},
+			{Name: "name_length", Header: "Длина ФИО"},
+		},
+		run: func(ctx context.Context, s *service.Service, args Args) ([][]any, error) {
+			data, err := s.Students.FindAllUppercaseWithLength(ctx)
+			var rows [][]any
+			for _, d := range data {
+				rows = append(rows, []any{d.ID, d.UppercaseName, d.NameLength})
+			}
+			return rows, err
+		},
+	},
+}
+
+var marksColumns = []Column{
+	{Name: "student_id", Header: "ID студента"},
+	{Name: "mark", Header: "Оценка"},
+	{Name: "date", Header: "Дата"},
+}
+
+var curatorsColumns = []Column{
+	{Name: "student_name", Header: "ФИО студента"},
+	{Name: "student_passport", Header: "Паспорт студента"},
+	{Name: "curator_name", Header: "ФИО куратора"},
+	{Name: "curator_passport", Header: "Паспорт куратора"},
+}
+
+func marksResult(data []dto.MarkBySubjectDTO, err error) ([][]any, error) {
+	var rows [][]any
+	for _, d := range data {
+		rows = append(rows, []any{d.StudentID, d.Mark, d.Date.Format(dateLayout)})
+	}
+	return rows, err
+}
+
+func curatorsResult(data []dto.StudentCuratorDTO, err error) ([][]any, error) {
+	var rows [][]any
+	for _, d := range data {
+		rows = append(rows, []any{d.StudentName, d.StudentPassport, d.CuratorName, d.CuratorPassport})
+	}
+	return rows, err
+}
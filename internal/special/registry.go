@@ -0,0 +1,126 @@
+package special
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"university-db-admin/internal/service"
+)
+
+// Register adds queries after the built-in ones, a query can't take
+// the name of a registered one
+func Register(qs ...Query) error {
+	for _, q := range qs {
+		if err := q.check(); err != nil {
+			return err
+		}
+		if _, ok := Find(q.Name); ok {
+			return fmt.Errorf("query %q is already registered", q.Name)
+		}
+		if q.Caption == "" {
+			q.Caption = q.Title
+		}
+		for i, p := range q.Params {
+			if p.Label == "" {
+				q.Params[i].Label = p.Name
+			}
+		}
+		if q.run == nil {
+			q.run = q.runSQL
+		}
+		Queries = append(Queries, q)
+	}
+	return nil
+}
+
+// LoadFile registers the queries of a JSON file holding an array of them,
+// every query of the file needs its SQL
+func LoadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	var qs []Query
+	if err = json.Unmarshal(data, &qs); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	for i, q := range qs {
+		if strings.TrimSpace(q.SQL) == "" {
+			return fmt.Errorf("%s: query %d has no sql", path, i+1)
+		}
+	}
+
+	if err = Register(qs...); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
+
+// reads a query of a file, its timeout is written as text
+func (q *Query) UnmarshalJSON(data []byte) error {
+	type plain Query
+	var file struct {
+		plain
+		Timeout string `json:"timeout"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return err
+	}
+
+	*q = Query(file.plain)
+	if file.Timeout != "" {
+		timeout, err := time.ParseDuration(file.Timeout)
+		if err != nil || timeout <= 0 {
+			return fmt.Errorf("query %q: timeout %q is not a positive duration", q.Name, file.Timeout)
+		}
+		q.Timeout = timeout
+	}
+	return nil
+}
+
+// checks the declaration of a query
+func (q Query) check() error {
+	if q.Name == "" {
+		return fmt.Errorf("query without a name")
+	}
+	if q.Title == "" {
+		return fmt.Errorf("query %q has no title", q.Name)
+	}
+	if len(q.Columns) == 0 {
+		return fmt.Errorf("query %q has no columns", q.Name)
+	}
+
+	params := map[string]bool{}
+	for _, p := range q.Params {
+		if p.Name == "" || params[p.Name] {
+			return fmt.Errorf("query %q has a parameter without a name or with a repeated one", q.Name)
+		}
+		if p.Kind != Uint && p.Kind != String {
+			return fmt.Errorf("query %q: unknown kind %q of parameter %s", q.Name, p.Kind, p.Name)
+		}
+		params[p.Name] = true
+	}
+	return nil
+}
+
+// runs the SQL of the query in a read-only transaction, the arguments
+// are sent as text and their types are inferred by the database
+func (q Query) runSQL(ctx context.Context, s *service.Service, args Args) ([][]any, error) {
+	values := make([]any, len(q.Params))
+	for i, p := range q.Params {
+		values[i] = args[p.Name]
+	}
+
+	res, err := s.Console.Report(ctx, q.SQL, values...)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Columns) != len(q.Columns) {
+		return nil, fmt.Errorf("query %q returned %d columns, %d are declared", q.Name, len(res.Columns), len(q.Columns))
+	}
+	return res.Rows, nil
+}
@@ -0,0 +1,46 @@
+package special
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeQueries(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "queries.json")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadFileTimeout(t *testing.T) {
+	builtin := Queries
+	t.Cleanup(func() { Queries = builtin })
+
+	path := writeQueries(t, `[
+		{"name": "slow", "title": "Slow", "columns": [{"name": "n"}], "sql": "SELECT 1", "timeout": "2m"},
+		{"name": "quick", "title": "Quick", "columns": [{"name": "n"}], "sql": "SELECT 1"}
+	]`)
+	if err := LoadFile(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	slow, _ := Find("slow")
+	quick, _ := Find("quick")
+	if got := slow.TimeoutOr(time.Second); got != 2*time.Minute {
+		t.Fatalf("got timeout %v for slow, want 2m", got)
+	}
+	if got := quick.TimeoutOr(time.Second); got != time.Second {
+		t.Fatalf("got timeout %v for quick, want the fallback", got)
+	}
+
+	for _, timeout := range []string{"soon", "-1m", "0s"} {
+		path := writeQueries(t, `[{"name": "bad", "title": "Bad", "columns": [{"name": "n"}], "sql": "SELECT 1", "timeout": "`+timeout+`"}]`)
+		if err := LoadFile(path); err == nil {
+			t.Fatalf("got no error for timeout %q", timeout)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// Package special is the registry of predefined reports. Every query declares
+// its parameters and columns once and the UI form, the CLI command and the
+// REST endpoint of the query are built from that declaration. Queries are
+// either written in Go on top of the services or loaded from a file as SQL.
+package special
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+	"university-db-admin/internal/service"
+)
+
+// ErrInvalidArgument is returned when a query parameter is missing or malformed
+var ErrInvalidArgument = errors.New("invalid query argument")
+
+type ParamKind string
+
+const (
+	Uint   ParamKind = "uint"
+	String ParamKind = "string"
+)
+
+type Param struct {
+	Name  string    `json:"name"`
+	Label string    `json:"label"` // how the UI asks for the value
+	Help  string    `json:"help"`
+	Kind  ParamKind `json:"kind"`
+}
+
+// Column describes one value of every row of a query
+type Column struct {
+	Name   string `json:"name"`   // key of the value in the CLI and the API
+	Header string `json:"header"` // heading of the column in the UI
+}
+
+// Args holds query arguments in their text form keyed by parameter name
+type Args map[string]string
+
+// Result is a table with one value per column in every row
+type Result struct {
+	Columns []string
+	Rows    [][]any
+}
+
+// Query is a predefined report, every parameter is required
+type Query struct {
+	Name    string   `json:"name"`
+	Title   string   `json:"title"`   // how the UI offers the query
+	Caption string   `json:"caption"` // name of the result table, e.g. in exported files
+	Help    string   `json:"help"`
+	Params  []Param  `json:"params"`
+	Columns []Column `json:"columns"`
+
+	// statement of a query loaded from a file, parameters are passed
+	// in their declared order as $1, $2...
+	SQL string `json:"sql,omitempty"`
+
+	// how long the query may run, 0 leaves it to the default of the
+	// frontend. Files give it as text like "2m"
+	Timeout time.Duration `json:"-"`
+
+	run func(ctx context.Context, s *service.Service, args Args) ([][]any, error)
+}
+
+// checks args against the declared parameters
+func (q Query) Validate(args Args) error {
+	for _, p := range q.Params {
+		value, ok := args[p.Name]
+		if !ok || value == "" {
+			return fmt.Errorf("%w: %s is required", ErrInvalidArgument, p.Name)
+		}
+		if p.Kind == Uint {
+			if n, err := strconv.ParseUint(value, 10, 64); err != nil || n == 0 {
+				return fmt.Errorf("%w: %s must be a positive number", ErrInvalidArgument, p.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func (q Query) Run(ctx context.Context, s *service.Service, args Args) (Result, error) {
+	if err := q.Validate(args); err != nil {
+		return Result{}, err
+	}
+
+	if q.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, q.Timeout)
+		defer cancel()
+	}
+	rows, err := q.run(ctx, s, args)
+	if err != nil {
+		return Result{}, err
+	}
+
+	r := Result{Columns: make([]string, len(q.Columns)), Rows: rows}
+	for i, c := range q.Columns {
+		r.Columns[i] = c.Name
+	}
+	return r, nil
+}
+
+// TimeoutOr returns the timeout of the query, fallback when it has none
+func (q Query) TimeoutOr(fallback time.Duration) time.Duration {
+	if q.Timeout > 0 {
+		return q.Timeout
+	}
+	return fallback
+}
+
+// headings of the columns in the UI
+func (q Query) Headers() []string {
+	headers := make([]string, len(q.Columns))
+	for i, c := range q.Columns {
+		headers[i] = c.Header
+	}
+	return headers
+}
+
+// returns the query with the given name
+func Find(name string) (Query, bool) {
+	for _, q := range Queries {
+		if q.Name == name {
+			return q, true
+		}
+	}
+	return Query{}, false
+}
+
+// parses an argument already checked by Run
+func (a Args) uint64(name string) uint64 {
+	n, _ := strconv.ParseUint(a[name], 10, 64)
+	return n
+}
+
+// like uint64 for parameters stored in 16 bits
+func (a Args) uint16(name string) uint16 {
+	n, _ := strconv.ParseUint(a[name], 10, 16)
+	return uint16(n)
+}
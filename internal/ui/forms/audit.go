@@ -0,0 +1,137 @@
+package forms
+
+import (
+	"context"
+	"fmt"
+	"university-db-admin/internal/repository"
+	"university-db-admin/internal/service"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// tables recorded in the audit log
+var auditEntities = []struct {
+	Label  string
+	Entity string
+}{
+	{"Сотрудники", "employees"},
+	{"Группы", "groups"},
+	{"Типы занятий", "lesson_types"},
+	{"Занятия", "lessons"},
+	{"Оценки", "marks"},
+	{"Должности", "positions"},
+	{"Студенты", "students"},
+	{"Предметы", "subjects"},
+	{"Знание предметов", "employees_subjects"},
+}
+
+var auditOperations = map[string]string{
+	"create": "создание",
+	"update": "изменение",
+	"delete": "удаление",
+}
+
+func ShowAuditForm(content *fyne.Container, w fyne.Window, s *service.Service) {
+	content.Objects = nil
+
+	labels := []string{"Все"}
+	entities := map[string]string{}
+	names := map[string]string{}
+	for _, e := range auditEntities {
+		labels = append(labels, e.Label)
+		entities[e.Label] = e.Entity
+		names[e.Entity] = e.Label
+	}
+
+	entitySelect := widget.NewSelect(labels, nil)
+	entitySelect.SetSelectedIndex(0)
+
+	recordEntry := widget.NewEntry()
+	recordEntry.SetPlaceHolder("ID записи (для знания предметов: ID сотрудника/ID предмета)")
+
+	fromEntry := widget.NewEntry()
+	fromEntry.SetPlaceHolder("С даты (YYYY-MM-DD)")
+	toEntry := widget.NewEntry()
+	toEntry.SetPlaceHolder("По дату включительно (YYYY-MM-DD)")
+
+	columns := []listColumn{
+		{"ID", "id"},
+		{"Сущность", "entity"},
+		{"Запись", "record_id"},
+		{"Операция", "operation"},
+		{"Оператор", "operator"},
+		{"Время", "changed_at"},
+		{"Старые значения", ""},
+		{"Новые значения", ""},
+	}
+
+	listContainer := container.NewVBox()
+
+	showButton := widget.NewButton("Показать", func() {
+		var filters []repository.Filter
+		if entity, ok := entities[entitySelect.Selected]; ok {
+			filters = append(filters, repository.Filter{Field: "entity", Op: repository.OpEq, Value: entity})
+		}
+		if recordEntry.Text != "" {
+			filters = append(filters, repository.Filter{Field: "record_id", Op: repository.OpEq, Value: recordEntry.Text})
+		}
+		if fromEntry.Text != "" {
+			from := parseDate(fromEntry.Text)
+			if from.IsZero() {
+				showResult(listContainer, "Ошибка: дата должна быть в формате YYYY-MM-DD")
+				return
+			}
+			filters = append(filters, repository.Filter{Field: "changed_at", Op: repository.OpGte, Value: from.Format(dateLayout)})
+		}
+		if toEntry.Text != "" {
+			to := parseDate(toEntry.Text)
+			if to.IsZero() {
+				showResult(listContainer, "Ошибка: дата должна быть в формате YYYY-MM-DD")
+				return
+			}
+			// the whole last day is included
+			filters = append(filters, repository.Filter{Field: "changed_at", Op: repository.OpLt, Value: to.AddDate(0, 0, 1).Format(dateLayout)})
+		}
+
+		listContainer.Objects = nil
+		showPagedList(listContainer, w, "audit", "Журнал изменений", "Фильтрация журнала", columns, func(ctx context.Context, opts repository.ListOptions) ([][]string, uint64, error) {
+			opts.Filters = append(append([]repository.Filter{}, filters...), opts.Filters...)
+			page, err := s.Audit.List(ctx, opts)
+			if err != nil {
+				return nil, 0, err
+			}
+
+			rows := make([][]string, len(page.Items))
+			for i, e := range page.Items {
+				entity := names[e.Entity]
+				if entity == "" {
+					entity = e.Entity
+				}
+				rows[i] = []string{
+					fmt.Sprintf("%d", e.ID),
+					entity,
+					e.RecordID,
+					auditOperations[e.Operation],
+					e.Operator,
+					e.ChangedAt.Local().Format("2006-01-02 15:04:05"),
+					string(e.OldValues),
+					string(e.NewValues),
+				}
+			}
+			return rows, page.Total, nil
+		})
+		listContainer.Refresh()
+	})
+
+	form := container.NewVBox(
+		entitySelect,
+		recordEntry,
+		container.NewGridWithColumns(2, fromEntry, toEntry),
+		showButton,
+	)
+
+	content.Add(form)
+	content.Add(listContainer)
+}
@@ -0,0 +1,110 @@
+package forms
+
+import (
+	"context"
+	"fmt"
+	"university-db-admin/internal/backup"
+	"university-db-admin/internal/service"
+	"university-db-admin/pkg/validation"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+func ShowBackupForm(content *fyne.Container, w fyne.Window, s *service.Service) {
+	content.Objects = nil
+
+	fileEntry := widget.NewEntry()
+	fileEntry.SetPlaceHolder("Путь к архиву .zip")
+
+	browseButton := widget.NewButton("Выбрать файл", func() {
+		fileDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			defer reader.Close()
+			fileEntry.SetText(reader.URI().Path())
+		}, w)
+		fileDialog.SetFilter(storage.NewExtensionFileFilter([]string{".zip"}))
+		fileDialog.Show()
+	})
+
+	replaceCheck := widget.NewCheck("Заменить текущие записи", nil)
+	result := container.NewVBox()
+
+	createButton := widget.NewButton("Создать резервную копию", func() {
+		if err := validation.ValidateEmptyStrings(fileEntry.Text); err != nil {
+			showResult(result, "Ошибка: "+errorMessage(err))
+			return
+		}
+
+		path := fileEntry.Text
+		var m backup.Manifest
+		runQuery(result, timeoutOf("backup", longQueryTimeout), func(ctx context.Context) error {
+			var err error
+			m, err = s.Backup.Create(ctx, path)
+			return err
+		}, func() {
+			showManifest(result, "Резервная копия сохранена: "+path, m)
+		})
+	})
+
+	restoreButton := widget.NewButton("Восстановить из резервной копии", func() {
+		if err := validation.ValidateEmptyStrings(fileEntry.Text); err != nil {
+			showResult(result, "Ошибка: "+errorMessage(err))
+			return
+		}
+
+		path := fileEntry.Text
+		opts := backup.RestoreOptions{Replace: replaceCheck.Checked}
+		question := "Все записи архива будут добавлены в базу данных, она должна быть пустой. Продолжить?"
+		if opts.Replace {
+			question = "Все записи базы данных, включая корзину, будут удалены и заменены записями архива, " +
+				"учётные записи сохранятся. Продолжить?"
+		}
+		dialog.ShowConfirm("Восстановление", question,
+			func(ok bool) {
+				if !ok {
+					return
+				}
+				var m backup.Manifest
+				runQueryOr(result, timeoutOf("backup", longQueryTimeout), func(ctx context.Context) error {
+					var err error
+					m, err = s.Backup.Restore(ctx, path, opts)
+					return err
+				}, func() {
+					showManifest(result, "Восстановлена копия от "+m.CreatedAt.Local().Format("02.01.2006 15:04:05"), m)
+				}, func(err error) {
+					showResult(result, "Ошибка: "+errorMessage(err)+", ничего не восстановлено")
+				})
+			}, w)
+	})
+
+	form := container.NewVBox(
+		widget.NewLabel("Копия включает все таблицы и записи из корзины, кроме журнала изменений и учётных записей:\n"+
+			"после восстановления в новую базу данных учётные записи создаются заново командой university_db_cli user add"),
+		container.NewBorder(nil, nil, nil, browseButton, fileEntry),
+		createButton,
+		restoreButton,
+		replaceCheck,
+		result,
+	)
+
+	content.Add(form)
+}
+
+// shows the tables of an archive with their row counts
+func showManifest(content *fyne.Container, msg string, m backup.Manifest) {
+	data := make([][]string, len(m.Tables))
+	for i, e := range m.Tables {
+		data[i] = []string{label(tableLabels, e.Table), fmt.Sprintf("%d", e.Rows)}
+	}
+
+	content.Objects = nil
+	content.Add(widget.NewLabel(msg))
+	content.Add(updateTable([]string{"Таблица", "Записей"}, data))
+	content.Refresh()
+}
@@ -0,0 +1,58 @@
+package forms
+
+import (
+	"context"
+	"fmt"
+	"university-db-admin/internal/schedule"
+	"university-db-admin/internal/service"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+var conflictKindLabels = map[schedule.Kind]string{
+	schedule.KindRoom:    "Аудитория",
+	schedule.KindGroup:   "Группа",
+	schedule.KindTeacher: "Преподаватель",
+}
+
+// shows the conflicts of the whole schedule, new lessons are checked
+// when saved but the ones stored before may still clash
+func ShowScheduleConflictsForm(content *fyne.Container, w fyne.Window, s *service.Service) {
+	content.Objects = nil
+
+	result := container.NewVBox()
+	check := func() {
+		var conflicts []schedule.Conflict
+		runQuery(result, timeoutOf("conflicts", queryTimeout), func(ctx context.Context) (err error) {
+			conflicts, err = s.Schedule.Conflicts(ctx)
+			return err
+		}, func() {
+			if len(conflicts) == 0 {
+				result.Add(widget.NewLabel("Конфликтов в расписании нет"))
+				return
+			}
+
+			headers := []string{"Занято", "ID занятия", "ID другого занятия", "Неделя", "День недели", "Пара", "Описание"}
+			rows := make([][]string, len(conflicts))
+			for i, c := range conflicts {
+				rows[i] = []string{
+					conflictKindLabels[c.Kind],
+					fmt.Sprintf("%d", c.LessonID),
+					fmt.Sprintf("%d", c.OtherID),
+					fmt.Sprintf("%d", c.Week),
+					fmt.Sprintf("%d", c.Weekday),
+					fmt.Sprintf("%d", c.Pair),
+					c.String(),
+				}
+			}
+			result.Add(exportableTable(w, "Конфликты расписания", headers, rows))
+		})
+	}
+
+	content.Add(widget.NewButton("Проверить снова", check))
+	content.Add(result)
+	check()
+	content.Refresh()
+}
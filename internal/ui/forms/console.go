@@ -0,0 +1,159 @@
+package forms
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+	"university-db-admin/internal/service"
+	"university-db-admin/pkg/validation"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// length of a statement in the history list, longer ones are cut
+const historyLabelLength = 80
+
+func ShowConsoleForm(content *fyne.Container, w fyne.Window, s *service.Service) {
+	content.Objects = nil
+
+	statementEntry := widget.NewMultiLineEntry()
+	statementEntry.SetPlaceHolder("SQL-запрос")
+	statementEntry.SetMinRowsVisible(6)
+
+	writeCheck := widget.NewCheck("Режим записи", nil)
+
+	historySelect := widget.NewSelect(nil, nil)
+	historySelect.PlaceHolder = "История запросов"
+	showHistory := func() {
+		entries, err := s.Console.History(baseCtx)
+		if err != nil {
+			historySelect.PlaceHolder = "История недоступна: " + errorMessage(err)
+			historySelect.Refresh()
+			return
+		}
+
+		labels := make([]string, len(entries))
+		statements := map[string]string{}
+		for i, e := range entries {
+			labels[i] = historyLabel(e.Statement, e.RanAt)
+			statements[labels[i]] = e.Statement
+		}
+		historySelect.OnChanged = nil
+		historySelect.SetOptions(labels)
+		historySelect.ClearSelected()
+		historySelect.OnChanged = func(label string) {
+			if statement, ok := statements[label]; ok {
+				statementEntry.SetText(statement)
+			}
+		}
+	}
+	showHistory()
+
+	result := container.NewVBox()
+
+	run := func(statement string, write bool) {
+		var res service.ConsoleRun
+		runQueryOr(result, timeoutOf("console", queryTimeout), func(ctx context.Context) error {
+			var err error
+			res, err = s.Console.Run(ctx, statement, write)
+			return err
+		}, func() {
+			showConsoleResult(result, w, res)
+			showHistory()
+		}, func(err error) {
+			showResult(result, "Ошибка: "+errorMessage(err))
+			showHistory()
+		})
+	}
+
+	runButton := widget.NewButton("Выполнить", func() {
+		statement := statementEntry.Text
+		if err := validation.ValidateEmptyStrings(strings.TrimSpace(statement)); err != nil {
+			showResult(result, "Ошибка: "+errorMessage(err))
+			return
+		}
+
+		if !writeCheck.Checked {
+			run(statement, false)
+			return
+		}
+		dialog.ShowConfirm("Режим записи",
+			"Изменения, сделанные запросом, будут сохранены в базе данных. Выполнить?",
+			func(ok bool) {
+				if ok {
+					run(statement, true)
+				}
+			}, w)
+	})
+
+	form := container.NewVBox(
+		widget.NewLabel("Без режима записи запрос выполняется в транзакции только для чтения"),
+		historySelect,
+		statementEntry,
+		container.NewHBox(writeCheck, runButton),
+		result,
+	)
+
+	content.Add(form)
+}
+
+// shows the rows of a statement or, for statements returning none, the command tag
+func showConsoleResult(content *fyne.Container, w fyne.Window, res service.ConsoleRun) {
+	summary := fmt.Sprintf("%s, время выполнения: %.1f мс", res.Command, float64(res.Duration.Microseconds())/1000)
+	if len(res.Columns) == 0 {
+		summary += fmt.Sprintf(", затронуто строк: %d", res.RowsAffected)
+	} else if res.Truncated {
+		summary += fmt.Sprintf(", показаны первые %d строк", len(res.Rows))
+	}
+
+	content.Objects = nil
+	content.Add(widget.NewLabel(summary))
+	if len(res.Columns) > 0 {
+		rows := make([][]string, len(res.Rows))
+		for i, row := range res.Rows {
+			rows[i] = make([]string, len(row))
+			for j, v := range row {
+				rows[i][j] = formatValue(v)
+			}
+		}
+		content.Add(exportableTable(w, "SQL-запрос", res.Columns, rows))
+	}
+	content.Refresh()
+}
+
+// text of a value read by the console, whatever its column type
+func formatValue(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return v
+	case time.Time:
+		if v.Equal(v.Truncate(24 * time.Hour)) { // values of date columns
+			return v.Format("2006-01-02")
+		}
+		return v.Format("2006-01-02 15:04:05")
+	case []byte:
+		return `\x` + hex.EncodeToString(v)
+	case driver.Valuer: // numeric and other pgtype values
+		if value, err := v.Value(); err == nil {
+			return formatValue(value)
+		}
+	}
+	return fmt.Sprint(v)
+}
+
+// one line label of a history entry
+func historyLabel(statement string, ranAt time.Time) string {
+	line := strings.Join(strings.Fields(statement), " ")
+	if r := []rune(line); len(r) > historyLabelLength {
+		line = string(r[:historyLabelLength]) + "…"
+	}
+	return ranAt.Local().Format("02.01 15:04") + "  " + line
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"university-db-admin/internal/domain"
 	"university-db-admin/internal/repository"
+	"university-db-admin/internal/service"
 	"university-db-admin/pkg/validation"
 
 	"fyne.io/fyne/v2"
@@ -12,32 +13,31 @@ import (
 	"fyne.io/fyne/v2/widget"
 )
 
-func ShowEmployeesForm(content *fyne.Container, action int, r *repository.Repository) {
+func ShowEmployeesForm(content *fyne.Container, w fyne.Window, action int, s *service.Service) {
 	content.Objects = nil
 
 	switch action {
 	case 0:
-		showAddEmployeesForm(content, r)
+		showAddEmployeesForm(content, s)
 	case 1:
-		showDeleteEmployeesForm(content, r)
+		showDeleteEmployeesForm(content, s)
 	case 2:
-		showUpdateEmployeesForm(content, r)
+		showUpdateEmployeesForm(content, s)
 	case 3:
-		showEmployeesList(content, r)
+		showEmployeesList(content, w, s)
 	}
 
 	content.Refresh()
 }
 
-func showAddEmployeesForm(content *fyne.Container, r *repository.Repository) {
+func showAddEmployeesForm(content *fyne.Container, s *service.Service) {
 	nameEntry := widget.NewEntry()
 	nameEntry.SetPlaceHolder("Имя")
 
 	passportEntry := widget.NewEntry()
 	passportEntry.SetPlaceHolder("Паспорт")
 
-	positionEntry := widget.NewEntry()
-	positionEntry.SetPlaceHolder("ID Должности")
+	positionEntry := newPicker("Должность", loadPositions)
 
 	submitButton := widget.NewButton("Добавить", func() {
 		err := validation.ValidateEmptyStrings(
@@ -46,26 +46,22 @@ func showAddEmployeesForm(content *fyne.Container, r *repository.Repository) {
 			positionEntry.Text,
 		)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
+			showResult(content, "Ошибка: "+errorMessage(err))
 			return
 		}
 
 		employee := domain.Employee{
 			Name:       nameEntry.Text,
 			Passport:   passportEntry.Text,
-			PositionID: parseUint64(positionEntry.Text),
+			PositionID: positionEntry.id(),
 		}
 
-		if err = validation.ValidateStruct(employee); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-
-		if err = r.Employees.Create(context.Background(), employee); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-		showResult(content, "Сотрудник успешно добавлен")
+		runQuery(content, timeoutOf("employees", queryTimeout), func(ctx context.Context) error {
+			_, err := s.Employees.Create(ctx, employee)
+			return err
+		}, func() {
+			showResult(content, "Сотрудник успешно добавлен")
+		})
 	})
 
 	form := container.NewVBox(
@@ -76,32 +72,27 @@ func showAddEmployeesForm(content *fyne.Container, r *repository.Repository) {
 		submitButton,
 	)
 
-	content.Add(form)
+	showWithPickers(content, s, form, positionEntry)
 }
 
-func showDeleteEmployeesForm(content *fyne.Container, r *repository.Repository) {
+func showDeleteEmployeesForm(content *fyne.Container, s *service.Service) {
 	idEntry := widget.NewEntry()
 	idEntry.SetPlaceHolder("ID сотрудника")
 
 	deleteButton := widget.NewButton("Удалить", func() {
 		err := validation.ValidateEmptyStrings(idEntry.Text)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
+			showResult(content, "Ошибка: "+errorMessage(err))
 			return
 		}
 
 		id := parseUint64(idEntry.Text)
-		err = validation.ValidatePositiveNumbers(id)
-		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
 
-		if err = r.Employees.Delete(context.Background(), id); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-		showResult(content, "Сотрудник удалён")
+		runQuery(content, timeoutOf("employees", queryTimeout), func(ctx context.Context) error {
+			return s.Employees.Delete(ctx, id)
+		}, func() {
+			showResult(content, "Сотрудник перемещён в корзину")
+		})
 	})
 
 	form := container.NewVBox(
@@ -113,7 +104,7 @@ func showDeleteEmployeesForm(content *fyne.Container, r *repository.Repository)
 	content.Add(form)
 }
 
-func showUpdateEmployeesForm(content *fyne.Container, r *repository.Repository) {
+func showUpdateEmployeesForm(content *fyne.Container, s *service.Service) {
 	idEntry := widget.NewEntry()
 	idEntry.SetPlaceHolder("ID сотрудника")
 
@@ -123,8 +114,7 @@ func showUpdateEmployeesForm(content *fyne.Container, r *repository.Repository)
 	passportEntry := widget.NewEntry()
 	passportEntry.SetPlaceHolder("Новый паспорт")
 
-	positionEntry := widget.NewEntry()
-	positionEntry.SetPlaceHolder("Новый ID Должности")
+	positionEntry := newPicker("Новая должность", loadPositions)
 
 	updateButton := widget.NewButton("Обновить", func() {
 		err := validation.ValidateEmptyStrings(
@@ -134,7 +124,7 @@ func showUpdateEmployeesForm(content *fyne.Container, r *repository.Repository)
 			positionEntry.Text,
 		)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
+			showResult(content, "Ошибка: "+errorMessage(err))
 			return
 		}
 
@@ -142,19 +132,14 @@ func showUpdateEmployeesForm(content *fyne.Container, r *repository.Repository)
 			ID:         parseUint64(idEntry.Text),
 			Name:       nameEntry.Text,
 			Passport:   passportEntry.Text,
-			PositionID: parseUint64(positionEntry.Text),
-		}
-
-		if err = validation.ValidateStruct(employee); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
+			PositionID: positionEntry.id(),
 		}
 
-		if err = r.Employees.Update(context.Background(), employee.ID, employee); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-		showResult(content, "Сотрудник обновлён")
+		runQuery(content, timeoutOf("employees", queryTimeout), func(ctx context.Context) error {
+			return s.Employees.Update(ctx, employee.ID, employee)
+		}, func() {
+			showResult(content, "Сотрудник обновлён")
+		})
 	})
 
 	form := container.NewVBox(
@@ -166,81 +151,25 @@ func showUpdateEmployeesForm(content *fyne.Container, r *repository.Repository)
 		updateButton,
 	)
 
-	content.Add(form)
+	showWithPickers(content, s, form, positionEntry)
 }
 
-func showEmployeesList(content *fyne.Container, r *repository.Repository) {
-	headers := []string{
-		"ID сотрудника",
-		"Имя",
-		"Паспорт",
-		"ID Должности",
-	}
-	options := []string{
-		"Все",
-		"ID",
-		"Имя",
-		"Паспорт",
-		"ID Должности",
+func showEmployeesList(content *fyne.Container, w fyne.Window, s *service.Service) {
+	columns := []listColumn{
+		{"ID сотрудника", "id"},
+		{"Имя", "name"},
+		{"Паспорт", "passport"},
+		{"ID Должности", "position_id"},
 	}
-	filterOptions := map[string]uint8{
-		"Все":          0,
-		"ID":           1,
-		"Имя":          2,
-		"Паспорт":      3,
-		"ID Должности": 4,
-	}
-
-	filterEntry := widget.NewEntry()
-	filterEntry.SetPlaceHolder("Введите значение")
-
-	var selectedField uint8
-	filterSelect := widget.NewSelect(options, func(value string) {
-		selectedField = filterOptions[value]
-
-		if selectedField == 0 {
-			filterEntry.SetText("")
-			filterEntry.Disable()
-		} else {
-			filterEntry.Enable()
-		}
-	})
-
-	var data [][]string
-	applyFilterButton := widget.NewButton("Применить фильтр", func() {
-		data = nil
-
-		var (
-			employees []domain.Employee
-			emp       domain.Employee
-			err       error
-		)
-
-		switch selectedField {
-		case 0:
-			employees, err = r.Employees.FindAll(context.Background())
-		case 1:
-			emp, err = r.Employees.FindOne(context.Background(), parseUint64(filterEntry.Text))
-			if err == nil {
-				employees = append(employees, emp)
-			}
-		case 2:
-			employees, err = r.Employees.FindByName(context.Background(), filterEntry.Text)
-		case 3:
-			emp, err = r.Employees.FindByPassport(context.Background(), filterEntry.Text)
-			if err == nil {
-				employees = append(employees, emp)
-			}
-		case 4:
-			employees, err = r.Employees.FindByPosition(context.Background(), parseUint64(filterEntry.Text))
-		}
 
+	showPagedList(content, w, "employees", "Сотрудники", "Фильтрация сотрудников", columns, func(ctx context.Context, opts repository.ListOptions) ([][]string, uint64, error) {
+		page, err := s.Employees.List(ctx, opts)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
+			return nil, 0, err
 		}
 
-		for _, e := range employees {
+		data := make([][]string, 0, len(page.Items))
+		for _, e := range page.Items {
 			data = append(data, []string{
 				fmt.Sprintf("%d", e.ID),
 				e.Name,
@@ -248,33 +177,6 @@ func showEmployeesList(content *fyne.Container, r *repository.Repository) {
 				fmt.Sprintf("%d", e.PositionID),
 			})
 		}
-
-		content.Objects = content.Objects[:1] // Only filter widgets remain
-		content.Add(updateTable(headers, data))
-		content.Refresh()
+		return data, page.Total, nil
 	})
-
-	employees, err := r.Employees.FindAll(context.Background())
-	if err != nil {
-		showResult(content, "Ошибка: "+err.Error())
-		return
-	}
-	for _, e := range employees {
-		data = append(data, []string{
-			fmt.Sprintf("%d", e.ID),
-			e.Name,
-			e.Passport,
-			fmt.Sprintf("%d", e.PositionID),
-		})
-	}
-
-	filterContainer := container.NewVBox(
-		widget.NewLabel("Фильтрация сотрудников"),
-		filterSelect,
-		filterEntry,
-		applyFilterButton,
-	)
-
-	content.Add(filterContainer)
-	content.Add(updateTable(headers, data))
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"university-db-admin/internal/domain"
 	"university-db-admin/internal/repository"
+	"university-db-admin/internal/service"
 	"university-db-admin/pkg/validation"
 
 	"fyne.io/fyne/v2"
@@ -12,62 +13,44 @@ import (
 	"fyne.io/fyne/v2/widget"
 )
 
-func ShowEmployeesSubjectsForm(content *fyne.Container, action int, r *repository.Repository) {
+func ShowEmployeesSubjectsForm(content *fyne.Container, w fyne.Window, action int, s *service.Service) {
 	content.Objects = nil
 
 	switch action {
 	case 0:
-		showAddEmployeesSubjectsForm(content, r)
+		showAddEmployeesSubjectsForm(content, s)
 	case 1:
-		showDeleteEmployeesSubjectsForm(content, r)
+		showDeleteEmployeesSubjectsForm(content, s)
 	case 2:
-		showUpdateEmployeesSubjectsForm(content, r)
+		showUpdateEmployeesSubjectsForm(content, s)
 	case 3:
-		showEmployeesSubjectsList(content, r)
+		showEmployeesSubjectsList(content, w, s)
 	}
 
 	content.Refresh()
 }
 
-func showAddEmployeesSubjectsForm(content *fyne.Container, r *repository.Repository) {
-	employeeEntry := widget.NewEntry()
-	employeeEntry.SetPlaceHolder("ID преподавателя")
-
-	subjectEntry := widget.NewEntry()
-	subjectEntry.SetPlaceHolder("ID предмета")
+func showAddEmployeesSubjectsForm(content *fyne.Container, s *service.Service) {
+	employeeEntry := newPicker("Преподаватель", loadTeachers)
+	subjectEntry := newPicker("Предмет", loadSubjects)
 
 	submitButton := widget.NewButton("Добавить", func() {
 		err := validation.ValidateEmptyStrings(employeeEntry.Text, subjectEntry.Text)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
+			showResult(content, "Ошибка: "+errorMessage(err))
 			return
 		}
 
 		es := domain.EmployeeSubject{
-			EmployeeID: parseUint64(employeeEntry.Text),
-			SubjectID:  parseUint64(subjectEntry.Text),
-		}
-
-		if err = validation.ValidateStruct(es); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-
-		res, err := r.Employees.IsTeacher(context.Background(), parseUint64(employeeEntry.Text))
-		if !res.IsTeacher {
-			if err != nil {
-				showResult(content, "Ошибка: "+err.Error())
-			} else {
-				showResult(content, "Ошибка: Указанный сотрудник не является преподавателем")
-			}
-			return
+			EmployeeID: employeeEntry.id(),
+			SubjectID:  subjectEntry.id(),
 		}
 
-		if err = r.EmployeesSubjects.Create(context.Background(), es); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-		showResult(content, "Знание предмета успешно добавлено")
+		runQuery(content, timeoutOf("employees_subjects", queryTimeout), func(ctx context.Context) error {
+			return s.EmployeesSubjects.Create(ctx, es)
+		}, func() {
+			showResult(content, "Знание предмета успешно добавлено")
+		})
 	})
 
 	form := container.NewVBox(
@@ -77,36 +60,29 @@ func showAddEmployeesSubjectsForm(content *fyne.Container, r *repository.Reposit
 		submitButton,
 	)
 
-	content.Add(form)
+	showWithPickers(content, s, form, employeeEntry, subjectEntry)
 }
 
-func showDeleteEmployeesSubjectsForm(content *fyne.Container, r *repository.Repository) {
-	employeeEntry := widget.NewEntry()
-	employeeEntry.SetPlaceHolder("ID преподавателя")
-
-	subjectEntry := widget.NewEntry()
-	subjectEntry.SetPlaceHolder("ID предмета")
+func showDeleteEmployeesSubjectsForm(content *fyne.Container, s *service.Service) {
+	employeeEntry := newPicker("Преподаватель", loadTeachers)
+	subjectEntry := newPicker("Предмет", nil)
+	linkSubjects(content, s, employeeEntry, subjectEntry)
 
 	deleteButton := widget.NewButton("Удалить", func() {
 		err := validation.ValidateEmptyStrings(employeeEntry.Text, subjectEntry.Text)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
+			showResult(content, "Ошибка: "+errorMessage(err))
 			return
 		}
 
-		employeeID := parseUint64(employeeEntry.Text)
-		subjectID := parseUint64(subjectEntry.Text)
-		err = validation.ValidatePositiveNumbers(employeeID, subjectID)
-		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
+		employeeID := employeeEntry.id()
+		subjectID := subjectEntry.id()
 
-		if err = r.EmployeesSubjects.Delete(context.Background(), employeeID, subjectID); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-		showResult(content, "Знание предмета удалено")
+		runQuery(content, timeoutOf("employees_subjects", queryTimeout), func(ctx context.Context) error {
+			return s.EmployeesSubjects.Delete(ctx, employeeID, subjectID)
+		}, func() {
+			showResult(content, "Знание предмета удалено")
+		})
 	})
 
 	form := container.NewVBox(
@@ -116,21 +92,16 @@ func showDeleteEmployeesSubjectsForm(content *fyne.Container, r *repository.Repo
 		deleteButton,
 	)
 
-	content.Add(form)
+	showWithPickers(content, s, form, employeeEntry)
 }
 
-func showUpdateEmployeesSubjectsForm(content *fyne.Container, r *repository.Repository) {
-	employeeEntry := widget.NewEntry()
-	employeeEntry.SetPlaceHolder("ID преподавателя")
+func showUpdateEmployeesSubjectsForm(content *fyne.Container, s *service.Service) {
+	employeeEntry := newPicker("Преподаватель", loadTeachers)
+	subjectEntry := newPicker("Предмет", nil)
+	linkSubjects(content, s, employeeEntry, subjectEntry)
 
-	subjectEntry := widget.NewEntry()
-	subjectEntry.SetPlaceHolder("ID предмета")
-
-	newEmployeeEntry := widget.NewEntry()
-	newEmployeeEntry.SetPlaceHolder("Новый ID преподавателя")
-
-	newSubjectEntry := widget.NewEntry()
-	newSubjectEntry.SetPlaceHolder("Новый ID предмета")
+	newEmployeeEntry := newPicker("Новый преподаватель", loadTeachers)
+	newSubjectEntry := newPicker("Новый предмет", loadSubjects)
 
 	updateButton := widget.NewButton("Обновить", func() {
 		err := validation.ValidateEmptyStrings(
@@ -140,43 +111,23 @@ func showUpdateEmployeesSubjectsForm(content *fyne.Container, r *repository.Repo
 			newSubjectEntry.Text,
 		)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
+			showResult(content, "Ошибка: "+errorMessage(err))
 			return
 		}
 
-		eid := parseUint64(employeeEntry.Text)
-		sid := parseUint64(subjectEntry.Text)
-		err = validation.ValidatePositiveNumbers(eid, sid)
-		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
+		eid := employeeEntry.id()
+		sid := subjectEntry.id()
 
 		es := domain.EmployeeSubject{
-			EmployeeID: parseUint64(newEmployeeEntry.Text),
-			SubjectID:  parseUint64(newSubjectEntry.Text),
-		}
-
-		if err = validation.ValidateStruct(es); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-
-		res, err := r.Employees.IsTeacher(context.Background(), parseUint64(employeeEntry.Text))
-		if !res.IsTeacher {
-			if err != nil {
-				showResult(content, "Ошибка: "+err.Error())
-			} else {
-				showResult(content, "Ошибка: указанный сотрудник не является преподавателем")
-			}
-			return
+			EmployeeID: newEmployeeEntry.id(),
+			SubjectID:  newSubjectEntry.id(),
 		}
 
-		if err = r.EmployeesSubjects.Update(context.Background(), eid, sid, es); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-		showResult(content, "Знание предмета обновлено")
+		runQuery(content, timeoutOf("employees_subjects", queryTimeout), func(ctx context.Context) error {
+			return s.EmployeesSubjects.Update(ctx, eid, sid, es)
+		}, func() {
+			showResult(content, "Знание предмета обновлено")
+		})
 	})
 
 	form := container.NewVBox(
@@ -188,94 +139,28 @@ func showUpdateEmployeesSubjectsForm(content *fyne.Container, r *repository.Repo
 		updateButton,
 	)
 
-	content.Add(form)
+	showWithPickers(content, s, form, employeeEntry, newEmployeeEntry, newSubjectEntry)
 }
 
-func showEmployeesSubjectsList(content *fyne.Container, r *repository.Repository) {
-	headers := []string{
-		"ID преподавателя",
-		"ID предмета",
-	}
-	options := []string{
-		"Все",
-		"ID преподавателя",
-		"ID предмета",
-	}
-	filterOptions := map[string]uint8{
-		"Все":              0,
-		"ID преподавателя": 1,
-		"ID предмета":      2,
+func showEmployeesSubjectsList(content *fyne.Container, w fyne.Window, s *service.Service) {
+	columns := []listColumn{
+		{"ID преподавателя", "employee_id"},
+		{"ID предмета", "subject_id"},
 	}
 
-	filterEntry := widget.NewEntry()
-	filterEntry.SetPlaceHolder("Введите значение")
-
-	var selectedField uint8
-	filterSelect := widget.NewSelect(options, func(value string) {
-		selectedField = filterOptions[value]
-
-		if selectedField == 0 {
-			filterEntry.SetText("")
-			filterEntry.Disable()
-		} else {
-			filterEntry.Enable()
-		}
-	})
-
-	var data [][]string
-	applyFilterButton := widget.NewButton("Применить фильтр", func() {
-		data = nil
-
-		var (
-			empSbjs []domain.EmployeeSubject
-			err     error
-		)
-
-		switch selectedField {
-		case 0:
-			empSbjs, err = r.EmployeesSubjects.FindAll(context.Background())
-		case 1:
-			empSbjs, err = r.EmployeesSubjects.FindByEmployeeID(context.Background(), parseUint64(filterEntry.Text))
-		case 2:
-			empSbjs, err = r.EmployeesSubjects.FindBySubjectID(context.Background(), parseUint64(filterEntry.Text))
-		}
-
+	showPagedList(content, w, "employees_subjects", "Знание предметов", "Фильтрация знания предмета", columns, func(ctx context.Context, opts repository.ListOptions) ([][]string, uint64, error) {
+		page, err := s.EmployeesSubjects.List(ctx, opts)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
+			return nil, 0, err
 		}
 
-		for _, e := range empSbjs {
+		data := make([][]string, 0, len(page.Items))
+		for _, e := range page.Items {
 			data = append(data, []string{
 				fmt.Sprintf("%d", e.EmployeeID),
 				fmt.Sprintf("%d", e.SubjectID),
 			})
 		}
-
-		content.Objects = content.Objects[:1] // Only filter widgets remain
-		content.Add(updateTable(headers, data))
-		content.Refresh()
+		return data, page.Total, nil
 	})
-
-	empSbjs, err := r.EmployeesSubjects.FindAll(context.Background())
-	if err != nil {
-		showResult(content, "Ошибка: "+err.Error())
-		return
-	}
-	for _, e := range empSbjs {
-		data = append(data, []string{
-			fmt.Sprintf("%d", e.EmployeeID),
-			fmt.Sprintf("%d", e.SubjectID),
-		})
-	}
-
-	filterContainer := container.NewVBox(
-		widget.NewLabel("Фильтрация знания предмета"),
-		filterSelect,
-		filterEntry,
-		applyFilterButton,
-	)
-
-	content.Add(filterContainer)
-	content.Add(updateTable(headers, data))
 }
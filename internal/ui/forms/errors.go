@@ -0,0 +1,111 @@
+package forms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"university-db-admin/internal/backup"
+	"university-db-admin/internal/repository"
+)
+
+// russian names of the tables reported by repository errors
+var tableLabels = map[string]string{
+	"employees":          "Сотрудники",
+	"groups":             "Группы",
+	"lesson_types":       "Типы занятий",
+	"lessons":            "Занятия",
+	"marks":              "Оценки",
+	"positions":          "Должности",
+	"students":           "Студенты",
+	"subjects":           "Предметы",
+	"employees_subjects": "Знание предметов",
+	"users":              "Учётные записи",
+}
+
+// russian names of the columns reported by repository errors
+var fieldLabels = map[string]string{
+	"name":                             "название",
+	"passport":                         "паспорт",
+	"number":                           "номер",
+	"employee_id":                      "ID сотрудника",
+	"student_id":                       "ID студента",
+	"subject_id":                       "ID предмета",
+	"group_id":                         "ID группы",
+	"position_id":                      "ID должности",
+	"lesson_type_id":                   "ID типа занятия",
+	"employee_id, subject_id":          "преподаватель и предмет",
+	"group_id, week, weekday, pair":    "группа и время занятия",
+	"employee_id, week, weekday, pair": "преподаватель и время занятия",
+	"room, week, weekday, pair":        "аудитория и время занятия",
+}
+
+// explanations of the check constraints declared in the migrations
+var constraintLabels = map[string]string{
+	"groups_number_check":        "номер группы должен быть положительным",
+	"lessons_week_check":         "номер недели должен быть положительным",
+	"lessons_weekday_check":      "день недели должен быть от 1 до 7",
+	"lessons_pair_check":         "номер пары должен быть положительным",
+	"lessons_room_check":         "номер аудитории должен быть положительным",
+	"marks_mark_check":           "оценка должна быть от 1 до 10",
+	"marks_grader_subject_check": "преподаватель не ведёт этот предмет, добавьте его в «Знание предметов»",
+	"marks_group_subject_check":  "у группы студента нет занятий по этому предмету",
+}
+
+func label(labels map[string]string, key string) string {
+	if l, ok := labels[key]; ok {
+		return l
+	}
+	return key
+}
+
+// turns an error returned by services or repositories into a message for the user
+func errorMessage(err error) string {
+	var (
+		dupErr   *repository.DuplicateError
+		refErr   *repository.ReferencedError
+		invErr   *repository.InvalidReferenceError
+		checkErr *repository.CheckViolationError
+		emptyErr *backup.NotEmptyError
+		restErr  *backup.RestoreError
+	)
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "запрос отменён"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "превышено время ожидания запроса"
+	case errors.As(err, &restErr):
+		return fmt.Sprintf("таблица «%s», запись %s: %s",
+			label(tableLabels, restErr.Table), restErr.ID, errorMessage(restErr.Err))
+	case errors.As(err, &emptyErr):
+		return fmt.Sprintf("без замены текущих записей восстановление возможно только в пустую базу данных, в таблице «%s» есть записи",
+			label(tableLabels, emptyErr.Table))
+	case errors.Is(err, backup.ErrMissingEmployee):
+		return "сотрудника, связанного с учётной записью преподавателя, нет в резервной копии"
+	case errors.Is(err, backup.ErrInvalidArchive):
+		return "файл не является резервной копией или повреждён: " + err.Error()
+	case errors.Is(err, repository.ErrNotFound):
+		return "запись не найдена"
+	case errors.Is(err, repository.ErrNotSupported):
+		return "операция недоступна для выбранной базы данных"
+	case errors.Is(err, repository.ErrInvalidOption):
+		return "недопустимое значение фильтра или сортировки"
+	case errors.As(err, &dupErr):
+		return fmt.Sprintf("в таблице «%s» уже есть запись с таким значением поля «%s»",
+			label(tableLabels, dupErr.Table), label(fieldLabels, dupErr.Field))
+	case errors.As(err, &refErr):
+		return fmt.Sprintf("запись нельзя удалить, на неё ссылаются записи из таблицы «%s»",
+			label(tableLabels, refErr.ReferencingTable))
+	case errors.As(err, &invErr):
+		return fmt.Sprintf("поле «%s» ссылается на несуществующую запись из таблицы «%s»",
+			label(fieldLabels, invErr.Field), label(tableLabels, invErr.ReferencedTable))
+	case errors.As(err, &checkErr):
+		if l, ok := constraintLabels[checkErr.Constraint]; ok {
+			return l
+		}
+		return fmt.Sprintf("значение нарушает ограничение «%s» таблицы «%s»",
+			checkErr.Constraint, label(tableLabels, checkErr.Table))
+	}
+
+	return err.Error()
+}
@@ -0,0 +1,63 @@
+package forms
+
+import (
+	"context"
+	"university-db-admin/internal/export"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+// loads the rows to be exported, lists load every matching row rather than the shown page
+type tableLoader func(ctx context.Context) ([][]string, error)
+
+// format picker and button saving a table to a file chosen by the user
+func newExportBar(w fyne.Window, title string, headers []string, load tableLoader) fyne.CanvasObject {
+	formats := make([]string, len(export.Formats))
+	for i, f := range export.Formats {
+		formats[i] = string(f)
+	}
+	formatSelect := widget.NewSelect(formats, nil)
+	formatSelect.SetSelected(string(export.XLSX))
+
+	exportButton := widget.NewButton("Экспорт", func() {
+		format := export.Format(formatSelect.Selected)
+
+		saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+			if err != nil {
+				dialog.ShowError(err, w)
+				return
+			}
+			if writer == nil {
+				return // cancelled
+			}
+
+			runQueryDialog(w, "Экспорт", timeoutOf("export", longQueryTimeout), func(ctx context.Context) error {
+				defer writer.Close()
+				rows, err := load(ctx)
+				if err != nil {
+					return err
+				}
+				return export.Write(writer, format, export.Table{Title: title, Headers: headers, Rows: rows})
+			}, func() {
+				dialog.ShowInformation("Экспорт", "Файл сохранён: "+writer.URI().Path(), w)
+			})
+		}, w)
+		saveDialog.SetFileName(title + "." + string(format))
+		saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{"." + string(format)}))
+		saveDialog.Show()
+	})
+
+	return container.NewHBox(formatSelect, exportButton)
+}
+
+// table of a finished query with an export bar under it
+func exportableTable(w fyne.Window, title string, headers []string, rows [][]string) *fyne.Container {
+	return container.NewVBox(
+		updateTable(headers, rows),
+		newExportBar(w, title, headers, func(context.Context) ([][]string, error) { return rows, nil }),
+	)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"university-db-admin/internal/domain"
 	"university-db-admin/internal/repository"
+	"university-db-admin/internal/service"
 	"university-db-admin/pkg/validation"
 
 	"fyne.io/fyne/v2"
@@ -12,31 +13,31 @@ import (
 	"fyne.io/fyne/v2/widget"
 )
 
-func ShowGroupsForm(content *fyne.Container, action int, r *repository.Repository) {
+func ShowGroupsForm(content *fyne.Container, w fyne.Window, action int, s *service.Service) {
 	content.Objects = nil
 
 	switch action {
 	case 0:
-		showAddGroupsForm(content, r)
+		showAddGroupsForm(content, s)
 	case 1:
-		showDeleteGroupsForm(content, r)
+		showDeleteGroupsForm(content, s)
 	case 2:
-		showUpdateGroupsForm(content, r)
+		showUpdateGroupsForm(content, s)
 	case 3:
-		showGroupsList(content, r)
+		showGroupsList(content, w, s)
 	}
 
 	content.Refresh()
 }
 
-func showAddGroupsForm(content *fyne.Container, r *repository.Repository) {
+func showAddGroupsForm(content *fyne.Container, s *service.Service) {
 	numberEntry := widget.NewEntry()
 	numberEntry.SetPlaceHolder("Номер")
 
 	submitButton := widget.NewButton("Добавить", func() {
 		err := validation.ValidateEmptyStrings(numberEntry.Text)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
+			showResult(content, "Ошибка: "+errorMessage(err))
 			return
 		}
 
@@ -44,16 +45,12 @@ func showAddGroupsForm(content *fyne.Container, r *repository.Repository) {
 			Number: parseUint64(numberEntry.Text),
 		}
 
-		if err = validation.ValidateStruct(group); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-
-		if err = r.Groups.Create(context.Background(), group); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-		showResult(content, "Группа успешно добавлена")
+		runQuery(content, timeoutOf("groups", queryTimeout), func(ctx context.Context) error {
+			_, err := s.Groups.Create(ctx, group)
+			return err
+		}, func() {
+			showResult(content, "Группа успешно добавлена")
+		})
 	})
 
 	form := container.NewVBox(
@@ -65,29 +62,24 @@ func showAddGroupsForm(content *fyne.Container, r *repository.Repository) {
 	content.Add(form)
 }
 
-func showDeleteGroupsForm(content *fyne.Container, r *repository.Repository) {
+func showDeleteGroupsForm(content *fyne.Container, s *service.Service) {
 	idEntry := widget.NewEntry()
 	idEntry.SetPlaceHolder("ID группы")
 
 	deleteButton := widget.NewButton("Удалить", func() {
 		err := validation.ValidateEmptyStrings(idEntry.Text)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
+			showResult(content, "Ошибка: "+errorMessage(err))
 			return
 		}
 
 		id := parseUint64(idEntry.Text)
-		err = validation.ValidatePositiveNumbers(id)
-		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
 
-		if err = r.Groups.Delete(context.Background(), id); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-		showResult(content, "Группа удалена")
+		runQuery(content, timeoutOf("groups", queryTimeout), func(ctx context.Context) error {
+			return s.Groups.Delete(ctx, id)
+		}, func() {
+			showResult(content, "Группа перемещена в корзину")
+		})
 	})
 
 	form := container.NewVBox(
@@ -99,7 +91,7 @@ func showDeleteGroupsForm(content *fyne.Container, r *repository.Repository) {
 	content.Add(form)
 }
 
-func showUpdateGroupsForm(content *fyne.Container, r *repository.Repository) {
+func showUpdateGroupsForm(content *fyne.Container, s *service.Service) {
 	idEntry := widget.NewEntry()
 	idEntry.SetPlaceHolder("ID группы")
 
@@ -109,7 +101,7 @@ func showUpdateGroupsForm(content *fyne.Container, r *repository.Repository) {
 	updateButton := widget.NewButton("Обновить", func() {
 		err := validation.ValidateEmptyStrings(idEntry.Text, numberEntry.Text)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
+			showResult(content, "Ошибка: "+errorMessage(err))
 			return
 		}
 
@@ -118,16 +110,11 @@ func showUpdateGroupsForm(content *fyne.Container, r *repository.Repository) {
 			Number: parseUint64(numberEntry.Text),
 		}
 
-		if err = validation.ValidateStruct(group); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-
-		if err = r.Groups.Update(context.Background(), group.ID, group); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-		showResult(content, "Группа обновлена")
+		runQuery(content, timeoutOf("groups", queryTimeout), func(ctx context.Context) error {
+			return s.Groups.Update(ctx, group.ID, group)
+		}, func() {
+			showResult(content, "Группа обновлена")
+		})
 	})
 
 	form := container.NewVBox(
@@ -140,98 +127,25 @@ func showUpdateGroupsForm(content *fyne.Container, r *repository.Repository) {
 	content.Add(form)
 }
 
-func showGroupsList(content *fyne.Container, r *repository.Repository) {
-	headers := []string{
-		"ID группы",
-		"Номер",
+func showGroupsList(content *fyne.Container, w fyne.Window, s *service.Service) {
+	columns := []listColumn{
+		{"ID группы", "id"},
+		{"Номер", "number"},
 	}
-	options := []string{
-		"Все",
-		"ID",
-		"Номер",
-	}
-	filterOptions := map[string]uint8{
-		"Все":   0,
-		"ID":    1,
-		"Номер": 2,
-	}
-
-	filterEntry := widget.NewEntry()
-	filterEntry.SetPlaceHolder("Введите значение")
-
-	var selectedField uint8
-	filterSelect := widget.NewSelect(options, func(value string) {
-		selectedField = filterOptions[value]
-
-		if selectedField == 0 {
-			filterEntry.SetText("")
-			filterEntry.Disable()
-		} else {
-			filterEntry.Enable()
-		}
-	})
-
-	var data [][]string
-	applyFilterButton := widget.NewButton("Применить фильтр", func() {
-		data = nil
-
-		var (
-			groups []domain.Group
-			grp    domain.Group
-			err    error
-		)
-
-		switch selectedField {
-		case 0:
-			groups, err = r.Groups.FindAll(context.Background())
-		case 1:
-			grp, err = r.Groups.FindOne(context.Background(), parseUint64(filterEntry.Text))
-			if err == nil {
-				groups = append(groups, grp)
-			}
-		case 2:
-			grp, err = r.Groups.FindByNumber(context.Background(), parseUint64(filterEntry.Text))
-			if err == nil {
-				groups = append(groups, grp)
-			}
-		}
 
+	showPagedList(content, w, "groups", "Группы", "Фильтрация групп", columns, func(ctx context.Context, opts repository.ListOptions) ([][]string, uint64, error) {
+		page, err := s.Groups.List(ctx, opts)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
+			return nil, 0, err
 		}
 
-		for _, g := range groups {
+		data := make([][]string, 0, len(page.Items))
+		for _, g := range page.Items {
 			data = append(data, []string{
 				fmt.Sprintf("%d", g.ID),
 				fmt.Sprintf("%d", g.Number),
 			})
 		}
-
-		content.Objects = content.Objects[:1] // Only filter widgets remain
-		content.Add(updateTable(headers, data))
-		content.Refresh()
+		return data, page.Total, nil
 	})
-
-	groups, err := r.Groups.FindAll(context.Background())
-	if err != nil {
-		showResult(content, "Ошибка: "+err.Error())
-		return
-	}
-	for _, g := range groups {
-		data = append(data, []string{
-			fmt.Sprintf("%d", g.ID),
-			fmt.Sprintf("%d", g.Number),
-		})
-	}
-
-	filterContainer := container.NewVBox(
-		widget.NewLabel("Фильтрация групп"),
-		filterSelect,
-		filterEntry,
-		applyFilterButton,
-	)
-
-	content.Add(filterContainer)
-	content.Add(updateTable(headers, data))
 }
@@ -0,0 +1,126 @@
+package forms
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"university-db-admin/internal/importer"
+	"university-db-admin/internal/service"
+	"university-db-admin/pkg/validation"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/storage"
+	"fyne.io/fyne/v2/widget"
+)
+
+var importKinds = map[string]importer.Kind{
+	"Студенты":   importer.Students,
+	"Сотрудники": importer.Employees,
+	"Оценки":     importer.Marks,
+}
+
+func ShowImportForm(content *fyne.Container, w fyne.Window, s *service.Service) {
+	content.Objects = nil
+
+	kindSelect := widget.NewSelect([]string{"Студенты", "Сотрудники", "Оценки"}, nil)
+	kindSelect.PlaceHolder = "Что импортировать"
+
+	fileEntry := widget.NewEntry()
+	fileEntry.SetPlaceHolder("Путь к файлу .csv или .xlsx")
+
+	browseButton := widget.NewButton("Выбрать файл", func() {
+		fileDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+			if err != nil || reader == nil {
+				return
+			}
+			defer reader.Close()
+			fileEntry.SetText(reader.URI().Path())
+		}, w)
+		fileDialog.SetFilter(storage.NewExtensionFileFilter([]string{".csv", ".xlsx"}))
+		fileDialog.Show()
+	})
+
+	result := container.NewVBox()
+
+	checkButton := widget.NewButton("Проверить", func() {
+		err := validation.ValidateEmptyStrings(kindSelect.Selected, fileEntry.Text)
+		if err != nil {
+			showResult(content, "Ошибка: "+errorMessage(err))
+			return
+		}
+
+		path, kind := fileEntry.Text, importKinds[kindSelect.Selected]
+		var preview *importer.Preview
+		runQuery(result, timeoutOf("import", longQueryTimeout), func(ctx context.Context) error {
+			sheet, err := importer.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			preview, err = importer.New(s).Preview(ctx, kind, sheet)
+			return err
+		}, func() {
+			showImportPreview(result, preview)
+		})
+	})
+
+	form := container.NewVBox(
+		widget.NewLabel("Столбцы файла сопоставляются по заголовкам первой строки"),
+		kindSelect,
+		container.NewBorder(nil, nil, nil, browseButton, fileEntry),
+		checkButton,
+	)
+
+	content.Add(form)
+	content.Add(result)
+}
+
+// shows every row with its problem and lets the user import the valid ones
+func showImportPreview(content *fyne.Container, preview *importer.Preview) {
+	headers := append([]string{"Строка"}, preview.Header...)
+	headers = append(headers, "Ошибка")
+
+	data := make([][]string, len(preview.Rows))
+	for i, row := range preview.Rows {
+		values := make([]string, len(preview.Header))
+		copy(values, row.Values)
+
+		status := ""
+		if row.Err != nil {
+			status = errorMessage(row.Err)
+		}
+
+		data[i] = append([]string{fmt.Sprintf("%d", row.Line)}, values...)
+		data[i] = append(data[i], status)
+	}
+
+	summary := widget.NewLabel(fmt.Sprintf("Корректных строк: %d, строк с ошибками: %d", preview.Valid(), preview.Invalid()))
+
+	importButton := widget.NewButton("Импортировать корректные строки", func() {
+		var n int
+		runQueryOr(content, timeoutOf("import", longQueryTimeout), func(ctx context.Context) error {
+			var err error
+			n, err = preview.Commit(ctx)
+			return err
+		}, func() {
+			showResult(content, fmt.Sprintf("Импортировано записей: %d", n))
+		}, func(err error) {
+			var lineErr *importer.LineError
+			if errors.As(err, &lineErr) {
+				showResult(content, fmt.Sprintf("Ошибка в строке %d: %s, ничего не импортировано", lineErr.Line, errorMessage(lineErr.Err)))
+				return
+			}
+			showResult(content, "Ошибка: "+errorMessage(err))
+		})
+	})
+	if preview.Valid() == 0 {
+		importButton.Disable()
+	}
+
+	content.Objects = nil
+	content.Add(summary)
+	content.Add(importButton)
+	content.Add(updateTable(headers, data))
+	content.Refresh()
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"university-db-admin/internal/domain"
 	"university-db-admin/internal/repository"
+	"university-db-admin/internal/service"
 	"university-db-admin/pkg/validation"
 
 	"fyne.io/fyne/v2"
@@ -12,31 +13,31 @@ import (
 	"fyne.io/fyne/v2/widget"
 )
 
-func ShowLessonTypesForm(content *fyne.Container, action int, r *repository.Repository) {
+func ShowLessonTypesForm(content *fyne.Container, w fyne.Window, action int, s *service.Service) {
 	content.Objects = nil
 
 	switch action {
 	case 0:
-		showAddLessonTypesForm(content, r)
+		showAddLessonTypesForm(content, s)
 	case 1:
-		showDeleteLessonTypesForm(content, r)
+		showDeleteLessonTypesForm(content, s)
 	case 2:
-		showUpdateLessonTypesForm(content, r)
+		showUpdateLessonTypesForm(content, s)
 	case 3:
-		showLessonTypesList(content, r)
+		showLessonTypesList(content, w, s)
 	}
 
 	content.Refresh()
 }
 
-func showAddLessonTypesForm(content *fyne.Container, r *repository.Repository) {
+func showAddLessonTypesForm(content *fyne.Container, s *service.Service) {
 	nameEntry := widget.NewEntry()
 	nameEntry.SetPlaceHolder("Название")
 
 	submitButton := widget.NewButton("Добавить", func() {
 		err := validation.ValidateEmptyStrings(nameEntry.Text)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
+			showResult(content, "Ошибка: "+errorMessage(err))
 			return
 		}
 
@@ -44,16 +45,12 @@ func showAddLessonTypesForm(content *fyne.Container, r *repository.Repository) {
 			Name: nameEntry.Text,
 		}
 
-		if err = validation.ValidateStruct(lessonType); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-
-		if err = r.LessonTypes.Create(context.Background(), lessonType); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-		showResult(content, "Тип занятия добавлен")
+		runQuery(content, timeoutOf("lesson_types", queryTimeout), func(ctx context.Context) error {
+			_, err := s.LessonTypes.Create(ctx, lessonType)
+			return err
+		}, func() {
+			showResult(content, "Тип занятия добавлен")
+		})
 	})
 
 	form := container.NewVBox(
@@ -65,29 +62,24 @@ func showAddLessonTypesForm(content *fyne.Container, r *repository.Repository) {
 	content.Add(form)
 }
 
-func showDeleteLessonTypesForm(content *fyne.Container, r *repository.Repository) {
+func showDeleteLessonTypesForm(content *fyne.Container, s *service.Service) {
 	idEntry := widget.NewEntry()
 	idEntry.SetPlaceHolder("ID типа занятия")
 
 	deleteButton := widget.NewButton("Удалить", func() {
 		err := validation.ValidateEmptyStrings(idEntry.Text)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
+			showResult(content, "Ошибка: "+errorMessage(err))
 			return
 		}
 
 		id := parseUint64(idEntry.Text)
-		err = validation.ValidatePositiveNumbers(id)
-		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
 
-		if err = r.LessonTypes.Delete(context.Background(), id); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-		showResult(content, "Тип занятия удален")
+		runQuery(content, timeoutOf("lesson_types", queryTimeout), func(ctx context.Context) error {
+			return s.LessonTypes.Delete(ctx, id)
+		}, func() {
+			showResult(content, "Тип занятия удален")
+		})
 	})
 
 	form := container.NewVBox(
@@ -99,7 +91,7 @@ func showDeleteLessonTypesForm(content *fyne.Container, r *repository.Repository
 	content.Add(form)
 }
 
-func showUpdateLessonTypesForm(content *fyne.Container, r *repository.Repository) {
+func showUpdateLessonTypesForm(content *fyne.Container, s *service.Service) {
 	idEntry := widget.NewEntry()
 	idEntry.SetPlaceHolder("ID типа занятия")
 
@@ -109,7 +101,7 @@ func showUpdateLessonTypesForm(content *fyne.Container, r *repository.Repository
 	updateButton := widget.NewButton("Обновить", func() {
 		err := validation.ValidateEmptyStrings(idEntry.Text, nameEntry.Text)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
+			showResult(content, "Ошибка: "+errorMessage(err))
 			return
 		}
 
@@ -118,16 +110,11 @@ func showUpdateLessonTypesForm(content *fyne.Container, r *repository.Repository
 			Name: nameEntry.Text,
 		}
 
-		if err = validation.ValidateStruct(lType); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-
-		if err = r.LessonTypes.Update(context.Background(), lType.ID, lType); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-		showResult(content, "Тип занятия обновлен")
+		runQuery(content, timeoutOf("lesson_types", queryTimeout), func(ctx context.Context) error {
+			return s.LessonTypes.Update(ctx, lType.ID, lType)
+		}, func() {
+			showResult(content, "Тип занятия обновлен")
+		})
 	})
 
 	form := container.NewVBox(
@@ -140,98 +127,25 @@ func showUpdateLessonTypesForm(content *fyne.Container, r *repository.Repository
 	content.Add(form)
 }
 
-func showLessonTypesList(content *fyne.Container, r *repository.Repository) {
-	headers := []string{
-		"ID типа занятия",
-		"Название",
+func showLessonTypesList(content *fyne.Container, w fyne.Window, s *service.Service) {
+	columns := []listColumn{
+		{"ID типа занятия", "id"},
+		{"Название", "name"},
 	}
-	options := []string{
-		"Все",
-		"ID",
-		"Название",
-	}
-	filterOptions := map[string]uint8{
-		"Все":      0,
-		"ID":       1,
-		"Название": 2,
-	}
-
-	filterEntry := widget.NewEntry()
-	filterEntry.SetPlaceHolder("Введите значение")
-
-	var selectedField uint8
-	filterSelect := widget.NewSelect(options, func(value string) {
-		selectedField = filterOptions[value]
-
-		if selectedField == 0 {
-			filterEntry.SetText("")
-			filterEntry.Disable()
-		} else {
-			filterEntry.Enable()
-		}
-	})
-
-	var data [][]string
-	applyFilterButton := widget.NewButton("Применить фильтр", func() {
-		data = nil
-
-		var (
-			lTypes []domain.LessonType
-			lType  domain.LessonType
-			err    error
-		)
-
-		switch selectedField {
-		case 0:
-			lTypes, err = r.LessonTypes.FindAll(context.Background())
-		case 1:
-			lType, err = r.LessonTypes.FindOne(context.Background(), parseUint64(filterEntry.Text))
-			if err == nil {
-				lTypes = append(lTypes, lType)
-			}
-		case 2:
-			lType, err = r.LessonTypes.FindByName(context.Background(), filterEntry.Text)
-			if err == nil {
-				lTypes = append(lTypes, lType)
-			}
-		}
 
+	showPagedList(content, w, "lesson_types", "Типы занятий", "Фильтрация типов занятий", columns, func(ctx context.Context, opts repository.ListOptions) ([][]string, uint64, error) {
+		page, err := s.LessonTypes.List(ctx, opts)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
+			return nil, 0, err
 		}
 
-		for _, l := range lTypes {
+		data := make([][]string, 0, len(page.Items))
+		for _, l := range page.Items {
 			data = append(data, []string{
 				fmt.Sprintf("%d", l.ID),
 				l.Name,
 			})
 		}
-
-		content.Objects = content.Objects[:1] // Only filter widgets remain
-		content.Add(updateTable(headers, data))
-		content.Refresh()
+		return data, page.Total, nil
 	})
-
-	lTypes, err := r.LessonTypes.FindAll(context.Background())
-	if err != nil {
-		showResult(content, "Ошибка: "+err.Error())
-		return
-	}
-	for _, l := range lTypes {
-		data = append(data, []string{
-			fmt.Sprintf("%d", l.ID),
-			l.Name,
-		})
-	}
-
-	filterContainer := container.NewVBox(
-		widget.NewLabel("Фильтрация типов занятий"),
-		filterSelect,
-		filterEntry,
-		applyFilterButton,
-	)
-
-	content.Add(filterContainer)
-	content.Add(updateTable(headers, data))
 }
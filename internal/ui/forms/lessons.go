@@ -3,8 +3,11 @@ package forms
 import (
 	"context"
 	"fmt"
+	"strings"
 	"university-db-admin/internal/domain"
 	"university-db-admin/internal/repository"
+	"university-db-admin/internal/schedule"
+	"university-db-admin/internal/service"
 	"university-db-admin/pkg/validation"
 
 	"fyne.io/fyne/v2"
@@ -12,32 +15,55 @@ import (
 	"fyne.io/fyne/v2/widget"
 )
 
-func ShowLessonsForm(content *fyne.Container, action int, r *repository.Repository) {
+func ShowLessonsForm(content *fyne.Container, w fyne.Window, action int, s *service.Service) {
 	content.Objects = nil
 
 	switch action {
 	case 0:
-		showAddLessonsForm(content, r)
+		showAddLessonsForm(content, s)
 	case 1:
-		showDeleteLessonsForm(content, r)
+		showDeleteLessonsForm(content, s)
 	case 2:
-		showUpdateLessonsForm(content, r)
+		showUpdateLessonsForm(content, s)
 	case 3:
-		showLessonsList(content, r)
+		showLessonsList(content, w, s)
 	}
 
 	content.Refresh()
 }
 
-func showAddLessonsForm(content *fyne.Container, r *repository.Repository) {
-	groupEntry := widget.NewEntry()
-	groupEntry.SetPlaceHolder("ID группы")
+// select of the pairs of the bell schedule with their time
+func newPairSelect(placeholder string, bells schedule.BellSchedule) *widget.Select {
+	options := make([]string, len(bells))
+	for i, b := range bells {
+		options[i] = fmt.Sprintf("%d (%s)", i+1, b)
+	}
+	sel := widget.NewSelect(options, nil)
+	sel.PlaceHolder = placeholder
+	return sel
+}
 
-	subjectEntry := widget.NewEntry()
-	subjectEntry.SetPlaceHolder("ID предмета")
+// pair chosen in sel, 0 when none is
+func pairOf(sel *widget.Select) uint16 {
+	return uint16(sel.SelectedIndex() + 1)
+}
 
-	lTypeEntry := widget.NewEntry()
-	lTypeEntry.SetPlaceHolder("ID типа занятия")
+// teacher picked in tp, nil for a lesson without one; text that isn't
+// a teacher of the subject gives id 0 rejected by the service validation
+func teacherOf(tp *picker) *uint64 {
+	if strings.TrimSpace(tp.Text) == "" {
+		return nil
+	}
+	id := tp.id()
+	return &id
+}
+
+func showAddLessonsForm(content *fyne.Container, s *service.Service) {
+	groupEntry := newPicker("Группа", loadGroups)
+	subjectEntry := newPicker("Предмет", loadSubjects)
+	lTypeEntry := newPicker("Тип занятия", loadLessonTypes)
+	teacherEntry := newPicker("Преподаватель (необязательно)", nil)
+	linkTeachers(content, s, subjectEntry, teacherEntry)
 
 	weekEntry := widget.NewEntry()
 	weekEntry.SetPlaceHolder("Неделя")
@@ -45,6 +71,8 @@ func showAddLessonsForm(content *fyne.Container, r *repository.Repository) {
 	weekdayEntry := widget.NewEntry()
 	weekdayEntry.SetPlaceHolder("День недели")
 
+	pairSelect := newPairSelect("Пара", s.Schedule.Bells())
+
 	roomEntry := widget.NewEntry()
 	roomEntry.SetPlaceHolder("Аудитория")
 
@@ -55,71 +83,67 @@ func showAddLessonsForm(content *fyne.Container, r *repository.Repository) {
 			lTypeEntry.Text,
 			weekEntry.Text,
 			weekdayEntry.Text,
+			pairSelect.Selected,
 			roomEntry.Text,
 		)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
+			showResult(content, "Ошибка: "+errorMessage(err))
 			return
 		}
 
 		lesson := domain.Lesson{
-			GroupID:      parseUint64(groupEntry.Text),
-			SubjectID:    parseUint64(subjectEntry.Text),
-			LessonTypeID: parseUint64(lTypeEntry.Text),
+			GroupID:      groupEntry.id(),
+			SubjectID:    subjectEntry.id(),
+			LessonTypeID: lTypeEntry.id(),
 			Week:         parseUint16(weekEntry.Text),
 			Weekday:      parseUint16(weekdayEntry.Text),
+			Pair:         pairOf(pairSelect),
 			Room:         parseUint64(roomEntry.Text),
+			EmployeeID:   teacherOf(teacherEntry),
 		}
 
-		if err = validation.ValidateStruct(lesson); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-
-		if err = r.Lessons.Create(context.Background(), lesson); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-		showResult(content, "Занятие успешно добавлено")
+		runQuery(content, timeoutOf("lessons", queryTimeout), func(ctx context.Context) error {
+			_, err := s.Schedule.Create(ctx, lesson)
+			return err
+		}, func() {
+			showResult(content, "Занятие успешно добавлено")
+		})
 	})
 
 	form := container.NewVBox(
 		widget.NewLabel("Добавление занятия"),
 		groupEntry,
 		subjectEntry,
+		teacherEntry,
 		lTypeEntry,
 		weekEntry,
 		weekdayEntry,
+		pairSelect,
 		roomEntry,
 		submitButton,
 	)
 
-	content.Add(form)
+	showWithPickers(content, s, form, groupEntry, subjectEntry, lTypeEntry)
 }
 
-func showDeleteLessonsForm(content *fyne.Container, r *repository.Repository) {
+func showDeleteLessonsForm(content *fyne.Container, s *service.Service) {
 	idEntry := widget.NewEntry()
 	idEntry.SetPlaceHolder("ID занятия")
 
 	deleteButton := widget.NewButton("Удалить", func() {
 		err := validation.ValidateEmptyStrings(idEntry.Text)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
+			showResult(content, "Ошибка: "+errorMessage(err))
 			return
 		}
 
 		id := parseUint64(idEntry.Text)
-		err = validation.ValidatePositiveNumbers(id)
-		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
 
-		if err = r.Lessons.Delete(context.Background(), id); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-		showResult(content, "Занятие удалено")
+		runQuery(content, timeoutOf("lessons", queryTimeout), func(ctx context.Context) error {
+			return s.Schedule.Delete(ctx, id)
+		}, func() {
+			showResult(content, "Занятие удалено")
+		})
 	})
 
 	form := container.NewVBox(
@@ -131,18 +155,15 @@ func showDeleteLessonsForm(content *fyne.Container, r *repository.Repository) {
 	content.Add(form)
 }
 
-func showUpdateLessonsForm(content *fyne.Container, r *repository.Repository) {
+func showUpdateLessonsForm(content *fyne.Container, s *service.Service) {
 	idEntry := widget.NewEntry()
 	idEntry.SetPlaceHolder("ID занятия")
 
-	groupEntry := widget.NewEntry()
-	groupEntry.SetPlaceHolder("Новый ID группы")
-
-	subjectEntry := widget.NewEntry()
-	subjectEntry.SetPlaceHolder("Новый ID предмета")
-
-	lTypeEntry := widget.NewEntry()
-	lTypeEntry.SetPlaceHolder("Новый ID типа занятия")
+	groupEntry := newPicker("Новая группа", loadGroups)
+	subjectEntry := newPicker("Новый предмет", loadSubjects)
+	lTypeEntry := newPicker("Новый тип занятия", loadLessonTypes)
+	teacherEntry := newPicker("Новый преподаватель (необязательно)", nil)
+	linkTeachers(content, s, subjectEntry, teacherEntry)
 
 	weekEntry := widget.NewEntry()
 	weekEntry.SetPlaceHolder("Новая неделя")
@@ -150,6 +171,8 @@ func showUpdateLessonsForm(content *fyne.Container, r *repository.Repository) {
 	weekdayEntry := widget.NewEntry()
 	weekdayEntry.SetPlaceHolder("Новый день недели")
 
+	pairSelect := newPairSelect("Новая пара", s.Schedule.Bells())
+
 	roomEntry := widget.NewEntry()
 	roomEntry.SetPlaceHolder("Новая аудитория")
 
@@ -161,33 +184,31 @@ func showUpdateLessonsForm(content *fyne.Container, r *repository.Repository) {
 			lTypeEntry.Text,
 			weekEntry.Text,
 			weekdayEntry.Text,
+			pairSelect.Selected,
 			roomEntry.Text,
 		)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
+			showResult(content, "Ошибка: "+errorMessage(err))
 			return
 		}
 
 		lesson := domain.Lesson{
 			ID:           parseUint64(idEntry.Text),
-			GroupID:      parseUint64(groupEntry.Text),
-			SubjectID:    parseUint64(subjectEntry.Text),
-			LessonTypeID: parseUint64(lTypeEntry.Text),
+			GroupID:      groupEntry.id(),
+			SubjectID:    subjectEntry.id(),
+			LessonTypeID: lTypeEntry.id(),
 			Week:         parseUint16(weekEntry.Text),
 			Weekday:      parseUint16(weekdayEntry.Text),
+			Pair:         pairOf(pairSelect),
 			Room:         parseUint64(roomEntry.Text),
+			EmployeeID:   teacherOf(teacherEntry),
 		}
 
-		if err = validation.ValidateStruct(lesson); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-
-		if err = r.Lessons.Update(context.Background(), lesson.ID, lesson); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-		showResult(content, "Занятие обновлено")
+		runQuery(content, timeoutOf("lessons", queryTimeout), func(ctx context.Context) error {
+			return s.Schedule.Update(ctx, lesson.ID, lesson)
+		}, func() {
+			showResult(content, "Занятие обновлено")
+		})
 	})
 
 	form := container.NewVBox(
@@ -195,100 +216,43 @@ func showUpdateLessonsForm(content *fyne.Container, r *repository.Repository) {
 		idEntry,
 		groupEntry,
 		subjectEntry,
+		teacherEntry,
 		lTypeEntry,
 		weekEntry,
 		weekdayEntry,
+		pairSelect,
 		roomEntry,
 		updateButton,
 	)
 
-	content.Add(form)
+	showWithPickers(content, s, form, groupEntry, subjectEntry, lTypeEntry)
 }
 
-func showLessonsList(content *fyne.Container, r *repository.Repository) {
-	headers := []string{
-		"ID занятия",
-		"ID группы",
-		"ID предмета",
-		"ID типа занятия",
-		"Неделя",
-		"День недели",
-		"Аудитория",
-	}
-	options := []string{
-		"Все",
-		"ID",
-		"ID группы",
-		"ID предмета",
-		"ID типа занятия",
-		"Неделя",
-		"День недели",
-		"Аудитория",
+func showLessonsList(content *fyne.Container, w fyne.Window, s *service.Service) {
+	columns := []listColumn{
+		{"ID занятия", "id"},
+		{"ID группы", "group_id"},
+		{"ID предмета", "subject_id"},
+		{"ID типа занятия", "lesson_type_id"},
+		{"Неделя", "week"},
+		{"День недели", "weekday"},
+		{"Пара", "pair"},
+		{"Аудитория", "room"},
+		{"ID преподавателя", "employee_id"},
 	}
-	filterOptions := map[string]uint8{
-		"Все":             0,
-		"ID":              1,
-		"ID группы":       2,
-		"ID предмета":     3,
-		"ID типа занятия": 4,
-		"Неделя":          5,
-		"День недели":     6,
-		"Аудитория":       7,
-	}
-
-	filterEntry := widget.NewEntry()
-	filterEntry.SetPlaceHolder("Введите значение")
-
-	var selectedField uint8
-	filterSelect := widget.NewSelect(options, func(value string) {
-		selectedField = filterOptions[value]
-
-		if selectedField == 0 {
-			filterEntry.SetText("")
-			filterEntry.Disable()
-		} else {
-			filterEntry.Enable()
-		}
-	})
-
-	var data [][]string
-	applyFilterButton := widget.NewButton("Применить фильтр", func() {
-		data = nil
-
-		var (
-			lessons []domain.Lesson
-			lesson  domain.Lesson
-			err     error
-		)
-
-		switch selectedField {
-		case 0:
-			lessons, err = r.Lessons.FindAll(context.Background())
-		case 1:
-			lesson, err = r.Lessons.FindOne(context.Background(), parseUint64(filterEntry.Text))
-			if err == nil {
-				lessons = append(lessons, lesson)
-			}
-		case 2:
-			lessons, err = r.Lessons.FindByGroupID(context.Background(), parseUint64(filterEntry.Text))
-		case 3:
-			lessons, err = r.Lessons.FindBySubjectID(context.Background(), parseUint64(filterEntry.Text))
-		case 4:
-			lessons, err = r.Lessons.FindByLessonTypeID(context.Background(), parseUint64(filterEntry.Text))
-		case 5:
-			lessons, err = r.Lessons.FindByWeek(context.Background(), parseUint16(filterEntry.Text))
-		case 6:
-			lessons, err = r.Lessons.FindByWeekday(context.Background(), parseUint16(filterEntry.Text))
-		case 7:
-			lessons, err = r.Lessons.FindByRoom(context.Background(), parseUint64(filterEntry.Text))
-		}
 
+	showPagedList(content, w, "lessons", "Занятия", "Фильтрация занятий", columns, func(ctx context.Context, opts repository.ListOptions) ([][]string, uint64, error) {
+		page, err := s.Schedule.List(ctx, opts)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
+			return nil, 0, err
 		}
 
-		for _, l := range lessons {
+		data := make([][]string, 0, len(page.Items))
+		for _, l := range page.Items {
+			var teacher string // empty for lessons without a teacher
+			if l.EmployeeID != nil {
+				teacher = fmt.Sprintf("%d", *l.EmployeeID)
+			}
 			data = append(data, []string{
 				fmt.Sprintf("%d", l.ID),
 				fmt.Sprintf("%d", l.GroupID),
@@ -296,39 +260,11 @@ func showLessonsList(content *fyne.Container, r *repository.Repository) {
 				fmt.Sprintf("%d", l.LessonTypeID),
 				fmt.Sprintf("%d", l.Week),
 				fmt.Sprintf("%d", l.Weekday),
+				fmt.Sprintf("%d (%s)", l.Pair, s.Schedule.Bells().Time(l.Pair)),
 				fmt.Sprintf("%d", l.Room),
+				teacher,
 			})
 		}
-
-		content.Objects = content.Objects[:1] // Only filter widgets remain
-		content.Add(updateTable(headers, data))
-		content.Refresh()
+		return data, page.Total, nil
 	})
-
-	lessons, err := r.Lessons.FindAll(context.Background())
-	if err != nil {
-		showResult(content, "Ошибка: "+err.Error())
-		return
-	}
-	for _, l := range lessons {
-		data = append(data, []string{
-			fmt.Sprintf("%d", l.ID),
-			fmt.Sprintf("%d", l.GroupID),
-			fmt.Sprintf("%d", l.SubjectID),
-			fmt.Sprintf("%d", l.LessonTypeID),
-			fmt.Sprintf("%d", l.Week),
-			fmt.Sprintf("%d", l.Weekday),
-			fmt.Sprintf("%d", l.Room),
-		})
-	}
-
-	filterContainer := container.NewVBox(
-		widget.NewLabel("Фильтрация занятий"),
-		filterSelect,
-		filterEntry,
-		applyFilterButton,
-	)
-
-	content.Add(filterContainer)
-	content.Add(updateTable(headers, data))
 }
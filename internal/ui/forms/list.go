@@ -0,0 +1,178 @@
+package forms
+
+import (
+	"context"
+	"fmt"
+	"university-db-admin/internal/repository"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// number of rows shown on one page of an entity list
+const listPageSize = 50
+
+// column of an entity list, Field is the column name known to the repository,
+// columns without it can't be filtered or sorted by
+type listColumn struct {
+	Header string
+	Field  string
+}
+
+// loads one page of table rows and the total number of matching rows
+type pageLoader func(ctx context.Context, opts repository.ListOptions) ([][]string, uint64, error)
+
+// filter operators offered to the user
+var listOperators = []struct {
+	Label string
+	Op    repository.FilterOp
+}{
+	{"равно", repository.OpEq},
+	{"содержит", repository.OpContains},
+	{"больше", repository.OpGt},
+	{"меньше", repository.OpLt},
+	{"не равно", repository.OpNe},
+}
+
+// displays a filterable, sortable and paginated table, name titles exported
+// files and the pages are loaded with the timeout of form
+func showPagedList(content *fyne.Container, w fyne.Window, form, name, title string, columns []listColumn, load pageLoader) {
+	var (
+		headers    = make([]string, len(columns))
+		selectable []string
+		fields     = map[string]string{}
+	)
+	for i, c := range columns {
+		headers[i] = c.Header
+		if c.Field != "" {
+			selectable = append(selectable, c.Header)
+			fields[c.Header] = c.Field
+		}
+	}
+
+	opLabels := make([]string, len(listOperators))
+	ops := map[string]repository.FilterOp{}
+	for i, o := range listOperators {
+		opLabels[i] = o.Label
+		ops[o.Label] = o.Op
+	}
+
+	filterEntry := widget.NewEntry()
+	filterEntry.SetPlaceHolder("Введите значение")
+	filterEntry.Disable()
+
+	opSelect := widget.NewSelect(opLabels, nil)
+	opSelect.SetSelectedIndex(0)
+	opSelect.Disable()
+
+	filterSelect := widget.NewSelect(append([]string{"Все"}, selectable...), func(value string) {
+		if value == "Все" {
+			filterEntry.SetText("")
+			filterEntry.Disable()
+			opSelect.Disable()
+		} else {
+			filterEntry.Enable()
+			opSelect.Enable()
+		}
+	})
+	filterSelect.SetSelectedIndex(0)
+
+	sortSelect := widget.NewSelect(selectable, nil)
+	sortSelect.PlaceHolder = "Сортировка"
+	descCheck := widget.NewCheck("По убыванию", nil)
+
+	var (
+		opts       repository.ListOptions
+		offset     uint64
+		showPage   func()
+		pagerLabel = widget.NewLabel("")
+		pageArea   = container.NewVBox()
+	)
+
+	prevButton := widget.NewButton("Назад", func() {
+		if offset >= listPageSize {
+			offset -= listPageSize
+			showPage()
+		}
+	})
+	nextButton := widget.NewButton("Вперёд", func() {
+		offset += listPageSize
+		showPage()
+	})
+
+	// exports every row matching the applied filter, not only the shown page
+	exportBar := newExportBar(w, name, headers, func(ctx context.Context) ([][]string, error) {
+		all := opts
+		all.Limit, all.Offset = 0, 0
+		rows, _, err := load(ctx, all)
+		return rows, err
+	})
+
+	showPage = func() {
+		opts.Limit = listPageSize
+		opts.Offset = offset
+
+		var (
+			page  = opts
+			rows  [][]string
+			total uint64
+		)
+		runQuery(pageArea, timeoutOf(form, queryTimeout), func(ctx context.Context) error {
+			var err error
+			rows, total, err = load(ctx, page)
+			return err
+		}, func() {
+			if total == 0 {
+				pagerLabel.SetText("Записей нет")
+			} else {
+				pagerLabel.SetText(fmt.Sprintf("Записи %d–%d из %d", page.Offset+1, page.Offset+uint64(len(rows)), total))
+			}
+
+			if page.Offset == 0 {
+				prevButton.Disable()
+			} else {
+				prevButton.Enable()
+			}
+			if page.Offset+uint64(len(rows)) >= total {
+				nextButton.Disable()
+			} else {
+				nextButton.Enable()
+			}
+
+			pageArea.Add(updateTable(headers, rows))
+			pageArea.Add(container.NewHBox(prevButton, pagerLabel, nextButton))
+			pageArea.Add(exportBar)
+		})
+	}
+
+	applyFilterButton := widget.NewButton("Применить фильтр", func() {
+		opts = repository.ListOptions{}
+		if field, ok := fields[filterSelect.Selected]; ok {
+			opts.Filters = []repository.Filter{{
+				Field: field,
+				Op:    ops[opSelect.Selected],
+				Value: filterEntry.Text,
+			}}
+		}
+		if field, ok := fields[sortSelect.Selected]; ok {
+			opts.Sort = []repository.Sort{{Field: field, Desc: descCheck.Checked}}
+		}
+
+		offset = 0
+		showPage()
+	})
+
+	filterContainer := container.NewVBox(
+		widget.NewLabel(title),
+		filterSelect,
+		opSelect,
+		filterEntry,
+		container.NewHBox(sortSelect, descCheck),
+		applyFilterButton,
+	)
+
+	content.Add(filterContainer)
+	content.Add(pageArea)
+	showPage()
+}
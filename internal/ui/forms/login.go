@@ -0,0 +1,82 @@
+package forms
+
+import (
+	"context"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/service"
+	"university-db-admin/pkg/validation"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Login checks the credentials typed on the login screen and returns
+// the services restricted to the role of the user
+type Login func(ctx context.Context, login, password string) (*service.Service, domain.User, error)
+
+// names of the roles shown to users
+var roleLabels = map[domain.Role]string{
+	domain.RoleAdmin:    "администратор",
+	domain.RoleDean:     "деканат",
+	domain.RoleTeacher:  "преподаватель",
+	domain.RoleReadOnly: "только чтение",
+}
+
+func RoleLabel(role domain.Role) string {
+	if l, ok := roleLabels[role]; ok {
+		return l
+	}
+	return string(role)
+}
+
+// asks for a login and a password, loggedIn is called once they are accepted
+func ShowLoginForm(content *fyne.Container, login Login, loggedIn func(s *service.Service, u domain.User)) {
+	content.Objects = nil
+
+	titleLabel := widget.NewLabelWithStyle("Вход в систему", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+
+	loginEntry := widget.NewEntry()
+	loginEntry.SetPlaceHolder("Логин")
+
+	passwordEntry := widget.NewPasswordEntry()
+	passwordEntry.SetPlaceHolder("Пароль")
+
+	result := container.NewVBox()
+
+	submit := func() {
+		name, password := loginEntry.Text, passwordEntry.Text
+		passwordEntry.SetText("")
+
+		err := validation.ValidateEmptyStrings(name, password)
+		if err != nil {
+			showResult(result, "Ошибка: "+errorMessage(err))
+			return
+		}
+
+		var (
+			s *service.Service
+			u domain.User
+		)
+		runQuery(result, timeoutOf("login", queryTimeout), func(ctx context.Context) error {
+			var err error
+			s, u, err = login(ctx, name, password)
+			return err
+		}, func() {
+			SetOperator(u.Login)
+			loggedIn(s, u)
+		})
+	}
+	passwordEntry.OnSubmitted = func(string) { submit() }
+
+	form := container.NewVBox(
+		titleLabel,
+		loginEntry,
+		passwordEntry,
+		widget.NewButton("Войти", submit),
+		result,
+	)
+
+	content.Add(form)
+	content.Refresh()
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"university-db-admin/internal/domain"
 	"university-db-admin/internal/repository"
+	"university-db-admin/internal/service"
 	"university-db-admin/pkg/validation"
 
 	"fyne.io/fyne/v2"
@@ -12,32 +13,28 @@ import (
 	"fyne.io/fyne/v2/widget"
 )
 
-func ShowMarksForm(content *fyne.Container, action int, r *repository.Repository) {
+func ShowMarksForm(content *fyne.Container, w fyne.Window, action int, s *service.Service) {
 	content.Objects = nil
 
 	switch action {
 	case 0:
-		showAddMarksForm(content, r)
+		showAddMarksForm(content, s)
 	case 1:
-		showDeleteMarksForm(content, r)
+		showDeleteMarksForm(content, s)
 	case 2:
-		showUpdateMarksForm(content, r)
+		showUpdateMarksForm(content, s)
 	case 3:
-		showMarksList(content, r)
+		showMarksList(content, w, s)
 	}
 
 	content.Refresh()
 }
 
-func showAddMarksForm(content *fyne.Container, r *repository.Repository) {
-	employeeEntry := widget.NewEntry()
-	employeeEntry.SetPlaceHolder("ID преподавателя")
-
-	studentEntry := widget.NewEntry()
-	studentEntry.SetPlaceHolder("ID студента")
-
-	subjectEntry := widget.NewEntry()
-	subjectEntry.SetPlaceHolder("ID предмета")
+func showAddMarksForm(content *fyne.Container, s *service.Service) {
+	employeeEntry := newPicker("Преподаватель", loadTeachers)
+	studentEntry := newPicker("Студент", loadStudents)
+	subjectEntry := newPicker("Предмет", nil)
+	linkSubjects(content, s, employeeEntry, subjectEntry)
 
 	markEntry := widget.NewEntry()
 	markEntry.SetPlaceHolder("Оценка")
@@ -54,38 +51,24 @@ func showAddMarksForm(content *fyne.Container, r *repository.Repository) {
 			dateEntry.Text,
 		)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
+			showResult(content, "Ошибка: "+errorMessage(err))
 			return
 		}
 
 		mark := domain.Mark{
-			EmployeeID: parseUint64(employeeEntry.Text),
-			StudentID:  parseUint64(studentEntry.Text),
-			SubjectID:  parseUint64(subjectEntry.Text),
+			EmployeeID: employeeEntry.id(),
+			StudentID:  studentEntry.id(),
+			SubjectID:  subjectEntry.id(),
 			Mark:       parseUint16(markEntry.Text),
 			Date:       parseDate(dateEntry.Text),
 		}
 
-		if err = validation.ValidateStruct(mark); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-
-		res, err := r.Employees.IsTeacher(context.Background(), parseUint64(employeeEntry.Text))
-		if !res.IsTeacher {
-			if err != nil {
-				showResult(content, "Ошибка: "+err.Error())
-			} else {
-				showResult(content, "Ошибка: указанный сотрудник не является преподавателем")
-			}
-			return
-		}
-
-		if err = r.Marks.Create(context.Background(), mark); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-		showResult(content, "Оценка успешно добавлена")
+		runQuery(content, timeoutOf("marks", queryTimeout), func(ctx context.Context) error {
+			_, err := s.Marks.Create(ctx, mark)
+			return err
+		}, func() {
+			showResult(content, "Оценка успешно добавлена")
+		})
 	})
 
 	form := container.NewVBox(
@@ -98,32 +81,27 @@ func showAddMarksForm(content *fyne.Container, r *repository.Repository) {
 		submitButton,
 	)
 
-	content.Add(form)
+	showWithPickers(content, s, form, employeeEntry, studentEntry)
 }
 
-func showDeleteMarksForm(content *fyne.Container, r *repository.Repository) {
+func showDeleteMarksForm(content *fyne.Container, s *service.Service) {
 	idEntry := widget.NewEntry()
 	idEntry.SetPlaceHolder("ID оценки")
 
 	deleteButton := widget.NewButton("Удалить", func() {
 		err := validation.ValidateEmptyStrings(idEntry.Text)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
+			showResult(content, "Ошибка: "+errorMessage(err))
 			return
 		}
 
 		id := parseUint64(idEntry.Text)
-		err = validation.ValidatePositiveNumbers(id)
-		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
 
-		if err = r.Marks.Delete(context.Background(), id); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-		showResult(content, "Оценка удалена")
+		runQuery(content, timeoutOf("marks", queryTimeout), func(ctx context.Context) error {
+			return s.Marks.Delete(ctx, id)
+		}, func() {
+			showResult(content, "Оценка удалена")
+		})
 	})
 
 	form := container.NewVBox(
@@ -135,18 +113,14 @@ func showDeleteMarksForm(content *fyne.Container, r *repository.Repository) {
 	content.Add(form)
 }
 
-func showUpdateMarksForm(content *fyne.Container, r *repository.Repository) {
+func showUpdateMarksForm(content *fyne.Container, s *service.Service) {
 	idEntry := widget.NewEntry()
 	idEntry.SetPlaceHolder("ID оценки")
 
-	employeeEntry := widget.NewEntry()
-	employeeEntry.SetPlaceHolder("Новый ID преподавателя")
-
-	studentEntry := widget.NewEntry()
-	studentEntry.SetPlaceHolder("Новый ID студента")
-
-	subjectEntry := widget.NewEntry()
-	subjectEntry.SetPlaceHolder("Новый ID предмета")
+	employeeEntry := newPicker("Новый преподаватель", loadTeachers)
+	studentEntry := newPicker("Новый студент", loadStudents)
+	subjectEntry := newPicker("Новый предмет", nil)
+	linkSubjects(content, s, employeeEntry, subjectEntry)
 
 	markEntry := widget.NewEntry()
 	markEntry.SetPlaceHolder("Новая оценка")
@@ -164,39 +138,24 @@ func showUpdateMarksForm(content *fyne.Container, r *repository.Repository) {
 			dateEntry.Text,
 		)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
+			showResult(content, "Ошибка: "+errorMessage(err))
 			return
 		}
 
 		mark := domain.Mark{
 			ID:         parseUint64(idEntry.Text),
-			EmployeeID: parseUint64(employeeEntry.Text),
-			StudentID:  parseUint64(studentEntry.Text),
-			SubjectID:  parseUint64(subjectEntry.Text),
+			EmployeeID: employeeEntry.id(),
+			StudentID:  studentEntry.id(),
+			SubjectID:  subjectEntry.id(),
 			Mark:       parseUint16(markEntry.Text),
 			Date:       parseDate(dateEntry.Text),
 		}
 
-		if err = validation.ValidateStruct(mark); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-
-		res, err := r.Employees.IsTeacher(context.Background(), parseUint64(employeeEntry.Text))
-		if !res.IsTeacher {
-			if err != nil {
-				showResult(content, "Ошибка: "+err.Error())
-			} else {
-				showResult(content, "Ошибка: указанный сотрудник не является преподавателем")
-			}
-			return
-		}
-
-		if err = r.Marks.Update(context.Background(), mark.ID, mark); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-		showResult(content, "Оценка обновлена")
+		runQuery(content, timeoutOf("marks", queryTimeout), func(ctx context.Context) error {
+			return s.Marks.Update(ctx, mark.ID, mark)
+		}, func() {
+			showResult(content, "Оценка обновлена")
+		})
 	})
 
 	form := container.NewVBox(
@@ -210,88 +169,27 @@ func showUpdateMarksForm(content *fyne.Container, r *repository.Repository) {
 		updateButton,
 	)
 
-	content.Add(form)
+	showWithPickers(content, s, form, employeeEntry, studentEntry)
 }
 
-func showMarksList(content *fyne.Container, r *repository.Repository) {
-	headers := []string{
-		"ID оценки",
-		"ID преподавателя",
-		"ID студента",
-		"ID предмета",
-		"Оценка",
-		"Дата",
+func showMarksList(content *fyne.Container, w fyne.Window, s *service.Service) {
+	columns := []listColumn{
+		{"ID оценки", "id"},
+		{"ID преподавателя", "employee_id"},
+		{"ID студента", "student_id"},
+		{"ID предмета", "subject_id"},
+		{"Оценка", "mark"},
+		{"Дата", "date"},
 	}
-	options := []string{
-		"Все",
-		"ID",
-		"ID преподавателя",
-		"ID студента",
-		"ID предмета",
-		"Оценка",
-		"Дата",
-	}
-	filterOptions := map[string]uint8{
-		"Все":              0,
-		"ID":               1,
-		"ID преподавателя": 2,
-		"ID студента":      3,
-		"ID предмета":      4,
-		"Оценка":           5,
-		"Дата":             6,
-	}
-
-	filterEntry := widget.NewEntry()
-	filterEntry.SetPlaceHolder("Введите значение")
-
-	var selectedField uint8
-	filterSelect := widget.NewSelect(options, func(value string) {
-		selectedField = filterOptions[value]
-
-		if selectedField == 0 {
-			filterEntry.SetText("")
-			filterEntry.Disable()
-		} else {
-			filterEntry.Enable()
-		}
-	})
-
-	var data [][]string
-	applyFilterButton := widget.NewButton("Применить фильтр", func() {
-		data = nil
-
-		var (
-			marks []domain.Mark
-			mark  domain.Mark
-			err   error
-		)
-
-		switch selectedField {
-		case 0:
-			marks, err = r.Marks.FindAll(context.Background())
-		case 1:
-			mark, err = r.Marks.FindOne(context.Background(), parseUint64(filterEntry.Text))
-			if err == nil {
-				marks = append(marks, mark)
-			}
-		case 2:
-			marks, err = r.Marks.FindByEmployeeID(context.Background(), parseUint64(filterEntry.Text))
-		case 3:
-			marks, err = r.Marks.FindByStudentID(context.Background(), parseUint64(filterEntry.Text))
-		case 4:
-			marks, err = r.Marks.FindBySubjectID(context.Background(), parseUint64(filterEntry.Text))
-		case 5:
-			marks, err = r.Marks.FindByMark(context.Background(), parseUint16(filterEntry.Text))
-		case 6:
-			marks, err = r.Marks.FindByDate(context.Background(), filterEntry.Text)
-		}
 
+	showPagedList(content, w, "marks", "Оценки", "Фильтрация оценок", columns, func(ctx context.Context, opts repository.ListOptions) ([][]string, uint64, error) {
+		page, err := s.Marks.List(ctx, opts)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
+			return nil, 0, err
 		}
 
-		for _, m := range marks {
+		data := make([][]string, 0, len(page.Items))
+		for _, m := range page.Items {
 			data = append(data, []string{
 				fmt.Sprintf("%d", m.ID),
 				fmt.Sprintf("%d", m.EmployeeID),
@@ -301,35 +199,6 @@ func showMarksList(content *fyne.Container, r *repository.Repository) {
 				m.Date.Format(dateLayout),
 			})
 		}
-
-		content.Objects = content.Objects[:1] // Only filter widgets remain
-		content.Add(updateTable(headers, data))
-		content.Refresh()
+		return data, page.Total, nil
 	})
-
-	marks, err := r.Marks.FindAll(context.Background())
-	if err != nil {
-		showResult(content, "Ошибка: "+err.Error())
-		return
-	}
-	for _, m := range marks {
-		data = append(data, []string{
-			fmt.Sprintf("%d", m.ID),
-			fmt.Sprintf("%d", m.EmployeeID),
-			fmt.Sprintf("%d", m.StudentID),
-			fmt.Sprintf("%d", m.SubjectID),
-			fmt.Sprintf("%d", m.Mark),
-			m.Date.Format(dateLayout),
-		})
-	}
-
-	filterContainer := container.NewVBox(
-		widget.NewLabel("Фильтрация оценок"),
-		filterSelect,
-		filterEntry,
-		applyFilterButton,
-	)
-
-	content.Add(filterContainer)
-	content.Add(updateTable(headers, data))
 }
@@ -0,0 +1,257 @@
+package forms
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/service"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/widget"
+)
+
+// option is a record offered by a picker
+type option struct {
+	id    uint64
+	label string
+}
+
+// picker is an entry with a dropdown of records instead of a raw id field,
+// typing narrows the dropdown and the record is found by its label
+type picker struct {
+	widget.SelectEntry
+	load    loader
+	options []option
+	picked  uint64
+
+	// called when the picked record changes, with 0 when nothing is picked
+	onPicked func(id uint64)
+}
+
+// loader reads the records a picker offers, pickers filled by
+// another picker like the ones of linkSubjects and linkTeachers have none
+type loader func(ctx context.Context, s *service.Service) ([]option, error)
+
+func newPicker(placeholder string, load loader) *picker {
+	p := &picker{load: load}
+	p.ExtendBaseWidget(p)
+	p.Wrapping = fyne.TextWrap(fyne.TextTruncateClip)
+	p.SetPlaceHolder(placeholder)
+	p.OnChanged = p.filter
+	return p
+}
+
+// replaces the records of the dropdown and clears the entry
+func (p *picker) setOptions(options []option) {
+	sort.Slice(options, func(i, j int) bool { return options[i].label < options[j].label })
+	p.options = options
+	p.SetText("")
+	p.filter("")
+}
+
+// id of the picked record, 0 if the text isn't a label of any,
+// zero ids are rejected by the service validation
+func (p *picker) id() uint64 {
+	for _, o := range p.options {
+		if o.label == p.Text {
+			return o.id
+		}
+	}
+	return 0
+}
+
+func (p *picker) filter(text string) {
+	query := strings.ToLower(strings.TrimSpace(text))
+	labels := make([]string, 0, len(p.options))
+	for _, o := range p.options {
+		if strings.Contains(strings.ToLower(o.label), query) {
+			labels = append(labels, o.label)
+		}
+	}
+	p.SetOptions(labels)
+
+	if id := p.id(); id != p.picked {
+		p.picked = id
+		if p.onPicked != nil {
+			p.onPicked(id)
+		}
+	}
+}
+
+// records offered by the pickers of the forms
+
+func employeeOptions(emps []domain.Employee) []option {
+	options := make([]option, 0, len(emps))
+	for _, emp := range emps {
+		options = append(options, option{emp.ID, fmt.Sprintf("%s (%s)", emp.Name, emp.Passport)})
+	}
+	return options
+}
+
+func loadTeachers(ctx context.Context, s *service.Service) ([]option, error) {
+	emps, err := s.Employees.FindTeachers(ctx)
+	return employeeOptions(emps), err
+}
+
+func loadCurators(ctx context.Context, s *service.Service) ([]option, error) {
+	emps, err := s.Employees.FindCurators(ctx)
+	return employeeOptions(emps), err
+}
+
+func loadStudents(ctx context.Context, s *service.Service) ([]option, error) {
+	studs, err := s.Students.FindAll(ctx)
+	options := make([]option, 0, len(studs))
+	for _, stud := range studs {
+		options = append(options, option{stud.ID, fmt.Sprintf("%s (%s)", stud.Name, stud.Passport)})
+	}
+	return options, err
+}
+
+func loadGroups(ctx context.Context, s *service.Service) ([]option, error) {
+	groups, err := s.Groups.FindAll(ctx)
+	options := make([]option, 0, len(groups))
+	for _, grp := range groups {
+		options = append(options, option{grp.ID, fmt.Sprintf("%d", grp.Number)})
+	}
+	return options, err
+}
+
+func loadPositions(ctx context.Context, s *service.Service) ([]option, error) {
+	positions, err := s.Positions.FindAll(ctx)
+	options := make([]option, 0, len(positions))
+	for _, pos := range positions {
+		options = append(options, option{pos.ID, pos.Name})
+	}
+	return options, err
+}
+
+func loadSubjects(ctx context.Context, s *service.Service) ([]option, error) {
+	subjects, err := s.Subjects.FindAll(ctx)
+	return subjectOptions(subjects, nil), err
+}
+
+// subjects the employee knows
+func loadEmployeeSubjects(ctx context.Context, s *service.Service, employeeID uint64) ([]option, error) {
+	known, err := s.EmployeesSubjects.FindByEmployeeID(ctx, employeeID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[uint64]bool, len(known))
+	for _, es := range known {
+		ids[es.SubjectID] = true
+	}
+
+	subjects, err := s.Subjects.FindAll(ctx)
+	return subjectOptions(subjects, ids), err
+}
+
+// teachers linked to the subject
+func loadSubjectTeachers(ctx context.Context, s *service.Service, subjectID uint64) ([]option, error) {
+	linked, err := s.EmployeesSubjects.FindBySubjectID(ctx, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	ids := make(map[uint64]bool, len(linked))
+	for _, es := range linked {
+		ids[es.EmployeeID] = true
+	}
+
+	teachers, err := s.Employees.FindTeachers(ctx)
+	var emps []domain.Employee
+	for _, emp := range teachers {
+		if ids[emp.ID] {
+			emps = append(emps, emp)
+		}
+	}
+	return employeeOptions(emps), err
+}
+
+// options of the subjects with one of ids, of all subjects if ids is nil
+func subjectOptions(subjects []domain.Subject, ids map[uint64]bool) []option {
+	options := make([]option, 0, len(subjects))
+	for _, sbj := range subjects {
+		if ids == nil || ids[sbj.ID] {
+			options = append(options, option{sbj.ID, sbj.Name})
+		}
+	}
+	return options
+}
+
+func loadLessonTypes(ctx context.Context, s *service.Service) ([]option, error) {
+	types, err := s.LessonTypes.FindAll(ctx)
+	options := make([]option, 0, len(types))
+	for _, lt := range types {
+		options = append(options, option{lt.ID, lt.Name})
+	}
+	return options, err
+}
+
+// shows form in content once the records of its pickers are loaded
+func showWithPickers(content *fyne.Container, s *service.Service, form fyne.CanvasObject, pickers ...*picker) {
+	options := make([][]option, len(pickers))
+	runQuery(content, timeoutOf("pickers", queryTimeout), func(ctx context.Context) error {
+		for i, p := range pickers {
+			var err error
+			if options[i], err = p.load(ctx, s); err != nil {
+				return err
+			}
+		}
+		return nil
+	}, func() {
+		for i, p := range pickers {
+			p.setOptions(options[i])
+		}
+		content.Add(form)
+	})
+}
+
+// keeps the subjects of sp to the ones known by the employee picked in ep
+func linkSubjects(content *fyne.Container, s *service.Service, ep, sp *picker) {
+	linkPickers(content, s, ep, sp, loadEmployeeSubjects)
+}
+
+// keeps the teachers of tp to the ones linked to the subject picked in sp
+func linkTeachers(content *fyne.Container, s *service.Service, sp, tp *picker) {
+	linkPickers(content, s, sp, tp, loadSubjectTeachers)
+}
+
+// fills dst with the records load returns for the one picked in src,
+// dst is disabled while they are loaded
+func linkPickers(
+	content *fyne.Container,
+	s *service.Service,
+	src, dst *picker,
+	load func(ctx context.Context, s *service.Service, id uint64) ([]option, error),
+) {
+	var latest atomic.Uint64 // record picked last
+	src.onPicked = func(id uint64) {
+		latest.Store(id)
+		dst.setOptions(nil)
+		if id == 0 {
+			return
+		}
+
+		dst.Disable()
+		go func() {
+			ctx, cancel := context.WithTimeout(baseCtx, timeoutOf("pickers", queryTimeout))
+			defer cancel()
+			options, err := load(ctx, s, id)
+
+			deliver(func() {
+				dst.Enable()
+				if err != nil {
+					showResult(content, "Ошибка: "+errorMessage(err))
+					return
+				}
+				// another record was picked while the options were loaded
+				if latest.Load() != id {
+					return
+				}
+				dst.setOptions(options)
+			})
+		}()
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"university-db-admin/internal/domain"
 	"university-db-admin/internal/repository"
+	"university-db-admin/internal/service"
 	"university-db-admin/pkg/validation"
 
 	"fyne.io/fyne/v2"
@@ -12,82 +13,99 @@ import (
 	"fyne.io/fyne/v2/widget"
 )
 
-func ShowPositionsForm(content *fyne.Container, action int, r *repository.Repository) {
+func ShowPositionsForm(content *fyne.Container, w fyne.Window, action int, s *service.Service) {
 	content.Objects = nil
 
 	switch action {
 	case 0:
-		showAddPositionsForm(content, r)
+		showAddPositionsForm(content, s)
 	case 1:
-		showDeletePositionsForm(content, r)
+		showDeletePositionsForm(content, s)
 	case 2:
-		showUpdatePositionsForm(content, r)
+		showUpdatePositionsForm(content, s)
 	case 3:
-		showPositionsList(content, r)
+		showPositionsList(content, w, s)
 	}
 
 	content.Refresh()
 }
 
-func showAddPositionsForm(content *fyne.Container, r *repository.Repository) {
+// checkboxes of the capabilities of a position
+type capabilityChecks struct {
+	teach, curate, administer *widget.Check
+}
+
+func newCapabilityChecks() capabilityChecks {
+	return capabilityChecks{
+		teach:      widget.NewCheck("Ведёт занятия и ставит оценки", nil),
+		curate:     widget.NewCheck("Курирует студентов", nil),
+		administer: widget.NewCheck("Работает в деканате", nil),
+	}
+}
+
+// sets the capabilities of pos from the checkboxes
+func (c capabilityChecks) apply(pos *domain.Position) {
+	pos.CanTeach = c.teach.Checked
+	pos.CanCurate = c.curate.Checked
+	pos.CanAdminister = c.administer.Checked
+}
+
+func showAddPositionsForm(content *fyne.Container, s *service.Service) {
 	nameEntry := widget.NewEntry()
 	nameEntry.SetPlaceHolder("Название")
 
+	checks := newCapabilityChecks()
+
 	submitButton := widget.NewButton("Добавить", func() {
 		err := validation.ValidateEmptyStrings(nameEntry.Text)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
+			showResult(content, "Ошибка: "+errorMessage(err))
 			return
 		}
 
 		pos := domain.Position{
 			Name: nameEntry.Text,
 		}
+		checks.apply(&pos)
 
-		if err = validation.ValidateStruct(pos); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-
-		if err = r.Positions.Create(context.Background(), pos); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-		showResult(content, "Должность добавлена")
+		runQuery(content, timeoutOf("positions", queryTimeout), func(ctx context.Context) error {
+			_, err := s.Positions.Create(ctx, pos)
+			return err
+		}, func() {
+			showResult(content, "Должность добавлена")
+		})
 	})
 
 	form := container.NewVBox(
 		widget.NewLabel("Добавление должности"),
 		nameEntry,
+		checks.teach,
+		checks.curate,
+		checks.administer,
 		submitButton,
 	)
 
 	content.Add(form)
 }
 
-func showDeletePositionsForm(content *fyne.Container, r *repository.Repository) {
+func showDeletePositionsForm(content *fyne.Container, s *service.Service) {
 	idEntry := widget.NewEntry()
 	idEntry.SetPlaceHolder("ID должности")
 
 	deleteButton := widget.NewButton("Удалить", func() {
 		err := validation.ValidateEmptyStrings(idEntry.Text)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
+			showResult(content, "Ошибка: "+errorMessage(err))
 			return
 		}
 
 		id := parseUint64(idEntry.Text)
-		err = validation.ValidatePositiveNumbers(id)
-		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
 
-		if err = r.Positions.Delete(context.Background(), id); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-		showResult(content, "Должность удалена")
+		runQuery(content, timeoutOf("positions", queryTimeout), func(ctx context.Context) error {
+			return s.Positions.Delete(ctx, id)
+		}, func() {
+			showResult(content, "Должность удалена")
+		})
 	})
 
 	form := container.NewVBox(
@@ -99,17 +117,19 @@ func showDeletePositionsForm(content *fyne.Container, r *repository.Repository)
 	content.Add(form)
 }
 
-func showUpdatePositionsForm(content *fyne.Container, r *repository.Repository) {
+func showUpdatePositionsForm(content *fyne.Container, s *service.Service) {
 	idEntry := widget.NewEntry()
 	idEntry.SetPlaceHolder("ID должности")
 
 	nameEntry := widget.NewEntry()
 	nameEntry.SetPlaceHolder("Новое название")
 
+	checks := newCapabilityChecks()
+
 	updateButton := widget.NewButton("Обновить", func() {
 		err := validation.ValidateEmptyStrings(idEntry.Text, nameEntry.Text)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
+			showResult(content, "Ошибка: "+errorMessage(err))
 			return
 		}
 
@@ -117,121 +137,61 @@ func showUpdatePositionsForm(content *fyne.Container, r *repository.Repository)
 			ID:   parseUint64(idEntry.Text),
 			Name: nameEntry.Text,
 		}
+		checks.apply(&pos)
 
-		if err = validation.ValidateStruct(pos); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-
-		if err = r.Positions.Update(context.Background(), pos.ID, pos); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-		showResult(content, "Должность обновлена")
+		runQuery(content, timeoutOf("positions", queryTimeout), func(ctx context.Context) error {
+			return s.Positions.Update(ctx, pos.ID, pos)
+		}, func() {
+			showResult(content, "Должность обновлена")
+		})
 	})
 
 	form := container.NewVBox(
 		widget.NewLabel("Обновление должности"),
 		idEntry,
 		nameEntry,
+		checks.teach,
+		checks.curate,
+		checks.administer,
 		updateButton,
 	)
 
 	content.Add(form)
 }
 
-func showPositionsList(content *fyne.Container, r *repository.Repository) {
-	headers := []string{
-		"ID должности",
-		"Название",
-	}
-	options := []string{
-		"Все",
-		"ID",
-		"Название",
+func showPositionsList(content *fyne.Container, w fyne.Window, s *service.Service) {
+	columns := []listColumn{
+		{"ID должности", "id"},
+		{"Название", "name"},
+		{"Преподаёт", "can_teach"},
+		{"Курирует", "can_curate"},
+		{"Деканат", "can_administer"},
 	}
-	filterOptions := map[string]uint8{
-		"Все":      0,
-		"ID":       1,
-		"Название": 2,
-	}
-
-	filterEntry := widget.NewEntry()
-	filterEntry.SetPlaceHolder("Введите значение")
-
-	var selectedField uint8
-	filterSelect := widget.NewSelect(options, func(value string) {
-		selectedField = filterOptions[value]
-
-		if selectedField == 0 {
-			filterEntry.SetText("")
-			filterEntry.Disable()
-		} else {
-			filterEntry.Enable()
-		}
-	})
-
-	var data [][]string
-	applyFilterButton := widget.NewButton("Применить фильтр", func() {
-		data = nil
-
-		var (
-			positions []domain.Position
-			position  domain.Position
-			err       error
-		)
-
-		switch selectedField {
-		case 0:
-			positions, err = r.Positions.FindAll(context.Background())
-		case 1:
-			position, err = r.Positions.FindOne(context.Background(), parseUint64(filterEntry.Text))
-			if err == nil {
-				positions = append(positions, position)
-			}
-		case 2:
-			position, err = r.Positions.FindByName(context.Background(), filterEntry.Text)
-			if err == nil {
-				positions = append(positions, position)
-			}
-		}
 
+	showPagedList(content, w, "positions", "Должности", "Фильтрация должностей", columns, func(ctx context.Context, opts repository.ListOptions) ([][]string, uint64, error) {
+		page, err := s.Positions.List(ctx, opts)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
+			return nil, 0, err
 		}
 
-		for _, p := range positions {
+		data := make([][]string, 0, len(page.Items))
+		for _, p := range page.Items {
 			data = append(data, []string{
 				fmt.Sprintf("%d", p.ID),
 				p.Name,
+				yesNo(p.CanTeach),
+				yesNo(p.CanCurate),
+				yesNo(p.CanAdminister),
 			})
 		}
-
-		content.Objects = content.Objects[:1] // Only filter widgets remain
-		content.Add(updateTable(headers, data))
-		content.Refresh()
+		return data, page.Total, nil
 	})
+}
 
-	positions, err := r.Positions.FindAll(context.Background())
-	if err != nil {
-		showResult(content, "Ошибка: "+err.Error())
-		return
+// capability flags as shown in the list
+func yesNo(b bool) string {
+	if b {
+		return "да"
 	}
-	for _, p := range positions {
-		data = append(data, []string{
-			fmt.Sprintf("%d", p.ID),
-			p.Name,
-		})
-	}
-
-	filterContainer := container.NewVBox(
-		widget.NewLabel("Фильтрация должностей"),
-		filterSelect,
-		filterEntry,
-		applyFilterButton,
-	)
-
-	content.Add(filterContainer)
-	content.Add(updateTable(headers, data))
+	return "нет"
 }
@@ -0,0 +1,158 @@
+package forms
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"university-db-admin/pkg/dbclient"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+)
+
+// how long a query may run before it's cancelled, long queries are the ones
+// going through whole tables: imports, exports and backups. Queries of the
+// forms in formTimeouts take the timeout set for their form instead
+var (
+	queryTimeout     = 30 * time.Second
+	longQueryTimeout = 10 * time.Minute
+	formTimeouts     = map[string]time.Duration{}
+)
+
+// forms whose queries may be given their own timeout, lists take the
+// timeout of the form of their table
+var formNames = []string{
+	"audit", "backup", "conflicts", "console", "employees", "employees_subjects", "export", "groups",
+	"import", "lesson_types", "lessons", "login", "marks", "pickers", "positions", "special",
+	"students", "subjects", "trash",
+}
+
+// SetTimeouts sets the timeouts of the queries run by the forms, forms
+// maps the names of forms to the timeout of their queries
+func SetTimeouts(query, long time.Duration, forms map[string]time.Duration) error {
+	for name := range forms {
+		if !slices.Contains(formNames, name) {
+			return fmt.Errorf("unknown form %q, the forms are %s", name, strings.Join(formNames, ", "))
+		}
+	}
+	queryTimeout = query
+	longQueryTimeout = long
+	formTimeouts = forms
+	return nil
+}
+
+// the timeout of the queries of form, fallback unless one was set for it
+func timeoutOf(form string, fallback time.Duration) time.Duration {
+	if timeout, ok := formTimeouts[form]; ok {
+		return timeout
+	}
+	return fallback
+}
+
+// context the queries of the forms start from, it carries
+// the operator their changes are attributed to
+var baseCtx = context.Background()
+
+// SetOperator attributes the changes made from the forms to the logged in user
+func SetOperator(name string) {
+	baseCtx = dbclient.WithOperator(context.Background(), name)
+}
+
+// results of queries are handled one at a time, fyne lets widgets
+// be updated from any goroutine but the forms aren't written for
+// two result handlers changing them at once
+var resultMu sync.Mutex
+
+// queries running in a target, guarded by resultMu. A query started in
+// a target cancels the one running there, whose result is dropped
+var running = map[*fyne.Container]*context.CancelFunc{}
+
+func deliver(f func()) {
+	resultMu.Lock()
+	defer resultMu.Unlock()
+	f()
+}
+
+// runs query off the UI goroutine while target shows a progress bar and
+// a button cancelling the query. Once the query succeeds target is emptied
+// and done fills it, a failed query leaves an error message in target
+func runQuery(target *fyne.Container, timeout time.Duration, query func(ctx context.Context) error, done func()) {
+	runQueryOr(target, timeout, query, done, func(err error) {
+		showResult(target, "Ошибка: "+errorMessage(err))
+	})
+}
+
+// runQuery with failed reporting the error instead
+func runQueryOr(target *fyne.Container, timeout time.Duration, query func(ctx context.Context) error, done func(), failed func(err error)) {
+	ctx, cancel := context.WithTimeout(baseCtx, timeout)
+	run := &cancel
+	deliver(func() {
+		if prev, ok := running[target]; ok {
+			(*prev)()
+		}
+		running[target] = run
+	})
+
+	progress := widget.NewProgressBarInfinite()
+	target.Objects = []fyne.CanvasObject{container.NewVBox(
+		widget.NewLabel("Выполняется запрос..."),
+		progress,
+		widget.NewButton("Отмена", cancel),
+	)}
+	target.Refresh()
+
+	go func() {
+		defer cancel()
+		err := query(ctx)
+
+		deliver(func() {
+			progress.Stop()
+			if running[target] != run {
+				return
+			}
+			delete(running, target)
+
+			target.Objects = nil
+			if err != nil {
+				failed(err)
+			} else {
+				done()
+			}
+			target.Refresh()
+		})
+	}()
+}
+
+// runs query like runQuery for actions started from dialogs,
+// the progress is shown in a dialog over w and so are errors
+func runQueryDialog(w fyne.Window, title string, timeout time.Duration, query func(ctx context.Context) error, done func()) {
+	ctx, cancel := context.WithTimeout(baseCtx, timeout)
+
+	progress := widget.NewProgressBarInfinite()
+	d := dialog.NewCustomWithoutButtons(title, container.NewVBox(
+		widget.NewLabel("Выполняется запрос..."),
+		progress,
+		widget.NewButton("Отмена", cancel),
+	), w)
+	d.Show()
+
+	go func() {
+		defer cancel()
+		err := query(ctx)
+
+		deliver(func() {
+			progress.Stop()
+			d.Hide()
+			if err != nil {
+				dialog.ShowInformation(title, "Ошибка: "+errorMessage(err), w)
+				return
+			}
+			done()
+		})
+	}()
+}
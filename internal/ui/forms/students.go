@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"university-db-admin/internal/domain"
 	"university-db-admin/internal/repository"
+	"university-db-admin/internal/service"
 	"university-db-admin/pkg/validation"
 
 	"fyne.io/fyne/v2"
@@ -12,35 +13,32 @@ import (
 	"fyne.io/fyne/v2/widget"
 )
 
-func ShowStudentsForm(content *fyne.Container, action int, r *repository.Repository) {
+func ShowStudentsForm(content *fyne.Container, w fyne.Window, action int, s *service.Service) {
 	content.Objects = nil
 
 	switch action {
 	case 0:
-		showAddStudentsForm(content, r)
+		showAddStudentsForm(content, s)
 	case 1:
-		showDeleteStudentsForm(content, r)
+		showDeleteStudentsForm(content, s)
 	case 2:
-		showUpdateStudentsForm(content, r)
+		showUpdateStudentsForm(content, s)
 	case 3:
-		showStudentsList(content, r)
+		showStudentsList(content, w, s)
 	}
 
 	content.Refresh()
 }
 
-func showAddStudentsForm(content *fyne.Container, r *repository.Repository) {
+func showAddStudentsForm(content *fyne.Container, s *service.Service) {
 	nameEntry := widget.NewEntry()
 	nameEntry.SetPlaceHolder("Имя")
 
 	passportEntry := widget.NewEntry()
 	passportEntry.SetPlaceHolder("Паспорт")
 
-	employeeEntry := widget.NewEntry()
-	employeeEntry.SetPlaceHolder("ID Куратора")
-
-	groupEntry := widget.NewEntry()
-	groupEntry.SetPlaceHolder("ID Группы")
+	employeeEntry := newPicker("Куратор", loadCurators)
+	groupEntry := newPicker("Группа", loadGroups)
 
 	submitButton := widget.NewButton("Добавить", func() {
 		err := validation.ValidateEmptyStrings(
@@ -50,27 +48,23 @@ func showAddStudentsForm(content *fyne.Container, r *repository.Repository) {
 			groupEntry.Text,
 		)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
+			showResult(content, "Ошибка: "+errorMessage(err))
 			return
 		}
 
 		student := domain.Student{
 			Name:       nameEntry.Text,
 			Passport:   passportEntry.Text,
-			EmployeeID: parseUint64(employeeEntry.Text),
-			GroupID:    parseUint64(groupEntry.Text),
-		}
-
-		if err = validation.ValidateStruct(student); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
+			EmployeeID: employeeEntry.id(),
+			GroupID:    groupEntry.id(),
 		}
 
-		if err = r.Students.Create(context.Background(), student); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-		showResult(content, "Студент успешно добавлен")
+		runQuery(content, timeoutOf("students", queryTimeout), func(ctx context.Context) error {
+			_, err := s.Students.Create(ctx, student)
+			return err
+		}, func() {
+			showResult(content, "Студент успешно добавлен")
+		})
 	})
 
 	form := container.NewVBox(
@@ -82,32 +76,27 @@ func showAddStudentsForm(content *fyne.Container, r *repository.Repository) {
 		submitButton,
 	)
 
-	content.Add(form)
+	showWithPickers(content, s, form, employeeEntry, groupEntry)
 }
 
-func showDeleteStudentsForm(content *fyne.Container, r *repository.Repository) {
+func showDeleteStudentsForm(content *fyne.Container, s *service.Service) {
 	idEntry := widget.NewEntry()
 	idEntry.SetPlaceHolder("ID студента")
 
 	deleteButton := widget.NewButton("Удалить", func() {
 		err := validation.ValidateEmptyStrings(idEntry.Text)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
+			showResult(content, "Ошибка: "+errorMessage(err))
 			return
 		}
 
 		id := parseUint64(idEntry.Text)
-		err = validation.ValidatePositiveNumbers(id)
-		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
 
-		if err = r.Students.Delete(context.Background(), id); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-		showResult(content, "Студент удалён")
+		runQuery(content, timeoutOf("students", queryTimeout), func(ctx context.Context) error {
+			return s.Students.Delete(ctx, id)
+		}, func() {
+			showResult(content, "Студент перемещён в корзину")
+		})
 	})
 
 	form := container.NewVBox(
@@ -119,7 +108,7 @@ func showDeleteStudentsForm(content *fyne.Container, r *repository.Repository) {
 	content.Add(form)
 }
 
-func showUpdateStudentsForm(content *fyne.Container, r *repository.Repository) {
+func showUpdateStudentsForm(content *fyne.Container, s *service.Service) {
 	idEntry := widget.NewEntry()
 	idEntry.SetPlaceHolder("ID студента")
 
@@ -129,11 +118,8 @@ func showUpdateStudentsForm(content *fyne.Container, r *repository.Repository) {
 	passportEntry := widget.NewEntry()
 	passportEntry.SetPlaceHolder("Новый паспорт")
 
-	employeeEntry := widget.NewEntry()
-	employeeEntry.SetPlaceHolder("Новый ID Куратора")
-
-	groupEntry := widget.NewEntry()
-	groupEntry.SetPlaceHolder("Новый ID Группы")
+	employeeEntry := newPicker("Новый куратор", loadCurators)
+	groupEntry := newPicker("Новая группа", loadGroups)
 
 	updateButton := widget.NewButton("Обновить", func() {
 		err := validation.ValidateEmptyStrings(
@@ -144,7 +130,7 @@ func showUpdateStudentsForm(content *fyne.Container, r *repository.Repository) {
 			groupEntry.Text,
 		)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
+			showResult(content, "Ошибка: "+errorMessage(err))
 			return
 		}
 
@@ -152,20 +138,15 @@ func showUpdateStudentsForm(content *fyne.Container, r *repository.Repository) {
 			ID:         parseUint64(idEntry.Text),
 			Name:       nameEntry.Text,
 			Passport:   passportEntry.Text,
-			EmployeeID: parseUint64(employeeEntry.Text),
-			GroupID:    parseUint64(groupEntry.Text),
+			EmployeeID: employeeEntry.id(),
+			GroupID:    groupEntry.id(),
 		}
 
-		if err = validation.ValidateStruct(student); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-
-		if err = r.Students.Update(context.Background(), student.ID, student); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-		showResult(content, "Студент обновлён")
+		runQuery(content, timeoutOf("students", queryTimeout), func(ctx context.Context) error {
+			return s.Students.Update(ctx, student.ID, student)
+		}, func() {
+			showResult(content, "Студент обновлён")
+		})
 	})
 
 	form := container.NewVBox(
@@ -178,122 +159,34 @@ func showUpdateStudentsForm(content *fyne.Container, r *repository.Repository) {
 		updateButton,
 	)
 
-	content.Add(form)
+	showWithPickers(content, s, form, employeeEntry, groupEntry)
 }
 
-func showStudentsList(content *fyne.Container, r *repository.Repository) {
-	headers := []string{
-		"ID студента",
-		"Имя",
-		"Паспорт",
-		"ID Куратора",
-		"ID Группы",
+func showStudentsList(content *fyne.Container, w fyne.Window, s *service.Service) {
+	columns := []listColumn{
+		{"ID студента", "id"},
+		{"Имя", "name"},
+		{"Паспорт", "passport"},
+		{"ID Куратора", "employee_id"},
+		{"ID Группы", "group_id"},
 	}
-	options := []string{
-		"Все",
-		"ID",
-		"Имя",
-		"Паспорт",
-		"ID Куратора",
-		"ID Группы",
-	}
-	filterOptions := map[string]uint8{
-		"Все":         0,
-		"ID":          1,
-		"Имя":         2,
-		"Паспорт":     3,
-		"ID Куратора": 4,
-		"ID Группы":   5,
-	}
-
-	filterEntry := widget.NewEntry()
-	filterEntry.SetPlaceHolder("Введите значение")
-
-	var selectedField uint8
-	filterSelect := widget.NewSelect(options, func(value string) {
-		selectedField = filterOptions[value]
-
-		if selectedField == 0 {
-			filterEntry.SetText("")
-			filterEntry.Disable()
-		} else {
-			filterEntry.Enable()
-		}
-	})
-
-	var data [][]string
-	applyFilterButton := widget.NewButton("Применить фильтр", func() {
-		data = nil
-
-		var (
-			students []domain.Student
-			stud     domain.Student
-			err      error
-		)
-
-		switch selectedField {
-		case 0:
-			students, err = r.Students.FindAll(context.Background())
-		case 1:
-			stud, err = r.Students.FindOne(context.Background(), parseUint64(filterEntry.Text))
-			if err == nil {
-				students = append(students, stud)
-			}
-		case 2:
-			students, err = r.Students.FindByName(context.Background(), filterEntry.Text)
-		case 3:
-			stud, err = r.Students.FindByPassport(context.Background(), filterEntry.Text)
-			if err == nil {
-				students = append(students, stud)
-			}
-		case 4:
-			students, err = r.Students.FindByEmployeeID(context.Background(), parseUint64(filterEntry.Text))
-		case 5:
-			students, err = r.Students.FindByGroupID(context.Background(), parseUint64(filterEntry.Text))
-		}
 
+	showPagedList(content, w, "students", "Студенты", "Фильтрация студентов", columns, func(ctx context.Context, opts repository.ListOptions) ([][]string, uint64, error) {
+		page, err := s.Students.List(ctx, opts)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
+			return nil, 0, err
 		}
 
-		for _, s := range students {
+		data := make([][]string, 0, len(page.Items))
+		for _, st := range page.Items {
 			data = append(data, []string{
-				fmt.Sprintf("%d", s.ID),
-				s.Name,
-				s.Passport,
-				fmt.Sprintf("%d", s.EmployeeID),
-				fmt.Sprintf("%d", s.GroupID),
+				fmt.Sprintf("%d", st.ID),
+				st.Name,
+				st.Passport,
+				fmt.Sprintf("%d", st.EmployeeID),
+				fmt.Sprintf("%d", st.GroupID),
 			})
 		}
-
-		content.Objects = content.Objects[:1] // Only filter widgets remain
-		content.Add(updateTable(headers, data))
-		content.Refresh()
+		return data, page.Total, nil
 	})
-
-	students, err := r.Students.FindAll(context.Background())
-	if err != nil {
-		showResult(content, "Ошибка: "+err.Error())
-		return
-	}
-	for _, s := range students {
-		data = append(data, []string{
-			fmt.Sprintf("%d", s.ID),
-			s.Name,
-			s.Passport,
-			fmt.Sprintf("%d", s.EmployeeID),
-			fmt.Sprintf("%d", s.GroupID),
-		})
-	}
-
-	filterContainer := container.NewVBox(
-		widget.NewLabel("Фильтрация студентов"),
-		filterSelect,
-		filterEntry,
-		applyFilterButton,
-	)
-
-	content.Add(filterContainer)
-	content.Add(updateTable(headers, data))
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"university-db-admin/internal/domain"
 	"university-db-admin/internal/repository"
+	"university-db-admin/internal/service"
 	"university-db-admin/pkg/validation"
 
 	"fyne.io/fyne/v2"
@@ -12,24 +13,24 @@ import (
 	"fyne.io/fyne/v2/widget"
 )
 
-func ShowSubjectsForm(content *fyne.Container, action int, r *repository.Repository) {
+func ShowSubjectsForm(content *fyne.Container, w fyne.Window, action int, s *service.Service) {
 	content.Objects = nil
 
 	switch action {
 	case 0:
-		showAddSubjectsForm(content, r)
+		showAddSubjectsForm(content, s)
 	case 1:
-		showDeleteSubjectsForm(content, r)
+		showDeleteSubjectsForm(content, s)
 	case 2:
-		showUpdateSubjectsForm(content, r)
+		showUpdateSubjectsForm(content, s)
 	case 3:
-		showSubjectsList(content, r)
+		showSubjectsList(content, w, s)
 	}
 
 	content.Refresh()
 }
 
-func showAddSubjectsForm(content *fyne.Container, r *repository.Repository) {
+func showAddSubjectsForm(content *fyne.Container, s *service.Service) {
 	nameEntry := widget.NewEntry()
 	nameEntry.SetPlaceHolder("Название")
 
@@ -39,7 +40,7 @@ func showAddSubjectsForm(content *fyne.Container, r *repository.Repository) {
 	submitButton := widget.NewButton("Добавить", func() {
 		err := validation.ValidateEmptyStrings(nameEntry.Text, dscrEntry.Text)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
+			showResult(content, "Ошибка: "+errorMessage(err))
 			return
 		}
 
@@ -48,16 +49,12 @@ func showAddSubjectsForm(content *fyne.Container, r *repository.Repository) {
 			Description: dscrEntry.Text,
 		}
 
-		if err = validation.ValidateStruct(sbj); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-
-		if err = r.Subjects.Create(context.Background(), sbj); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-		showResult(content, "Предмет добавлен")
+		runQuery(content, timeoutOf("subjects", queryTimeout), func(ctx context.Context) error {
+			_, err := s.Subjects.Create(ctx, sbj)
+			return err
+		}, func() {
+			showResult(content, "Предмет добавлен")
+		})
 	})
 
 	form := container.NewVBox(
@@ -70,29 +67,24 @@ func showAddSubjectsForm(content *fyne.Container, r *repository.Repository) {
 	content.Add(form)
 }
 
-func showDeleteSubjectsForm(content *fyne.Container, r *repository.Repository) {
+func showDeleteSubjectsForm(content *fyne.Container, s *service.Service) {
 	idEntry := widget.NewEntry()
 	idEntry.SetPlaceHolder("ID предмета")
 
 	deleteButton := widget.NewButton("Удалить", func() {
 		err := validation.ValidateEmptyStrings(idEntry.Text)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
+			showResult(content, "Ошибка: "+errorMessage(err))
 			return
 		}
 
 		id := parseUint64(idEntry.Text)
-		err = validation.ValidatePositiveNumbers(id)
-		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
 
-		if err = r.Subjects.Delete(context.Background(), id); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-		showResult(content, "Предмет удален")
+		runQuery(content, timeoutOf("subjects", queryTimeout), func(ctx context.Context) error {
+			return s.Subjects.Delete(ctx, id)
+		}, func() {
+			showResult(content, "Предмет удален")
+		})
 	})
 
 	form := container.NewVBox(
@@ -104,7 +96,7 @@ func showDeleteSubjectsForm(content *fyne.Container, r *repository.Repository) {
 	content.Add(form)
 }
 
-func showUpdateSubjectsForm(content *fyne.Container, r *repository.Repository) {
+func showUpdateSubjectsForm(content *fyne.Container, s *service.Service) {
 	idEntry := widget.NewEntry()
 	idEntry.SetPlaceHolder("ID предмета")
 
@@ -121,7 +113,7 @@ func showUpdateSubjectsForm(content *fyne.Container, r *repository.Repository) {
 			dscrEntry.Text,
 		)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
+			showResult(content, "Ошибка: "+errorMessage(err))
 			return
 		}
 
@@ -131,16 +123,11 @@ func showUpdateSubjectsForm(content *fyne.Container, r *repository.Repository) {
 			Description: dscrEntry.Text,
 		}
 
-		if err = validation.ValidateStruct(sbj); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-
-		if err = r.Subjects.Update(context.Background(), sbj.ID, sbj); err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
-		}
-		showResult(content, "Предмет обновлен")
+		runQuery(content, timeoutOf("subjects", queryTimeout), func(ctx context.Context) error {
+			return s.Subjects.Update(ctx, sbj.ID, sbj)
+		}, func() {
+			showResult(content, "Предмет обновлен")
+		})
 	})
 
 	form := container.NewVBox(
@@ -154,101 +141,27 @@ func showUpdateSubjectsForm(content *fyne.Container, r *repository.Repository) {
 	content.Add(form)
 }
 
-func showSubjectsList(content *fyne.Container, r *repository.Repository) {
-	headers := []string{
-		"ID предмета",
-		"Название",
-		"Описание",
-	}
-	options := []string{
-		"Все",
-		"ID",
-		"Название",
-	}
-	filterOptions := map[string]uint8{
-		"Все":      0,
-		"ID":       1,
-		"Название": 2,
+func showSubjectsList(content *fyne.Container, w fyne.Window, s *service.Service) {
+	columns := []listColumn{
+		{"ID предмета", "id"},
+		{"Название", "name"},
+		{"Описание", ""},
 	}
 
-	filterEntry := widget.NewEntry()
-	filterEntry.SetPlaceHolder("Введите значение")
-
-	var selectedField uint8
-	filterSelect := widget.NewSelect(options, func(value string) {
-		selectedField = filterOptions[value]
-
-		if selectedField == 0 {
-			filterEntry.SetText("")
-			filterEntry.Disable()
-		} else {
-			filterEntry.Enable()
-		}
-	})
-
-	var data [][]string
-	applyFilterButton := widget.NewButton("Применить фильтр", func() {
-		data = nil
-
-		var (
-			subjects []domain.Subject
-			subject  domain.Subject
-			err      error
-		)
-
-		switch selectedField {
-		case 0:
-			subjects, err = r.Subjects.FindAll(context.Background())
-		case 1:
-			subject, err = r.Subjects.FindOne(context.Background(), parseUint64(filterEntry.Text))
-			if err == nil {
-				subjects = append(subjects, subject)
-			}
-		case 2:
-			subject, err = r.Subjects.FindByName(context.Background(), filterEntry.Text)
-			if err == nil {
-				subjects = append(subjects, subject)
-			}
-		}
-
+	showPagedList(content, w, "subjects", "Предметы", "Фильтрация предметов", columns, func(ctx context.Context, opts repository.ListOptions) ([][]string, uint64, error) {
+		page, err := s.Subjects.List(ctx, opts)
 		if err != nil {
-			showResult(content, "Ошибка: "+err.Error())
-			return
+			return nil, 0, err
 		}
 
-		for _, s := range subjects {
+		data := make([][]string, 0, len(page.Items))
+		for _, sb := range page.Items {
 			data = append(data, []string{
-				fmt.Sprintf("%d", s.ID),
-				s.Name,
-				s.Description,
+				fmt.Sprintf("%d", sb.ID),
+				sb.Name,
+				sb.Description,
 			})
 		}
-
-		content.Objects = content.Objects[:1] // Only filter widgets remain
-		content.Add(updateTable(headers, data))
-		content.Refresh()
+		return data, page.Total, nil
 	})
-
-	subjects, err := r.Subjects.FindAll(context.Background())
-	if err != nil {
-		showResult(content, "Ошибка: "+err.Error())
-		return
-	}
-	for _, s := range subjects {
-		data = append(data, []string{
-			fmt.Sprintf("%d", s.ID),
-			s.Name,
-			s.Description,
-		})
-	}
-
-	filterContainer := container.NewVBox(
-		widget.NewLabel("Фильтрация предметов"),
-		filterSelect,
-		filterEntry,
-		applyFilterButton,
-	)
-
-	content.Add(filterContainer)
-	content.Add(updateTable(headers, data))
 }
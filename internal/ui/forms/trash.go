@@ -0,0 +1,195 @@
+package forms
+
+import (
+	"context"
+	"fmt"
+	"university-db-admin/internal/repository"
+	"university-db-admin/internal/service"
+	"university-db-admin/pkg/validation"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/widget"
+)
+
+// entity whose deleted records can be listed and restored
+type trashEntity struct {
+	Label   string
+	Columns []listColumn
+	Load    func(s *service.Service) pageLoader
+	Restore func(s *service.Service) func(ctx context.Context, id uint64) error
+}
+
+var trashEntities = []trashEntity{
+	{
+		Label: "Студенты",
+		Columns: []listColumn{
+			{"ID студента", "id"},
+			{"Имя", "name"},
+			{"Паспорт", "passport"},
+			{"ID Куратора", "employee_id"},
+			{"ID Группы", "group_id"},
+			{"Удалён", "deleted_at"},
+		},
+		Load: func(s *service.Service) pageLoader {
+			return func(ctx context.Context, opts repository.ListOptions) ([][]string, uint64, error) {
+				page, err := s.Students.List(ctx, opts)
+				if err != nil {
+					return nil, 0, err
+				}
+
+				data := make([][]string, 0, len(page.Items))
+				for _, st := range page.Items {
+					data = append(data, []string{
+						fmt.Sprintf("%d", st.ID),
+						st.Name,
+						st.Passport,
+						fmt.Sprintf("%d", st.EmployeeID),
+						fmt.Sprintf("%d", st.GroupID),
+						st.DeletedAt.Local().Format("2006-01-02 15:04"),
+					})
+				}
+				return data, page.Total, nil
+			}
+		},
+		Restore: func(s *service.Service) func(ctx context.Context, id uint64) error {
+			return s.Students.Restore
+		},
+	},
+	{
+		Label: "Сотрудники",
+		Columns: []listColumn{
+			{"ID сотрудника", "id"},
+			{"Имя", "name"},
+			{"Паспорт", "passport"},
+			{"ID Должности", "position_id"},
+			{"Удалён", "deleted_at"},
+		},
+		Load: func(s *service.Service) pageLoader {
+			return func(ctx context.Context, opts repository.ListOptions) ([][]string, uint64, error) {
+				page, err := s.Employees.List(ctx, opts)
+				if err != nil {
+					return nil, 0, err
+				}
+
+				data := make([][]string, 0, len(page.Items))
+				for _, emp := range page.Items {
+					data = append(data, []string{
+						fmt.Sprintf("%d", emp.ID),
+						emp.Name,
+						emp.Passport,
+						fmt.Sprintf("%d", emp.PositionID),
+						emp.DeletedAt.Local().Format("2006-01-02 15:04"),
+					})
+				}
+				return data, page.Total, nil
+			}
+		},
+		Restore: func(s *service.Service) func(ctx context.Context, id uint64) error {
+			return s.Employees.Restore
+		},
+	},
+	{
+		Label: "Группы",
+		Columns: []listColumn{
+			{"ID группы", "id"},
+			{"Номер группы", "number"},
+			{"Удалена", "deleted_at"},
+		},
+		Load: func(s *service.Service) pageLoader {
+			return func(ctx context.Context, opts repository.ListOptions) ([][]string, uint64, error) {
+				page, err := s.Groups.List(ctx, opts)
+				if err != nil {
+					return nil, 0, err
+				}
+
+				data := make([][]string, 0, len(page.Items))
+				for _, grp := range page.Items {
+					data = append(data, []string{
+						fmt.Sprintf("%d", grp.ID),
+						fmt.Sprintf("%d", grp.Number),
+						grp.DeletedAt.Local().Format("2006-01-02 15:04"),
+					})
+				}
+				return data, page.Total, nil
+			}
+		},
+		Restore: func(s *service.Service) func(ctx context.Context, id uint64) error {
+			return s.Groups.Restore
+		},
+	},
+}
+
+// lists deleted students, employees and groups and restores them by id
+func ShowTrashForm(content *fyne.Container, w fyne.Window, s *service.Service) {
+	content.Objects = nil
+
+	labels := make([]string, len(trashEntities))
+	for i, e := range trashEntities {
+		labels[i] = e.Label
+	}
+
+	listContainer := container.NewVBox()
+	resultLabel := widget.NewLabel("")
+	result := container.NewVBox(resultLabel)
+
+	var current *trashEntity
+	showList := func() {
+		listContainer.Objects = nil
+		load := current.Load(s)
+		showPagedList(listContainer, w, "trash", "Корзина — "+current.Label, "Фильтрация удалённых записей", current.Columns,
+			func(ctx context.Context, opts repository.ListOptions) ([][]string, uint64, error) {
+				opts.Deleted = repository.OnlyDeleted
+				return load(ctx, opts)
+			})
+		listContainer.Refresh()
+	}
+
+	idEntry := widget.NewEntry()
+	idEntry.SetPlaceHolder("ID записи")
+	restoreButton := widget.NewButton("Восстановить", func() {
+		if current == nil {
+			resultLabel.SetText("Ошибка: выберите, что восстановить")
+			return
+		}
+		if err := validation.ValidateEmptyStrings(idEntry.Text); err != nil {
+			resultLabel.SetText("Ошибка: " + errorMessage(err))
+			return
+		}
+
+		restore, id := current.Restore(s), parseUint64(idEntry.Text)
+		runQueryOr(result, timeoutOf("trash", queryTimeout), func(ctx context.Context) error {
+			return restore(ctx, id)
+		}, func() {
+			result.Add(resultLabel)
+			resultLabel.SetText("Запись восстановлена")
+			idEntry.SetText("")
+			showList()
+		}, func(err error) {
+			result.Add(resultLabel)
+			resultLabel.SetText("Ошибка: " + errorMessage(err))
+		})
+	})
+	restoreButton.Disable()
+
+	entitySelect := widget.NewSelect(labels, func(label string) {
+		for i := range trashEntities {
+			if trashEntities[i].Label == label {
+				current = &trashEntities[i]
+			}
+		}
+		resultLabel.SetText("")
+		restoreButton.Enable()
+		showList()
+	})
+	entitySelect.PlaceHolder = "Что показать"
+
+	form := container.NewVBox(
+		entitySelect,
+		container.NewBorder(nil, nil, nil, restoreButton, idEntry),
+		result,
+	)
+
+	content.Add(form)
+	content.Add(listContainer)
+}
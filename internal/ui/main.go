@@ -1,7 +1,12 @@
 package ui
 
 import (
-	"university-db-admin/internal/repository"
+	"fmt"
+	"log"
+	"university-db-admin/internal/config"
+	"university-db-admin/internal/domain"
+	"university-db-admin/internal/service"
+	"university-db-admin/internal/special"
 	"university-db-admin/internal/ui/forms"
 
 	"fyne.io/fyne/v2"
@@ -10,42 +15,107 @@ import (
 	"fyne.io/fyne/v2/widget"
 )
 
-func Run(r *repository.Repository) {
+// session of the window: the check of the credentials and the user logged in
+// with them, the menu shows their name and hides what their role can't use
+var (
+	login forms.Login
+	user  domain.User
+)
+
+func Run(l forms.Login, cfg config.UIConfig) {
+	login = l
+	if err := forms.SetTimeouts(cfg.QueryTimeout, cfg.LongQueryTimeout, cfg.FormTimeouts); err != nil {
+		log.Fatal("UI_FORM_TIMEOUTS: ", err)
+	}
+
 	a := app.New()
 	w := a.NewWindow("База данных \"Университет\"")
 	w.Resize(fyne.NewSize(1100, 750))
 
 	contentContainer := container.NewVBox()
-	showMainMenu(contentContainer, r)
+	showLogin(contentContainer, w)
 
 	w.SetContent(contentContainer)
 	w.ShowAndRun()
 }
 
-func showMainMenu(content *fyne.Container, r *repository.Repository) {
+func showLogin(content *fyne.Container, w fyne.Window) {
+	user = domain.User{}
+	forms.ShowLoginForm(content, login, func(s *service.Service, u domain.User) {
+		user = u
+		showMainMenu(content, w, s)
+	})
+}
+
+func showMainMenu(content *fyne.Container, w fyne.Window, s *service.Service) {
 	content.Objects = nil
 
 	titleLabel := widget.NewLabelWithStyle("Выберите режим работы", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+	userLabel := widget.NewLabelWithStyle(
+		fmt.Sprintf("Пользователь: %s (%s)", user.Login, forms.RoleLabel(user.Role)),
+		fyne.TextAlignCenter, fyne.TextStyle{},
+	)
 
 	crudButton := widget.NewButton("Операции с сущностями", func() {
-		showEntitySelection(content, r)
+		showEntitySelection(content, w, s)
 	})
 
 	queriesButton := widget.NewButton("Специальные SQL-запросы", func() {
-		showSpecialQuerySelection(content, r)
+		showSpecialQuerySelection(content, w, s)
+	})
+
+	conflictsButton := widget.NewButton("Конфликты расписания", func() {
+		showConflicts(content, w, s)
+	})
+
+	importButton := widget.NewButton("Импорт из файла", func() {
+		showImport(content, w, s)
+	})
+
+	auditButton := widget.NewButton("Журнал изменений", func() {
+		showAudit(content, w, s)
+	})
+
+	trashButton := widget.NewButton("Корзина", func() {
+		showTrash(content, w, s)
+	})
+
+	backupButton := widget.NewButton("Резервное копирование", func() {
+		showBackup(content, w, s)
+	})
+
+	consoleButton := widget.NewButton("SQL-консоль", func() {
+		showConsole(content, w, s)
+	})
+
+	logoutButton := widget.NewButton("Выйти", func() {
+		showLogin(content, w)
 	})
 
 	menu := container.NewVBox(
 		titleLabel,
+		userLabel,
 		crudButton,
 		queriesButton,
+		conflictsButton,
+		importButton,
 	)
+	// the service refuses these to other roles anyway
+	if user.Role == domain.RoleAdmin || user.Role == domain.RoleDean {
+		menu.Add(auditButton)
+	}
+	menu.Add(trashButton)
+	if user.Role == domain.RoleAdmin {
+		menu.Add(backupButton)
+		menu.Add(consoleButton)
+	}
+	menu.Add(logoutButton)
 
 	content.Add(menu)
 	content.Refresh()
 }
 
-func showEntitySelection(content *fyne.Container, r *repository.Repository) {
+func showEntitySelection(content *fyne.Container, w fyne.Window, s *service.Service) {
 	content.Objects = nil
 
 	titleLabel := widget.NewLabelWithStyle("Выберите действие", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
@@ -66,11 +136,11 @@ func showEntitySelection(content *fyne.Container, r *repository.Repository) {
 
 	contentContainer := container.NewVBox()
 	executeButton := widget.NewButton("Применить", func() {
-		updateEntityContent(contentContainer, actionSelect.SelectedIndex(), entitySelect.SelectedIndex(), r)
+		updateEntityContent(contentContainer, w, actionSelect.SelectedIndex(), entitySelect.SelectedIndex(), s)
 	})
 
 	backButton := widget.NewButton("Меню", func() {
-		showMainMenu(content, r)
+		showMainMenu(content, w, s)
 	})
 
 	mainContent := container.NewVBox(titleLabel, actionSelect, entitySelect, executeButton, backButton, contentContainer)
@@ -78,37 +148,27 @@ func showEntitySelection(content *fyne.Container, r *repository.Repository) {
 	content.Refresh()
 }
 
-func showSpecialQuerySelection(content *fyne.Container, r *repository.Repository) {
+func showSpecialQuerySelection(content *fyne.Container, w fyne.Window, s *service.Service) {
 	content.Objects = nil
 
 	titleLabel := widget.NewLabelWithStyle("Выберите действие", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
 
-	options := []string{
-		"Получить ФИО и номер паспорта всех сотрудников",
-		"Получить ФИО и номер паспорта сотрудника по id",
-		"Получить информацию о студентах у которых нет куратора",
-		"Получить ФИО сотрудников либо с одной должностью, либо другой по id",
-		"Получить информацию об оценках выше заданной и выставленных по предмету с заданным id",
-		"Получить ФИО и номер паспорта студентов c отчествами, заканчивающимися на заданную последовательность",
-		"Получить список предметов отсортированных в алфавитном порядке",
-		"Получить информацию об оценках отсортированных по дате в порядке возрастания и по значению в порядке убывания",
-		"Получить все возможные сочетания студентов и групп",
-		"Получить расписание занятий по группам",
-		"Получить всех студентов и их кураторов, включая студентов без куратора",
-		"Получить всех кураторов и их студентов, включая кураторов без студентов",
-		"Получить всех студентов и их кураторов, включая студентов без куратора и кураторов без студентов",
-		"Получить ФИО студентов в верхнем регистре и посчитать в них количество символов",
+	options := make([]string, len(special.Queries))
+	for i, q := range special.Queries {
+		options[i] = q.Title
 	}
 	actionSelect := widget.NewSelect(options, nil)
 
 	contentContainer := container.NewVBox()
 
 	executeButton := widget.NewButton("Выполнить", func() {
-		forms.ShowSpecialQueryForm(contentContainer, actionSelect.SelectedIndex(), r)
+		if i := actionSelect.SelectedIndex(); i >= 0 {
+			forms.ShowSpecialQueryForm(contentContainer, w, special.Queries[i], s)
+		}
 	})
 
 	backButton := widget.NewButton("Меню", func() {
-		showMainMenu(content, r)
+		showMainMenu(content, w, s)
 	})
 
 	mainContent := container.NewVBox(titleLabel, actionSelect, executeButton, backButton, contentContainer)
@@ -116,28 +176,130 @@ func showSpecialQuerySelection(content *fyne.Container, r *repository.Repository
 	content.Refresh()
 }
 
-func updateEntityContent(content *fyne.Container, action, entity int, r *repository.Repository) {
+func showConflicts(content *fyne.Container, w fyne.Window, s *service.Service) {
+	content.Objects = nil
+
+	titleLabel := widget.NewLabelWithStyle("Конфликты расписания", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+
+	contentContainer := container.NewVBox()
+	forms.ShowScheduleConflictsForm(contentContainer, w, s)
+
+	backButton := widget.NewButton("Меню", func() {
+		showMainMenu(content, w, s)
+	})
+
+	mainContent := container.NewVBox(titleLabel, backButton, contentContainer)
+	content.Add(mainContent)
+	content.Refresh()
+}
+
+func showImport(content *fyne.Container, w fyne.Window, s *service.Service) {
+	content.Objects = nil
+
+	titleLabel := widget.NewLabelWithStyle("Импорт из файла", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+
+	contentContainer := container.NewVBox()
+	forms.ShowImportForm(contentContainer, w, s)
+
+	backButton := widget.NewButton("Меню", func() {
+		showMainMenu(content, w, s)
+	})
+
+	mainContent := container.NewVBox(titleLabel, backButton, contentContainer)
+	content.Add(mainContent)
+	content.Refresh()
+}
+
+func showAudit(content *fyne.Container, w fyne.Window, s *service.Service) {
+	content.Objects = nil
+
+	titleLabel := widget.NewLabelWithStyle("Журнал изменений", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+
+	contentContainer := container.NewVBox()
+	forms.ShowAuditForm(contentContainer, w, s)
+
+	backButton := widget.NewButton("Меню", func() {
+		showMainMenu(content, w, s)
+	})
+
+	mainContent := container.NewVBox(titleLabel, backButton, contentContainer)
+	content.Add(mainContent)
+	content.Refresh()
+}
+
+func showTrash(content *fyne.Container, w fyne.Window, s *service.Service) {
+	content.Objects = nil
+
+	titleLabel := widget.NewLabelWithStyle("Корзина", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+
+	contentContainer := container.NewVBox()
+	forms.ShowTrashForm(contentContainer, w, s)
+
+	backButton := widget.NewButton("Меню", func() {
+		showMainMenu(content, w, s)
+	})
+
+	mainContent := container.NewVBox(titleLabel, backButton, contentContainer)
+	content.Add(mainContent)
+	content.Refresh()
+}
+
+func showBackup(content *fyne.Container, w fyne.Window, s *service.Service) {
+	content.Objects = nil
+
+	titleLabel := widget.NewLabelWithStyle("Резервное копирование", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+
+	contentContainer := container.NewVBox()
+	forms.ShowBackupForm(contentContainer, w, s)
+
+	backButton := widget.NewButton("Меню", func() {
+		showMainMenu(content, w, s)
+	})
+
+	mainContent := container.NewVBox(titleLabel, backButton, contentContainer)
+	content.Add(mainContent)
+	content.Refresh()
+}
+
+func showConsole(content *fyne.Container, w fyne.Window, s *service.Service) {
+	content.Objects = nil
+
+	titleLabel := widget.NewLabelWithStyle("SQL-консоль", fyne.TextAlignCenter, fyne.TextStyle{Bold: true})
+
+	contentContainer := container.NewVBox()
+	forms.ShowConsoleForm(contentContainer, w, s)
+
+	backButton := widget.NewButton("Меню", func() {
+		showMainMenu(content, w, s)
+	})
+
+	mainContent := container.NewVBox(titleLabel, backButton, contentContainer)
+	content.Add(mainContent)
+	content.Refresh()
+}
+
+func updateEntityContent(content *fyne.Container, w fyne.Window, action, entity int, s *service.Service) {
 	content.Objects = nil
 
 	switch entity {
 	case 0:
-		forms.ShowEmployeesForm(content, action, r)
+		forms.ShowEmployeesForm(content, w, action, s)
 	case 1:
-		forms.ShowGroupsForm(content, action, r)
+		forms.ShowGroupsForm(content, w, action, s)
 	case 2:
-		forms.ShowLessonTypesForm(content, action, r)
+		forms.ShowLessonTypesForm(content, w, action, s)
 	case 3:
-		forms.ShowLessonsForm(content, action, r)
+		forms.ShowLessonsForm(content, w, action, s)
 	case 4:
-		forms.ShowMarksForm(content, action, r)
+		forms.ShowMarksForm(content, w, action, s)
 	case 5:
-		forms.ShowPositionsForm(content, action, r)
+		forms.ShowPositionsForm(content, w, action, s)
 	case 6:
-		forms.ShowStudentsForm(content, action, r)
+		forms.ShowStudentsForm(content, w, action, s)
 	case 7:
-		forms.ShowSubjectsForm(content, action, r)
+		forms.ShowSubjectsForm(content, w, action, s)
 	case 8:
-		forms.ShowEmployeesSubjectsForm(content, action, r)
+		forms.ShowEmployeesSubjectsForm(content, w, action, s)
 	}
 
 	content.Refresh()
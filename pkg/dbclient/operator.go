@@ -0,0 +1,66 @@
+package dbclient
+
+import (
+	"context"
+	"os/user"
+	"sync"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type operatorKey struct{}
+
+// returns a context whose database changes are attributed to name in the audit log
+func WithOperator(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, operatorKey{}, name)
+}
+
+// returns the operator stored in ctx, or the name of the
+// system user running the application when there is none
+func Operator(ctx context.Context) string {
+	if name, ok := ctx.Value(operatorKey{}).(string); ok && name != "" {
+		return name
+	}
+	return systemUser
+}
+
+var systemUser = func() string {
+	u, err := user.Current()
+	if err != nil {
+		return ""
+	}
+	return u.Username
+}()
+
+// keeps the app.operator setting of pooled connections in sync with the
+// context they are acquired for; the setting is read by audit triggers
+type operatorTracker struct {
+	mu    sync.Mutex
+	conns map[*pgx.Conn]string
+}
+
+func (t *operatorTracker) beforeAcquire(ctx context.Context, conn *pgx.Conn) bool {
+	name := Operator(ctx)
+
+	t.mu.Lock()
+	current, ok := t.conns[conn]
+	t.mu.Unlock()
+	if ok && current == name {
+		return true
+	}
+
+	if _, err := conn.Exec(ctx, "SELECT set_config('app.operator', $1, false)", name); err != nil {
+		return false // the pool drops the connection
+	}
+
+	t.mu.Lock()
+	t.conns[conn] = name
+	t.mu.Unlock()
+	return true
+}
+
+func (t *operatorTracker) beforeClose(conn *pgx.Conn) {
+	t.mu.Lock()
+	delete(t.conns, conn)
+	t.mu.Unlock()
+}
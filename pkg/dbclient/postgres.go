@@ -7,16 +7,52 @@ import (
 	"university-db-admin/internal/config"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func NewClientPG(cfg config.DatabaseConfig) *pgx.Conn {
+// common subset of *pgxpool.Pool, *pgx.Conn and pgx.Tx used by repositories
+type Querier interface {
+	Begin(ctx context.Context) (pgx.Tx, error)
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+	CopyFrom(ctx context.Context, table pgx.Identifier, columns []string, src pgx.CopyFromSource) (int64, error)
+}
+
+func NewClientPG(cfg config.DatabaseConfig) *pgxpool.Pool {
 	dsn := fmt.Sprintf("postgresql://%s:%s@%s:%s/%s", cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Name)
 
-	log.Printf("connecting to %s\n", dsn)
-	conn, err := pgx.Connect(context.Background(), dsn)
+	poolCfg, err := pgxpool.ParseConfig(dsn)
+	if err != nil {
+		log.Fatalf("invalid database config: %v\n", err)
+	}
+
+	poolCfg.MinConns = cfg.MinConns
+	poolCfg.MaxConns = cfg.MaxConns
+	poolCfg.MaxConnLifetime = cfg.MaxConnLifetime
+	poolCfg.MaxConnIdleTime = cfg.MaxConnIdleTime
+	poolCfg.HealthCheckPeriod = cfg.HealthCheckPeriod
+	poolCfg.ConnConfig.ConnectTimeout = cfg.ConnectTimeout
+
+	operators := &operatorTracker{conns: map[*pgx.Conn]string{}}
+	poolCfg.BeforeAcquire = operators.beforeAcquire
+	poolCfg.BeforeClose = operators.beforeClose
+
+	// broken connections are dropped by the health check and on release,
+	// so after a server restart the pool reconnects on the next acquire
+	log.Printf("connecting to %s:%s/%s as %s\n", cfg.Host, cfg.Port, cfg.Name, cfg.User)
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolCfg)
 	if err != nil {
+		log.Fatalf("unable to create connection pool: %v\n", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ConnectTimeout)
+	defer cancel()
+
+	if err = pool.Ping(ctx); err != nil {
 		log.Fatalf("unable to connect to database: %v\n", err)
 	}
 
-	return conn
+	return pool
 }
@@ -1,18 +1,30 @@
 package validation
 
 import (
-	"errors"
-
 	"github.com/go-playground/validator/v10"
 )
 
 var validate = validator.New()
 
+// Error is returned when input doesn't pass validation
+type Error struct {
+	msg string
+}
+
+func (e *Error) Error() string {
+	return e.msg
+}
+
+// NewError returns an Error for checks the struct tags can't express
+func NewError(msg string) *Error {
+	return &Error{msg: msg}
+}
+
 // validates all required empty fields
 func ValidateEmptyStrings(fields ...string) error {
 	for _, field := range fields {
 		if err := validate.Var(field, "required"); err != nil {
-			return errors.New("все поля должны быть заполнены")
+			return &Error{msg: "все поля должны быть заполнены"}
 		}
 	}
 	return nil
@@ -22,7 +34,7 @@ func ValidateEmptyStrings(fields ...string) error {
 func ValidatePositiveNumbers(nums ...any) error {
 	for _, num := range nums {
 		if err := validate.Var(num, "gt=0"); err != nil {
-			return errors.New("число должно быть положительным")
+			return &Error{msg: "число должно быть положительным"}
 		}
 	}
 	return nil
@@ -32,7 +44,7 @@ func ValidatePositiveNumbers(nums ...any) error {
 func ValidateStruct(s any) error {
 	err := validate.Struct(s)
 	if err != nil {
-		return errors.New("поля содержат некорректные данные")
+		return &Error{msg: "поля содержат некорректные данные"}
 	}
 	return nil
 }